@@ -0,0 +1,40 @@
+package gasless
+
+// Gasless package - submits a signed gasless order from the CLI
+// Usage: solver submit-gasless <order.json>
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/sirupsen/logrus"
+)
+
+// RunSubmitGasless opens the signed gasless order in the file named by args on its origin chain
+func RunSubmitGasless(args []string) {
+	if len(args) != 1 {
+		logrus.Fatalf("Usage: solver submit-gasless <order.json>")
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		logrus.Fatalf("Failed to load configuration: %v", err)
+	}
+	config.InitializeNetworks()
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	orderID, err := solvercore.NewSolverManager(cfg).SubmitGaslessOrder(ctx, args[0])
+	if err != nil {
+		logrus.Errorf("Gasless order submission failed: %v", err)
+		cancel()
+		os.Exit(1)
+	}
+	fmt.Printf("✅ Gasless order opened: %s\n", orderID)
+}
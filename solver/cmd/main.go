@@ -8,6 +8,10 @@ import (
 	"os"
 	"strings"
 
+	"github.com/NethermindEth/oif-starknet/solver/cmd/gasless"
+	"github.com/NethermindEth/oif-starknet/solver/cmd/orders"
+	"github.com/NethermindEth/oif-starknet/solver/cmd/refund"
+	"github.com/NethermindEth/oif-starknet/solver/cmd/report"
 	"github.com/NethermindEth/oif-starknet/solver/cmd/solver"
 	openorder "github.com/NethermindEth/oif-starknet/solver/cmd/tools/open-order"
 )
@@ -24,6 +28,18 @@ func main() {
 	case "solver":
 		// Run the main solver
 		runSolver()
+	case "refund":
+		// Refund an expired, unfilled order
+		refund.RunRefund(os.Args[2:])
+	case "submit-gasless":
+		// Open a signed gasless order on the user's behalf
+		gasless.RunSubmitGasless(os.Args[2:])
+	case "orders":
+		// Query the order history
+		orders.RunOrders(os.Args[2:])
+	case "report":
+		// Print profit and loss per day and route
+		report.RunReport(os.Args[2:])
 	case "tools":
 		// Route to development tools
 		runTools()
@@ -44,6 +60,11 @@ func printUsage() {
 	fmt.Println()
 	fmt.Println("Commands:")
 	fmt.Println("  solver                    Run the main solver")
+	fmt.Println("  refund <network> <order>  Refund an expired, unfilled order opened on <network> [--wait]")
+	fmt.Println("  submit-gasless <file>     Validate a signed gasless order and submit openFor for it")
+	fmt.Println("  orders list [filters]     List recorded orders [--chain --status --since --until --limit --json]")
+	fmt.Println("  orders show <order>       Show everything recorded about one order")
+	fmt.Println("  report [options]          Print profit and loss [--by day,route] [--format csv|json] [--since --until] [--dry-run]")
 	fmt.Println("  tools <tool> [options]    Run development tools")
 	fmt.Println("  help                      Show this help message")
 	fmt.Println()
@@ -53,6 +74,10 @@ func printUsage() {
 	fmt.Println()
 	fmt.Println("Examples:")
 	fmt.Println("  solver solver                    # Run main solver")
+	fmt.Println("  solver refund base 0x1234... --wait # Refund a Base order and wait for the funds")
+	fmt.Println("  solver submit-gasless gasless-order.json # Open a signed gasless order")
+	fmt.Println("  solver orders list --chain base --status failed # Failed orders touching Base")
+	fmt.Println("  solver report --by route --format json # Profit and loss per route")
 	fmt.Println("  solver tools open-order starknet # Create Starknet order")
 	fmt.Println("  solver tools open-order evm      # Create EVM order")
 	fmt.Println("  solver tools setup-forks deploy  # Deploy to forks")
@@ -88,7 +113,7 @@ func runOpenOrder() {
 	if len(os.Args) < 4 {
 		fmt.Println("Usage: solver tools open-order <chain> [command]")
 		fmt.Println("Available chains: starknet, evm")
-		fmt.Println("Available EVM commands: random-to-evm, random-to-sn, default-evm-evm, default-evm-sn")
+		fmt.Println("Available EVM commands: random-to-evm, random-to-sn, default-evm-evm, default-evm-sn, gasless")
 		fmt.Println("Available Starknet commands: random, default")
 		os.Exit(1)
 	}
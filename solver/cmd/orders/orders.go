@@ -0,0 +1,128 @@
+package orders
+
+// Orders package - queries the order history from the CLI
+// Usage: solver orders list [--chain <network>] [--status <status>] [--since <time>] [--until <time>] [--limit <n>] [--json]
+//        solver orders show <order-id>
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/history"
+	"github.com/sirupsen/logrus"
+)
+
+const usage = "Usage: solver orders list [--chain <network>] [--status <status>] [--since <RFC3339>] [--until <RFC3339>] [--limit <n>] [--json]\n" +
+	"       solver orders show <order-id>"
+
+// defaultLimit is how many orders list prints when --limit is not given
+const defaultLimit = 50
+
+// RunOrders lists recorded orders or shows one of them, reading the file the solver writes
+func RunOrders(args []string) {
+	if len(args) == 0 {
+		logrus.Fatal(usage)
+	}
+
+	if _, err := config.LoadConfig(); err != nil {
+		logrus.Fatalf("Failed to load configuration: %v", err)
+	}
+	store, err := history.Load(history.ConfigFromEnv().File)
+	if err != nil {
+		logrus.Fatalf("Failed to load order history: %v", err)
+	}
+
+	switch args[0] {
+	case "list":
+		err = listOrders(os.Stdout, store, args[1:])
+	case "show":
+		if len(args) != 2 {
+			logrus.Fatal(usage)
+		}
+		err = showOrder(os.Stdout, store, args[1])
+	default:
+		logrus.Fatal(usage)
+	}
+	if err != nil {
+		logrus.Fatalf("%v", err)
+	}
+}
+
+// listOrders prints the orders passing the filter given in args, newest first
+func listOrders(w io.Writer, store *history.Store, args []string) error {
+	flags := flag.NewFlagSet("orders list", flag.ContinueOnError)
+	chain := flags.String("chain", "", "only orders starting or filling on this network")
+	status := flags.String("status", "", "only orders in this status (RECEIVED, SKIPPED, REJECTED, FILLED, SETTLED, FAILED, SIMULATED)")
+	since := flags.String("since", "", "only orders first seen at or after this RFC 3339 time")
+	until := flags.String("until", "", "only orders first seen before this RFC 3339 time")
+	limit := flags.Int("limit", defaultLimit, "maximum number of orders; 0 lists all")
+	asJSON := flags.Bool("json", false, "print the full records as JSON")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	filter := history.Filter{
+		Chain:  *chain,
+		Status: history.Status(strings.ToUpper(*status)),
+		Since:  time.Time{},
+		Until:  time.Time{},
+		Limit:  *limit,
+	}
+	var err error
+	if *since != "" {
+		if filter.Since, err = time.Parse(time.RFC3339, *since); err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if *until != "" {
+		if filter.Until, err = time.Parse(time.RFC3339, *until); err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+
+	records := store.List(filter)
+	if *asJSON {
+		return printJSON(w, records)
+	}
+
+	table := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(table, "ORDER ID\tSTATUS\tROUTE\tCREATED\tDURATION\tPROFIT\tERROR")
+	for _, record := range records {
+		errText := record.Error
+		if record.ErrorKind != "" {
+			errText = fmt.Sprintf("[%s] %s", record.ErrorKind, record.Error)
+		}
+		fmt.Fprintf(table, "%s\t%s\t%s → %s\t%s\t%s\t%s\t%s\n",
+			record.OrderID, record.Status, record.Origin, strings.Join(record.Destinations, ","),
+			record.CreatedAt.Format(time.RFC3339), record.Duration().Round(time.Second), record.Profit, errText)
+	}
+	if err := table.Flush(); err != nil {
+		return fmt.Errorf("failed to print orders: %w", err)
+	}
+	return nil
+}
+
+// showOrder prints the full record of one order
+func showOrder(w io.Writer, store *history.Store, orderID string) error {
+	record, err := store.Get(orderID)
+	if err != nil {
+		return err
+	}
+	return printJSON(w, record)
+}
+
+func printJSON(w io.Writer, value any) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(value); err != nil {
+		return fmt.Errorf("failed to print JSON: %w", err)
+	}
+	return nil
+}
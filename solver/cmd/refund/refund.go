@@ -0,0 +1,53 @@
+package refund
+
+// Refund package - refunds an expired, unfilled order from the CLI
+// Usage: solver refund <origin-network> <order-id> [--wait]
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/sirupsen/logrus"
+)
+
+// RunRefund refunds the order identified by args: origin network, order ID and an optional --wait flag
+func RunRefund(args []string) {
+	var positional []string
+	wait := false
+	for _, arg := range args {
+		if arg == "--wait" {
+			wait = true
+			continue
+		}
+		positional = append(positional, arg)
+	}
+	if len(positional) != 2 {
+		logrus.Fatalf("Usage: solver refund <origin-network> <order-id> [--wait]")
+	}
+
+	cfg, err := config.LoadConfig()
+	if err != nil {
+		logrus.Fatalf("Failed to load configuration: %v", err)
+	}
+	config.InitializeNetworks()
+
+	logger, err := logutil.New(os.Stdout, cfg.LogLevel, cfg.LogFormat)
+	if err != nil {
+		logrus.Fatalf("Invalid logging configuration: %v", err)
+	}
+	logutil.SetDefault(logger)
+
+	ctx, cancel := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer cancel()
+
+	if err := solvercore.NewSolverManager(cfg).RefundOrder(ctx, positional[0], positional[1], wait); err != nil {
+		logrus.Errorf("Refund failed: %v", err)
+		cancel()
+		os.Exit(1)
+	}
+}
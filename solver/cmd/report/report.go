@@ -0,0 +1,79 @@
+package report
+
+// Report package - prints profit and loss from the accounting ledger from the CLI
+// Usage: solver report [--by day,route] [--format csv|json] [--since <time>] [--until <time>] [--dry-run]
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/accounting"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/sirupsen/logrus"
+)
+
+// RunReport prints the profit and loss recorded in the ledger the solver writes
+func RunReport(args []string) {
+	if _, err := config.LoadConfig(); err != nil {
+		logrus.Fatalf("Failed to load configuration: %v", err)
+	}
+	if err := writeReport(os.Stdout, accounting.ConfigFromEnv(), args); err != nil {
+		logrus.Fatalf("Report failed: %v", err)
+	}
+}
+
+// writeReport reads the ledger configured by cfg and writes the report selected by args to w
+func writeReport(w io.Writer, cfg accounting.Config, args []string) error {
+	flags := flag.NewFlagSet("report", flag.ContinueOnError)
+	by := flags.String("by", "day,route", "group rows by day, route, both (day,route) or neither (total)")
+	format := flags.String("format", "csv", "output format: csv or json")
+	since := flags.String("since", "", "only entries at or after this RFC 3339 time")
+	until := flags.String("until", "", "only entries before this RFC 3339 time")
+	dryRun := flags.Bool("dry-run", false, "report the hypothetical profit and loss of orders simulated in dry-run mode")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+
+	opts := accounting.Options{ByDay: false, ByRoute: false, Since: time.Time{}, Until: time.Time{}}
+	for _, group := range strings.Split(*by, ",") {
+		switch strings.TrimSpace(group) {
+		case "day":
+			opts.ByDay = true
+		case "route":
+			opts.ByRoute = true
+		case "total":
+		default:
+			return fmt.Errorf("invalid --by %q: use day, route or total", group)
+		}
+	}
+	var err error
+	if *since != "" {
+		if opts.Since, err = time.Parse(time.RFC3339, *since); err != nil {
+			return fmt.Errorf("invalid --since: %w", err)
+		}
+	}
+	if *until != "" {
+		if opts.Until, err = time.Parse(time.RFC3339, *until); err != nil {
+			return fmt.Errorf("invalid --until: %w", err)
+		}
+	}
+
+	entries, err := accounting.ReadEntries(cfg.Path(*dryRun))
+	if err != nil {
+		return err
+	}
+	rows := accounting.BuildReport(entries, opts)
+
+	switch *format {
+	case "csv":
+		return accounting.WriteCSV(w, rows)
+	case "json":
+		return accounting.WriteJSON(w, rows)
+	default:
+		return fmt.Errorf("invalid --format %q: use csv or json", *format)
+	}
+}
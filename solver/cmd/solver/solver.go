@@ -12,6 +12,7 @@ import (
 
 	"github.com/NethermindEth/oif-starknet/solver/solvercore"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/sirupsen/logrus"
 )
@@ -23,6 +24,48 @@ func (f *cleanFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 	return append([]byte(entry.Message), '\n'), nil
 }
 
+// configureLogrus makes the command's own logrus output follow LOG_LEVEL and LOG_FORMAT
+func configureLogrus(level, format string) {
+	switch strings.ToLower(format) {
+	case logutil.FormatJSON:
+		logrus.SetFormatter(&logrus.JSONFormatter{
+			TimestampFormat:   "",
+			DisableTimestamp:  false,
+			DisableHTMLEscape: false,
+			DataKey:           "",
+			FieldMap:          nil,
+			CallerPrettyfier:  nil,
+			PrettyPrint:       false,
+		})
+	case logutil.FormatLogfmt:
+		logrus.SetFormatter(&logrus.TextFormatter{
+			ForceColors:               false,
+			DisableColors:             true,
+			ForceQuote:                false,
+			DisableQuote:              false,
+			EnvironmentOverrideColors: false,
+			DisableTimestamp:          false,
+			FullTimestamp:             true,
+			TimestampFormat:           "",
+			DisableSorting:            false,
+			SortingFunc:               nil,
+			DisableLevelTruncation:    false,
+			PadLevelText:              false,
+			QuoteEmptyFields:          false,
+			FieldMap:                  nil,
+			CallerPrettyfier:          nil,
+		})
+	default:
+		logrus.SetFormatter(&cleanFormatter{})
+	}
+
+	logrusLevel, err := logrus.ParseLevel(level)
+	if err != nil {
+		logrusLevel = logrus.InfoLevel
+	}
+	logrus.SetLevel(logrusLevel)
+}
+
 // RunSolver runs the main solver application
 func RunSolver() {
 	// Load configuration
@@ -34,9 +77,13 @@ func RunSolver() {
 	// Initialize networks from centralized config after .env is loaded
 	config.InitializeNetworks()
 
-	// Set up clean logging
-	logrus.SetFormatter(&cleanFormatter{})
-	logrus.SetLevel(logrus.InfoLevel)
+	// Set up logging as configured by LOG_LEVEL and LOG_FORMAT
+	logger, err := logutil.New(os.Stdout, cfg.LogLevel, cfg.LogFormat)
+	if err != nil {
+		logrus.Fatalf("Invalid logging configuration: %v", err)
+	}
+	logutil.SetDefault(logger)
+	configureLogrus(cfg.LogLevel, cfg.LogFormat)
 
 	// Create context with cancellation
 	ctx, cancel := context.WithCancel(context.Background())
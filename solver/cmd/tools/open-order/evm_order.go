@@ -5,8 +5,8 @@ package openorder
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"crypto/rand"
-	"encoding/hex"
 	"fmt"
 	"log"
 	"math/big"
@@ -16,13 +16,11 @@ import (
 
 	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
 	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderutil"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
 	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
 
-	"github.com/ethereum/go-ethereum"
-	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/holiman/uint256"
 )
@@ -89,20 +87,7 @@ type OrderData struct {
 }
 
 // ABIOrderData struct for ABI encoding (matches Solidity interface)
-type ABIOrderData struct {
-	Sender             [32]byte
-	Recipient          [32]byte
-	InputToken         [32]byte
-	OutputToken        [32]byte
-	AmountIn           *big.Int
-	AmountOut          *big.Int
-	SenderNonce        *big.Int
-	OriginDomain       uint32
-	DestinationDomain  uint32
-	DestinationSettler [32]byte
-	FillDeadline       uint32
-	Data               []byte
-}
+type ABIOrderData = orderutil.OrderData
 
 // TokenAmount represents a token and its amount
 type TokenAmount struct {
@@ -316,6 +301,8 @@ func RunEVMOrder(command string) {
 		openDefaultEvmToEvm(networks)
 	case "default-evm-sn":
 		openDefaultEvmToStarknet(networks)
+	case "gasless":
+		openGaslessEvmToEvm(networks)
 	default:
 		// Default to random EVM order
 		openRandomToEvm(networks)
@@ -458,23 +445,7 @@ func executeOrder(order *OrderConfig, networks []NetworkConfig) {
 		log.Fatalf("Origin network not found: %s", order.OriginChain)
 	}
 
-	// Get user private key using conditional environment variable logic
-	var userKey string
-	isDevnet := os.Getenv("IS_DEVNET") == "true"
-	if isDevnet {
-		userKey = os.Getenv(fmt.Sprintf("LOCAL_%s_PRIVATE_KEY", strings.ToUpper(order.User)))
-	} else {
-		userKey = os.Getenv(fmt.Sprintf("%s_PRIVATE_KEY", strings.ToUpper(order.User)))
-	}
-	if userKey == "" {
-		log.Fatalf("Private key not found for user: %s (IS_DEVNET=%s)", order.User, os.Getenv("IS_DEVNET"))
-	}
-
-	// Parse private key
-	privateKey, err := ethutil.ParsePrivateKey(userKey)
-	if err != nil {
-		log.Fatalf("Failed to parse private key for %s: %v", order.User, err)
-	}
+	privateKey := userPrivateKey(order.User)
 
 	// Create auth
 	auth, err := ethutil.NewTransactor(big.NewInt(int64(originNetwork.chainID)), privateKey)
@@ -525,7 +496,7 @@ func executeOrder(order *OrderConfig, networks []NetworkConfig) {
 	}
 
 	// Read localDomain from the origin Hyperlane contract to guarantee it matches on-chain
-	localDomain, err := getLocalDomain(client, originNetwork.hyperlaneAddress)
+	localDomain, err := orderutil.LocalDomain(context.Background(), client, originNetwork.hyperlaneAddress)
 	if err != nil {
 		client.Close()
 		log.Fatalf("Failed to read localDomain from origin contract: %v", err)
@@ -607,7 +578,7 @@ func executeOrder(order *OrderConfig, networks []NetworkConfig) {
 	}
 
 	// Pick a fresh senderNonce recognized by the contract to avoid InvalidNonce
-	senderNonce, err := pickValidSenderNonce(client, originNetwork.hyperlaneAddress, auth.From)
+	senderNonce, err := orderutil.PickValidSenderNonce(context.Background(), client, originNetwork.hyperlaneAddress, auth.From)
 	if err != nil {
 		client.Close()
 		log.Fatalf("Failed to pick a valid sender nonce: %v", err)
@@ -616,7 +587,6 @@ func executeOrder(order *OrderConfig, networks []NetworkConfig) {
 	// Build the order data
 	orderData := buildOrderData(order, originNetwork, destinationNetwork, localDomain, senderNonce)
 
-
 	// Build the OnchainCrossChainOrder
 	crossChainOrder := OnchainCrossChainOrder{
 		FillDeadline:  order.FillDeadline,
@@ -685,6 +655,26 @@ func executeOrder(order *OrderConfig, networks []NetworkConfig) {
 	fmt.Printf("   Destination Chain: %s\n", order.DestinationChain)
 }
 
+// userPrivateKey loads a test user's private key using conditional environment variable logic
+func userPrivateKey(user string) *ecdsa.PrivateKey {
+	var userKey string
+	isDevnet := os.Getenv("IS_DEVNET") == "true"
+	if isDevnet {
+		userKey = os.Getenv(fmt.Sprintf("LOCAL_%s_PRIVATE_KEY", strings.ToUpper(user)))
+	} else {
+		userKey = os.Getenv(fmt.Sprintf("%s_PRIVATE_KEY", strings.ToUpper(user)))
+	}
+	if userKey == "" {
+		log.Fatalf("Private key not found for user: %s (IS_DEVNET=%s)", user, os.Getenv("IS_DEVNET"))
+	}
+
+	privateKey, err := ethutil.ParsePrivateKey(userKey)
+	if err != nil {
+		log.Fatalf("Failed to parse private key for %s: %v", user, err)
+	}
+	return privateKey
+}
+
 func buildOrderData(order *OrderConfig, originNetwork, destinationNetwork *NetworkConfig, originDomain uint32, _ *big.Int) OrderData {
 	// Input token from origin network, output token from destination network
 	// inputTokenAddr := originNetwork.dogCoinAddress
@@ -750,131 +740,16 @@ func buildOrderData(order *OrderConfig, originNetwork, destinationNetwork *Netwo
 	}
 }
 
-// getLocalDomain reads the `localDomain()` from the Hyperlane7683 contract on the connected chain
-func getLocalDomain(client *ethclient.Client, contractAddress common.Address) (uint32, error) {
-	abiStr := `[{"inputs":[],"name":"localDomain","outputs":[{"internalType":"uint32","name":"","type":"uint32"}],"stateMutability":"view","type":"function"}]`
-
-	parsedABI, err := abi.JSON(strings.NewReader(abiStr))
-	if err != nil {
-		return 0, err
-	}
-
-	data, err := parsedABI.Pack("localDomain")
-	if err != nil {
-		return 0, err
-	}
-
-	msg := ethereum.CallMsg{
-		From:            common.Address{},
-		To:              &contractAddress,
-		Gas:             0,
-		GasPrice:        nil,
-		GasFeeCap:       nil,
-		GasTipCap:       nil,
-		Value:           nil,
-		Data:            data,
-		AccessList:      nil,
-		BlobGasFeeCap:   nil,
-		BlobHashes:      nil,
-		AuthorizationList: nil,
-	}
-	result, err := client.CallContract(context.Background(), msg, nil)
-	if err != nil {
-		return 0, err
-	}
-
-	var domain uint32
-	if err := parsedABI.UnpackIntoInterface(&domain, "localDomain", result); err != nil {
-		return 0, err
-	}
-
-	return domain, nil
-}
-
-// isValidNonce calls the contract to check whether a nonce is usable for a given address
-func isValidNonce(client *ethclient.Client, contractAddress, from common.Address, nonce *big.Int) (bool, error) {
-	abiStr := `[{"inputs":[{"internalType":"address","name":"_from","type":"address"},{"internalType":"uint256","name":"_nonce","type":"uint256"}],"name":"isValidNonce","outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"view","type":"function"}]`
-
-	parsedABI, err := abi.JSON(strings.NewReader(abiStr))
-	if err != nil {
-		return false, err
-	}
-
-	data, err := parsedABI.Pack("isValidNonce", from, nonce)
-	if err != nil {
-		return false, err
-	}
-
-	msg := ethereum.CallMsg{
-		From:            common.Address{},
-		To:              &contractAddress,
-		Gas:             0,
-		GasPrice:        nil,
-		GasFeeCap:       nil,
-		GasTipCap:       nil,
-		Value:           nil,
-		Data:            data,
-		AccessList:      nil,
-		BlobGasFeeCap:   nil,
-		BlobHashes:      nil,
-		AuthorizationList: nil,
-	}
-	result, err := client.CallContract(context.Background(), msg, nil)
-	if err != nil {
-		return false, err
-	}
-
-	var valid bool
-	if err := parsedABI.UnpackIntoInterface(&valid, "isValidNonce", result); err != nil {
-		return false, err
-	}
-	return valid, nil
-}
-
-// pickValidSenderNonce finds a nonce that the contract reports as valid for the provided sender
-func pickValidSenderNonce(client *ethclient.Client, contractAddress, from common.Address) (*big.Int, error) {
-	// Start with a pseudo-random seed and probe upward
-	seed := time.Now().Unix() % 1_000_000
-	if seed < 1 {
-		seed = 1
-	}
-	nonce := big.NewInt(seed)
-	for i := 0; i < 1000; i++ {
-		valid, err := isValidNonce(client, contractAddress, from, nonce)
-		if err != nil {
-			return nil, err
-		}
-		if valid {
-			return new(big.Int).Set(nonce), nil
-		}
-		nonce = new(big.Int).Add(nonce, big.NewInt(1))
-	}
-	return nil, fmt.Errorf("could not find a valid sender nonce after 1000 attempts starting from %d", seed)
-}
-
 func getOrderDataTypeHash() [32]byte {
-	// This should match OrderEncoder.orderDataType() from Solidity EXACTLY
-	// Including field names and spacing
-	orderDataType := "OrderData(bytes32 sender,bytes32 recipient,bytes32 inputToken,bytes32 outputToken,uint256 amountIn,uint256 amountOut,uint256 senderNonce,uint32 originDomain,uint32 destinationDomain,bytes32 destinationSettler,uint32 fillDeadline,bytes data)"
-	hash := crypto.Keccak256Hash([]byte(orderDataType))
-	return hash
+	return orderutil.OrderDataTypeHash()
 }
 
 // hexToBytes32 converts a hex string to bytes32, handling both EVM and Starknet addresses
 func hexToBytes32(hexStr string) [32]byte {
-	s := strings.TrimPrefix(hexStr, "0x")
-	if len(s)%2 == 1 {
-		s = "0" + s
-	}
-	b, err := hex.DecodeString(s)
+	out, err := orderutil.HexToBytes32(hexStr)
 	if err != nil {
-		log.Fatalf("invalid hex for bytes32: %s (%v)", hexStr, err)
+		log.Fatalf("%v", err)
 	}
-	if len(b) > 32 {
-		b = b[len(b)-32:]
-	}
-	var out [32]byte
-	copy(out[32-len(b):], b)
 	return out
 }
 
@@ -882,31 +757,9 @@ func encodeOrderData(orderData *OrderData, senderNonce *big.Int, networks []Netw
 	// Convert OrderData to ABIOrderData for encoding
 	abiOrderData := convertToABIOrderData(orderData, senderNonce, networks)
 
-
-	// Pack as a tuple to match Solidity's abi.encode(order)
-	tupleT, err := abi.NewType("tuple", "", []abi.ArgumentMarshaling{
-		{Name: "sender", Type: "bytes32", InternalType: "", Components: nil, Indexed: false},
-		{Name: "recipient", Type: "bytes32", InternalType: "", Components: nil, Indexed: false},
-		{Name: "inputToken", Type: "bytes32", InternalType: "", Components: nil, Indexed: false},
-		{Name: "outputToken", Type: "bytes32", InternalType: "", Components: nil, Indexed: false},
-		{Name: "amountIn", Type: "uint256", InternalType: "", Components: nil, Indexed: false},
-		{Name: "amountOut", Type: "uint256", InternalType: "", Components: nil, Indexed: false},
-		{Name: "senderNonce", Type: "uint256", InternalType: "", Components: nil, Indexed: false},
-		{Name: "originDomain", Type: "uint32", InternalType: "", Components: nil, Indexed: false},
-		{Name: "destinationDomain", Type: "uint32", InternalType: "", Components: nil, Indexed: false},
-		{Name: "destinationSettler", Type: "bytes32", InternalType: "", Components: nil, Indexed: false},
-		{Name: "fillDeadline", Type: "uint32", InternalType: "", Components: nil, Indexed: false},
-		{Name: "data", Type: "bytes", InternalType: "", Components: nil, Indexed: false},
-	})
-	if err != nil {
-		log.Fatalf("Failed to define OrderData tuple type: %v", err)
-	}
-
-	args := abi.Arguments{{Type: tupleT, Name: "", Indexed: false}}
-
-	encoded, err := args.Pack(abiOrderData)
+	encoded, err := orderutil.EncodeOrderData(&abiOrderData)
 	if err != nil {
-		log.Fatalf("Failed to ABI-pack OrderData: %v", err)
+		log.Fatalf("%v", err)
 	}
 
 	return encoded
@@ -1046,4 +899,3 @@ func convertToABIOrderData(orderData *OrderData, senderNonce *big.Int, networks
 		Data:               []byte{}, // Empty data for now
 	}
 }
-
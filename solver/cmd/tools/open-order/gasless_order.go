@@ -0,0 +1,195 @@
+package openorder
+
+// Gasless EVM order creation - Alice signs a Permit2 witness over the order instead of calling open()
+// The signed order is written to disk for `solver submit-gasless`, which opens it via openFor
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/json"
+	"fmt"
+	"log"
+	"math/big"
+	"os"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderutil"
+	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// gaslessOrderFile is where the signed gasless order is written
+const gaslessOrderFile = "gasless-order.json"
+
+func openGaslessEvmToEvm(networks []NetworkConfig) {
+	fmt.Println("✍️  Signing Gasless EVM → EVM Test Order...")
+
+	order := OrderConfig{
+		OriginChain:      "Ethereum",
+		DestinationChain: "Optimism",
+		InputToken:       "DogCoin",
+		OutputToken:      "DogCoin",
+		InputAmount:      CreateTokenAmount(testInputAmount, tokenDecimals),  // 1001 tokens (what solver receives)
+		OutputAmount:     CreateTokenAmount(testOutputAmount, tokenDecimals), // 1000 tokens (what solver provides)
+		User:             AliceUserName,
+		OpenDeadline:     uint32(time.Now().Add(1 * time.Hour).Unix()),
+		FillDeadline:     uint32(time.Now().Add(orderDeadlineHours * time.Hour).Unix()),
+	}
+
+	signGaslessOrder(&order, networks)
+}
+
+// signGaslessOrder approves Permit2 for the input token if needed, then signs the order and writes it to disk
+func signGaslessOrder(order *OrderConfig, networks []NetworkConfig) {
+	fmt.Printf("\n📋 Signing Gasless Order: %s → %s\n", order.OriginChain, order.DestinationChain)
+
+	originNetwork := findNetwork(networks, order.OriginChain)
+	destinationNetwork := findNetwork(networks, order.DestinationChain)
+	if originNetwork == nil || destinationNetwork == nil {
+		log.Fatalf("Gasless orders need EVM origin and destination networks: %s → %s", order.OriginChain, order.DestinationChain)
+	}
+
+	privateKey := userPrivateKey(order.User)
+	user := crypto.PubkeyToAddress(privateKey.PublicKey)
+
+	client, err := ethclient.Dial(originNetwork.url)
+	if err != nil {
+		log.Fatalf("Failed to connect to %s: %v", order.OriginChain, err)
+	}
+	defer client.Close()
+
+	ctx := context.Background()
+	localDomain, err := orderutil.LocalDomain(ctx, client, originNetwork.hyperlaneAddress)
+	if err != nil {
+		log.Fatalf("Failed to read localDomain from origin contract: %v", err)
+	}
+	// Permit2 signs over the EVM chain ID, which may differ from the Hyperlane domain
+	chainID, err := client.ChainID(ctx)
+	if err != nil {
+		log.Fatalf("Failed to read chain ID: %v", err)
+	}
+	contract, err := contracts.NewHyperlane7683(originNetwork.hyperlaneAddress, client)
+	if err != nil {
+		log.Fatalf("Failed to bind Hyperlane7683: %v", err)
+	}
+	permit2, err := contract.PERMIT2(&bind.CallOpts{
+		Pending:     false,
+		From:        common.Address{},
+		BlockNumber: nil,
+		BlockHash:   common.Hash{},
+		Context:     ctx,
+	})
+	if err != nil {
+		log.Fatalf("Failed to read PERMIT2 from origin contract: %v", err)
+	}
+
+	// Permit2 pulls the input from Alice when the order is opened, so it needs an allowance, not the settler
+	ensurePermit2Allowance(client, chainID, privateKey, originNetwork.dogCoinAddress, permit2, order.InputAmount)
+
+	// One random nonce serves as both the Permit2 nonce and the settler's senderNonce
+	senderNonce, err := orderutil.PickValidSenderNonce(ctx, client, originNetwork.hyperlaneAddress, user)
+	if err != nil {
+		log.Fatalf("Failed to pick a valid sender nonce: %v", err)
+	}
+	orderData := buildOrderData(order, originNetwork, destinationNetwork, localDomain, senderNonce)
+
+	gaslessOrder := orderutil.GaslessOrder{
+		OriginSettler: originNetwork.hyperlaneAddress,
+		User:          user,
+		Nonce:         senderNonce,
+		OriginChainID: new(big.Int).SetUint64(uint64(localDomain)),
+		OpenDeadline:  order.OpenDeadline,
+		FillDeadline:  order.FillDeadline,
+		OrderDataType: getOrderDataTypeHash(),
+		OrderData:     encodeOrderData(&orderData, senderNonce, networks),
+	}
+	signed, err := gaslessOrder.Sign(privateKey, permit2, chainID)
+	if err != nil {
+		log.Fatalf("Failed to sign gasless order: %v", err)
+	}
+	resolved, err := gaslessOrder.Resolve()
+	if err != nil {
+		log.Fatalf("Failed to resolve gasless order: %v", err)
+	}
+
+	encoded, err := json.MarshalIndent(signed, "", "  ")
+	if err != nil {
+		log.Fatalf("Failed to encode gasless order: %v", err)
+	}
+	if err := os.WriteFile(gaslessOrderFile, encoded, 0o600); err != nil {
+		log.Fatalf("Failed to write %s: %v", gaslessOrderFile, err)
+	}
+
+	fmt.Printf("%s\n", encoded)
+	fmt.Printf("\n🎉 Gasless order signed!\n")
+	fmt.Printf("📊 Order Summary:\n")
+	fmt.Printf("   Order ID: %s\n", common.BytesToHash(resolved.OrderID[:]).Hex())
+	fmt.Printf("   Input Amount: %s\n", order.InputAmount.String())
+	fmt.Printf("   Output Amount: %s\n", order.OutputAmount.String())
+	fmt.Printf("   Origin Chain: %s\n", order.OriginChain)
+	fmt.Printf("   Destination Chain: %s\n", order.DestinationChain)
+	fmt.Printf("   Saved to: %s (submit with `solver submit-gasless %s`)\n", gaslessOrderFile, gaslessOrderFile)
+}
+
+// ensurePermit2Allowance approves Permit2 to move amount of token for the key's owner if it cannot already
+func ensurePermit2Allowance(client *ethclient.Client, chainID *big.Int, privateKey *ecdsa.PrivateKey,
+	token, permit2 common.Address, amount *big.Int,
+) {
+	owner := crypto.PubkeyToAddress(privateKey.PublicKey)
+	balance, err := ethutil.ERC20Balance(client, token, owner)
+	if err != nil {
+		log.Fatalf("Failed to read input token balance: %v", err)
+	}
+	if balance.Cmp(amount) < 0 {
+		log.Fatalf("Insufficient token balance: need %s, have %s",
+			ethutil.FormatTokenAmount(amount, tokenDecimals), ethutil.FormatTokenAmount(balance, tokenDecimals))
+	}
+
+	allowance, err := ethutil.ERC20Allowance(client, token, owner, permit2)
+	if err != nil {
+		log.Fatalf("Failed to read Permit2 allowance: %v", err)
+	}
+	if allowance.Cmp(amount) >= 0 {
+		fmt.Printf("   ✅ Sufficient Permit2 allowance already exists\n")
+		return
+	}
+
+	auth, err := ethutil.NewTransactor(chainID, privateKey)
+	if err != nil {
+		log.Fatalf("Failed to create auth: %v", err)
+	}
+	gasPrice, err := ethutil.SuggestGas(client)
+	if err != nil {
+		log.Fatalf("Failed to get gas price: %v", err)
+	}
+	auth.GasPrice = gasPrice
+
+	fmt.Printf("   🔄 Approving Permit2 for %s tokens...\n", amount.String())
+	approveTx, err := ethutil.ERC20Approve(client, auth, token, permit2, amount)
+	if err != nil {
+		log.Fatalf("Failed to approve Permit2: %v", err)
+	}
+	receipt, err := ethutil.WaitForTransaction(client, approveTx)
+	if err != nil {
+		log.Fatalf("Failed to wait for Permit2 approval: %v", err)
+	}
+	if receipt.Status != 1 {
+		log.Fatalf("Permit2 approval transaction failed")
+	}
+	fmt.Printf("   ✅ Permit2 approval confirmed!\n")
+}
+
+// findNetwork returns the configured network with the given name, or nil
+func findNetwork(networks []NetworkConfig, name string) *NetworkConfig {
+	for i := range networks {
+		if networks[i].name == name {
+			return &networks[i]
+		}
+	}
+	return nil
+}
@@ -20,15 +20,11 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 
 	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderutil"
 	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
 )
 
-const (
-	// Data offset for Cairo bytes
-	dataOffset = 384
-)
-
 // getAliceAddressForNetwork gets Alice's address for a specific network using IS_DEVNET logic
 func getAliceAddressForNetwork(networkName string) (string, error) {
 	if strings.Contains(strings.ToLower(networkName), "starknet") {
@@ -572,122 +568,38 @@ func buildStarknetOrderData(order *StarknetOrderConfig, originNetwork *StarknetN
 }
 
 func getOrderDataTypeHashU256() (low, high *felt.Felt) {
-	// Solidity ORDER_DATA_TYPE_HASH (32 bytes)
-	hashHex := getEnvWithDefault("ORDER_DATA_TYPE_HASH", "0x08d75650babf4de09c9273d48ef647876057ed91d4323f8a2e3ebc2cd8a63b5e")
-	bi, ok := new(big.Int).SetString(hashHex, 0)
-	if !ok {
+	// Solidity ORDER_DATA_TYPE_HASH (32 bytes), overridable for custom deployments
+	hashHex := getEnvWithDefault("ORDER_DATA_TYPE_HASH", "")
+	if hashHex == "" {
+		return orderutil.SplitU256(orderutil.OrderDataTypeHash())
+	}
+	hash, err := orderutil.HexToBytes32(hashHex)
+	if err != nil {
 		panic("Failed to parse ORDER_DATA_TYPE_HASH")
 	}
-	// Split into low/high 128-bit felts: low = lower 128, high = upper 128
-	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
-	lowBI := new(big.Int).And(bi, mask)
-	highBI := new(big.Int).Rsh(bi, 128)
-	return utils.BigIntToFelt(lowBI), utils.BigIntToFelt(highBI)
+	return orderutil.SplitU256(hash)
 }
 
 func encodeStarknetOrderData(orderData *StarknetOrderData) []*felt.Felt {
-	leftPad := func(src []byte, size int) []byte {
-		if len(src) >= size {
-			return src[len(src)-size:]
-		}
-		out := make([]byte, size)
-		copy(out[size-len(src):], src)
-		return out
-	}
-
-	feltToBytes32 := func(f *felt.Felt) []byte {
-		b := f.Bytes()
-		// Ensure we always return exactly 32 bytes
-		result := make([]byte, 32)
-
-		if len(b) >= 32 {
-			// Take the last 32 bytes if longer
-			start := len(b) - 32
-			for i := 0; i < 32; i++ {
-				result[i] = b[start+i]
-			}
-		} else {
-			// Pad with zeros if shorter
-			start := 32 - len(b)
-			for i := 0; i < len(b); i++ {
-				result[start+i] = b[i]
-			}
-		}
-
-		return result
-	}
-
-	u256ToBytes32 := func(n *big.Int) []byte {
-		if n == nil {
-			return make([]byte, 32)
-		}
-		return leftPad(n.Bytes(), 32)
-	}
-
-	u32Word := func(v uint32) []byte {
-		b := make([]byte, 32)
-		b[28] = byte(v >> 24)
-		b[29] = byte(v >> 16)
-		b[30] = byte(v >> 8)
-		b[31] = byte(v)
-		return b
-	}
-
-	writeWord := func(dst *[]byte, word []byte) {
-		*dst = append(*dst, leftPad(word, 32)...)
-	}
-
-	var raw []byte
-
-	// 0) Dynamic data offset (32 bytes) - this is the offset to where the data field starts
-	writeWord(&raw, u32Word(32))
-
-	// 1) sender, recipient, input_token, output_token (bytes32)
-	writeWord(&raw, feltToBytes32(orderData.Sender))
-	writeWord(&raw, feltToBytes32(orderData.Recipient))
-	writeWord(&raw, feltToBytes32(orderData.InputToken))
-	writeWord(&raw, feltToBytes32(orderData.OutputToken))
-	// 2) amount_in, amount_out (uint256)
-	writeWord(&raw, u256ToBytes32(orderData.AmountIn))
-	writeWord(&raw, u256ToBytes32(orderData.AmountOut))
-	// 3) sender_nonce (uint256 from felt)
-	writeWord(&raw, feltToBytes32(orderData.SenderNonce))
-	// 4) origin_domain (uint32), destination_domain (uint32) as 32-byte words
-	writeWord(&raw, u32Word(orderData.OriginDomain))
-	writeWord(&raw, u32Word(orderData.DestinationDomain))
-	// 5) destination_settler (bytes32)
-	writeWord(&raw, feltToBytes32(orderData.DestinationSettler))
-	// 6) fill_deadline (uint32) in 32-byte word (clamp lower 32 bits)
-	fill32 := uint32(orderData.FillDeadline)
-	writeWord(&raw, u32Word(fill32))
-	// 7) data offset (32 * 12 = 384)
-	writeWord(&raw, u32Word(dataOffset))
-
-	// Tail: data length (32 bytes) then data padded to 32
-	writeWord(&raw, make([]byte, 0)) // length = 0 -> becomes 32 zero bytes
-
-	// Now wrap into Cairo Bytes: size, words_len, then 16-byte words as felts
-	numElements := (len(raw) + 15) / 16
-	words := make([]*felt.Felt, 0, numElements)
-	for i := 0; i < len(raw); i += 16 {
-		end := i + 16
-		if end > len(raw) {
-			chunk := make([]byte, 16)
-			copy(chunk, raw[i:])
-			words = append(words, utils.BigIntToFelt(new(big.Int).SetBytes(chunk)))
-		} else {
-			words = append(words, utils.BigIntToFelt(new(big.Int).SetBytes(raw[i:end])))
-		}
+	// Cairo decodes orderData as the Solidity ABI encoding wrapped in Bytes, so both origins share one encoder
+	encoded, err := orderutil.EncodeCairoOrderData(&orderutil.OrderData{
+		Sender:             orderData.Sender.Bytes(),
+		Recipient:          orderData.Recipient.Bytes(),
+		InputToken:         orderData.InputToken.Bytes(),
+		OutputToken:        orderData.OutputToken.Bytes(),
+		AmountIn:           orderData.AmountIn,
+		AmountOut:          orderData.AmountOut,
+		SenderNonce:        orderData.SenderNonce.BigInt(new(big.Int)),
+		OriginDomain:       orderData.OriginDomain,
+		DestinationDomain:  orderData.DestinationDomain,
+		DestinationSettler: orderData.DestinationSettler.Bytes(),
+		FillDeadline:       uint32(orderData.FillDeadline),
+		Data:               []byte{},
+	})
+	if err != nil {
+		log.Fatalf("%v", err)
 	}
-
-	bytesStruct := make([]*felt.Felt, 0, 2+len(words))
-	bytesStruct = append(bytesStruct,
-		utils.Uint64ToFelt(uint64(len(raw))),
-		utils.Uint64ToFelt(uint64(len(words))),
-	)
-	bytesStruct = append(bytesStruct, words...)
-
-	return bytesStruct
+	return encoded
 }
 
 // getRandomDestinationChain gets a random destination chain from available networks
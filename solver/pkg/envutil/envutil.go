@@ -3,6 +3,8 @@ package envutil
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
 )
 
 const (
@@ -120,6 +122,16 @@ func GetEnvInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// GetEnvFloat64 gets an environment variable as float64 with a default fallback
+func GetEnvFloat64(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.ParseFloat(strings.TrimSpace(value), 64); err == nil {
+			return parsed
+		}
+	}
+	return defaultValue
+}
+
 // parseUint64 parses a string to uint64
 func parseUint64(s string) (uint64, error) {
 	var result uint64
@@ -598,6 +598,29 @@ func TestGetEnvInt(t *testing.T) {
 	})
 }
 
+func TestGetEnvFloat64(t *testing.T) {
+	t.Run("Returns parsed float when valid", func(t *testing.T) {
+		t.Setenv("TEST_FLOAT", "1.25")
+
+		result := GetEnvFloat64("TEST_FLOAT", 2)
+		assert.InDelta(t, 1.25, result, 1e-9)
+	})
+
+	t.Run("Returns default when invalid", func(t *testing.T) {
+		t.Setenv("TEST_FLOAT", "invalid")
+
+		result := GetEnvFloat64("TEST_FLOAT", 2)
+		assert.InDelta(t, 2.0, result, 1e-9)
+	})
+
+	t.Run("Returns default when not set", func(t *testing.T) {
+		os.Unsetenv("TEST_FLOAT")
+
+		result := GetEnvFloat64("TEST_FLOAT", 2)
+		assert.InDelta(t, 2.0, result, 1e-9)
+	})
+}
+
 func TestParseUint64(t *testing.T) {
 	t.Run("Parses valid uint64", func(t *testing.T) {
 		result, err := parseUint64("12345")
@@ -33,6 +33,13 @@ var ERC20ABI = `[
 		"stateMutability": "view",
 		"type": "function"
 	},
+	{
+		"inputs": [],
+		"name": "decimals",
+		"outputs": [{"internalType": "uint8", "name": "", "type": "uint8"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
 	{
 		"inputs": [
 			{"internalType": "address", "name": "", "type": "address"},
@@ -97,6 +104,11 @@ func GetBlockNumber(rpcURL string) (uint64, error) {
 	return client.BlockNumber(context.Background())
 }
 
+// NativeBalance gets the native (ETH) balance for a given address
+func NativeBalance(client *ethclient.Client, ownerAddress common.Address) (*big.Int, error) {
+	return client.BalanceAt(context.Background(), ownerAddress, nil)
+}
+
 // ERC20Balance gets the ERC20 token balance for a given address
 func ERC20Balance(client *ethclient.Client, tokenAddress, ownerAddress common.Address) (*big.Int, error) {
 	parsedABI, err := abi.JSON(strings.NewReader(ERC20ABI))
@@ -110,17 +122,17 @@ func ERC20Balance(client *ethclient.Client, tokenAddress, ownerAddress common.Ad
 	}
 
 	msg := ethereum.CallMsg{
-		From:            common.Address{},
-		To:              &tokenAddress,
-		Gas:             0,
-		GasPrice:        nil,
-		GasFeeCap:       nil,
-		GasTipCap:       nil,
-		Value:           nil,
-		Data:            data,
-		AccessList:      nil,
-		BlobGasFeeCap:   nil,
-		BlobHashes:      nil,
+		From:              common.Address{},
+		To:                &tokenAddress,
+		Gas:               0,
+		GasPrice:          nil,
+		GasFeeCap:         nil,
+		GasTipCap:         nil,
+		Value:             nil,
+		Data:              data,
+		AccessList:        nil,
+		BlobGasFeeCap:     nil,
+		BlobHashes:        nil,
 		AuthorizationList: nil,
 	}
 	result, err := client.CallContract(context.Background(), msg, nil)
@@ -141,6 +153,50 @@ func ERC20Balance(client *ethclient.Client, tokenAddress, ownerAddress common.Ad
 	return balance, nil
 }
 
+// ERC20Decimals gets the number of decimals of an ERC20 token
+func ERC20Decimals(client *ethclient.Client, tokenAddress common.Address) (uint8, error) {
+	parsedABI, err := abi.JSON(strings.NewReader(ERC20ABI))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse ERC20 ABI: %w", err)
+	}
+
+	data, err := parsedABI.Pack("decimals")
+	if err != nil {
+		return 0, fmt.Errorf("failed to pack decimals call: %w", err)
+	}
+
+	msg := ethereum.CallMsg{
+		From:              common.Address{},
+		To:                &tokenAddress,
+		Gas:               0,
+		GasPrice:          nil,
+		GasFeeCap:         nil,
+		GasTipCap:         nil,
+		Value:             nil,
+		Data:              data,
+		AccessList:        nil,
+		BlobGasFeeCap:     nil,
+		BlobHashes:        nil,
+		AuthorizationList: nil,
+	}
+	result, err := client.CallContract(context.Background(), msg, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to call decimals: %w", err)
+	}
+
+	// Check if result is empty (contract might not exist)
+	if len(result) == 0 {
+		return 0, fmt.Errorf("empty result from decimals call - contract may not exist at address %s", tokenAddress.Hex())
+	}
+
+	var decimals uint8
+	if err := parsedABI.UnpackIntoInterface(&decimals, "decimals", result); err != nil {
+		return 0, fmt.Errorf("failed to unpack decimals result: %w", err)
+	}
+
+	return decimals, nil
+}
+
 // ERC20Allowance gets the ERC20 token allowance for a given owner and spender
 func ERC20Allowance(client *ethclient.Client, tokenAddress, ownerAddress, spenderAddress common.Address) (*big.Int, error) {
 	parsedABI, err := abi.JSON(strings.NewReader(ERC20ABI))
@@ -154,17 +210,17 @@ func ERC20Allowance(client *ethclient.Client, tokenAddress, ownerAddress, spende
 	}
 
 	msg := ethereum.CallMsg{
-		From:            common.Address{},
-		To:              &tokenAddress,
-		Gas:             0,
-		GasPrice:        nil,
-		GasFeeCap:       nil,
-		GasTipCap:       nil,
-		Value:           nil,
-		Data:            data,
-		AccessList:      nil,
-		BlobGasFeeCap:   nil,
-		BlobHashes:      nil,
+		From:              common.Address{},
+		To:                &tokenAddress,
+		Gas:               0,
+		GasPrice:          nil,
+		GasFeeCap:         nil,
+		GasTipCap:         nil,
+		Value:             nil,
+		Data:              data,
+		AccessList:        nil,
+		BlobGasFeeCap:     nil,
+		BlobHashes:        nil,
 		AuthorizationList: nil,
 	}
 	result, err := client.CallContract(context.Background(), msg, nil)
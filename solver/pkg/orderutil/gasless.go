@@ -0,0 +1,310 @@
+package orderutil
+
+// Gasless (openFor) orders: the user signs a Permit2 batch witness transfer over the resolved order
+// instead of sending open() themselves; a filler then submits openFor with the signature.
+// Resolution and hashing mirror BasicSwap7683._resolvedOrder, Base7683.witnessHash and Permit2's PermitHash.
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// Permit2Address is the canonical Uniswap Permit2 deployment, identical on every EVM chain
+const Permit2Address = "0x000000000022D473030F116dDEE9F6B43aC78BA3"
+
+// ResolvedOrderTypeString must match Base7683.RESOLVED_CROSS_CHAIN_ORDER_TYPEHASH's preimage, spaces included
+const ResolvedOrderTypeString = "ResolvedCrossChainOrder(address user, uint64 originChainId, uint32 openDeadline, " +
+	"uint32 fillDeadline, Output[] maxSpent, Output[] minReceived, FillInstruction[] fillInstructions)" +
+	"Output(bytes32 token, uint256 amount, bytes32 recipient, uint64 chainId)" +
+	"FillInstruction(uint64 destinationChainId, bytes32 destinationSettler, bytes originData)"
+
+// WitnessTypeString must match Base7683.witnessTypeString
+const WitnessTypeString = "ResolvedCrossChainOrder witness)" + ResolvedOrderTypeString +
+	"TokenPermissions(address token,uint256 amount)"
+
+const (
+	permitBatchWitnessTypeStub = "PermitBatchWitnessTransferFrom(TokenPermissions[] permitted,address spender," +
+		"uint256 nonce,uint256 deadline,"
+	tokenPermissionsType = "TokenPermissions(address token,uint256 amount)"
+	eip712DomainType     = "EIP712Domain(string name,uint256 chainId,address verifyingContract)"
+	permit2Name          = "Permit2"
+
+	signatureLength = 65
+	// ecrecover expects v in {27, 28}; crypto.Sign produces {0, 1}
+	signatureVOffset = 27
+)
+
+// GaslessOrder mirrors the ERC-7683 GaslessCrossChainOrder struct; JSON uses the Solidity field names
+type GaslessOrder struct {
+	OriginSettler common.Address `json:"originSettler"`
+	User          common.Address `json:"user"`
+	// Nonce is the Permit2 nonce; OrderData carries the settler's own senderNonce
+	Nonce *big.Int `json:"nonce"`
+	// OriginChainID is the origin's Hyperlane domain, as returned by localDomain()
+	OriginChainID *big.Int      `json:"originChainId"`
+	OpenDeadline  uint32        `json:"openDeadline"`
+	FillDeadline  uint32        `json:"fillDeadline"`
+	OrderDataType common.Hash   `json:"orderDataType"`
+	OrderData     hexutil.Bytes `json:"orderData"`
+}
+
+// SignedGaslessOrder is a gasless order together with the user's Permit2 signature
+type SignedGaslessOrder struct {
+	Order     GaslessOrder  `json:"order"`
+	Signature hexutil.Bytes `json:"signature"`
+}
+
+// Output mirrors the ERC-7683 Output struct
+type Output struct {
+	Token     [32]byte
+	Amount    *big.Int
+	Recipient [32]byte
+	ChainId   *big.Int
+}
+
+// FillInstruction mirrors the ERC-7683 FillInstruction struct
+type FillInstruction struct {
+	DestinationChainId *big.Int
+	DestinationSettler [32]byte
+	OriginData         []byte
+}
+
+// ResolvedOrder is the ResolvedCrossChainOrder the origin settler emits when the order is opened
+type ResolvedOrder struct {
+	User             common.Address
+	OriginChainID    *big.Int
+	OpenDeadline     uint32
+	FillDeadline     uint32
+	OrderID          [32]byte
+	MaxSpent         []Output
+	MinReceived      []Output
+	FillInstructions []FillInstruction
+}
+
+// Resolve resolves the order exactly like the origin settler: the order's user and fill deadline override
+// the sender and fillDeadline inside the order data, which also changes the order ID.
+func (o *GaslessOrder) Resolve() (*ResolvedOrder, error) {
+	if o.OrderDataType != OrderDataTypeHash() {
+		return nil, fmt.Errorf("unsupported order data type %s", o.OrderDataType.Hex())
+	}
+	if o.OriginChainID == nil {
+		return nil, fmt.Errorf("order has no origin chain ID")
+	}
+	orderData, err := DecodeOrderData(o.OrderData)
+	if err != nil {
+		return nil, err
+	}
+	if uint64(orderData.OriginDomain) != o.OriginChainID.Uint64() {
+		return nil, fmt.Errorf("order data origin domain %d does not match origin chain ID %s",
+			orderData.OriginDomain, o.OriginChainID)
+	}
+
+	orderData.FillDeadline = o.FillDeadline
+	orderData.Sender = AddressToBytes32(o.User)
+	encoded, err := EncodeOrderData(orderData)
+	if err != nil {
+		return nil, err
+	}
+
+	destinationDomain := new(big.Int).SetUint64(uint64(orderData.DestinationDomain))
+	originDomain := new(big.Int).SetUint64(uint64(orderData.OriginDomain))
+	return &ResolvedOrder{
+		User:          o.User,
+		OriginChainID: originDomain,
+		OpenDeadline:  o.OpenDeadline,
+		FillDeadline:  o.FillDeadline,
+		OrderID:       crypto.Keccak256Hash(encoded),
+		MaxSpent: []Output{{
+			Token:     orderData.OutputToken,
+			Amount:    orderData.AmountOut,
+			Recipient: orderData.DestinationSettler,
+			ChainId:   destinationDomain,
+		}},
+		MinReceived: []Output{{
+			Token:     orderData.InputToken,
+			Amount:    orderData.AmountIn,
+			Recipient: [32]byte{},
+			ChainId:   originDomain,
+		}},
+		FillInstructions: []FillInstruction{{
+			DestinationChainId: destinationDomain,
+			DestinationSettler: orderData.DestinationSettler,
+			OriginData:         encoded,
+		}},
+	}, nil
+}
+
+// SenderNonce returns the settler nonce inside the order data, which openFor marks as used for the user
+func (o *GaslessOrder) SenderNonce() (*big.Int, error) {
+	orderData, err := DecodeOrderData(o.OrderData)
+	if err != nil {
+		return nil, err
+	}
+	return orderData.SenderNonce, nil
+}
+
+// WitnessHash computes Base7683.witnessHash: abi.encode of the type hash and the resolved order's fields
+// (without the order ID), hashed
+func WitnessHash(resolved *ResolvedOrder) (common.Hash, error) {
+	outputs, err := abi.NewType("tuple[]", "", []abi.ArgumentMarshaling{
+		{Name: "token", Type: "bytes32", InternalType: "", Components: nil, Indexed: false},
+		{Name: "amount", Type: "uint256", InternalType: "", Components: nil, Indexed: false},
+		{Name: "recipient", Type: "bytes32", InternalType: "", Components: nil, Indexed: false},
+		{Name: "chainId", Type: "uint256", InternalType: "", Components: nil, Indexed: false},
+	})
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to build Output type: %w", err)
+	}
+	instructions, err := abi.NewType("tuple[]", "", []abi.ArgumentMarshaling{
+		{Name: "destinationChainId", Type: "uint256", InternalType: "", Components: nil, Indexed: false},
+		{Name: "destinationSettler", Type: "bytes32", InternalType: "", Components: nil, Indexed: false},
+		{Name: "originData", Type: "bytes", InternalType: "", Components: nil, Indexed: false},
+	})
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to build FillInstruction type: %w", err)
+	}
+
+	args, err := typedArguments("bytes32", "address", "uint256", "uint32", "uint32")
+	if err != nil {
+		return common.Hash{}, err
+	}
+	for _, t := range []abi.Type{outputs, outputs, instructions} {
+		args = append(args, abi.Argument{Name: "", Type: t, Indexed: false})
+	}
+	encoded, err := args.Pack(
+		crypto.Keccak256Hash([]byte(ResolvedOrderTypeString)),
+		resolved.User,
+		resolved.OriginChainID,
+		resolved.OpenDeadline,
+		resolved.FillDeadline,
+		resolved.MaxSpent,
+		resolved.MinReceived,
+		resolved.FillInstructions,
+	)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to encode witness: %w", err)
+	}
+	return crypto.Keccak256Hash(encoded), nil
+}
+
+// Permit2Digest returns the EIP-712 digest the user signs: a Permit2 batch witness transfer of the order's
+// inputs to the origin settler, deadline openDeadline, with the resolved order as witness.
+// chainID is the EVM chain ID of the origin (Permit2's domain), not its Hyperlane domain.
+func (o *GaslessOrder) Permit2Digest(permit2 common.Address, chainID *big.Int) (common.Hash, error) {
+	resolved, err := o.Resolve()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	witness, err := WitnessHash(resolved)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	nonce := o.Nonce
+	if nonce == nil {
+		nonce = big.NewInt(0)
+	}
+
+	tokenPermissionsArgs, err := typedArguments("bytes32", "address", "uint256")
+	if err != nil {
+		return common.Hash{}, err
+	}
+	structArgs, err := typedArguments("bytes32", "bytes32", "address", "uint256", "uint256", "bytes32")
+	if err != nil {
+		return common.Hash{}, err
+	}
+	domainArgs, err := typedArguments("bytes32", "bytes32", "uint256", "address")
+	if err != nil {
+		return common.Hash{}, err
+	}
+
+	tokenPermissionsTypeHash := crypto.Keccak256Hash([]byte(tokenPermissionsType))
+	permissionHashes := make([]byte, 0, len(resolved.MinReceived)*bytes32Length)
+	for _, input := range resolved.MinReceived {
+		packed, err := tokenPermissionsArgs.Pack(tokenPermissionsTypeHash,
+			common.BytesToAddress(input.Token[:]), input.Amount)
+		if err != nil {
+			return common.Hash{}, fmt.Errorf("failed to encode token permissions: %w", err)
+		}
+		permissionHashes = append(permissionHashes, crypto.Keccak256(packed)...)
+	}
+
+	typeHash := crypto.Keccak256Hash([]byte(permitBatchWitnessTypeStub + WitnessTypeString))
+	structEncoded, err := structArgs.Pack(typeHash, crypto.Keccak256Hash(permissionHashes), o.OriginSettler, nonce,
+		new(big.Int).SetUint64(uint64(o.OpenDeadline)), witness)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to encode permit: %w", err)
+	}
+
+	domainEncoded, err := domainArgs.Pack(crypto.Keccak256Hash([]byte(eip712DomainType)),
+		crypto.Keccak256Hash([]byte(permit2Name)), chainID, permit2)
+	if err != nil {
+		return common.Hash{}, fmt.Errorf("failed to encode Permit2 domain: %w", err)
+	}
+
+	return crypto.Keccak256Hash([]byte("\x19\x01"), crypto.Keccak256(domainEncoded), crypto.Keccak256(structEncoded)), nil
+}
+
+// Sign signs the order's Permit2 digest with key, returning a 65-byte r||s||v signature
+func (o *GaslessOrder) Sign(key *ecdsa.PrivateKey, permit2 common.Address, chainID *big.Int) (*SignedGaslessOrder, error) {
+	digest, err := o.Permit2Digest(permit2, chainID)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := crypto.Sign(digest.Bytes(), key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign order: %w", err)
+	}
+	signature[signatureLength-1] += signatureVOffset
+	return &SignedGaslessOrder{Order: *o, Signature: signature}, nil
+}
+
+// Signer recovers the address that signed the order's Permit2 digest
+func (s *SignedGaslessOrder) Signer(permit2 common.Address, chainID *big.Int) (common.Address, error) {
+	if len(s.Signature) != signatureLength {
+		return common.Address{}, fmt.Errorf("signature must be %d bytes, got %d", signatureLength, len(s.Signature))
+	}
+	digest, err := s.Order.Permit2Digest(permit2, chainID)
+	if err != nil {
+		return common.Address{}, err
+	}
+	signature := append([]byte(nil), s.Signature...)
+	if signature[signatureLength-1] >= signatureVOffset {
+		signature[signatureLength-1] -= signatureVOffset
+	}
+	pub, err := crypto.SigToPub(digest.Bytes(), signature)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to recover signer: %w", err)
+	}
+	return crypto.PubkeyToAddress(*pub), nil
+}
+
+// Verify checks that the order's user signed it
+func (s *SignedGaslessOrder) Verify(permit2 common.Address, chainID *big.Int) error {
+	signer, err := s.Signer(permit2, chainID)
+	if err != nil {
+		return err
+	}
+	if signer != s.Order.User {
+		return fmt.Errorf("order signed by %s, not its user %s", signer.Hex(), s.Order.User.Hex())
+	}
+	return nil
+}
+
+// typedArguments builds unnamed ABI arguments of the given elementary types
+func typedArguments(typeNames ...string) (abi.Arguments, error) {
+	args := make(abi.Arguments, 0, len(typeNames))
+	for _, name := range typeNames {
+		t, err := abi.NewType(name, "", nil)
+		if err != nil {
+			return nil, fmt.Errorf("invalid ABI type %s: %w", name, err)
+		}
+		args = append(args, abi.Argument{Name: "", Type: t, Indexed: false})
+	}
+	return args, nil
+}
@@ -0,0 +1,106 @@
+package orderutil
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleGaslessOrder(t *testing.T, user common.Address) *GaslessOrder {
+	t.Helper()
+	order := sampleOrder(t)
+	encoded, err := EncodeOrderData(order)
+	require.NoError(t, err)
+
+	return &GaslessOrder{
+		OriginSettler: common.HexToAddress("0xf614c6bF94b022E16BEF7dBecF7614FFD2b201d3"),
+		User:          user,
+		Nonce:         big.NewInt(42),
+		OriginChainID: big.NewInt(int64(order.OriginDomain)),
+		OpenDeadline:  1800000000,
+		FillDeadline:  1850000000,
+		OrderDataType: OrderDataTypeHash(),
+		OrderData:     encoded,
+	}
+}
+
+func TestGaslessOrderResolve(t *testing.T) {
+	user := common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")
+	order := sampleGaslessOrder(t, user)
+
+	resolved, err := order.Resolve()
+	require.NoError(t, err)
+
+	// The order's user and fill deadline override the order data, so the ID differs from the raw data's hash
+	decoded, err := DecodeOrderData(resolved.FillInstructions[0].OriginData)
+	require.NoError(t, err)
+	assert.Equal(t, AddressToBytes32(user), decoded.Sender)
+	assert.Equal(t, order.FillDeadline, decoded.FillDeadline)
+	assert.NotEqual(t, crypto.Keccak256Hash(order.OrderData), common.Hash(resolved.OrderID))
+	assert.Equal(t, crypto.Keccak256Hash(resolved.FillInstructions[0].OriginData), common.Hash(resolved.OrderID))
+
+	require.Len(t, resolved.MinReceived, 1)
+	assert.Equal(t, big.NewInt(1001), resolved.MinReceived[0].Amount)
+	assert.Equal(t, uint64(11155111), resolved.MinReceived[0].ChainId.Uint64())
+	assert.Equal(t, uint64(84532), resolved.MaxSpent[0].ChainId.Uint64())
+
+	nonce, err := order.SenderNonce()
+	require.NoError(t, err)
+	assert.Equal(t, big.NewInt(42), nonce)
+
+	t.Run("rejects mismatched origin", func(t *testing.T) {
+		bad := *order
+		bad.OriginChainID = big.NewInt(1)
+		_, err := bad.Resolve()
+		assert.ErrorContains(t, err, "origin domain")
+	})
+
+	t.Run("rejects unknown order data type", func(t *testing.T) {
+		bad := *order
+		bad.OrderDataType = common.Hash{}
+		_, err := bad.Resolve()
+		assert.ErrorContains(t, err, "unsupported order data type")
+	})
+}
+
+func TestGaslessOrderSignVerify(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	user := crypto.PubkeyToAddress(key.PublicKey)
+	permit2 := common.HexToAddress(Permit2Address)
+	chainID := big.NewInt(11155111)
+
+	signed, err := sampleGaslessOrder(t, user).Sign(key, permit2, chainID)
+	require.NoError(t, err)
+	require.Len(t, signed.Signature, signatureLength)
+	assert.Contains(t, []byte{27, 28}, signed.Signature[signatureLength-1])
+	require.NoError(t, signed.Verify(permit2, chainID))
+
+	// The signature survives the JSON round trip used by the intake path
+	raw, err := json.Marshal(signed)
+	require.NoError(t, err)
+	var decoded SignedGaslessOrder
+	require.NoError(t, json.Unmarshal(raw, &decoded))
+	require.NoError(t, decoded.Verify(permit2, chainID))
+
+	t.Run("wrong chain", func(t *testing.T) {
+		assert.ErrorContains(t, signed.Verify(permit2, big.NewInt(1)), "not its user")
+	})
+
+	t.Run("tampered order", func(t *testing.T) {
+		tampered := *signed
+		tampered.Order.OpenDeadline++
+		assert.ErrorContains(t, tampered.Verify(permit2, chainID), "not its user")
+	})
+
+	t.Run("truncated signature", func(t *testing.T) {
+		truncated := *signed
+		truncated.Signature = signed.Signature[:64]
+		assert.ErrorContains(t, truncated.Verify(permit2, chainID), "65 bytes")
+	})
+}
@@ -0,0 +1,279 @@
+package orderutil
+
+// Module: Hyperlane7683 order building utilities
+// - Encodes OrderData exactly like Solidity's OrderEncoder (abi.encode of the struct)
+// - Wraps the same encoding as Cairo Bytes for Starknet origins
+// - Reads origin-contract state needed to open orders (localDomain, sender nonces)
+// Shared by the open-order tooling and the solver's own order opening (rebalancing)
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// OrderDataType must match OrderEncoder.orderDataType() in Solidity exactly, including field names and spacing
+const OrderDataType = "OrderData(bytes32 sender,bytes32 recipient,bytes32 inputToken,bytes32 outputToken,uint256 amountIn," +
+	"uint256 amountOut,uint256 senderNonce,uint32 originDomain,uint32 destinationDomain,bytes32 destinationSettler," +
+	"uint32 fillDeadline,bytes data)"
+
+const (
+	bytes32Length = 32
+	bytes16Length = 16
+	u128Bits      = 128
+	// maxNonceProbes bounds the search for an unused sender nonce
+	maxNonceProbes = 1000
+	nonceSeedRange = 1_000_000
+)
+
+// OrderData mirrors the Solidity OrderData struct used as orderData in OnchainCrossChainOrder
+type OrderData struct {
+	Sender             [32]byte
+	Recipient          [32]byte
+	InputToken         [32]byte
+	OutputToken        [32]byte
+	AmountIn           *big.Int
+	AmountOut          *big.Int
+	SenderNonce        *big.Int
+	OriginDomain       uint32
+	DestinationDomain  uint32
+	DestinationSettler [32]byte
+	FillDeadline       uint32
+	Data               []byte
+}
+
+// OrderDataTypeHash returns keccak256(OrderDataType), the orderDataType of every Hyperlane7683 order
+func OrderDataTypeHash() [32]byte {
+	return crypto.Keccak256Hash([]byte(OrderDataType))
+}
+
+// SplitU256 splits a 32-byte big-endian value into Cairo u256 (low, high) felts
+func SplitU256(value [32]byte) (low, high *felt.Felt) {
+	bi := new(big.Int).SetBytes(value[:])
+	mask := new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), u128Bits), big.NewInt(1))
+	return utils.BigIntToFelt(new(big.Int).And(bi, mask)), utils.BigIntToFelt(new(big.Int).Rsh(bi, u128Bits))
+}
+
+// orderDataArguments describes OrderData as a single ABI tuple, matching Solidity's abi.encode(order)
+func orderDataArguments() (abi.Arguments, error) {
+	tupleT, err := abi.NewType("tuple", "", []abi.ArgumentMarshaling{
+		{Name: "sender", Type: "bytes32", InternalType: "", Components: nil, Indexed: false},
+		{Name: "recipient", Type: "bytes32", InternalType: "", Components: nil, Indexed: false},
+		{Name: "inputToken", Type: "bytes32", InternalType: "", Components: nil, Indexed: false},
+		{Name: "outputToken", Type: "bytes32", InternalType: "", Components: nil, Indexed: false},
+		{Name: "amountIn", Type: "uint256", InternalType: "", Components: nil, Indexed: false},
+		{Name: "amountOut", Type: "uint256", InternalType: "", Components: nil, Indexed: false},
+		{Name: "senderNonce", Type: "uint256", InternalType: "", Components: nil, Indexed: false},
+		{Name: "originDomain", Type: "uint32", InternalType: "", Components: nil, Indexed: false},
+		{Name: "destinationDomain", Type: "uint32", InternalType: "", Components: nil, Indexed: false},
+		{Name: "destinationSettler", Type: "bytes32", InternalType: "", Components: nil, Indexed: false},
+		{Name: "fillDeadline", Type: "uint32", InternalType: "", Components: nil, Indexed: false},
+		{Name: "data", Type: "bytes", InternalType: "", Components: nil, Indexed: false},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to define OrderData tuple type: %w", err)
+	}
+	return abi.Arguments{{Type: tupleT, Name: "", Indexed: false}}, nil
+}
+
+// EncodeOrderData ABI-encodes order the way the Hyperlane7683 contracts expect orderData
+func EncodeOrderData(order *OrderData) ([]byte, error) {
+	args, err := orderDataArguments()
+	if err != nil {
+		return nil, err
+	}
+
+	packed := *order
+	if packed.Data == nil {
+		packed.Data = []byte{}
+	}
+	for _, amount := range []**big.Int{&packed.AmountIn, &packed.AmountOut, &packed.SenderNonce} {
+		if *amount == nil {
+			*amount = big.NewInt(0)
+		}
+	}
+
+	encoded, err := args.Pack(packed)
+	if err != nil {
+		return nil, fmt.Errorf("failed to ABI-pack OrderData: %w", err)
+	}
+	return encoded, nil
+}
+
+// DecodeOrderData reverses EncodeOrderData
+func DecodeOrderData(encoded []byte) (*OrderData, error) {
+	args, err := orderDataArguments()
+	if err != nil {
+		return nil, err
+	}
+
+	values, err := args.Unpack(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("failed to ABI-unpack OrderData: %w", err)
+	}
+	if len(values) != 1 {
+		return nil, fmt.Errorf("unexpected OrderData value count: %d", len(values))
+	}
+
+	order, ok := abi.ConvertType(values[0], new(OrderData)).(*OrderData)
+	if !ok {
+		return nil, fmt.Errorf("failed to convert OrderData tuple")
+	}
+	return order, nil
+}
+
+// EncodeCairoOrderData encodes order for a Starknet open(): the Solidity ABI encoding wrapped as Cairo Bytes
+// (size, words_len, big-endian u128 words)
+func EncodeCairoOrderData(order *OrderData) ([]*felt.Felt, error) {
+	raw, err := EncodeOrderData(order)
+	if err != nil {
+		return nil, err
+	}
+	return CairoBytes(raw), nil
+}
+
+// CairoBytes wraps raw bytes as a Cairo Bytes struct
+func CairoBytes(raw []byte) []*felt.Felt {
+	words := starknetutil.BytesToU128Felts(raw)
+	out := make([]*felt.Felt, 0, 2+len(words))
+	out = append(out, utils.Uint64ToFelt(uint64(len(raw))), utils.Uint64ToFelt(uint64(len(words))))
+	return append(out, words...)
+}
+
+// DecodeCairoBytes unwraps a Cairo Bytes struct (size, words_len, big-endian u128 words) back into raw bytes
+func DecodeCairoBytes(felts []*felt.Felt) ([]byte, error) {
+	if len(felts) < 2 {
+		return nil, fmt.Errorf("cairo bytes too short: %d felts", len(felts))
+	}
+	size := felts[0].Uint64()
+	wordsLen := felts[1].Uint64()
+	if uint64(len(felts)-2) < wordsLen || size > wordsLen*bytes16Length {
+		return nil, fmt.Errorf("malformed cairo bytes: size %d, %d words, %d felts", size, wordsLen, len(felts))
+	}
+
+	raw := make([]byte, 0, wordsLen*bytes16Length)
+	for _, word := range felts[2 : 2+wordsLen] {
+		var chunk [bytes16Length]byte
+		word.BigInt(new(big.Int)).FillBytes(chunk[:])
+		raw = append(raw, chunk[:]...)
+	}
+	return raw[:size], nil
+}
+
+// HexToBytes32 left-pads a hex string (EVM address, felt or bytes32) into 32 bytes
+func HexToBytes32(hexStr string) ([32]byte, error) {
+	var out [32]byte
+	s := strings.TrimPrefix(strings.TrimSpace(hexStr), "0x")
+	if len(s)%2 == 1 {
+		s = "0" + s
+	}
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		return out, fmt.Errorf("invalid hex for bytes32: %s (%w)", hexStr, err)
+	}
+	if len(b) > bytes32Length {
+		b = b[len(b)-bytes32Length:]
+	}
+	copy(out[bytes32Length-len(b):], b)
+	return out, nil
+}
+
+// AddressToBytes32 left-pads an EVM address into 32 bytes
+func AddressToBytes32(addr common.Address) [32]byte {
+	var out [32]byte
+	copy(out[bytes32Length-common.AddressLength:], addr.Bytes())
+	return out
+}
+
+// callView runs a single-function view call against contract using a minimal ABI
+func callView(ctx context.Context, client *ethclient.Client, contract common.Address, abiStr, method string,
+	out interface{}, args ...interface{}) error {
+	parsedABI, err := abi.JSON(strings.NewReader(abiStr))
+	if err != nil {
+		return err
+	}
+
+	data, err := parsedABI.Pack(method, args...)
+	if err != nil {
+		return err
+	}
+
+	msg := ethereum.CallMsg{
+		From:              common.Address{},
+		To:                &contract,
+		Gas:               0,
+		GasPrice:          nil,
+		GasFeeCap:         nil,
+		GasTipCap:         nil,
+		Value:             nil,
+		Data:              data,
+		AccessList:        nil,
+		BlobGasFeeCap:     nil,
+		BlobHashes:        nil,
+		AuthorizationList: nil,
+	}
+	result, err := client.CallContract(ctx, msg, nil)
+	if err != nil {
+		return err
+	}
+
+	return parsedABI.UnpackIntoInterface(out, method, result)
+}
+
+// LocalDomain reads localDomain() from a Hyperlane7683 contract
+func LocalDomain(ctx context.Context, client *ethclient.Client, contract common.Address) (uint32, error) {
+	abiStr := `[{"inputs":[],"name":"localDomain","outputs":[{"internalType":"uint32","name":"","type":"uint32"}],` +
+		`"stateMutability":"view","type":"function"}]`
+
+	var domain uint32
+	if err := callView(ctx, client, contract, abiStr, "localDomain", &domain); err != nil {
+		return 0, err
+	}
+	return domain, nil
+}
+
+// IsValidNonce asks the contract whether nonce is still usable for from
+func IsValidNonce(ctx context.Context, client *ethclient.Client, contract, from common.Address, nonce *big.Int) (bool, error) {
+	abiStr := `[{"inputs":[{"internalType":"address","name":"_from","type":"address"},` +
+		`{"internalType":"uint256","name":"_nonce","type":"uint256"}],"name":"isValidNonce",` +
+		`"outputs":[{"internalType":"bool","name":"","type":"bool"}],"stateMutability":"view","type":"function"}]`
+
+	var valid bool
+	if err := callView(ctx, client, contract, abiStr, "isValidNonce", &valid, from, nonce); err != nil {
+		return false, err
+	}
+	return valid, nil
+}
+
+// PickValidSenderNonce finds a nonce that the contract reports as valid for from
+func PickValidSenderNonce(ctx context.Context, client *ethclient.Client, contract, from common.Address) (*big.Int, error) {
+	// Start with a pseudo-random seed and probe upward
+	seed := time.Now().Unix() % nonceSeedRange
+	if seed < 1 {
+		seed = 1
+	}
+	nonce := big.NewInt(seed)
+	for i := 0; i < maxNonceProbes; i++ {
+		valid, err := IsValidNonce(ctx, client, contract, from, nonce)
+		if err != nil {
+			return nil, err
+		}
+		if valid {
+			return new(big.Int).Set(nonce), nil
+		}
+		nonce = new(big.Int).Add(nonce, big.NewInt(1))
+	}
+	return nil, fmt.Errorf("could not find a valid sender nonce after %d attempts starting from %d", maxNonceProbes, seed)
+}
@@ -0,0 +1,110 @@
+package orderutil
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func sampleOrder(t *testing.T) *OrderData {
+	t.Helper()
+	settler, err := HexToBytes32("0xf614c6bF94b022E16BEF7dBecF7614FFD2b201d3")
+	require.NoError(t, err)
+
+	return &OrderData{
+		Sender:             AddressToBytes32(common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")),
+		Recipient:          AddressToBytes32(common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")),
+		InputToken:         AddressToBytes32(common.HexToAddress("0x76878654a2D96dDdF8cF0CFe8FA608aB4CE0D499")),
+		OutputToken:        AddressToBytes32(common.HexToAddress("0xB844EEd1581f3fB810FFb6Dd6C5E30C049cF23F4")),
+		AmountIn:           big.NewInt(1001),
+		AmountOut:          big.NewInt(1000),
+		SenderNonce:        big.NewInt(42),
+		OriginDomain:       11155111,
+		DestinationDomain:  84532,
+		DestinationSettler: settler,
+		FillDeadline:       1900000000,
+		Data:               []byte{},
+	}
+}
+
+func TestOrderDataTypeHash(t *testing.T) {
+	// Matches OrderEncoder.orderDataTypeHash() in the deployed contracts
+	hash := OrderDataTypeHash()
+	assert.Equal(t, "0x08d75650babf4de09c9273d48ef647876057ed91d4323f8a2e3ebc2cd8a63b5e", common.Hash(hash).Hex())
+
+	low, high := SplitU256(hash)
+	recombined := new(big.Int).Lsh(high.BigInt(new(big.Int)), u128Bits)
+	recombined.Add(recombined, low.BigInt(new(big.Int)))
+	assert.Equal(t, new(big.Int).SetBytes(hash[:]), recombined)
+}
+
+func TestEncodeDecodeOrderData(t *testing.T) {
+	order := sampleOrder(t)
+
+	encoded, err := EncodeOrderData(order)
+	require.NoError(t, err)
+	// Dynamic tuple: offset word + 12 head words + data length word
+	assert.Len(t, encoded, 14*32)
+	assert.Equal(t, byte(0x20), encoded[31])
+
+	decoded, err := DecodeOrderData(encoded)
+	require.NoError(t, err)
+	assert.Equal(t, order.Sender, decoded.Sender)
+	assert.Equal(t, order.OutputToken, decoded.OutputToken)
+	assert.Equal(t, order.AmountIn, decoded.AmountIn)
+	assert.Equal(t, order.AmountOut, decoded.AmountOut)
+	assert.Equal(t, order.OriginDomain, decoded.OriginDomain)
+	assert.Equal(t, order.DestinationDomain, decoded.DestinationDomain)
+	assert.Equal(t, order.FillDeadline, decoded.FillDeadline)
+
+	t.Run("nil amounts encode as zero", func(t *testing.T) {
+		order := sampleOrder(t)
+		order.SenderNonce = nil
+		order.Data = nil
+		_, err := EncodeOrderData(order)
+		require.NoError(t, err)
+	})
+}
+
+func TestEncodeCairoOrderData(t *testing.T) {
+	order := sampleOrder(t)
+
+	encoded, err := EncodeOrderData(order)
+	require.NoError(t, err)
+	cairo, err := EncodeCairoOrderData(order)
+	require.NoError(t, err)
+
+	// size, words_len, then 16-byte words
+	require.Len(t, cairo, 2+len(encoded)/16)
+	assert.Equal(t, uint64(len(encoded)), cairo[0].Uint64())
+	assert.Equal(t, uint64(len(encoded)/16), cairo[1].Uint64())
+	assert.Equal(t, new(big.Int).SetBytes(encoded[:16]), cairo[2].BigInt(new(big.Int)))
+}
+
+func TestDecodeCairoBytes(t *testing.T) {
+	// An odd length exercises the zero-padded last word
+	raw := []byte("refund me: not a multiple of sixteen")
+	decoded, err := DecodeCairoBytes(CairoBytes(raw))
+	require.NoError(t, err)
+	assert.Equal(t, raw, decoded)
+
+	_, err = DecodeCairoBytes(CairoBytes(raw)[:3])
+	assert.Error(t, err)
+}
+
+func TestHexToBytes32(t *testing.T) {
+	out, err := HexToBytes32("0x1")
+	require.NoError(t, err)
+	assert.Equal(t, byte(1), out[31])
+
+	felt := "0x312be4cb8416dda9e192d7b4d42520e3365f71414aefad7ccd837595125f503"
+	out, err = HexToBytes32(felt)
+	require.NoError(t, err)
+	assert.Equal(t, felt, "0x"+new(big.Int).SetBytes(out[:]).Text(16))
+
+	_, err = HexToBytes32("0xzz")
+	assert.Error(t, err)
+}
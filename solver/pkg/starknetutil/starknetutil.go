@@ -8,9 +8,11 @@ package starknetutil
 import (
 	"context"
 	"fmt"
+	"math"
 	"math/big"
 
 	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 	"github.com/NethermindEth/starknet.go/rpc"
 	"github.com/NethermindEth/starknet.go/utils"
@@ -25,6 +27,26 @@ const (
 	TokenDecimals = 18
 )
 
+// Starknet has no native asset outside ERC20s: STRK is the fee token and stands in for it
+const (
+	STRKTokenAddress = "0x04718f5a0fc34cc1af16a1cdee98ffb20c31f5cd61d6ab07201858f4287c938d"
+	ETHTokenAddress  = "0x049d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7"
+)
+
+// FeeTokenAddress is the fee token contract that native outputs on Starknet are paid in:
+// STARKNET_FEE_TOKEN_ADDRESS, or STRK when unset
+func FeeTokenAddress() string {
+	return envutil.GetEnvWithDefault("STARKNET_FEE_TOKEN_ADDRESS", STRKTokenAddress)
+}
+
+// TokenContract returns the ERC20 contract behind token: the fee token for the native token, token otherwise
+func TokenContract(token string) string {
+	if types.IsNativeToken(token) {
+		return FeeTokenAddress()
+	}
+	return token
+}
+
 // Helper functions for uint256 conversion
 // ToUint256 converts *big.Int to uint256.Int
 func ToUint256(bi *big.Int) *uint256.Int {
@@ -85,6 +107,35 @@ func ERC20Balance(provider *rpc.Provider, tokenAddress, ownerAddress string) (*b
 	return balanceBigInt, nil
 }
 
+// ERC20Decimals gets the number of decimals of an ERC20 token on Starknet
+func ERC20Decimals(provider *rpc.Provider, tokenAddress string) (uint8, error) {
+	tokenAddrFelt, err := utils.HexToFelt(tokenAddress)
+	if err != nil {
+		return 0, fmt.Errorf("invalid token address: %w", err)
+	}
+
+	decimalsCall := rpc.FunctionCall{
+		ContractAddress:    tokenAddrFelt,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("decimals"),
+		Calldata:           []*felt.Felt{},
+	}
+
+	resp, err := provider.Call(context.Background(), decimalsCall, rpc.WithBlockTag("latest"))
+	if err != nil {
+		return 0, fmt.Errorf("failed to call decimals: %w", err)
+	}
+
+	if len(resp) == 0 {
+		return 0, fmt.Errorf("no response from decimals call")
+	}
+
+	decimals := utils.FeltToBigInt(resp[0])
+	if !decimals.IsUint64() || decimals.Uint64() > math.MaxUint8 {
+		return 0, fmt.Errorf("decimals %s out of range", decimals)
+	}
+	return uint8(decimals.Uint64()), nil
+}
+
 // ERC20Allowance gets the ERC20 token allowance for a given owner and spender on Starknet
 func ERC20Allowance(provider *rpc.Provider, tokenAddress, ownerAddress, spenderAddress string) (*big.Int, error) {
 	// Convert addresses to felt
@@ -246,3 +297,17 @@ func BytesToU128Felts(b []byte) []*felt.Felt {
 	}
 	return words
 }
+
+// U128FeltsToBytes converts u128 felts from Cairo back to bytes, keeping the first size bytes;
+// it is the inverse of BytesToU128Felts
+func U128FeltsToBytes(words []*felt.Felt, size uint64) []byte {
+	b := make([]byte, 0, len(words)*Bytes16Length)
+	for _, word := range words {
+		wordBytes := word.Bytes()
+		b = append(b, wordBytes[len(wordBytes)-Bytes16Length:]...)
+	}
+	if size < uint64(len(b)) {
+		b = b[:size]
+	}
+	return b
+}
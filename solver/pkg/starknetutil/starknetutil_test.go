@@ -481,3 +481,16 @@ func TestUint256Operations(t *testing.T) {
 		assert.Equal(t, 0, small.Cmp(new(uint256.Int).Set(small)))
 	})
 }
+
+func TestU128FeltsToBytes(t *testing.T) {
+	receiver := make([]byte, 32)
+	receiver[0], receiver[31] = 0x01, 0xff
+
+	words := BytesToU128Felts(receiver)
+	require.Len(t, words, 2)
+	assert.Equal(t, receiver, U128FeltsToBytes(words, 32))
+
+	partial := []byte{0xde, 0xad, 0xbe, 0xef}
+	assert.Equal(t, partial, U128FeltsToBytes(BytesToU128Felts(partial), uint64(len(partial))))
+	assert.Empty(t, U128FeltsToBytes(nil, 0))
+}
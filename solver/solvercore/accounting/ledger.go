@@ -0,0 +1,247 @@
+package accounting
+
+// Module: Profit and loss accounting
+// - An append-only ledger of the tokens the solver spends and receives for each order, and the fees it pays
+// - Spent entries are the MaxSpent outputs of a fill, received entries the MinReceived outputs once settled;
+//   each is booked once per order, so orders found already filled or settled on a re-scan are not counted twice
+// - Fee entries come from receipts (gas) and from the interchain gas payment quoted for settle and refund messages
+// - Handlers attach fees to the orders carried by their context, as they do for the order history
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orderlog"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+const (
+	// DefaultFile is where the ledger is kept when ACCOUNTING_FILE is unset
+	DefaultFile = "state/accounting/ledger.jsonl"
+	// DefaultDryRunFile is where the hypothetical ledger of a dry run is kept when ACCOUNTING_DRY_RUN_FILE is unset
+	DefaultDryRunFile = "state/accounting/dry_run_ledger.jsonl"
+
+	// maxLineBytes bounds one entry line when reading the file back
+	maxLineBytes = 64 << 10
+)
+
+// Kind is what an entry accounts for
+type Kind string
+
+const (
+	KindSpent         Kind = "spent"          // tokens sent to the recipient of a fill
+	KindReceived      Kind = "received"       // tokens released to the solver on the origin chain at settlement
+	KindGas           Kind = "gas"            // the fee of a transaction the solver sent
+	KindInterchainFee Kind = "interchain_fee" // the Hyperlane gas payment for a settle or refund message
+)
+
+// Units fees are paid in; token entries carry the token address instead
+const (
+	UnitWei = "WEI"
+	UnitFri = "FRI"
+)
+
+// Entry is one movement of tokens attributed to an order
+type Entry struct {
+	Time    time.Time `json:"time"`
+	OrderID string    `json:"orderId"`
+	Kind    Kind      `json:"kind"`
+	// Origin and Destination describe the order's route; Destination lists every destination, comma-separated
+	Origin      string `json:"origin"`
+	Destination string `json:"destination"`
+	// Stage is the operation a fee was paid for (approve, fill, settle, refund)
+	Stage string `json:"stage,omitempty"`
+	Chain string `json:"chain"`
+	// Token is the token address, or the fee unit (WEI, FRI) for fees
+	Token  string `json:"token"`
+	Amount string `json:"amount"`
+}
+
+// Config configures the ledger
+type Config struct {
+	orderlog.FileConfig
+	// DryRunFile takes the entries of simulated orders instead of File while the solver runs in dry-run mode
+	DryRunFile string
+}
+
+// ConfigFromEnv reads ACCOUNTING_FILE, ACCOUNTING_DRY_RUN_FILE and ACCOUNTING_ENABLED
+func ConfigFromEnv() Config {
+	return Config{
+		FileConfig: orderlog.FileConfigFromEnv("ACCOUNTING_FILE", "ACCOUNTING_ENABLED", DefaultFile),
+		DryRunFile: envutil.GetEnvWithDefault("ACCOUNTING_DRY_RUN_FILE", DefaultDryRunFile),
+	}
+}
+
+// Path returns the file entries go to: DryRunFile in dry-run mode, File otherwise
+func (c Config) Path(dryRun bool) string {
+	if dryRun {
+		return c.DryRunFile
+	}
+	return c.File
+}
+
+// Ledger appends entries to its file
+type Ledger struct {
+	mu   sync.Mutex
+	file *os.File
+	now  func() time.Time
+	// booked holds the orders whose spent or received outputs are in the file
+	booked map[bookedOutputs]bool
+}
+
+// bookedOutputs names the spent or received outputs of one order
+type bookedOutputs struct {
+	orderID string
+	kind    Kind
+}
+
+// Open opens the ledger at path for appending, creating it if needed
+func Open(path string) (*Ledger, error) {
+	existing, err := ReadEntries(path)
+	if err != nil {
+		return nil, err
+	}
+	booked := make(map[bookedOutputs]bool)
+	for _, entry := range existing {
+		if entry.Kind == KindSpent || entry.Kind == KindReceived {
+			booked[bookedOutputs{orderID: entry.OrderID, kind: entry.Kind}] = true
+		}
+	}
+
+	file, err := orderlog.OpenAppend(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open ledger file: %w", err)
+	}
+	return &Ledger{mu: sync.Mutex{}, file: file, now: time.Now, booked: booked}, nil
+}
+
+// Close stops appending to the file
+func (l *Ledger) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return nil
+	}
+	err := l.file.Close()
+	l.file = nil
+	return err
+}
+
+// RecordFill records the tokens a filled order sent to its recipients, unless they are already booked
+func (l *Ledger) RecordFill(args *types.ParsedArgs) {
+	l.recordOutputs(args, KindSpent, args.ResolvedOrder.MaxSpent)
+}
+
+// RecordSettlement records the tokens a settled order released to the solver on the origin chain, unless they are
+// already booked
+func (l *Ledger) RecordSettlement(args *types.ParsedArgs) {
+	l.recordOutputs(args, KindReceived, args.ResolvedOrder.MinReceived)
+}
+
+func (l *Ledger) recordOutputs(args *types.ParsedArgs, kind Kind, outputs []types.Output) {
+	key := bookedOutputs{orderID: args.OrderID, kind: kind}
+	l.mu.Lock()
+	if l.booked[key] {
+		l.mu.Unlock()
+		return
+	}
+	l.booked[key] = true
+	l.mu.Unlock()
+
+	origin, destination := route(args)
+	entries := make([]Entry, 0, len(outputs))
+	for _, output := range outputs {
+		if output.Amount == nil {
+			continue
+		}
+		entries = append(entries, Entry{
+			Time:        time.Time{},
+			OrderID:     args.OrderID,
+			Kind:        kind,
+			Origin:      origin,
+			Destination: destination,
+			Stage:       "",
+			Chain:       orderlog.ChainName(output.ChainID),
+			Token:       output.Token,
+			Amount:      output.Amount.String(),
+		})
+	}
+	l.append(entries...)
+}
+
+// append stamps and writes entries; a failed write is logged, as losing an entry must not stop a fill
+func (l *Ledger) append(entries ...Entry) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.file == nil {
+		return
+	}
+	now := l.now().UTC()
+	for _, entry := range entries {
+		entry.Time = now
+		if err := orderlog.WriteLine(l.file, entry); err != nil {
+			logutil.Default().Warn("⚠️  Failed to write ledger entry", logutil.OrderID(entry.OrderID), logutil.Err(err))
+		}
+	}
+}
+
+// ReadEntries reads every entry in the ledger at path; a missing file is an empty ledger and a torn last line is skipped
+func ReadEntries(path string) ([]Entry, error) {
+	entries, err := orderlog.ReadFile(path, maxLineBytes, func(entry Entry) bool { return entry.OrderID != "" })
+	if err != nil {
+		return nil, fmt.Errorf("failed to read ledger file: %w", err)
+	}
+	return entries, nil
+}
+
+// route names the order's origin and its destinations, comma-separated
+func route(args *types.ParsedArgs) (string, string) {
+	origin, destinations := orderlog.Route(args)
+	return origin, strings.Join(destinations, ",")
+}
+
+// WithOrders returns a context whose fees RecordFee attributes to orders in ledger
+func WithOrders(ctx context.Context, ledger *Ledger, orders ...*types.ParsedArgs) context.Context {
+	if ledger == nil || len(orders) == 0 {
+		return ctx
+	}
+	return orderlog.WithOrders(ctx, ledger, orders)
+}
+
+// RecordFee attributes a fee paid on chain to the orders carried by ctx, split evenly between them;
+// it does nothing without them
+func RecordFee(ctx context.Context, kind Kind, stage, chain, unit string, amount *big.Int) {
+	ledger, orders, ok := orderlog.OrdersFrom[*Ledger](ctx)
+	if !ok || amount == nil || amount.Sign() == 0 {
+		return
+	}
+	share, remainder := new(big.Int).QuoRem(amount, big.NewInt(int64(len(orders))), new(big.Int))
+	entries := make([]Entry, 0, len(orders))
+	for i, order := range orders {
+		paid := new(big.Int).Set(share)
+		if i == 0 {
+			// The first order carries the remainder, so the shares add up to what was paid
+			paid.Add(paid, remainder)
+		}
+		origin, destination := route(order)
+		entries = append(entries, Entry{
+			Time:        time.Time{},
+			OrderID:     order.OrderID,
+			Kind:        kind,
+			Origin:      origin,
+			Destination: destination,
+			Stage:       stage,
+			Chain:       chain,
+			Token:       unit,
+			Amount:      paid.String(),
+		})
+	}
+	ledger.append(entries...)
+}
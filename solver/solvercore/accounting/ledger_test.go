@@ -0,0 +1,171 @@
+package accounting
+
+import (
+	"context"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// Chain IDs of the ledger's test route; names come from the configured networks, as the ledger's do
+const (
+	ethereumID = 1
+	baseID     = 10
+)
+
+var ledgerClock = time.Date(2026, 5, 1, 12, 0, 0, 0, time.UTC)
+
+func output(chainID int64, token string, amount int64) types.Output {
+	return types.Output{Token: token, Amount: big.NewInt(amount), Recipient: "0x00", ChainID: big.NewInt(chainID)}
+}
+
+// ethereumToBase is an order spending 495 of token 0xcc on Base for 500 of token 0xdd on Ethereum
+func ethereumToBase(orderID string) *types.ParsedArgs {
+	return &types.ParsedArgs{
+		OrderID: orderID,
+		ResolvedOrder: types.ResolvedCrossChainOrder{
+			OriginChainID:    big.NewInt(ethereumID),
+			MaxSpent:         []types.Output{output(baseID, "0xcc", 495)},
+			MinReceived:      []types.Output{output(ethereumID, "0xdd", 500)},
+			FillInstructions: []types.FillInstruction{{DestinationChainID: big.NewInt(baseID)}},
+		},
+	}
+}
+
+// openLedger opens the ledger at path with its clock stopped at ledgerClock
+func openLedger(t *testing.T, path string) *Ledger {
+	t.Helper()
+	ledger, err := Open(path)
+	require.NoError(t, err)
+	ledger.now = func() time.Time { return ledgerClock }
+	t.Cleanup(func() { _ = ledger.Close() })
+	return ledger
+}
+
+func TestLedgerRecordsOrderFlows(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "accounting", "ledger.jsonl")
+	ledger := openLedger(t, path)
+	args := ethereumToBase("0x01")
+
+	ctx := WithOrders(context.Background(), ledger, args)
+	ledger.RecordFill(args)
+	RecordFee(ctx, KindGas, "fill", "Base", UnitWei, big.NewInt(21_000))
+	RecordFee(ctx, KindGas, "fill", "Base", UnitWei, big.NewInt(0)) // nothing paid, nothing recorded
+	ledger.RecordSettlement(args)
+	require.NoError(t, ledger.Close())
+
+	entries, err := ReadEntries(path)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+
+	spent, fee, received := entries[0], entries[1], entries[2]
+	assert.Equal(t, KindSpent, spent.Kind)
+	assert.Equal(t, "495", spent.Amount)
+	assert.Equal(t, "0xcc", spent.Token)
+	assert.Equal(t, logutil.NetworkNameByChainID(baseID), spent.Chain)
+	assert.Equal(t, logutil.NetworkNameByChainID(ethereumID), spent.Origin)
+	assert.Equal(t, logutil.NetworkNameByChainID(baseID), spent.Destination)
+	assert.Equal(t, ledgerClock, spent.Time)
+
+	assert.Equal(t, KindGas, fee.Kind)
+	assert.Equal(t, "fill", fee.Stage)
+	assert.Equal(t, UnitWei, fee.Token)
+	assert.Equal(t, "21000", fee.Amount)
+
+	assert.Equal(t, KindReceived, received.Kind)
+	assert.Equal(t, "500", received.Amount)
+	assert.Equal(t, logutil.NetworkNameByChainID(ethereumID), received.Chain)
+}
+
+func TestLedgerBooksOrderOutputsOnce(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	args := ethereumToBase("0x01")
+
+	ledger := openLedger(t, path)
+	ledger.RecordFill(args)
+	ledger.RecordFill(args)
+	require.NoError(t, ledger.Close())
+
+	// An order found already filled after a restart is settled, but its spent outputs are not booked again
+	reopened := openLedger(t, path)
+	reopened.RecordFill(args)
+	reopened.RecordSettlement(args)
+	reopened.RecordSettlement(args)
+
+	entries, err := ReadEntries(path)
+	require.NoError(t, err)
+	require.Len(t, entries, 2)
+	assert.Equal(t, KindSpent, entries[0].Kind)
+	assert.Equal(t, KindReceived, entries[1].Kind)
+}
+
+func TestRecordFeeSplitsBetweenOrders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	ledger := openLedger(t, path)
+
+	ctx := WithOrders(context.Background(), ledger, ethereumToBase("0x01"), ethereumToBase("0x02"), ethereumToBase("0x03"))
+	RecordFee(ctx, KindInterchainFee, "settle", "Base", UnitWei, big.NewInt(100))
+
+	entries, err := ReadEntries(path)
+	require.NoError(t, err)
+	require.Len(t, entries, 3)
+	assert.Equal(t, "34", entries[0].Amount, "the first order carries the remainder")
+	assert.Equal(t, "33", entries[1].Amount)
+	assert.Equal(t, "33", entries[2].Amount)
+}
+
+func TestRecordFeeWithoutOrders(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	ledger := openLedger(t, path)
+
+	RecordFee(context.Background(), KindGas, "fill", "Base", UnitWei, big.NewInt(1))
+	RecordFee(WithOrders(context.Background(), nil, ethereumToBase("0x01")), KindGas, "fill", "Base", UnitWei, big.NewInt(1))
+	RecordFee(WithOrders(context.Background(), ledger), KindGas, "fill", "Base", UnitWei, big.NewInt(1))
+
+	entries, err := ReadEntries(path)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestReadEntriesSkipsTornLine(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	ledger := openLedger(t, path)
+	ledger.RecordFill(ethereumToBase("0x01"))
+	require.NoError(t, ledger.Close())
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"orderId":"0x02","kind":"sp`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	entries, err := ReadEntries(path)
+	require.NoError(t, err)
+	assert.Len(t, entries, 1)
+
+	missing, err := ReadEntries(filepath.Join(t.TempDir(), "missing.jsonl"))
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("ACCOUNTING_FILE", "")
+	t.Setenv("ACCOUNTING_DRY_RUN_FILE", "")
+	t.Setenv("ACCOUNTING_ENABLED", "")
+	cfg := ConfigFromEnv()
+	assert.True(t, cfg.Enabled())
+	assert.Equal(t, DefaultFile, cfg.File)
+	assert.Equal(t, DefaultFile, cfg.Path(false))
+	assert.Equal(t, DefaultDryRunFile, cfg.Path(true))
+
+	t.Setenv("ACCOUNTING_ENABLED", "false")
+	assert.False(t, ConfigFromEnv().Enabled())
+}
@@ -0,0 +1,178 @@
+package accounting
+
+import (
+	"cmp"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math/big"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dayLayout formats the day a row covers
+const dayLayout = time.DateOnly
+
+// Options selects and groups the entries of a report
+type Options struct {
+	// ByDay and ByRoute split rows per UTC day and per origin/destination route; with neither, one row sums everything
+	ByDay   bool
+	ByRoute bool
+	// Since and Until bound the entry times included
+	Since time.Time
+	Until time.Time
+}
+
+// Row is the profit and loss of one group of entries
+type Row struct {
+	Day         string `json:"day,omitempty"`
+	Origin      string `json:"origin,omitempty"`
+	Destination string `json:"destination,omitempty"`
+	// Orders counts the distinct orders with an entry in the group
+	Orders   int    `json:"orders"`
+	Spent    string `json:"spent"`
+	Received string `json:"received"`
+	// TokenPnL is Received minus Spent, assuming like-for-like tokens as the profitability rule does
+	TokenPnL string `json:"tokenPnl"`
+	// GasFees and InterchainFees are keyed by fee unit (WEI, FRI)
+	GasFees        map[string]string `json:"gasFees"`
+	InterchainFees map[string]string `json:"interchainFees"`
+}
+
+// groupKey identifies the row an entry is summed into
+type groupKey struct {
+	day, origin, destination string
+}
+
+// totals accumulates the entries of one row
+type totals struct {
+	key            groupKey
+	orders         map[string]bool
+	spent          *big.Int
+	received       *big.Int
+	gasFees        map[string]*big.Int
+	interchainFees map[string]*big.Int
+}
+
+// BuildReport sums entries into rows, sorted by day and then route
+func BuildReport(entries []Entry, opts Options) []Row {
+	groups := make(map[groupKey]*totals)
+	for _, entry := range entries {
+		if (!opts.Since.IsZero() && entry.Time.Before(opts.Since)) || (!opts.Until.IsZero() && !entry.Time.Before(opts.Until)) {
+			continue
+		}
+		amount, ok := new(big.Int).SetString(entry.Amount, 10)
+		if !ok {
+			continue
+		}
+
+		var key groupKey
+		if opts.ByDay {
+			key.day = entry.Time.UTC().Format(dayLayout)
+		}
+		if opts.ByRoute {
+			key.origin, key.destination = entry.Origin, entry.Destination
+		}
+		group, ok := groups[key]
+		if !ok {
+			group = &totals{
+				key:            key,
+				orders:         make(map[string]bool),
+				spent:          new(big.Int),
+				received:       new(big.Int),
+				gasFees:        make(map[string]*big.Int),
+				interchainFees: make(map[string]*big.Int),
+			}
+			groups[key] = group
+		}
+
+		group.orders[entry.OrderID] = true
+		switch entry.Kind {
+		case KindSpent:
+			group.spent.Add(group.spent, amount)
+		case KindReceived:
+			group.received.Add(group.received, amount)
+		case KindGas:
+			addFee(group.gasFees, entry.Token, amount)
+		case KindInterchainFee:
+			addFee(group.interchainFees, entry.Token, amount)
+		}
+	}
+
+	rows := make([]Row, 0, len(groups))
+	for _, group := range groups {
+		rows = append(rows, Row{
+			Day:            group.key.day,
+			Origin:         group.key.origin,
+			Destination:    group.key.destination,
+			Orders:         len(group.orders),
+			Spent:          group.spent.String(),
+			Received:       group.received.String(),
+			TokenPnL:       new(big.Int).Sub(group.received, group.spent).String(),
+			GasFees:        feeStrings(group.gasFees),
+			InterchainFees: feeStrings(group.interchainFees),
+		})
+	}
+	slices.SortFunc(rows, func(a, b Row) int {
+		return cmp.Or(strings.Compare(a.Day, b.Day), strings.Compare(a.Origin, b.Origin),
+			strings.Compare(a.Destination, b.Destination))
+	})
+	return rows
+}
+
+func addFee(fees map[string]*big.Int, unit string, amount *big.Int) {
+	if fees[unit] == nil {
+		fees[unit] = new(big.Int)
+	}
+	fees[unit].Add(fees[unit], amount)
+}
+
+func feeStrings(fees map[string]*big.Int) map[string]string {
+	strs := make(map[string]string, len(fees))
+	for unit, amount := range fees {
+		strs[unit] = amount.String()
+	}
+	return strs
+}
+
+// WriteJSON writes rows as an indented JSON array
+func WriteJSON(w io.Writer, rows []Row) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	if err := encoder.Encode(rows); err != nil {
+		return fmt.Errorf("failed to write JSON report: %w", err)
+	}
+	return nil
+}
+
+// WriteCSV writes rows with one fee column per kind and unit
+func WriteCSV(w io.Writer, rows []Row) error {
+	writer := csv.NewWriter(w)
+	header := []string{"day", "origin", "destination", "orders", "spent", "received", "token_pnl",
+		"gas_fees_wei", "gas_fees_fri", "interchain_fees_wei", "interchain_fees_fri"}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("failed to write CSV report: %w", err)
+	}
+	for _, row := range rows {
+		record := []string{row.Day, row.Origin, row.Destination, strconv.Itoa(row.Orders), row.Spent, row.Received, row.TokenPnL,
+			fee(row.GasFees, UnitWei), fee(row.GasFees, UnitFri), fee(row.InterchainFees, UnitWei), fee(row.InterchainFees, UnitFri)}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("failed to write CSV report: %w", err)
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("failed to write CSV report: %w", err)
+	}
+	return nil
+}
+
+func fee(fees map[string]string, unit string) string {
+	if amount, ok := fees[unit]; ok {
+		return amount
+	}
+	return "0"
+}
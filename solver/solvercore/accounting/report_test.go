@@ -0,0 +1,99 @@
+package accounting
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func entry(day int, orderID string, kind Kind, destination, unit, amount string) Entry {
+	return Entry{
+		Time:        time.Date(2026, 5, day, 12, 0, 0, 0, time.UTC),
+		OrderID:     orderID,
+		Kind:        kind,
+		Origin:      "Ethereum",
+		Destination: destination,
+		Stage:       "",
+		Chain:       destination,
+		Token:       unit,
+		Amount:      amount,
+	}
+}
+
+func ledgerEntries() []Entry {
+	return []Entry{
+		entry(1, "0x01", KindSpent, "Base", "0xaa", "990"),
+		entry(1, "0x01", KindGas, "Base", UnitWei, "50"),
+		entry(1, "0x01", KindInterchainFee, "Base", UnitWei, "7"),
+		entry(2, "0x01", KindReceived, "Base", "0xbb", "1000"),
+		entry(2, "0x02", KindSpent, "Starknet", "0xcc", "495"),
+		entry(2, "0x02", KindGas, "Starknet", UnitFri, "30"),
+		entry(2, "0x02", KindReceived, "Starknet", "0xdd", "500"),
+		entry(3, "0x03", KindGas, "Base", UnitWei, "40"), // a fill that reverted still paid gas
+	}
+}
+
+func TestBuildReportByDayAndRoute(t *testing.T) {
+	rows := BuildReport(ledgerEntries(), Options{ByDay: true, ByRoute: true})
+	require.Len(t, rows, 4)
+
+	assert.Equal(t, Row{Day: "2026-05-01", Origin: "Ethereum", Destination: "Base", Orders: 1, Spent: "990", Received: "0",
+		TokenPnL: "-990", GasFees: map[string]string{UnitWei: "50"}, InterchainFees: map[string]string{UnitWei: "7"}}, rows[0])
+	assert.Equal(t, "2026-05-02", rows[1].Day)
+	assert.Equal(t, "Base", rows[1].Destination)
+	assert.Equal(t, "1000", rows[1].TokenPnL)
+	assert.Equal(t, "Starknet", rows[2].Destination)
+	assert.Equal(t, map[string]string{UnitFri: "30"}, rows[2].GasFees)
+	assert.Equal(t, "2026-05-03", rows[3].Day)
+	assert.Equal(t, "0", rows[3].TokenPnL)
+}
+
+func TestBuildReportByRoute(t *testing.T) {
+	rows := BuildReport(ledgerEntries(), Options{ByRoute: true})
+	require.Len(t, rows, 2)
+
+	base := rows[0]
+	assert.Empty(t, base.Day)
+	assert.Equal(t, "Base", base.Destination)
+	assert.Equal(t, 2, base.Orders)
+	assert.Equal(t, "10", base.TokenPnL)
+	assert.Equal(t, map[string]string{UnitWei: "90"}, base.GasFees)
+
+	starknet := rows[1]
+	assert.Equal(t, 1, starknet.Orders)
+	assert.Equal(t, "5", starknet.TokenPnL)
+}
+
+func TestBuildReportTotalWithinWindow(t *testing.T) {
+	rows := BuildReport(ledgerEntries(), Options{
+		Since: time.Date(2026, 5, 2, 0, 0, 0, 0, time.UTC),
+		Until: time.Date(2026, 5, 3, 0, 0, 0, 0, time.UTC),
+	})
+	require.Len(t, rows, 1)
+	assert.Equal(t, 2, rows[0].Orders)
+	assert.Equal(t, "495", rows[0].Spent)
+	assert.Equal(t, "1500", rows[0].Received)
+}
+
+func TestWriteReport(t *testing.T) {
+	rows := BuildReport(ledgerEntries(), Options{ByRoute: true})
+
+	var csvOut bytes.Buffer
+	require.NoError(t, WriteCSV(&csvOut, rows))
+	lines := strings.Split(strings.TrimSpace(csvOut.String()), "\n")
+	require.Len(t, lines, 3)
+	assert.Equal(t, "day,origin,destination,orders,spent,received,token_pnl,gas_fees_wei,gas_fees_fri,interchain_fees_wei,interchain_fees_fri", lines[0])
+	assert.Equal(t, ",Ethereum,Base,2,990,1000,10,90,0,7,0", lines[1])
+	assert.Equal(t, ",Ethereum,Starknet,1,495,500,5,0,30,0,0", lines[2])
+
+	var jsonOut bytes.Buffer
+	require.NoError(t, WriteJSON(&jsonOut, rows))
+	var decoded []Row
+	require.NoError(t, json.Unmarshal(jsonOut.Bytes(), &decoded))
+	assert.Equal(t, rows, decoded)
+}
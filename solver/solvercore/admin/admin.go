@@ -0,0 +1,265 @@
+package admin
+
+// Module: Operator admin HTTP API
+// - Lets on-call engineers steer a running solver: enable/disable solvers, replace allow/block lists,
+//   pause/resume network listeners, re-scan from a block and list in-flight orders
+// - Every request needs "Authorization: Bearer <ADMIN_TOKEN>"
+// - Served on its own address (ADMIN_LISTEN_ADDR), separate from the public API
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+const (
+	maxRequestBodyBytes = 64 << 10
+	readHeaderTimeout   = 10 * time.Second
+	requestTimeout      = 30 * time.Second
+	shutdownTimeout     = 5 * time.Second
+)
+
+// ErrNotFound is wrapped by Controller errors for unknown solvers and networks
+var ErrNotFound = errors.New("not found")
+
+// Config configures the admin server
+type Config struct {
+	// ListenAddr is the host:port to listen on; empty disables the admin API
+	ListenAddr string
+	// Token is the bearer token every request must present
+	Token string
+}
+
+// ConfigFromEnv reads ADMIN_LISTEN_ADDR and ADMIN_TOKEN
+func ConfigFromEnv() Config {
+	return Config{
+		ListenAddr: envutil.GetEnvWithDefault("ADMIN_LISTEN_ADDR", ""),
+		Token:      envutil.GetEnvWithDefault("ADMIN_TOKEN", ""),
+	}
+}
+
+// Enabled reports whether the admin API should be served
+func (c Config) Enabled() bool {
+	return c.ListenAddr != ""
+}
+
+// Controller is the runtime control surface of the solver; the SolverManager implements it
+type Controller interface {
+	GetSolverStatus() map[string]bool
+	EnableSolver(name string) error
+	DisableSolver(name string) error
+	GetAllowBlockLists() types.AllowBlockLists
+	SetAllowBlockLists(allowBlockLists types.AllowBlockLists)
+	ListenerStatus() []base.ListenerStatus
+	PauseListener(network string) error
+	ResumeListener(network string) error
+	RescanListener(network string, fromBlock uint64) error
+	InFlightOrders() []hyperlane7683.InFlightOrder
+}
+
+// RescanRequest is the body of a re-scan request
+type RescanRequest struct {
+	FromBlock uint64 `json:"fromBlock"`
+}
+
+// ErrorResponse is returned for failed requests
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// Server serves the admin API
+type Server struct {
+	cfg        Config
+	controller Controller
+}
+
+// NewServer creates an admin server acting on controller
+func NewServer(cfg Config, controller Controller) *Server {
+	return &Server{cfg: cfg, controller: controller}
+}
+
+// Handler returns the admin routes, all behind bearer token authentication
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /admin/v1/solvers", s.handleSolverStatus)
+	mux.HandleFunc("POST /admin/v1/solvers/{name}/enable", s.handleSetSolverEnabled(true))
+	mux.HandleFunc("POST /admin/v1/solvers/{name}/disable", s.handleSetSolverEnabled(false))
+	mux.HandleFunc("GET /admin/v1/allow-block-lists", s.handleGetAllowBlockLists)
+	mux.HandleFunc("PUT /admin/v1/allow-block-lists", s.handleSetAllowBlockLists)
+	mux.HandleFunc("GET /admin/v1/listeners", s.handleListenerStatus)
+	mux.HandleFunc("POST /admin/v1/listeners/{network}/pause", s.handlePauseListener)
+	mux.HandleFunc("POST /admin/v1/listeners/{network}/resume", s.handleResumeListener)
+	mux.HandleFunc("POST /admin/v1/listeners/{network}/rescan", s.handleRescanListener)
+	mux.HandleFunc("GET /admin/v1/orders/in-flight", s.handleInFlightOrders)
+	return s.authenticate(mux)
+}
+
+// Start listens on the configured address and serves until ctx is done
+func (s *Server) Start(ctx context.Context) error {
+	if s.cfg.Token == "" {
+		return fmt.Errorf("ADMIN_TOKEN must be set to serve the admin API")
+	}
+	listener, err := net.Listen("tcp", s.cfg.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.cfg.ListenAddr, err)
+	}
+	server := &http.Server{
+		Addr:                         s.cfg.ListenAddr,
+		Handler:                      http.TimeoutHandler(s.Handler(), requestTimeout, `{"error":"request timed out"}`),
+		DisableGeneralOptionsHandler: false,
+		TLSConfig:                    nil,
+		ReadTimeout:                  0,
+		ReadHeaderTimeout:            readHeaderTimeout,
+		WriteTimeout:                 0,
+		IdleTimeout:                  0,
+		MaxHeaderBytes:               0,
+		TLSNextProto:                 nil,
+		ConnState:                    nil,
+		ErrorLog:                     nil,
+		BaseContext:                  func(net.Listener) context.Context { return ctx },
+		ConnContext:                  nil,
+		HTTP2:                        nil,
+		Protocols:                    nil,
+	}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logutil.Default().Error("❌ Admin server stopped", logutil.Err(err))
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	logutil.Default().Info(fmt.Sprintf("🛠️  Admin API listening on %s", listener.Addr()))
+	return nil
+}
+
+// authenticate rejects requests without the configured bearer token
+func (s *Server) authenticate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || s.cfg.Token == "" || subtle.ConstantTimeCompare([]byte(token), []byte(s.cfg.Token)) != 1 {
+			writeJSON(w, http.StatusUnauthorized, ErrorResponse{Error: "missing or invalid admin token"})
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (s *Server) handleSolverStatus(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.controller.GetSolverStatus())
+}
+
+func (s *Server) handleSetSolverEnabled(enabled bool) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+		action := s.controller.DisableSolver
+		if enabled {
+			action = s.controller.EnableSolver
+		}
+		if err := action(name); err != nil {
+			writeError(w, err)
+			return
+		}
+		logutil.Default().Info(fmt.Sprintf("🛠️  Admin: solver %s enabled=%t", name, enabled))
+		writeJSON(w, http.StatusOK, s.controller.GetSolverStatus())
+	}
+}
+
+func (s *Server) handleGetAllowBlockLists(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.controller.GetAllowBlockLists())
+}
+
+func (s *Server) handleSetAllowBlockLists(w http.ResponseWriter, r *http.Request) {
+	var lists types.AllowBlockLists
+	if err := decode(w, r, &lists); err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("invalid allow/block lists JSON: %v", err)})
+		return
+	}
+	if lists.AllowList == nil {
+		lists.AllowList = []types.AllowBlockListItem{}
+	}
+	if lists.BlockList == nil {
+		lists.BlockList = []types.AllowBlockListItem{}
+	}
+	s.controller.SetAllowBlockLists(lists)
+	logutil.Default().Info(fmt.Sprintf("🛠️  Admin: allow/block lists replaced (%d allowed, %d blocked)", len(lists.AllowList), len(lists.BlockList)))
+	writeJSON(w, http.StatusOK, s.controller.GetAllowBlockLists())
+}
+
+func (s *Server) handleListenerStatus(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.controller.ListenerStatus())
+}
+
+func (s *Server) handlePauseListener(w http.ResponseWriter, r *http.Request) {
+	s.listenerAction(w, r.PathValue("network"), "paused", s.controller.PauseListener)
+}
+
+func (s *Server) handleResumeListener(w http.ResponseWriter, r *http.Request) {
+	s.listenerAction(w, r.PathValue("network"), "resumed", s.controller.ResumeListener)
+}
+
+func (s *Server) handleRescanListener(w http.ResponseWriter, r *http.Request) {
+	var req RescanRequest
+	if err := decode(w, r, &req); err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: fmt.Sprintf("invalid re-scan JSON: %v", err)})
+		return
+	}
+	s.listenerAction(w, r.PathValue("network"), fmt.Sprintf("re-scanning from block %d", req.FromBlock),
+		func(network string) error {
+			return s.controller.RescanListener(network, req.FromBlock)
+		})
+}
+
+// listenerAction applies action to the network's listener and answers with every listener's status
+func (s *Server) listenerAction(w http.ResponseWriter, network, done string, action func(network string) error) {
+	if err := action(network); err != nil {
+		writeError(w, err)
+		return
+	}
+	logutil.Default().Info(fmt.Sprintf("🛠️  Admin: %s listener %s", network, done))
+	writeJSON(w, http.StatusOK, s.controller.ListenerStatus())
+}
+
+func (s *Server) handleInFlightOrders(w http.ResponseWriter, _ *http.Request) {
+	writeJSON(w, http.StatusOK, s.controller.InFlightOrders())
+}
+
+func decode(w http.ResponseWriter, r *http.Request, into any) error {
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes))
+	decoder.DisallowUnknownFields()
+	return decoder.Decode(into)
+}
+
+// writeError answers 404 for unknown solvers and networks and 400 for any other refused command
+func writeError(w http.ResponseWriter, err error) {
+	status := http.StatusBadRequest
+	if errors.Is(err, ErrNotFound) {
+		status = http.StatusNotFound
+	}
+	writeJSON(w, status, ErrorResponse{Error: err.Error()})
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logutil.Default().Warn("⚠️  Failed to write admin response", logutil.Err(err))
+	}
+}
@@ -0,0 +1,220 @@
+package admin
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+const testToken = "s3cret"
+
+// fakeController keeps solver and listener state in memory
+type fakeController struct {
+	solvers   map[string]bool
+	lists     types.AllowBlockLists
+	listeners map[string]*base.ListenerStatus
+	inFlight  []hyperlane7683.InFlightOrder
+}
+
+func newFakeController() *fakeController {
+	return &fakeController{
+		solvers: map[string]bool{"hyperlane7683": true},
+		listeners: map[string]*base.ListenerStatus{
+			"Base": {Network: "Base", Solver: "hyperlane7683", LastProcessedBlock: 100},
+		},
+		inFlight: []hyperlane7683.InFlightOrder{{OrderID: "0x01", OriginChainID: 84532}},
+	}
+}
+
+func (f *fakeController) GetSolverStatus() map[string]bool { return f.solvers }
+
+func (f *fakeController) EnableSolver(name string) error { return f.setSolver(name, true) }
+
+func (f *fakeController) DisableSolver(name string) error { return f.setSolver(name, false) }
+
+func (f *fakeController) setSolver(name string, enabled bool) error {
+	if _, ok := f.solvers[name]; !ok {
+		return fmt.Errorf("solver %s %w", name, ErrNotFound)
+	}
+	f.solvers[name] = enabled
+	return nil
+}
+
+func (f *fakeController) GetAllowBlockLists() types.AllowBlockLists { return f.lists }
+
+func (f *fakeController) SetAllowBlockLists(lists types.AllowBlockLists) { f.lists = lists }
+
+func (f *fakeController) ListenerStatus() []base.ListenerStatus {
+	statuses := make([]base.ListenerStatus, 0, len(f.listeners))
+	for _, status := range f.listeners {
+		statuses = append(statuses, *status)
+	}
+	return statuses
+}
+
+func (f *fakeController) PauseListener(network string) error {
+	return f.withListener(network, func(s *base.ListenerStatus) error { s.Paused = true; return nil })
+}
+
+func (f *fakeController) ResumeListener(network string) error {
+	return f.withListener(network, func(s *base.ListenerStatus) error { s.Paused = false; return nil })
+}
+
+func (f *fakeController) RescanListener(network string, fromBlock uint64) error {
+	return f.withListener(network, func(s *base.ListenerStatus) error {
+		if fromBlock > s.LastProcessedBlock+1 {
+			return base.ErrRescanAhead
+		}
+		s.LastProcessedBlock = fromBlock - 1
+		return nil
+	})
+}
+
+func (f *fakeController) withListener(network string, apply func(*base.ListenerStatus) error) error {
+	status, ok := f.listeners[network]
+	if !ok {
+		return fmt.Errorf("listener for network %s %w", network, ErrNotFound)
+	}
+	return apply(status)
+}
+
+func (f *fakeController) InFlightOrders() []hyperlane7683.InFlightOrder { return f.inFlight }
+
+func do(t *testing.T, controller Controller, method, path, body string) *httptest.ResponseRecorder {
+	t.Helper()
+	server := NewServer(Config{ListenAddr: "", Token: testToken}, controller)
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	req.Header.Set("Authorization", "Bearer "+testToken)
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func TestAuthentication(t *testing.T) {
+	server := NewServer(Config{ListenAddr: "", Token: testToken}, newFakeController())
+
+	for name, header := range map[string]string{
+		"missing": "",
+		"wrong":   "Bearer nope",
+		"scheme":  "Basic " + testToken,
+	} {
+		req := httptest.NewRequest(http.MethodGet, "/admin/v1/solvers", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rec := httptest.NewRecorder()
+		server.Handler().ServeHTTP(rec, req)
+		assert.Equal(t, http.StatusUnauthorized, rec.Code, name)
+	}
+
+	// An unset token never authenticates anyone
+	open := NewServer(Config{ListenAddr: "", Token: ""}, newFakeController())
+	req := httptest.NewRequest(http.MethodGet, "/admin/v1/solvers", nil)
+	req.Header.Set("Authorization", "Bearer ")
+	rec := httptest.NewRecorder()
+	open.Handler().ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+}
+
+func TestSolvers(t *testing.T) {
+	controller := newFakeController()
+
+	rec := do(t, controller, http.MethodPost, "/admin/v1/solvers/hyperlane7683/disable", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.False(t, controller.solvers["hyperlane7683"])
+
+	rec = do(t, controller, http.MethodPost, "/admin/v1/solvers/hyperlane7683/enable", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var status map[string]bool
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &status))
+	assert.True(t, status["hyperlane7683"])
+
+	rec = do(t, controller, http.MethodPost, "/admin/v1/solvers/unknown/enable", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+
+	rec = do(t, controller, http.MethodGet, "/admin/v1/solvers/hyperlane7683/enable", "")
+	assert.Equal(t, http.StatusMethodNotAllowed, rec.Code)
+}
+
+func TestAllowBlockLists(t *testing.T) {
+	controller := newFakeController()
+
+	body := `{"allowList": [], "blockList": [{"senderAddress": "0xbad", "destinationDomain": "*", "recipientAddress": "*"}]}`
+	rec := do(t, controller, http.MethodPut, "/admin/v1/allow-block-lists", body)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, controller.lists.BlockList, 1)
+	assert.Equal(t, "0xbad", controller.lists.BlockList[0].SenderAddress)
+	assert.NotNil(t, controller.lists.AllowList)
+
+	rec = do(t, controller, http.MethodGet, "/admin/v1/allow-block-lists", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), "0xbad")
+
+	rec = do(t, controller, http.MethodPut, "/admin/v1/allow-block-lists", `{"denyList": []}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+}
+
+func TestListeners(t *testing.T) {
+	controller := newFakeController()
+
+	rec := do(t, controller, http.MethodPost, "/admin/v1/listeners/Base/pause", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	var statuses []base.ListenerStatus
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &statuses))
+	require.Len(t, statuses, 1)
+	assert.True(t, statuses[0].Paused)
+
+	rec = do(t, controller, http.MethodPost, "/admin/v1/listeners/Base/resume", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.False(t, controller.listeners["Base"].Paused)
+
+	rec = do(t, controller, http.MethodPost, "/admin/v1/listeners/Base/rescan", `{"fromBlock": 50}`)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, uint64(49), controller.listeners["Base"].LastProcessedBlock)
+
+	rec = do(t, controller, http.MethodPost, "/admin/v1/listeners/Base/rescan", `{"fromBlock": 500}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+	assert.Contains(t, rec.Body.String(), "ahead")
+
+	rec = do(t, controller, http.MethodPost, "/admin/v1/listeners/Base/rescan", `{"block": 1}`)
+	assert.Equal(t, http.StatusBadRequest, rec.Code)
+
+	rec = do(t, controller, http.MethodPost, "/admin/v1/listeners/Mars/pause", "")
+	assert.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestInFlightOrders(t *testing.T) {
+	rec := do(t, newFakeController(), http.MethodGet, "/admin/v1/orders/in-flight", "")
+	assert.Equal(t, http.StatusOK, rec.Code)
+
+	var orders []hyperlane7683.InFlightOrder
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &orders))
+	require.Len(t, orders, 1)
+	assert.Equal(t, "0x01", orders[0].OrderID)
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("ADMIN_LISTEN_ADDR", "")
+	assert.False(t, ConfigFromEnv().Enabled())
+
+	t.Setenv("ADMIN_LISTEN_ADDR", "127.0.0.1:9090")
+	t.Setenv("ADMIN_TOKEN", testToken)
+	cfg := ConfigFromEnv()
+	assert.True(t, cfg.Enabled())
+	assert.Equal(t, testToken, cfg.Token)
+
+	// Serving without a token is refused
+	err := NewServer(Config{ListenAddr: "127.0.0.1:0", Token: ""}, newFakeController()).Start(t.Context())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "ADMIN_TOKEN")
+}
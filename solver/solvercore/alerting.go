@@ -0,0 +1,83 @@
+package solvercore
+
+// Module: Alerting probe of a running solver
+// - Reads token balances through the shared inventory, which keeps them refreshed
+// - Reads orders filled but not settled, and orders that recently failed, from the order history
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/alerting"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/history"
+)
+
+// TokenBalance returns the solver's balance of token on network, as last read by the inventory
+func (sm *SolverManager) TokenBalance(ctx context.Context, network, token string) (*big.Int, error) {
+	networkConfig, ok := config.Networks[network]
+	if !ok {
+		return nil, fmt.Errorf("network %s not configured", network)
+	}
+	return sm.inventory.Balance(ctx, networkConfig.ChainID, token)
+}
+
+// FilledOrders returns the orders filled but not yet settled, or nil when the order history is disabled
+func (sm *SolverManager) FilledOrders() []history.Record {
+	if sm.history == nil {
+		return nil
+	}
+	return sm.history.List(history.Filter{Chain: "", Status: history.StatusFilled, Since: time.Time{}, Until: time.Time{}, Limit: 0})
+}
+
+// FailedOrders returns the orders that failed or were rejected since the given time, or nil when the order history is disabled
+func (sm *SolverManager) FailedOrders(since time.Time) []history.Record {
+	if sm.history == nil {
+		return nil
+	}
+	var failed []history.Record
+	for _, status := range []history.Status{history.StatusFailed, history.StatusRejected} {
+		for _, record := range sm.history.List(history.Filter{Chain: "", Status: status, Since: time.Time{}, Until: time.Time{}, Limit: 0}) {
+			if !record.UpdatedAt.Before(since) {
+				failed = append(failed, record)
+			}
+		}
+	}
+	return failed
+}
+
+// initializeAlerting starts the alert monitor when ALERTING_CONFIG_FILE points to an enabled config
+func (sm *SolverManager) initializeAlerting(ctx context.Context) error {
+	path := envutil.GetEnvWithDefault("ALERTING_CONFIG_FILE", "")
+	if path == "" {
+		return nil
+	}
+
+	cfg, err := alerting.LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	if !cfg.Enabled {
+		sm.logger.Info(fmt.Sprintf("   ⏭️  Alerting disabled in %s", path))
+		return nil
+	}
+	if (cfg.StuckOrders != nil || cfg.FailedOrders != nil) && sm.history == nil {
+		sm.logger.Warn("⚠️  Stuck and failed order alerts need the order history (ORDER_HISTORY_ENABLED); they will not fire")
+	}
+
+	notifiers := make([]alerting.Notifier, 0, len(cfg.Notifiers))
+	for _, notifierConfig := range cfg.Notifiers {
+		notifier, err := alerting.NewNotifier(notifierConfig)
+		if err != nil {
+			return err
+		}
+		notifiers = append(notifiers, notifier)
+	}
+
+	sm.logger.Info(fmt.Sprintf("   🔔 Alerting enabled with %d notifier(s), checking every %s", len(notifiers), cfg.Interval()))
+	go alerting.NewMonitor(cfg, sm, notifiers).Start(ctx)
+	return nil
+}
@@ -0,0 +1,211 @@
+package alerting
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/url"
+	"os"
+	"slices"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
+)
+
+// Defaults applied to zero-valued config fields
+const (
+	DefaultIntervalSeconds = 60
+	DefaultRepeatMinutes   = 60
+	DefaultSMTPPort        = 587
+)
+
+// Notifier types
+const (
+	NotifierWebhook = "webhook"
+	NotifierSlack   = "slack"
+	NotifierEmail   = "email"
+)
+
+// smtpPasswordEnv holds the SMTP password, kept out of the config file
+const smtpPasswordEnv = "ALERTING_SMTP_PASSWORD"
+
+// Config is the alerting configuration, loaded from ALERTING_CONFIG_FILE
+type Config struct {
+	Enabled         bool `json:"enabled"`
+	IntervalSeconds int  `json:"intervalSeconds"`
+	// RepeatMinutes is how often a condition that stays true is notified again; negative never repeats
+	RepeatMinutes int              `json:"repeatMinutes"`
+	Notifiers     []NotifierConfig `json:"notifiers"`
+	// Conditions; a nil or empty condition is not evaluated
+	ListenerStall *ListenerStallCondition `json:"listenerStall"`
+	LowBalances   []LowBalanceCondition   `json:"lowBalances"`
+	StuckOrders   *StuckOrderCondition    `json:"stuckOrders"`
+	FailedOrders  *FailedOrderCondition   `json:"failedOrders"`
+}
+
+// NotifierConfig describes one destination for alerts
+type NotifierConfig struct {
+	// Type is webhook (the alert as JSON), slack (a Slack-compatible {"text": ...} payload) or email
+	Type string `json:"type"`
+	// URL receives webhook and slack notifications
+	URL string `json:"url"`
+	// SMTP settings for email; the password is read from ALERTING_SMTP_PASSWORD
+	SMTPHost string   `json:"smtpHost"`
+	SMTPPort int      `json:"smtpPort"`
+	Username string   `json:"username"`
+	From     string   `json:"from"`
+	To       []string `json:"to"`
+}
+
+// ListenerStallCondition fires when a running listener's cursor has not advanced for MaxSeconds
+type ListenerStallCondition struct {
+	MaxSeconds int `json:"maxSeconds"`
+}
+
+// LowBalanceCondition fires when the solver's balance of Token on Network falls below Min
+type LowBalanceCondition struct {
+	Network string `json:"network"`
+	Token   string `json:"token"`
+	// Min is a decimal amount in the token's base units
+	Min string `json:"min"`
+
+	min *big.Int
+}
+
+// StuckOrderCondition fires when an order stays FILLED without being SETTLED for MaxMinutes
+type StuckOrderCondition struct {
+	MaxMinutes int `json:"maxMinutes"`
+}
+
+// FailedOrderCondition fires, per failure kind, when at least MinCount orders failed or were rejected with that
+// kind in the last WindowMinutes
+type FailedOrderCondition struct {
+	// Kinds are failure kinds (network, revert, insufficient_funds, ...); empty selects DefaultFailureKinds
+	Kinds         []failure.Kind `json:"kinds"`
+	WindowMinutes int            `json:"windowMinutes"`
+	// MinCount defaults to 1
+	MinCount int `json:"minCount"`
+}
+
+// DefaultFailureKinds are the failures that need an operator; validation failures, passed deadlines and orders
+// lost to competitors are part of normal operation
+var DefaultFailureKinds = []failure.Kind{
+	failure.KindNetwork, failure.KindRevert, failure.KindInsufficientFunds, failure.KindUnknown,
+}
+
+// LoadConfig reads and validates an alerting config file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read alerting config %s: %w", path, err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse alerting config %s: %w", path, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid alerting config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Validate applies defaults and checks notifiers and conditions
+func (c *Config) Validate() error {
+	if c.IntervalSeconds <= 0 {
+		c.IntervalSeconds = DefaultIntervalSeconds
+	}
+	if c.RepeatMinutes == 0 {
+		c.RepeatMinutes = DefaultRepeatMinutes
+	}
+
+	if len(c.Notifiers) == 0 {
+		return fmt.Errorf("at least one notifier is required")
+	}
+	for i := range c.Notifiers {
+		if err := c.Notifiers[i].validate(); err != nil {
+			return fmt.Errorf("notifier %d: %w", i, err)
+		}
+	}
+
+	if c.ListenerStall != nil && c.ListenerStall.MaxSeconds <= 0 {
+		return fmt.Errorf("listenerStall: maxSeconds must be positive")
+	}
+	if c.StuckOrders != nil && c.StuckOrders.MaxMinutes <= 0 {
+		return fmt.Errorf("stuckOrders: maxMinutes must be positive")
+	}
+	if c.FailedOrders != nil {
+		if err := c.FailedOrders.validate(); err != nil {
+			return err
+		}
+	}
+	for i := range c.LowBalances {
+		if err := c.LowBalances[i].validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Interval returns the time between evaluations
+func (c *Config) Interval() time.Duration {
+	return time.Duration(c.IntervalSeconds) * time.Second
+}
+
+// Repeat returns how long a firing alert stays quiet before it is sent again; zero never repeats
+func (c *Config) Repeat() time.Duration {
+	if c.RepeatMinutes < 0 {
+		return 0
+	}
+	return time.Duration(c.RepeatMinutes) * time.Minute
+}
+
+func (n *NotifierConfig) validate() error {
+	switch n.Type {
+	case NotifierWebhook, NotifierSlack:
+		if _, err := url.ParseRequestURI(n.URL); err != nil {
+			return fmt.Errorf("%s notifier needs a valid url: %w", n.Type, err)
+		}
+	case NotifierEmail:
+		if n.SMTPHost == "" || n.From == "" || len(n.To) == 0 {
+			return fmt.Errorf("email notifier needs smtpHost, from and to")
+		}
+		if n.SMTPPort <= 0 {
+			n.SMTPPort = DefaultSMTPPort
+		}
+	default:
+		return fmt.Errorf("unknown notifier type %q", n.Type)
+	}
+	return nil
+}
+
+func (f *FailedOrderCondition) validate() error {
+	if f.WindowMinutes <= 0 {
+		return fmt.Errorf("failedOrders: windowMinutes must be positive")
+	}
+	if f.MinCount <= 0 {
+		f.MinCount = 1
+	}
+	if len(f.Kinds) == 0 {
+		f.Kinds = DefaultFailureKinds
+	}
+	for _, kind := range f.Kinds {
+		if !slices.Contains(failure.Kinds, kind) {
+			return fmt.Errorf("failedOrders: unknown failure kind %q", kind)
+		}
+	}
+	return nil
+}
+
+func (l *LowBalanceCondition) validate() error {
+	if l.Network == "" || l.Token == "" {
+		return fmt.Errorf("lowBalances: network and token are required")
+	}
+	minimum, ok := new(big.Int).SetString(l.Min, 10)
+	if !ok || minimum.Sign() <= 0 {
+		return fmt.Errorf("lowBalances: invalid min %q for %s on %s", l.Min, l.Token, l.Network)
+	}
+	l.min = minimum
+	return nil
+}
@@ -0,0 +1,63 @@
+package alerting
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfig(t *testing.T, body string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "alerting.json")
+	require.NoError(t, os.WriteFile(path, []byte(body), 0o600))
+	return path
+}
+
+func TestLoadConfigAppliesDefaults(t *testing.T) {
+	cfg, err := LoadConfig(writeConfig(t, `{
+		"enabled": true,
+		"notifiers": [
+			{"type": "webhook", "url": "http://localhost:9000/alerts"},
+			{"type": "email", "smtpHost": "smtp.example.com", "from": "solver@example.com", "to": ["ops@example.com"]}
+		],
+		"lowBalances": [{"network": "Base", "token": "0xabc", "min": "1000"}]
+	}`))
+	require.NoError(t, err)
+
+	assert.Equal(t, time.Minute, cfg.Interval())
+	assert.Equal(t, time.Hour, cfg.Repeat())
+	assert.Equal(t, DefaultSMTPPort, cfg.Notifiers[1].SMTPPort)
+	assert.Equal(t, "1000", cfg.LowBalances[0].min.String())
+}
+
+func TestValidateRejectsInvalidConfigs(t *testing.T) {
+	webhook := []NotifierConfig{{Type: NotifierWebhook, URL: "http://localhost/alerts"}}
+	cases := map[string]Config{
+		"no notifiers":        {},
+		"unknown notifier":    {Notifiers: []NotifierConfig{{Type: "pager"}}},
+		"webhook without url": {Notifiers: []NotifierConfig{{Type: NotifierWebhook}}},
+		"email without to":    {Notifiers: []NotifierConfig{{Type: NotifierEmail, SMTPHost: "smtp", From: "a@b"}}},
+		"zero stall":          {Notifiers: webhook, ListenerStall: &ListenerStallCondition{}},
+		"zero stuck":          {Notifiers: webhook, StuckOrders: &StuckOrderCondition{}},
+		"zero failure window": {Notifiers: webhook, FailedOrders: &FailedOrderCondition{}},
+		"unknown kind":        {Notifiers: webhook, FailedOrders: &FailedOrderCondition{Kinds: []failure.Kind{"oops"}, WindowMinutes: 5}},
+		"bad min":             {Notifiers: webhook, LowBalances: []LowBalanceCondition{{Network: "Base", Token: "0xabc", Min: "ten"}}},
+		"missing token":       {Notifiers: webhook, LowBalances: []LowBalanceCondition{{Network: "Base", Min: "10"}}},
+	}
+	for name, cfg := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Error(t, cfg.Validate())
+		})
+	}
+}
+
+func TestRepeatNegativeNeverRepeats(t *testing.T) {
+	cfg := Config{RepeatMinutes: -1, Notifiers: []NotifierConfig{{Type: NotifierSlack, URL: "http://localhost/hook"}}}
+	require.NoError(t, cfg.Validate())
+	assert.Zero(t, cfg.Repeat())
+}
@@ -0,0 +1,263 @@
+package alerting
+
+// Module: Alerting on conditions that need an operator
+// - Listener stalls: a running listener whose cursor has stopped advancing
+// - Low balances: a token balance of the solver below a configured minimum
+// - Stuck orders: orders FILLED for too long without being SETTLED, read from the order history
+// - Failed orders: orders that recently failed or were rejected, per failure kind (insufficient funds, revert, ...)
+// - An alert is sent once when its condition turns true, repeated while it stays true and resolved when it clears
+// - Delivered to webhooks, Slack-compatible webhooks and email; configured by ALERTING_CONFIG_FILE
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/history"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+)
+
+// Conditions an alert can be raised for
+const (
+	ConditionListenerStall = "listener_stall"
+	ConditionLowBalance    = "low_balance"
+	ConditionStuckOrder    = "stuck_order"
+	ConditionFailedOrders  = "failed_orders"
+)
+
+// Alert is one notification about a condition turning true, staying true or clearing
+type Alert struct {
+	Condition string `json:"condition"`
+	// Key identifies what the condition is about, such as a network or an order; alerts are deduplicated by it
+	Key      string `json:"key"`
+	Summary  string `json:"summary"`
+	Resolved bool   `json:"resolved"`
+	// FiringSince is when the condition was first seen true
+	FiringSince time.Time `json:"firingSince"`
+	At          time.Time `json:"at"`
+}
+
+// Text is the alert as one line
+func (a Alert) Text() string {
+	if a.Resolved {
+		return fmt.Sprintf("✅ Resolved [%s] %s", a.Condition, a.Summary)
+	}
+	return fmt.Sprintf("🚨 [%s] %s", a.Condition, a.Summary)
+}
+
+// Probe reports the state the conditions are evaluated against; the SolverManager implements it
+type Probe interface {
+	ListenerStatus() []base.ListenerStatus
+	// TokenBalance returns the solver's balance of token on network
+	TokenBalance(ctx context.Context, network, token string) (*big.Int, error)
+	// FilledOrders returns the orders filled but not yet settled; nil without an order history
+	FilledOrders() []history.Record
+	// FailedOrders returns the orders that failed or were rejected since the given time; nil without an order history
+	FailedOrders(since time.Time) []history.Record
+}
+
+// cursor is the last block a listener was seen at, and when it got there
+type cursor struct {
+	block      uint64
+	advancedAt time.Time
+}
+
+// firing is an alert that has been sent and not yet resolved
+type firing struct {
+	alert      Alert
+	notifiedAt time.Time
+}
+
+// Monitor evaluates the configured conditions and notifies on changes
+type Monitor struct {
+	cfg       *Config
+	probe     Probe
+	notifiers []Notifier
+	cursors   map[string]cursor
+	firing    map[string]*firing
+	now       func() time.Time
+}
+
+// NewMonitor creates a monitor checking probe against cfg and notifying notifiers
+func NewMonitor(cfg *Config, probe Probe, notifiers []Notifier) *Monitor {
+	return &Monitor{
+		cfg:       cfg,
+		probe:     probe,
+		notifiers: notifiers,
+		cursors:   make(map[string]cursor),
+		firing:    make(map[string]*firing),
+		now:       time.Now,
+	}
+}
+
+// Start evaluates the conditions every interval until ctx is cancelled
+func (m *Monitor) Start(ctx context.Context) {
+	ticker := time.NewTicker(m.cfg.Interval())
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.Check(ctx)
+		}
+	}
+}
+
+// Check evaluates every condition once and sends the alerts that turned true, are due again or cleared
+func (m *Monitor) Check(ctx context.Context) {
+	now := m.now()
+	active := make(map[string]Alert)
+	// unknown holds keys that could not be evaluated this time; they keep their previous state
+	unknown := make(map[string]bool)
+
+	m.checkListeners(now, active)
+	m.checkBalances(ctx, active, unknown)
+	m.checkOrders(now, active)
+	m.checkFailures(now, active)
+
+	keys := make([]string, 0, len(active))
+	for key := range active {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		alert := active[key]
+		previous, ok := m.firing[key]
+		switch {
+		case !ok:
+			alert.FiringSince = now
+			alert.At = now
+			m.firing[key] = &firing{alert: alert, notifiedAt: now}
+			m.notify(ctx, alert)
+		case m.cfg.Repeat() > 0 && now.Sub(previous.notifiedAt) >= m.cfg.Repeat():
+			alert.FiringSince = previous.alert.FiringSince
+			alert.At = now
+			previous.alert, previous.notifiedAt = alert, now
+			m.notify(ctx, alert)
+		default:
+			alert.FiringSince = previous.alert.FiringSince
+			previous.alert = alert
+		}
+	}
+
+	for key, previous := range m.firing {
+		if _, ok := active[key]; ok || unknown[key] {
+			continue
+		}
+		resolved := previous.alert
+		resolved.Resolved = true
+		resolved.At = now
+		delete(m.firing, key)
+		m.notify(ctx, resolved)
+	}
+}
+
+// checkListeners flags running listeners whose cursor has not moved for longer than allowed
+func (m *Monitor) checkListeners(now time.Time, active map[string]Alert) {
+	if m.cfg.ListenerStall == nil {
+		return
+	}
+	maxStall := time.Duration(m.cfg.ListenerStall.MaxSeconds) * time.Second
+	for _, status := range m.probe.ListenerStatus() {
+		if status.Paused {
+			// A paused listener is not expected to advance; start afresh once it resumes
+			delete(m.cursors, status.Network)
+			continue
+		}
+		last, ok := m.cursors[status.Network]
+		if !ok || status.LastProcessedBlock != last.block {
+			m.cursors[status.Network] = cursor{block: status.LastProcessedBlock, advancedAt: now}
+			continue
+		}
+		if stalled := now.Sub(last.advancedAt); stalled > maxStall {
+			key := ConditionListenerStall + "/" + status.Network
+			active[key] = newAlert(ConditionListenerStall, key, fmt.Sprintf("%s listener has not advanced past block %d for %s",
+				status.Network, status.LastProcessedBlock, stalled.Round(time.Second)))
+		}
+	}
+}
+
+// checkBalances flags tokens below their minimum; a balance that cannot be read leaves its alert as it was
+func (m *Monitor) checkBalances(ctx context.Context, active map[string]Alert, unknown map[string]bool) {
+	for _, condition := range m.cfg.LowBalances {
+		key := ConditionLowBalance + "/" + condition.Network + "/" + strings.ToLower(condition.Token)
+		balance, err := m.probe.TokenBalance(ctx, condition.Network, condition.Token)
+		if err != nil {
+			logutil.Default().Warn("⚠️  Failed to read balance for alerting", logutil.Chain(condition.Network), logutil.Err(err))
+			unknown[key] = true
+			continue
+		}
+		if balance.Cmp(condition.min) < 0 {
+			active[key] = newAlert(ConditionLowBalance, key, fmt.Sprintf("Balance of %s on %s is %s, below the minimum %s",
+				condition.Token, condition.Network, balance, condition.min))
+		}
+	}
+}
+
+// checkOrders flags orders filled longer ago than allowed that are still not settled
+func (m *Monitor) checkOrders(now time.Time, active map[string]Alert) {
+	if m.cfg.StuckOrders == nil {
+		return
+	}
+	maxWait := time.Duration(m.cfg.StuckOrders.MaxMinutes) * time.Minute
+	for _, record := range m.probe.FilledOrders() {
+		if record.FilledAt.IsZero() || now.Sub(record.FilledAt) <= maxWait {
+			continue
+		}
+		key := ConditionStuckOrder + "/" + record.OrderID
+		summary := fmt.Sprintf("Order %s (%s → %s) filled %s ago and not settled",
+			record.OrderID, record.Origin, strings.Join(record.Destinations, ","), now.Sub(record.FilledAt).Round(time.Second))
+		if record.Error != "" {
+			summary += ": " + record.Error
+		}
+		active[key] = newAlert(ConditionStuckOrder, key, summary)
+	}
+}
+
+// checkFailures flags the failure kinds at least MinCount orders failed with inside the window
+func (m *Monitor) checkFailures(now time.Time, active map[string]Alert) {
+	condition := m.cfg.FailedOrders
+	if condition == nil {
+		return
+	}
+	window := time.Duration(condition.WindowMinutes) * time.Minute
+	byKind := make(map[failure.Kind][]history.Record)
+	for _, record := range m.probe.FailedOrders(now.Add(-window)) {
+		byKind[record.ErrorKind] = append(byKind[record.ErrorKind], record)
+	}
+	for _, kind := range condition.Kinds {
+		records := byKind[kind]
+		if len(records) < condition.MinCount {
+			continue
+		}
+		latest := records[0]
+		for _, record := range records[1:] {
+			if record.UpdatedAt.After(latest.UpdatedAt) {
+				latest = record
+			}
+		}
+		key := ConditionFailedOrders + "/" + string(kind)
+		active[key] = newAlert(ConditionFailedOrders, key, fmt.Sprintf("%d order(s) failed with %s in the last %s; latest %s: %s",
+			len(records), kind, window, latest.OrderID, latest.Error))
+	}
+}
+
+// notify sends alert to every notifier; failures are logged so one broken destination does not silence the others
+func (m *Monitor) notify(ctx context.Context, alert Alert) {
+	logutil.Default().Warn(alert.Text())
+	for _, notifier := range m.notifiers {
+		if err := notifier.Notify(ctx, alert); err != nil {
+			logutil.Default().Error(fmt.Sprintf("❌ Failed to send alert via %s", notifier.Name()), logutil.Err(err))
+		}
+	}
+}
+
+func newAlert(condition, key, summary string) Alert {
+	return Alert{Condition: condition, Key: key, Summary: summary, Resolved: false, FiringSince: time.Time{}, At: time.Time{}}
+}
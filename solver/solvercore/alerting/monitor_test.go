@@ -0,0 +1,234 @@
+package alerting
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/history"
+)
+
+type fakeProbe struct {
+	listeners []base.ListenerStatus
+	balances  map[string]*big.Int
+	orders    []history.Record
+	failed    []history.Record
+}
+
+func (p *fakeProbe) ListenerStatus() []base.ListenerStatus {
+	return p.listeners
+}
+
+func (p *fakeProbe) TokenBalance(_ context.Context, network, token string) (*big.Int, error) {
+	balance, ok := p.balances[network+"/"+token]
+	if !ok {
+		return nil, errors.New("balance unavailable")
+	}
+	return balance, nil
+}
+
+func (p *fakeProbe) FilledOrders() []history.Record {
+	return p.orders
+}
+
+func (p *fakeProbe) FailedOrders(since time.Time) []history.Record {
+	var failed []history.Record
+	for _, record := range p.failed {
+		if !record.UpdatedAt.Before(since) {
+			failed = append(failed, record)
+		}
+	}
+	return failed
+}
+
+type recordingNotifier struct {
+	alerts []Alert
+}
+
+func (r *recordingNotifier) Name() string {
+	return "recording"
+}
+
+func (r *recordingNotifier) Notify(_ context.Context, alert Alert) error {
+	r.alerts = append(r.alerts, alert)
+	return nil
+}
+
+// newTestMonitor returns a monitor whose clock is advanced by the returned function
+func newTestMonitor(t *testing.T, cfg Config, probe Probe) (*Monitor, *recordingNotifier, func(time.Duration)) {
+	t.Helper()
+	cfg.Notifiers = []NotifierConfig{{Type: NotifierWebhook, URL: "http://localhost/alerts"}}
+	require.NoError(t, cfg.Validate())
+	notifier := &recordingNotifier{}
+	monitor := NewMonitor(&cfg, probe, []Notifier{notifier})
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	monitor.now = func() time.Time { return now }
+	return monitor, notifier, func(d time.Duration) { now = now.Add(d) }
+}
+
+func TestMonitorLowBalanceFiresOnceRepeatsAndResolves(t *testing.T) {
+	probe := &fakeProbe{balances: map[string]*big.Int{"Base/0xABC": big.NewInt(5)}}
+	monitor, notifier, advance := newTestMonitor(t, Config{
+		RepeatMinutes: 30,
+		LowBalances:   []LowBalanceCondition{{Network: "Base", Token: "0xABC", Min: "10"}},
+	}, probe)
+	ctx := context.Background()
+
+	monitor.Check(ctx)
+	require.Len(t, notifier.alerts, 1)
+	assert.Equal(t, ConditionLowBalance, notifier.alerts[0].Condition)
+	assert.Equal(t, "low_balance/Base/0xabc", notifier.alerts[0].Key)
+	assert.False(t, notifier.alerts[0].Resolved)
+	firingSince := notifier.alerts[0].FiringSince
+
+	// Still low but not yet due again
+	advance(10 * time.Minute)
+	monitor.Check(ctx)
+	assert.Len(t, notifier.alerts, 1)
+
+	advance(25 * time.Minute)
+	monitor.Check(ctx)
+	require.Len(t, notifier.alerts, 2)
+	assert.False(t, notifier.alerts[1].Resolved)
+	assert.Equal(t, firingSince, notifier.alerts[1].FiringSince)
+
+	probe.balances["Base/0xABC"] = big.NewInt(10)
+	advance(time.Minute)
+	monitor.Check(ctx)
+	require.Len(t, notifier.alerts, 3)
+	assert.True(t, notifier.alerts[2].Resolved)
+	assert.Equal(t, firingSince, notifier.alerts[2].FiringSince)
+
+	monitor.Check(ctx)
+	assert.Len(t, notifier.alerts, 3)
+}
+
+func TestMonitorUnreadableBalanceKeepsState(t *testing.T) {
+	probe := &fakeProbe{balances: map[string]*big.Int{"Base/0xabc": big.NewInt(1)}}
+	monitor, notifier, advance := newTestMonitor(t, Config{
+		LowBalances: []LowBalanceCondition{{Network: "Base", Token: "0xabc", Min: "10"}},
+	}, probe)
+	ctx := context.Background()
+
+	monitor.Check(ctx)
+	require.Len(t, notifier.alerts, 1)
+
+	delete(probe.balances, "Base/0xabc")
+	advance(time.Minute)
+	monitor.Check(ctx)
+	assert.Len(t, notifier.alerts, 1, "an unreadable balance must neither resolve nor re-fire the alert")
+}
+
+func TestMonitorListenerStall(t *testing.T) {
+	probe := &fakeProbe{listeners: []base.ListenerStatus{
+		{Network: "Base", LastProcessedBlock: 100},
+		{Network: "Starknet", Paused: true, LastProcessedBlock: 7},
+	}}
+	monitor, notifier, advance := newTestMonitor(t, Config{
+		RepeatMinutes: -1,
+		ListenerStall: &ListenerStallCondition{MaxSeconds: 60},
+	}, probe)
+	ctx := context.Background()
+
+	monitor.Check(ctx)
+	advance(45 * time.Second)
+	probe.listeners[0].LastProcessedBlock = 101
+	monitor.Check(ctx)
+	advance(45 * time.Second)
+	monitor.Check(ctx)
+	assert.Empty(t, notifier.alerts, "the cursor advanced within the allowed time")
+
+	advance(30 * time.Second)
+	monitor.Check(ctx)
+	require.Len(t, notifier.alerts, 1)
+	assert.Equal(t, "listener_stall/Base", notifier.alerts[0].Key)
+
+	// Never repeats while stalled
+	advance(24 * time.Hour)
+	monitor.Check(ctx)
+	assert.Len(t, notifier.alerts, 1)
+
+	probe.listeners[0].LastProcessedBlock = 102
+	monitor.Check(ctx)
+	require.Len(t, notifier.alerts, 2)
+	assert.True(t, notifier.alerts[1].Resolved)
+}
+
+func TestMonitorStuckOrder(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	probe := &fakeProbe{orders: []history.Record{
+		{OrderID: "0x01", Origin: "Base", Destinations: []string{"Starknet"}, FilledAt: start, Error: "settle reverted"},
+		{OrderID: "0x02", Origin: "Base", Destinations: []string{"Optimism"}, FilledAt: start.Add(20 * time.Minute)},
+	}}
+	monitor, notifier, advance := newTestMonitor(t, Config{
+		StuckOrders: &StuckOrderCondition{MaxMinutes: 15},
+	}, probe)
+	ctx := context.Background()
+
+	advance(16 * time.Minute)
+	monitor.Check(ctx)
+	require.Len(t, notifier.alerts, 1)
+	assert.Equal(t, "stuck_order/0x01", notifier.alerts[0].Key)
+	assert.Contains(t, notifier.alerts[0].Summary, "Base → Starknet")
+	assert.Contains(t, notifier.alerts[0].Summary, "settle reverted")
+
+	// The order settles and leaves the FILLED list
+	probe.orders = probe.orders[1:]
+	advance(time.Minute)
+	monitor.Check(ctx)
+	require.Len(t, notifier.alerts, 2)
+	assert.True(t, notifier.alerts[1].Resolved)
+	assert.Equal(t, "stuck_order/0x01", notifier.alerts[1].Key)
+}
+
+func TestMonitorFailedOrdersByKind(t *testing.T) {
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	probe := &fakeProbe{failed: []history.Record{
+		{OrderID: "0x01", ErrorKind: failure.KindInsufficientFunds, Error: "insufficient inventory", UpdatedAt: start},
+		{OrderID: "0x02", ErrorKind: failure.KindInsufficientFunds, Error: "insufficient native balance", UpdatedAt: start.Add(time.Minute)},
+		{OrderID: "0x03", ErrorKind: failure.KindValidation, Error: "order not profitable", UpdatedAt: start},
+		{OrderID: "0x04", ErrorKind: failure.KindRevert, Error: "fill would revert", UpdatedAt: start},
+	}}
+	monitor, notifier, advance := newTestMonitor(t, Config{
+		FailedOrders: &FailedOrderCondition{Kinds: nil, WindowMinutes: 30, MinCount: 2},
+	}, probe)
+	ctx := context.Background()
+
+	advance(2 * time.Minute)
+	monitor.Check(ctx)
+	require.Len(t, notifier.alerts, 1, "validation is not alerted on by default and one revert is below minCount")
+	assert.Equal(t, "failed_orders/insufficient_funds", notifier.alerts[0].Key)
+	assert.Contains(t, notifier.alerts[0].Summary, "2 order(s) failed with insufficient_funds")
+	assert.Contains(t, notifier.alerts[0].Summary, "latest 0x02: insufficient native balance")
+
+	// The failures age out of the window
+	advance(30 * time.Minute)
+	monitor.Check(ctx)
+	require.Len(t, notifier.alerts, 2)
+	assert.True(t, notifier.alerts[1].Resolved)
+}
+
+func TestMonitorDeliversThroughWebhook(t *testing.T) {
+	hook := newTestWebhook(t)
+	cfg := Config{
+		Notifiers:   []NotifierConfig{{Type: NotifierWebhook, URL: hook.URL}},
+		LowBalances: []LowBalanceCondition{{Network: "Base", Token: "0xabc", Min: "10"}},
+	}
+	require.NoError(t, cfg.Validate())
+	notifier, err := NewNotifier(cfg.Notifiers[0])
+	require.NoError(t, err)
+	probe := &fakeProbe{balances: map[string]*big.Int{"Base/0xabc": big.NewInt(3)}}
+
+	NewMonitor(&cfg, probe, []Notifier{notifier}).Check(context.Background())
+
+	alerts := hook.alerts(t)
+	require.Len(t, alerts, 1)
+	assert.Equal(t, "Balance of 0xabc on Base is 3, below the minimum 10", alerts[0].Summary)
+}
@@ -0,0 +1,159 @@
+package alerting
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+)
+
+// notifyTimeout bounds one webhook request
+const notifyTimeout = 10 * time.Second
+
+// Notifier delivers alerts to one destination
+type Notifier interface {
+	Name() string
+	Notify(ctx context.Context, alert Alert) error
+}
+
+// NewNotifier builds the notifier described by cfg
+func NewNotifier(cfg NotifierConfig) (Notifier, error) {
+	switch cfg.Type {
+	case NotifierWebhook:
+		return NewWebhookNotifier(cfg.URL), nil
+	case NotifierSlack:
+		return NewSlackNotifier(cfg.URL), nil
+	case NotifierEmail:
+		return NewEmailNotifier(cfg), nil
+	default:
+		return nil, fmt.Errorf("unknown notifier type %q", cfg.Type)
+	}
+}
+
+// WebhookNotifier posts each alert as JSON
+type WebhookNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// NewWebhookNotifier posts alerts to url
+func NewWebhookNotifier(url string) *WebhookNotifier {
+	return &WebhookNotifier{url: url, client: newHTTPClient()}
+}
+
+// Name identifies the notifier in logs
+func (w *WebhookNotifier) Name() string {
+	return NotifierWebhook
+}
+
+// Notify posts alert as JSON
+func (w *WebhookNotifier) Notify(ctx context.Context, alert Alert) error {
+	return postJSON(ctx, w.client, w.url, alert)
+}
+
+// SlackNotifier posts each alert as a Slack-compatible {"text": ...} message
+type SlackNotifier struct {
+	url    string
+	client *http.Client
+}
+
+// SlackMessage is the payload of an incoming Slack webhook
+type SlackMessage struct {
+	Text string `json:"text"`
+}
+
+// NewSlackNotifier posts alerts to the incoming webhook url
+func NewSlackNotifier(url string) *SlackNotifier {
+	return &SlackNotifier{url: url, client: newHTTPClient()}
+}
+
+// Name identifies the notifier in logs
+func (s *SlackNotifier) Name() string {
+	return NotifierSlack
+}
+
+// Notify posts alert as a one-line message
+func (s *SlackNotifier) Notify(ctx context.Context, alert Alert) error {
+	return postJSON(ctx, s.client, s.url, SlackMessage{Text: alert.Text()})
+}
+
+func newHTTPClient() *http.Client {
+	return &http.Client{Transport: nil, CheckRedirect: nil, Jar: nil, Timeout: notifyTimeout}
+}
+
+func postJSON(ctx context.Context, client *http.Client, url string, body any) error {
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return fmt.Errorf("failed to marshal alert: %w", err)
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("failed to build alert request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post alert: %w", err)
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("alert webhook answered %s", resp.Status)
+	}
+	return nil
+}
+
+// EmailNotifier sends each alert as a plain-text email over SMTP
+type EmailNotifier struct {
+	addr string
+	auth smtp.Auth
+	from string
+	to   []string
+	// sendMail is smtp.SendMail, replaced in tests
+	sendMail func(addr string, auth smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewEmailNotifier sends alerts through the SMTP server in cfg, authenticating when a username is set
+func NewEmailNotifier(cfg NotifierConfig) *EmailNotifier {
+	var auth smtp.Auth
+	if cfg.Username != "" {
+		auth = smtp.PlainAuth("", cfg.Username, envutil.GetEnvWithDefault(smtpPasswordEnv, ""), cfg.SMTPHost)
+	}
+	return &EmailNotifier{
+		addr:     net.JoinHostPort(cfg.SMTPHost, strconv.Itoa(cfg.SMTPPort)),
+		auth:     auth,
+		from:     cfg.From,
+		to:       cfg.To,
+		sendMail: smtp.SendMail,
+	}
+}
+
+// Name identifies the notifier in logs
+func (e *EmailNotifier) Name() string {
+	return NotifierEmail
+}
+
+// Notify sends alert with its text as the subject and its details in the body
+func (e *EmailNotifier) Notify(_ context.Context, alert Alert) error {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", e.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", strings.Join(e.to, ", "))
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", alert.Text()))
+	fmt.Fprintf(&msg, "Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	fmt.Fprintf(&msg, "%s\r\n\r\nCondition: %s\r\nKey: %s\r\nAt: %s\r\n",
+		alert.Summary, alert.Condition, alert.Key, alert.At.Format(time.RFC3339))
+	if err := e.sendMail(e.addr, e.auth, e.from, e.to, []byte(msg.String())); err != nil {
+		return fmt.Errorf("failed to send alert email via %s: %w", e.addr, err)
+	}
+	return nil
+}
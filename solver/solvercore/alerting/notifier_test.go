@@ -0,0 +1,68 @@
+package alerting
+
+import (
+	"context"
+	"net/http"
+	"net/smtp"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testAlert = Alert{
+	Condition:   ConditionLowBalance,
+	Key:         "low_balance/Base/0xabc",
+	Summary:     "Balance of 0xabc on Base is 5, below the minimum 10",
+	FiringSince: time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+	At:          time.Date(2025, 1, 2, 3, 4, 5, 0, time.UTC),
+}
+
+func TestWebhookNotifierPostsAlert(t *testing.T) {
+	hook := newTestWebhook(t)
+
+	require.NoError(t, NewWebhookNotifier(hook.URL).Notify(context.Background(), testAlert))
+	assert.Equal(t, []Alert{testAlert}, hook.alerts(t))
+}
+
+func TestSlackNotifierPostsText(t *testing.T) {
+	hook := newTestWebhook(t)
+
+	require.NoError(t, NewSlackNotifier(hook.URL).Notify(context.Background(), testAlert))
+	assert.Equal(t, []string{"🚨 [low_balance] Balance of 0xabc on Base is 5, below the minimum 10"}, hook.messages(t))
+}
+
+func TestWebhookNotifierReportsFailedDelivery(t *testing.T) {
+	hook := newTestWebhook(t)
+	hook.failWith(http.StatusInternalServerError)
+
+	err := NewWebhookNotifier(hook.URL).Notify(context.Background(), testAlert)
+	assert.ErrorContains(t, err, "500")
+}
+
+func TestNewNotifierRejectsUnknownType(t *testing.T) {
+	_, err := NewNotifier(NotifierConfig{Type: "pager"})
+	assert.Error(t, err)
+}
+
+func TestEmailNotifierSendsMessage(t *testing.T) {
+	notifier := NewEmailNotifier(NotifierConfig{
+		Type: NotifierEmail, SMTPHost: "smtp.example.com", SMTPPort: 2525,
+		From: "solver@example.com", To: []string{"ops@example.com", "oncall@example.com"},
+	})
+	var gotAddr string
+	var gotTo []string
+	var gotMsg string
+	notifier.sendMail = func(addr string, _ smtp.Auth, _ string, to []string, msg []byte) error {
+		gotAddr, gotTo, gotMsg = addr, to, string(msg)
+		return nil
+	}
+
+	require.NoError(t, notifier.Notify(context.Background(), testAlert))
+	assert.Equal(t, "smtp.example.com:2525", gotAddr)
+	assert.Equal(t, []string{"ops@example.com", "oncall@example.com"}, gotTo)
+	assert.Contains(t, gotMsg, "To: ops@example.com, oncall@example.com\r\n")
+	assert.Contains(t, gotMsg, "Subject: =?utf-8?q?")
+	assert.Contains(t, gotMsg, "Key: low_balance/Base/0xabc\r\n")
+}
@@ -0,0 +1,75 @@
+package alerting
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+)
+
+// testWebhook is a local HTTP server standing in for a real alerting endpoint; it records every body posted to it
+type testWebhook struct {
+	URL string
+
+	mu     sync.Mutex
+	bodies [][]byte
+	status int
+}
+
+// newTestWebhook starts a webhook answering 200, closed when the test ends
+func newTestWebhook(t *testing.T) *testWebhook {
+	t.Helper()
+	w := &testWebhook{status: http.StatusOK}
+	server := httptest.NewServer(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		w.mu.Lock()
+		w.bodies = append(w.bodies, body)
+		status := w.status
+		w.mu.Unlock()
+		rw.WriteHeader(status)
+	}))
+	t.Cleanup(server.Close)
+	w.URL = server.URL
+	return w
+}
+
+// failWith makes the webhook answer status from now on
+func (w *testWebhook) failWith(status int) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.status = status
+}
+
+// alerts decodes the bodies posted by a webhook notifier
+func (w *testWebhook) alerts(t *testing.T) []Alert {
+	t.Helper()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	alerts := make([]Alert, 0, len(w.bodies))
+	for _, body := range w.bodies {
+		var alert Alert
+		if err := json.Unmarshal(body, &alert); err != nil {
+			t.Fatalf("webhook received invalid alert %s: %v", body, err)
+		}
+		alerts = append(alerts, alert)
+	}
+	return alerts
+}
+
+// messages decodes the bodies posted by a Slack notifier
+func (w *testWebhook) messages(t *testing.T) []string {
+	t.Helper()
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	messages := make([]string, 0, len(w.bodies))
+	for _, body := range w.bodies {
+		var message SlackMessage
+		if err := json.Unmarshal(body, &message); err != nil {
+			t.Fatalf("webhook received invalid message %s: %v", body, err)
+		}
+		messages = append(messages, message.Text)
+	}
+	return messages
+}
@@ -0,0 +1,281 @@
+package api
+
+// Module: Public HTTP API of the solver
+// - POST /v1/orders: off-chain intake of signed ERC-7683 gasless orders
+// - Validated orders are opened via openFor and answered with the solver's quote; others are rejected with a reason
+// - POST /v1/quote: prices a prospective order (minimum output, validity window, refusal reasons) without opening it
+// - GET /v1/orders and GET /v1/orders/{id}: the order history, when ORDER_HISTORY_ENABLED is on
+// - Enabled by setting API_LISTEN_ADDR
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/history"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+)
+
+// Defaults used when the corresponding environment variables are unset
+const (
+	// DefaultRequestTimeout bounds a request, including waiting for the openFor receipt
+	DefaultRequestTimeout = 2 * time.Minute
+
+	maxRequestBodyBytes = 64 << 10
+	readHeaderTimeout   = 10 * time.Second
+	shutdownTimeout     = 5 * time.Second
+)
+
+// Config configures the public API server
+type Config struct {
+	// ListenAddr is the host:port to listen on; empty disables the API
+	ListenAddr     string
+	RequestTimeout time.Duration
+}
+
+// ConfigFromEnv reads API_LISTEN_ADDR and API_REQUEST_TIMEOUT_MS
+func ConfigFromEnv() Config {
+	return Config{
+		ListenAddr: envutil.GetEnvWithDefault("API_LISTEN_ADDR", ""),
+		RequestTimeout: time.Duration(envutil.GetEnvUint64("API_REQUEST_TIMEOUT_MS",
+			uint64(DefaultRequestTimeout.Milliseconds()))) * time.Millisecond,
+	}
+}
+
+// Enabled reports whether the API should be served
+func (c Config) Enabled() bool {
+	return c.ListenAddr != ""
+}
+
+// GaslessIntake accepts signed gasless orders; the Hyperlane7683 solver implements it
+type GaslessIntake interface {
+	SubmitGaslessOrder(ctx context.Context, signed *orderutil.SignedGaslessOrder) (*hyperlane7683.GaslessQuote, error)
+}
+
+// Quoter prices prospective orders; the Hyperlane7683 solver implements it
+type Quoter interface {
+	Quote(ctx context.Context, req *hyperlane7683.QuoteRequest) (*hyperlane7683.Quote, error)
+}
+
+// Backend is everything the API serves from
+type Backend interface {
+	GaslessIntake
+	Quoter
+}
+
+// OrderHistory looks up processed orders; history.Store implements it
+type OrderHistory interface {
+	Get(orderID string) (history.Record, error)
+	List(filter history.Filter) []history.Record
+}
+
+// ErrorResponse is returned for failed order history requests
+type ErrorResponse struct {
+	Error string `json:"error"`
+}
+
+// OrderResponse is the decision returned for a submitted order
+type OrderResponse struct {
+	Accepted bool                        `json:"accepted"`
+	OrderID  string                      `json:"orderId,omitempty"`
+	Quote    *hyperlane7683.GaslessQuote `json:"quote,omitempty"`
+	Reason   string                      `json:"reason,omitempty"`
+}
+
+// Server serves the public API
+type Server struct {
+	cfg     Config
+	backend Backend
+	// Optional; without it the order history routes are not served
+	history OrderHistory
+}
+
+// NewServer creates an API server that hands submitted orders and quote requests to backend
+func NewServer(cfg Config, backend Backend) *Server {
+	if cfg.RequestTimeout <= 0 {
+		cfg.RequestTimeout = DefaultRequestTimeout
+	}
+	return &Server{cfg: cfg, backend: backend, history: nil}
+}
+
+// SetHistory serves the order history from orders
+func (s *Server) SetHistory(orders OrderHistory) {
+	s.history = orders
+}
+
+// Handler returns the API's routes
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/orders", s.handleSubmitOrder)
+	mux.HandleFunc("POST /v1/quote", s.handleQuote)
+	if s.history != nil {
+		mux.HandleFunc("GET /v1/orders", s.handleListOrders)
+		mux.HandleFunc("GET /v1/orders/{id}", s.handleGetOrder)
+	}
+	return mux
+}
+
+// Start listens on the configured address and serves until ctx is done
+func (s *Server) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.cfg.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.cfg.ListenAddr, err)
+	}
+	server := &http.Server{
+		Addr:                         s.cfg.ListenAddr,
+		Handler:                      s.Handler(),
+		DisableGeneralOptionsHandler: false,
+		TLSConfig:                    nil,
+		ReadTimeout:                  0,
+		ReadHeaderTimeout:            readHeaderTimeout,
+		WriteTimeout:                 0, // requests are bounded by RequestTimeout instead
+		IdleTimeout:                  0,
+		MaxHeaderBytes:               0,
+		TLSNextProto:                 nil,
+		ConnState:                    nil,
+		ErrorLog:                     nil,
+		BaseContext:                  func(net.Listener) context.Context { return ctx },
+		ConnContext:                  nil,
+		HTTP2:                        nil,
+		Protocols:                    nil,
+	}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logutil.Default().Error("❌ API server stopped", logutil.Err(err))
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	logutil.Default().Info(fmt.Sprintf("🌐 API listening on %s", listener.Addr()))
+	return nil
+}
+
+// handleSubmitOrder validates a signed gasless order and, if the solver accepts it, opens it via openFor
+func (s *Server) handleSubmitOrder(w http.ResponseWriter, r *http.Request) {
+	var signed orderutil.SignedGaslessOrder
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&signed); err != nil {
+		writeJSON(w, http.StatusBadRequest, rejection(fmt.Sprintf("invalid order JSON: %v", err)))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.cfg.RequestTimeout)
+	defer cancel()
+
+	quote, err := s.backend.SubmitGaslessOrder(ctx, &signed)
+	switch {
+	case errors.Is(err, hyperlane7683.ErrOrderRejected):
+		writeJSON(w, http.StatusUnprocessableEntity, rejection(err.Error()))
+	case errors.Is(err, hyperlane7683.ErrDryRun):
+		writeJSON(w, http.StatusServiceUnavailable, rejection(err.Error()))
+	case err != nil:
+		logutil.Default().Error("❌ Gasless order intake failed", logutil.Err(err))
+		writeJSON(w, http.StatusInternalServerError, rejection(err.Error()))
+	default:
+		writeJSON(w, http.StatusOK, OrderResponse{Accepted: true, OrderID: quote.OrderID, Quote: quote, Reason: ""})
+	}
+}
+
+// handleQuote prices a prospective order; refusals are quotes with reasons, not errors
+func (s *Server) handleQuote(w http.ResponseWriter, r *http.Request) {
+	var req hyperlane7683.QuoteRequest
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, maxRequestBodyBytes))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, rejection(fmt.Sprintf("invalid quote request JSON: %v", err)))
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), s.cfg.RequestTimeout)
+	defer cancel()
+
+	quote, err := s.backend.Quote(ctx, &req)
+	switch {
+	case errors.Is(err, hyperlane7683.ErrInvalidQuoteRequest):
+		writeJSON(w, http.StatusBadRequest, rejection(err.Error()))
+	case err != nil:
+		logutil.Default().Error("❌ Quote failed", logutil.Err(err))
+		writeJSON(w, http.StatusInternalServerError, rejection(err.Error()))
+	default:
+		writeJSON(w, http.StatusOK, quote)
+	}
+}
+
+// handleListOrders lists recorded orders, newest first, filtered by chain, status, since, until and limit
+func (s *Server) handleListOrders(w http.ResponseWriter, r *http.Request) {
+	filter, err := filterFromQuery(r)
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{Error: err.Error()})
+		return
+	}
+	writeJSON(w, http.StatusOK, s.history.List(filter))
+}
+
+// handleGetOrder returns the record of one order
+func (s *Server) handleGetOrder(w http.ResponseWriter, r *http.Request) {
+	record, err := s.history.Get(r.PathValue("id"))
+	switch {
+	case errors.Is(err, history.ErrNotFound):
+		writeJSON(w, http.StatusNotFound, ErrorResponse{Error: err.Error()})
+	case err != nil:
+		writeJSON(w, http.StatusInternalServerError, ErrorResponse{Error: err.Error()})
+	default:
+		writeJSON(w, http.StatusOK, record)
+	}
+}
+
+// filterFromQuery reads an order history filter; times are RFC 3339
+func filterFromQuery(r *http.Request) (history.Filter, error) {
+	query := r.URL.Query()
+	filter := history.Filter{
+		Chain:  query.Get("chain"),
+		Status: history.Status(query.Get("status")),
+		Since:  time.Time{},
+		Until:  time.Time{},
+		Limit:  0,
+	}
+	var err error
+	if value := query.Get("since"); value != "" {
+		if filter.Since, err = time.Parse(time.RFC3339, value); err != nil {
+			return filter, fmt.Errorf("invalid since: %w", err)
+		}
+	}
+	if value := query.Get("until"); value != "" {
+		if filter.Until, err = time.Parse(time.RFC3339, value); err != nil {
+			return filter, fmt.Errorf("invalid until: %w", err)
+		}
+	}
+	if value := query.Get("limit"); value != "" {
+		if filter.Limit, err = strconv.Atoi(value); err != nil || filter.Limit < 0 {
+			return filter, fmt.Errorf("invalid limit %q", value)
+		}
+	}
+	return filter, nil
+}
+
+func rejection(reason string) OrderResponse {
+	return OrderResponse{Accepted: false, OrderID: "", Quote: nil, Reason: reason}
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(body); err != nil {
+		logutil.Default().Warn("⚠️  Failed to write API response", logutil.Err(err))
+	}
+}
@@ -0,0 +1,280 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/history"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// fakeIntake returns err, or a quote for the submitted order or quote request
+type fakeIntake struct {
+	err      error
+	received *orderutil.SignedGaslessOrder
+	quoted   *hyperlane7683.QuoteRequest
+}
+
+func (f *fakeIntake) SubmitGaslessOrder(_ context.Context, signed *orderutil.SignedGaslessOrder) (*hyperlane7683.GaslessQuote, error) {
+	f.received = signed
+	if f.err != nil {
+		return nil, f.err
+	}
+	return &hyperlane7683.GaslessQuote{
+		OrderID:      "0x01",
+		FillDeadline: signed.Order.FillDeadline,
+		MaxSpent:     []types.Output{{Token: "0xaa", Amount: big.NewInt(1000), Recipient: "0xbb", ChainID: big.NewInt(2)}},
+		MinReceived:  []types.Output{{Token: "0xcc", Amount: big.NewInt(1001), Recipient: "0x00", ChainID: big.NewInt(1)}},
+	}, nil
+}
+
+func (f *fakeIntake) Quote(_ context.Context, req *hyperlane7683.QuoteRequest) (*hyperlane7683.Quote, error) {
+	f.quoted = req
+	if f.err != nil {
+		return nil, f.err
+	}
+	if req.AmountIn.Cmp(big.NewInt(10)) < 0 {
+		return &hyperlane7683.Quote{AmountIn: req.AmountIn, AmountOut: big.NewInt(0), ValidUntil: 1, Reasons: []string{"too small"}}, nil
+	}
+	return &hyperlane7683.Quote{
+		Accepted:         true,
+		AmountIn:         req.AmountIn,
+		AmountOut:        new(big.Int).Sub(req.AmountIn, big.NewInt(1)),
+		EstimatedGasCost: big.NewInt(5),
+		ValidUntil:       1850000000,
+		Reasons:          []string{},
+	}, nil
+}
+
+const orderJSON = `{
+	"order": {
+		"originSettler": "0xf614c6bf94b022e16bef7dbecf7614ffd2b201d3",
+		"user": "0x70997970c51812dc3a010c7d01b50e0d17dc79c8",
+		"nonce": 7,
+		"originChainId": 11155111,
+		"openDeadline": 1800000000,
+		"fillDeadline": 1850000000,
+		"orderDataType": "0x08d75650babf4de09c9273d48ef647876057ed91d4323f8a2e3ebc2cd8a63b5e",
+		"orderData": "0x1234"
+	},
+	"signature": "0xabcd"
+}`
+
+func serve(backend Backend, method, path, body string) *httptest.ResponseRecorder {
+	server := NewServer(Config{ListenAddr: "", RequestTimeout: time.Second}, backend)
+	req := httptest.NewRequest(method, path, strings.NewReader(body))
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, req)
+	return rec
+}
+
+func submit(t *testing.T, backend Backend, method, body string) (int, OrderResponse) {
+	t.Helper()
+	rec := serve(backend, method, "/v1/orders", body)
+
+	var resp OrderResponse
+	if rec.Code != http.StatusMethodNotAllowed {
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	}
+	return rec.Code, resp
+}
+
+func TestSubmitOrder(t *testing.T) {
+	t.Run("accepted", func(t *testing.T) {
+		intake := &fakeIntake{}
+		code, resp := submit(t, intake, http.MethodPost, orderJSON)
+		assert.Equal(t, http.StatusOK, code)
+		assert.True(t, resp.Accepted)
+		assert.Equal(t, "0x01", resp.OrderID)
+		require.NotNil(t, resp.Quote)
+		assert.Equal(t, uint32(1850000000), resp.Quote.FillDeadline)
+		assert.Equal(t, big.NewInt(1001), resp.Quote.MinReceived[0].Amount)
+
+		require.NotNil(t, intake.received)
+		assert.Equal(t, big.NewInt(7), intake.received.Order.Nonce)
+		assert.Equal(t, []byte{0xab, 0xcd}, []byte(intake.received.Signature))
+	})
+
+	t.Run("rejected", func(t *testing.T) {
+		intake := &fakeIntake{err: fmt.Errorf("%w: invalid signature", hyperlane7683.ErrOrderRejected)}
+		code, resp := submit(t, intake, http.MethodPost, orderJSON)
+		assert.Equal(t, http.StatusUnprocessableEntity, code)
+		assert.False(t, resp.Accepted)
+		assert.Contains(t, resp.Reason, "invalid signature")
+		assert.Nil(t, resp.Quote)
+	})
+
+	t.Run("dry run", func(t *testing.T) {
+		code, resp := submit(t, &fakeIntake{err: hyperlane7683.ErrDryRun}, http.MethodPost, orderJSON)
+		assert.Equal(t, http.StatusServiceUnavailable, code)
+		assert.False(t, resp.Accepted)
+		assert.Contains(t, resp.Reason, "dry-run")
+	})
+
+	t.Run("internal error", func(t *testing.T) {
+		code, resp := submit(t, &fakeIntake{err: errors.New("rpc unavailable")}, http.MethodPost, orderJSON)
+		assert.Equal(t, http.StatusInternalServerError, code)
+		assert.False(t, resp.Accepted)
+	})
+
+	t.Run("malformed JSON", func(t *testing.T) {
+		intake := &fakeIntake{}
+		code, resp := submit(t, intake, http.MethodPost, `{"order": {"unknown": 1}}`)
+		assert.Equal(t, http.StatusBadRequest, code)
+		assert.Contains(t, resp.Reason, "invalid order JSON")
+		assert.Nil(t, intake.received)
+	})
+
+	t.Run("wrong method", func(t *testing.T) {
+		code, _ := submit(t, &fakeIntake{}, http.MethodGet, "")
+		assert.Equal(t, http.StatusMethodNotAllowed, code)
+	})
+}
+
+const quoteJSON = `{
+	"originChainId": 11155111,
+	"destinationChainId": 11155420,
+	"inputToken": "0x76878654a2D96dDdF8cF0CFe8FA608aB4CE0D499",
+	"outputToken": "0xB844EEd1581f3fB810FFb6Dd6C5E30C049cF23F4",
+	"amountIn": %s
+}`
+
+func TestQuote(t *testing.T) {
+	t.Run("accepted", func(t *testing.T) {
+		backend := &fakeIntake{}
+		rec := serve(backend, http.MethodPost, "/v1/quote", fmt.Sprintf(quoteJSON, "1000000000000000000000"))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var quote hyperlane7683.Quote
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &quote))
+		assert.True(t, quote.Accepted)
+		assert.Equal(t, "999999999999999999999", quote.AmountOut.String())
+		assert.Equal(t, int64(1850000000), quote.ValidUntil)
+
+		require.NotNil(t, backend.quoted)
+		assert.Equal(t, uint64(11155420), backend.quoted.DestinationChainID)
+	})
+
+	t.Run("refused with reasons", func(t *testing.T) {
+		rec := serve(&fakeIntake{}, http.MethodPost, "/v1/quote", fmt.Sprintf(quoteJSON, "1"))
+		assert.Equal(t, http.StatusOK, rec.Code)
+
+		var quote hyperlane7683.Quote
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &quote))
+		assert.False(t, quote.Accepted)
+		assert.Equal(t, []string{"too small"}, quote.Reasons)
+		assert.Nil(t, quote.EstimatedGasCost)
+	})
+
+	t.Run("invalid request", func(t *testing.T) {
+		backend := &fakeIntake{err: fmt.Errorf("%w: amountIn must be positive", hyperlane7683.ErrInvalidQuoteRequest)}
+		rec := serve(backend, http.MethodPost, "/v1/quote", fmt.Sprintf(quoteJSON, "0"))
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Contains(t, rec.Body.String(), "amountIn must be positive")
+	})
+
+	t.Run("internal error", func(t *testing.T) {
+		rec := serve(&fakeIntake{err: errors.New("rpc unavailable")}, http.MethodPost, "/v1/quote", fmt.Sprintf(quoteJSON, "10"))
+		assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	})
+
+	t.Run("malformed JSON", func(t *testing.T) {
+		backend := &fakeIntake{}
+		rec := serve(backend, http.MethodPost, "/v1/quote", `{"amountIn": "lots"}`)
+		assert.Equal(t, http.StatusBadRequest, rec.Code)
+		assert.Nil(t, backend.quoted)
+	})
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("API_LISTEN_ADDR", "")
+	assert.False(t, ConfigFromEnv().Enabled())
+
+	t.Setenv("API_LISTEN_ADDR", ":8080")
+	t.Setenv("API_REQUEST_TIMEOUT_MS", "30000")
+	cfg := ConfigFromEnv()
+	assert.True(t, cfg.Enabled())
+	assert.Equal(t, 30*time.Second, cfg.RequestTimeout)
+}
+
+// fakeHistory serves a fixed set of records
+type fakeHistory struct {
+	records []history.Record
+	filter  history.Filter
+}
+
+func (f *fakeHistory) Get(orderID string) (history.Record, error) {
+	for _, record := range f.records {
+		if record.OrderID == orderID {
+			return record, nil
+		}
+	}
+	return history.Record{}, fmt.Errorf("%w: %s", history.ErrNotFound, orderID)
+}
+
+func (f *fakeHistory) List(filter history.Filter) []history.Record {
+	f.filter = filter
+	return f.records
+}
+
+func serveHistory(orders OrderHistory, path string) *httptest.ResponseRecorder {
+	server := NewServer(Config{ListenAddr: "", RequestTimeout: time.Second}, &fakeIntake{})
+	if orders != nil {
+		server.SetHistory(orders)
+	}
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	return rec
+}
+
+func TestOrderHistory(t *testing.T) {
+	orders := &fakeHistory{records: []history.Record{{OrderID: "0x01", Status: history.StatusSettled, Origin: "Base"}}}
+
+	t.Run("list with filter", func(t *testing.T) {
+		rec := serveHistory(orders, "/v1/orders?chain=Base&status=FAILED&since=2026-01-01T00:00:00Z&limit=5")
+		require.Equal(t, http.StatusOK, rec.Code)
+		var records []history.Record
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &records))
+		require.Len(t, records, 1)
+		assert.Equal(t, "Base", orders.filter.Chain)
+		assert.Equal(t, history.StatusFailed, orders.filter.Status)
+		assert.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC), orders.filter.Since)
+		assert.True(t, orders.filter.Until.IsZero())
+		assert.Equal(t, 5, orders.filter.Limit)
+	})
+
+	t.Run("invalid filter", func(t *testing.T) {
+		assert.Equal(t, http.StatusBadRequest, serveHistory(orders, "/v1/orders?since=yesterday").Code)
+		assert.Equal(t, http.StatusBadRequest, serveHistory(orders, "/v1/orders?limit=-1").Code)
+	})
+
+	t.Run("get", func(t *testing.T) {
+		rec := serveHistory(orders, "/v1/orders/0x01")
+		require.Equal(t, http.StatusOK, rec.Code)
+		var record history.Record
+		require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &record))
+		assert.Equal(t, history.StatusSettled, record.Status)
+
+		rec = serveHistory(orders, "/v1/orders/0x02")
+		assert.Equal(t, http.StatusNotFound, rec.Code)
+		assert.Contains(t, rec.Body.String(), "order not found")
+	})
+
+	t.Run("history disabled", func(t *testing.T) {
+		assert.Equal(t, http.StatusMethodNotAllowed, serveHistory(nil, "/v1/orders").Code)
+		assert.Equal(t, http.StatusNotFound, serveHistory(nil, "/v1/orders/0x01").Code)
+	})
+}
@@ -2,14 +2,17 @@ package base
 
 import (
 	"context"
+	"errors"
 	"math/big"
+	"time"
 
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 )
 
 // EventHandler is a function that processes parsed event arguments
-// Returns (settled, error) where settled=true means the order was fully settled
-type EventHandler func(args types.ParsedArgs, originChainName string, blockNumber uint64) (bool, error)
+// Returns (settled, error) where settled=true means the order was fully settled.
+// ctx carries the span of the event's detection, so the order's processing is traced under it.
+type EventHandler func(ctx context.Context, args types.ParsedArgs, originChainName string, blockNumber uint64) (bool, error)
 
 // ShutdownFunc is a function that stops the listener
 type ShutdownFunc func()
@@ -26,6 +29,38 @@ type Listener interface {
 	GetLastProcessedBlock() uint64
 }
 
+// ErrRescanAhead is returned when a re-scan would skip blocks that have not been processed yet
+var ErrRescanAhead = errors.New("re-scan block is ahead of the last processed block")
+
+// ControllableListener is a Listener that operators can pause, resume and rewind while it runs
+type ControllableListener interface {
+	Listener
+
+	// Pause stops the listener from processing new blocks until Resume is called
+	Pause()
+
+	// Resume continues processing from the last processed block
+	Resume()
+
+	// Paused reports whether the listener is paused
+	Paused() bool
+
+	// RescanFrom makes the listener process blocks again starting at block
+	RescanFrom(block uint64) error
+
+	// LastPolled returns when the listener last finished a poll of the chain; zero until it first does
+	LastPolled() time.Time
+}
+
+// ListenerStatus describes a running listener
+type ListenerStatus struct {
+	Network            string    `json:"network"`
+	Solver             string    `json:"solver"`
+	Paused             bool      `json:"paused"`
+	LastProcessedBlock uint64    `json:"lastProcessedBlock"`
+	LastPolledAt       time.Time `json:"lastPolledAt,omitzero"`
+}
+
 // ListenerConfig contains configuration for a listener
 type ListenerConfig struct {
 	ContractAddress    string
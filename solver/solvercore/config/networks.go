@@ -35,6 +35,10 @@ const (
 	StarknetDefaultPollIntervalMs = 2000
 	DefaultMaxBlockRange          = 10
 	StarknetDefaultMaxBlockRange  = 100
+
+	// DefaultTxConfirmations is the number of blocks, counting the one a transaction is mined in,
+	// the solver waits for before treating its transactions as final (1 = mined)
+	DefaultTxConfirmations = 1
 )
 
 // NetworkConfig represents a single network configuration
@@ -50,6 +54,9 @@ type NetworkConfig struct {
 	PollInterval       int    // milliseconds, 0 = use default
 	ConfirmationBlocks uint64 // 0 = use default
 	MaxBlockRange      uint64 // 0 = use default
+	// Transaction confirmation depth: blocks, counting the one a transaction is mined in,
+	// the solver waits for before acting on its own transactions
+	TxConfirmations uint64
 }
 
 // GetConditionalAccountEnv gets account-related environment variables based on IS_DEVNET flag
@@ -100,6 +107,7 @@ func initializeNetworks() {
 			PollInterval:       envutil.GetEnvInt("POLL_INTERVAL_MS", DefaultPollIntervalMs),
 			ConfirmationBlocks: envutil.GetEnvUint64("CONFIRMATION_BLOCKS", 0),
 			MaxBlockRange:      envutil.GetEnvUint64("MAX_BLOCK_RANGE", DefaultMaxBlockRange),
+			TxConfirmations:    envutil.GetEnvUint64("ETHEREUM_TX_CONFIRMATIONS", envutil.GetEnvUint64("TX_CONFIRMATIONS", DefaultTxConfirmations)),
 		},
 		"Optimism": {
 			Name:               "Optimism",
@@ -112,6 +120,7 @@ func initializeNetworks() {
 			PollInterval:       envutil.GetEnvInt("POLL_INTERVAL_MS", DefaultPollIntervalMs),
 			ConfirmationBlocks: envutil.GetEnvUint64("CONFIRMATION_BLOCKS", 0),
 			MaxBlockRange:      envutil.GetEnvUint64("MAX_BLOCK_RANGE", DefaultMaxBlockRange),
+			TxConfirmations:    envutil.GetEnvUint64("OPTIMISM_TX_CONFIRMATIONS", envutil.GetEnvUint64("TX_CONFIRMATIONS", DefaultTxConfirmations)),
 		},
 		"Arbitrum": {
 			Name:               "Arbitrum",
@@ -124,6 +133,7 @@ func initializeNetworks() {
 			PollInterval:       envutil.GetEnvInt("POLL_INTERVAL_MS", DefaultPollIntervalMs),
 			ConfirmationBlocks: envutil.GetEnvUint64("CONFIRMATION_BLOCKS", 0),
 			MaxBlockRange:      envutil.GetEnvUint64("MAX_BLOCK_RANGE", DefaultMaxBlockRange),
+			TxConfirmations:    envutil.GetEnvUint64("ARBITRUM_TX_CONFIRMATIONS", envutil.GetEnvUint64("TX_CONFIRMATIONS", DefaultTxConfirmations)),
 		},
 		"Base": {
 			Name:               "Base",
@@ -136,6 +146,7 @@ func initializeNetworks() {
 			PollInterval:       envutil.GetEnvInt("POLL_INTERVAL_MS", DefaultPollIntervalMs),
 			ConfirmationBlocks: envutil.GetEnvUint64("CONFIRMATION_BLOCKS", 0),
 			MaxBlockRange:      envutil.GetEnvUint64("MAX_BLOCK_RANGE", DefaultMaxBlockRange),
+			TxConfirmations:    envutil.GetEnvUint64("BASE_TX_CONFIRMATIONS", envutil.GetEnvUint64("TX_CONFIRMATIONS", DefaultTxConfirmations)),
 		},
 		"Starknet": {
 			Name:               "Starknet",
@@ -246,6 +257,17 @@ func GetHyperlaneAddressByChainID(chainID uint64) (common.Address, error) {
 	return common.Address{}, fmt.Errorf("network not found for chain ID: %d", chainID)
 }
 
+// GetTxConfirmationsByChainID returns the transaction confirmation depth for a given chain ID
+func GetTxConfirmationsByChainID(chainID uint64) (uint64, error) {
+	ensureInitialized()
+	for _, network := range Networks {
+		if network.ChainID == chainID {
+			return network.TxConfirmations, nil
+		}
+	}
+	return 0, fmt.Errorf("network not found for chain ID: %d", chainID)
+}
+
 // GetNetworkNames returns all available network names
 func GetNetworkNames() []string {
 	ensureInitialized()
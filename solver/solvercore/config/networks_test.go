@@ -6,6 +6,7 @@ import (
 
 	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestConditionalEnvironment(t *testing.T) {
@@ -153,3 +154,21 @@ func TestUtilityFunctions(t *testing.T) {
 	// Note: parseUint64 is now internal to envutil package, so we test it indirectly
 	// through the public functions that use it
 }
+
+func TestTxConfirmations(t *testing.T) {
+	t.Setenv("TX_CONFIRMATIONS", "2")
+	t.Setenv("BASE_TX_CONFIRMATIONS", "5")
+	ResetNetworks()
+	t.Cleanup(ResetNetworks)
+	InitializeNetworks()
+
+	assert.Equal(t, uint64(2), Networks["Ethereum"].TxConfirmations, "global default")
+	assert.Equal(t, uint64(5), Networks["Base"].TxConfirmations, "per-network override")
+
+	confirmations, err := GetTxConfirmationsByChainID(Networks["Base"].ChainID)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(5), confirmations)
+
+	_, err = GetTxConfirmationsByChainID(999999999)
+	assert.Error(t, err)
+}
@@ -0,0 +1,114 @@
+package solvercore
+
+// Module: Runtime control of a running solver, served by the admin API
+// - Tracks the network listeners each solver started
+// - A listener runs only while its solver is enabled and no operator has paused it
+// - Re-scans, allow/block list changes and in-flight order listing act on the running solver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/admin"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+)
+
+// networkListener is a running listener, the solver it feeds and whether an operator paused it
+type networkListener struct {
+	solver   string
+	listener base.ControllableListener
+	paused   bool
+}
+
+// registerListener tracks a started listener so operators can control it
+func (sm *SolverManager) registerListener(network, solver string, listener base.ControllableListener) {
+	sm.controlMu.Lock()
+	defer sm.controlMu.Unlock()
+	entry := &networkListener{solver: solver, listener: listener, paused: false}
+	sm.listeners[network] = entry
+	sm.applyListenerStateLocked(entry)
+}
+
+// applyListenerStateLocked pauses or resumes the listener to match its solver and operator state
+func (sm *SolverManager) applyListenerStateLocked(entry *networkListener) {
+	if entry.paused || !sm.solverRegistry[entry.solver].Enabled {
+		entry.listener.Pause()
+		return
+	}
+	entry.listener.Resume()
+}
+
+// ListenerStatus returns the status of every running listener, by network name
+func (sm *SolverManager) ListenerStatus() []base.ListenerStatus {
+	sm.controlMu.RLock()
+	defer sm.controlMu.RUnlock()
+
+	statuses := make([]base.ListenerStatus, 0, len(sm.listeners))
+	for network, entry := range sm.listeners {
+		statuses = append(statuses, base.ListenerStatus{
+			Network:            network,
+			Solver:             entry.solver,
+			Paused:             entry.listener.Paused(),
+			LastProcessedBlock: entry.listener.GetLastProcessedBlock(),
+			LastPolledAt:       entry.listener.LastPolled(),
+		})
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		return statuses[i].Network < statuses[j].Network
+	})
+	return statuses
+}
+
+// PauseListener stops the network's listener from processing new blocks
+func (sm *SolverManager) PauseListener(network string) error {
+	return sm.setListenerPaused(network, true)
+}
+
+// ResumeListener lets the network's listener continue, unless its solver is disabled
+func (sm *SolverManager) ResumeListener(network string) error {
+	return sm.setListenerPaused(network, false)
+}
+
+func (sm *SolverManager) setListenerPaused(network string, paused bool) error {
+	sm.controlMu.Lock()
+	defer sm.controlMu.Unlock()
+
+	entry, ok := sm.listeners[network]
+	if !ok {
+		return fmt.Errorf("listener for network %s %w", network, admin.ErrNotFound)
+	}
+	entry.paused = paused
+	sm.applyListenerStateLocked(entry)
+	return nil
+}
+
+// RescanListener makes the network's listener process blocks again starting at fromBlock.
+// Orders seen again are skipped once their on-chain status shows them filled.
+func (sm *SolverManager) RescanListener(network string, fromBlock uint64) error {
+	sm.controlMu.RLock()
+	entry, ok := sm.listeners[network]
+	sm.controlMu.RUnlock()
+	if !ok {
+		return fmt.Errorf("listener for network %s %w", network, admin.ErrNotFound)
+	}
+	return entry.listener.RescanFrom(fromBlock)
+}
+
+// InFlightOrders returns the orders the Hyperlane7683 solver is processing
+func (sm *SolverManager) InFlightOrders() []contracts.InFlightOrder {
+	if sm.hyperlane7683Solver == nil {
+		return []contracts.InFlightOrder{}
+	}
+	return sm.hyperlane7683Solver.InFlightOrders()
+}
+
+// initializeAdmin starts the admin API when ADMIN_LISTEN_ADDR is set
+func (sm *SolverManager) initializeAdmin(ctx context.Context) error {
+	cfg := admin.ConfigFromEnv()
+	if !cfg.Enabled() {
+		return nil
+	}
+	return admin.NewServer(cfg, sm).Start(ctx)
+}
@@ -0,0 +1,85 @@
+package solvercore
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/admin"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// fakeListener records pause state and re-scans
+type fakeListener struct {
+	paused     bool
+	lastBlock  uint64
+	rescanFrom uint64
+}
+
+func (l *fakeListener) Start(context.Context, base.EventHandler) (base.ShutdownFunc, error) {
+	return func() {}, nil
+}
+func (l *fakeListener) Stop() error                   { return nil }
+func (l *fakeListener) GetLastProcessedBlock() uint64 { return l.lastBlock }
+func (l *fakeListener) Pause()                        { l.paused = true }
+func (l *fakeListener) Resume()                       { l.paused = false }
+func (l *fakeListener) Paused() bool                  { return l.paused }
+func (l *fakeListener) LastPolled() time.Time         { return time.Time{} }
+func (l *fakeListener) RescanFrom(block uint64) error {
+	l.rescanFrom = block
+	return nil
+}
+
+func TestListenerControl(t *testing.T) {
+	sm := NewSolverManager(&config.Config{})
+	baseListener := &fakeListener{lastBlock: 120}
+	starknetListener := &fakeListener{lastBlock: 900}
+	sm.registerListener("Base", "hyperlane7683", baseListener)
+	sm.registerListener("Starknet", "hyperlane7683", starknetListener)
+
+	statuses := sm.ListenerStatus()
+	require.Len(t, statuses, 2)
+	assert.Equal(t, base.ListenerStatus{Network: "Base", Solver: "hyperlane7683", Paused: false, LastProcessedBlock: 120}, statuses[0])
+
+	// Disabling the solver pauses its listeners; enabling resumes those no operator paused
+	require.NoError(t, sm.PauseListener("Starknet"))
+	require.NoError(t, sm.DisableSolver("hyperlane7683"))
+	assert.True(t, baseListener.paused)
+	assert.True(t, starknetListener.paused)
+
+	require.NoError(t, sm.EnableSolver("hyperlane7683"))
+	assert.False(t, baseListener.paused)
+	assert.True(t, starknetListener.paused)
+
+	// Resuming a listener of a disabled solver keeps it paused
+	require.NoError(t, sm.DisableSolver("hyperlane7683"))
+	require.NoError(t, sm.ResumeListener("Starknet"))
+	assert.True(t, starknetListener.paused)
+	require.NoError(t, sm.EnableSolver("hyperlane7683"))
+	assert.False(t, starknetListener.paused)
+
+	require.NoError(t, sm.RescanListener("Base", 100))
+	assert.Equal(t, uint64(100), baseListener.rescanFrom)
+
+	assert.ErrorIs(t, sm.PauseListener("Mars"), admin.ErrNotFound)
+	assert.ErrorIs(t, sm.RescanListener("Mars", 1), admin.ErrNotFound)
+	assert.ErrorIs(t, sm.EnableSolver("nonexistent"), admin.ErrNotFound)
+}
+
+func TestInFlightOrdersWithoutSolver(t *testing.T) {
+	sm := NewSolverManager(&config.Config{})
+	assert.Empty(t, sm.InFlightOrders())
+
+	// Allow/block lists set before the solver starts are kept for it
+	lists := types.AllowBlockLists{
+		AllowList: []types.AllowBlockListItem{},
+		BlockList: []types.AllowBlockListItem{{SenderAddress: "0xbad", DestinationDomain: "*", RecipientAddress: "*"}},
+	}
+	sm.SetAllowBlockLists(lists)
+	assert.Equal(t, lists, sm.GetAllowBlockLists())
+}
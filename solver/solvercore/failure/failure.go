@@ -0,0 +1,159 @@
+package failure
+
+// Module: Error taxonomy shared by chain handlers, rules, listeners and the solver
+// - Sentinels name why an operation failed: network, validation, revert, deadline, funds, competitor
+// - Errors carry them through fmt.Errorf's %w, so every layer can add context and callers still classify with errors.Is
+// - KindOf and Permanent drive retries, the errors_total metric and alerting
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"syscall"
+
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+var (
+	// ErrNetwork: an RPC or network call failed or timed out; trying again may succeed
+	ErrNetwork = errors.New("network error")
+	// ErrValidation: the order or the request is invalid for this solver; trying again cannot succeed
+	ErrValidation = errors.New("validation failed")
+	// ErrReverted: a transaction reverted or would revert; the error says why
+	ErrReverted = errors.New("transaction reverted")
+	// ErrDeadlinePassed: the order's open or fill deadline has passed
+	ErrDeadlinePassed = errors.New("order deadline passed")
+	// ErrInsufficientFunds: the solver lacks the balance or allowance an operation spends
+	ErrInsufficientFunds = errors.New("insufficient funds")
+	// ErrCompetitorFilled: another solver filled the order first
+	ErrCompetitorFilled = errors.New("order filled by another solver")
+)
+
+// Kind names the class of an error, for metric labels, the order history and alerts
+type Kind string
+
+const (
+	KindNone              Kind = ""
+	KindNetwork           Kind = "network"
+	KindValidation        Kind = "validation"
+	KindRevert            Kind = "revert"
+	KindDeadline          Kind = "deadline"
+	KindInsufficientFunds Kind = "insufficient_funds"
+	KindCompetitorFilled  Kind = "competitor_filled"
+	KindUnknown           Kind = "unknown"
+)
+
+// Kinds lists every kind an error can be classified as, in the order KindOf checks them
+var Kinds = []Kind{
+	KindCompetitorFilled, KindDeadline, KindInsufficientFunds, KindValidation, KindRevert, KindNetwork, KindUnknown,
+}
+
+// kindSentinels maps each kind to its sentinel; an error wrapping several is classified by the first in Kinds
+var kindSentinels = map[Kind]error{
+	KindCompetitorFilled:  ErrCompetitorFilled,
+	KindDeadline:          ErrDeadlinePassed,
+	KindInsufficientFunds: ErrInsufficientFunds,
+	KindValidation:        ErrValidation,
+	KindRevert:            ErrReverted,
+	KindNetwork:           ErrNetwork,
+}
+
+// insufficientFundsMessages are how nodes and tokens word a missing balance or allowance
+var insufficientFundsMessages = []string{
+	"insufficient funds", "insufficient balance", "exceeds balance", "insufficient allowance", "exceeds allowance",
+	"insufficient max fee", "smaller than the transaction's max",
+}
+
+// networkMessages are how RPC clients word a call that did not reach the node or got no answer
+var networkMessages = []string{
+	"connection refused", "connection reset", "i/o timeout", "no such host", "timeout", "too many requests",
+	"bad gateway", "service unavailable", "gateway timeout",
+}
+
+// marked is an error classified under kind without changing its message
+type marked struct {
+	err  error
+	kind error
+}
+
+func (m *marked) Error() string   { return m.err.Error() }
+func (m *marked) Unwrap() []error { return []error{m.err, m.kind} }
+
+// Mark classifies err under the sentinel kind, e.g. Mark(err, ErrNetwork), keeping err's message.
+// It also turns a package's own sentinels into members of a kind: errors.Is still matches both.
+func Mark(err, kind error) error {
+	if err == nil {
+		return nil
+	}
+	return &marked{err: err, kind: kind}
+}
+
+// KindOf classifies err; errors wrapping no sentinel are recognised as network errors from their type or message
+// and are otherwise KindUnknown. A nil error is KindNone.
+func KindOf(err error) Kind {
+	if err == nil {
+		return KindNone
+	}
+	for _, kind := range Kinds {
+		if sentinel, ok := kindSentinels[kind]; ok && errors.Is(err, sentinel) {
+			return kind
+		}
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, known := range insufficientFundsMessages {
+		if strings.Contains(message, known) {
+			return KindInsufficientFunds
+		}
+	}
+	if isNetwork(err, message) {
+		return KindNetwork
+	}
+	return KindUnknown
+}
+
+// isNetwork recognises transport failures that were not wrapped with ErrNetwork
+func isNetwork(err error, message string) bool {
+	var netErr net.Error
+	var httpErr rpc.HTTPError
+	switch {
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF),
+		errors.Is(err, syscall.ECONNREFUSED), errors.Is(err, syscall.ECONNRESET), errors.As(err, &netErr):
+		return true
+	case errors.As(err, &httpErr):
+		return httpErr.StatusCode == http.StatusTooManyRequests || httpErr.StatusCode >= http.StatusInternalServerError
+	}
+	for _, known := range networkMessages {
+		if strings.Contains(message, known) {
+			return true
+		}
+	}
+	return false
+}
+
+// Permanent reports whether retrying the operation that returned err cannot succeed: the order is invalid,
+// past its deadline or filled by someone else, or an error in the chain says so through a Permanent() bool method
+func Permanent(err error) bool {
+	switch KindOf(err) {
+	case KindValidation, KindDeadline, KindCompetitorFilled:
+		return true
+	case KindNone:
+		return false
+	}
+	var permanent interface{ Permanent() bool }
+	return errors.As(err, &permanent) && permanent.Permanent()
+}
+
+// Retryable reports whether the operation that returned err may succeed if tried again
+func Retryable(err error) bool {
+	return err != nil && !Permanent(err)
+}
+
+// Transient reports whether err is a network failure or timeout that may clear up by itself shortly.
+// Unlike Retryable it excludes reverts, missing funds and unclassified errors, which do not go away on their own.
+func Transient(err error) bool {
+	return KindOf(err) == KindNetwork
+}
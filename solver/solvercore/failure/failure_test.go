@@ -0,0 +1,89 @@
+package failure
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"syscall"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+)
+
+type permanentError struct{ permanent bool }
+
+func (e permanentError) Error() string   { return "custom" }
+func (e permanentError) Permanent() bool { return e.permanent }
+
+func TestKindOf(t *testing.T) {
+	cases := map[string]struct {
+		err  error
+		want Kind
+	}{
+		"nil":                 {err: nil, want: KindNone},
+		"wrapped sentinel":    {err: fmt.Errorf("fill: %w", ErrDeadlinePassed), want: KindDeadline},
+		"competitor wins":     {err: errors.Join(ErrReverted, ErrCompetitorFilled), want: KindCompetitorFilled},
+		"funds over revert":   {err: fmt.Errorf("%w: %w", ErrReverted, ErrInsufficientFunds), want: KindInsufficientFunds},
+		"funds message":       {err: errors.New("insufficient funds for gas * price + value"), want: KindInsufficientFunds},
+		"allowance message":   {err: errors.New("ERC20: transfer amount exceeds allowance"), want: KindInsufficientFunds},
+		"deadline exceeded":   {err: fmt.Errorf("call: %w", context.DeadlineExceeded), want: KindNetwork},
+		"connection refused":  {err: fmt.Errorf("dial: %w", syscall.ECONNREFUSED), want: KindNetwork},
+		"rate limited":        {err: rpc.HTTPError{StatusCode: 429, Status: "429 Too Many Requests", Body: nil}, want: KindNetwork},
+		"bad request":         {err: rpc.HTTPError{StatusCode: 400, Status: "400 Bad Request", Body: nil}, want: KindUnknown},
+		"network message":     {err: errors.New("read tcp: i/o timeout"), want: KindNetwork},
+		"unclassified":        {err: errors.New("something odd"), want: KindUnknown},
+		"marked keeps prefix": {err: fmt.Errorf("outer: %w", Mark(errors.New("inner"), ErrValidation)), want: KindValidation},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.want, KindOf(tc.err))
+		})
+	}
+}
+
+func TestMarkKeepsMessageAndIdentity(t *testing.T) {
+	sentinel := errors.New("order rejected")
+	err := Mark(sentinel, ErrValidation)
+
+	assert.Equal(t, "order rejected", err.Error())
+	assert.ErrorIs(t, err, sentinel)
+	assert.ErrorIs(t, err, ErrValidation)
+	assert.NoError(t, Mark(nil, ErrNetwork))
+}
+
+func TestPermanentAndRetryable(t *testing.T) {
+	cases := map[string]struct {
+		err       error
+		permanent bool
+	}{
+		"validation":        {err: ErrValidation, permanent: true},
+		"deadline":          {err: ErrDeadlinePassed, permanent: true},
+		"competitor":        {err: ErrCompetitorFilled, permanent: true},
+		"network":           {err: ErrNetwork, permanent: false},
+		"insufficient":      {err: ErrInsufficientFunds, permanent: false},
+		"plain revert":      {err: ErrReverted, permanent: false},
+		"permanent method":  {err: fmt.Errorf("settle: %w", permanentError{permanent: true}), permanent: true},
+		"transient method":  {err: permanentError{permanent: false}, permanent: false},
+		"unknown":           {err: errors.New("something odd"), permanent: false},
+		"marked permanent":  {err: Mark(permanentError{permanent: true}, ErrReverted), permanent: true},
+		"network over flag": {err: Mark(errors.New("timeout"), ErrNetwork), permanent: false},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			assert.Equal(t, tc.permanent, Permanent(tc.err))
+			assert.Equal(t, !tc.permanent, Retryable(tc.err))
+		})
+	}
+	assert.False(t, Permanent(nil))
+	assert.False(t, Retryable(nil))
+}
+
+func TestTransient(t *testing.T) {
+	assert.True(t, Transient(fmt.Errorf("rpc: %w", ErrNetwork)))
+	assert.True(t, Transient(fmt.Errorf("call: %w", context.DeadlineExceeded)))
+	assert.False(t, Transient(ErrReverted))
+	assert.False(t, Transient(ErrInsufficientFunds))
+	assert.False(t, Transient(errors.New("status must be filled")))
+	assert.False(t, Transient(nil))
+}
@@ -0,0 +1,46 @@
+package solvercore
+
+// Module: Gasless order submission for the submit-gasless subcommand
+// - Reads a signed gasless order (JSON, as written by the open-order tool) from disk
+// - Validates it and submits openFor on its origin chain; running solvers then fill it from the Open event
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderutil"
+)
+
+// SubmitGaslessOrder opens the signed gasless order stored at path and returns its order ID
+func (sm *SolverManager) SubmitGaslessOrder(ctx context.Context, path string) (string, error) {
+	signed, err := LoadSignedGaslessOrder(path)
+	if err != nil {
+		return "", err
+	}
+	if err := sm.initializeEVMClients(); err != nil {
+		return "", fmt.Errorf("failed to initialize EVM clients: %w", err)
+	}
+
+	solver := sm.newOneShotSolver()
+	solver.AddDefaultRules()
+	quote, err := solver.SubmitGaslessOrder(ctx, signed)
+	if err != nil {
+		return "", err
+	}
+	return quote.OrderID, nil
+}
+
+// LoadSignedGaslessOrder reads a signed gasless order from a JSON file
+func LoadSignedGaslessOrder(path string) (*orderutil.SignedGaslessOrder, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read gasless order: %w", err)
+	}
+	var signed orderutil.SignedGaslessOrder
+	if err := json.Unmarshal(raw, &signed); err != nil {
+		return nil, fmt.Errorf("failed to parse gasless order %s: %w", path, err)
+	}
+	return &signed, nil
+}
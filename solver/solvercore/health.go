@@ -0,0 +1,82 @@
+package solvercore
+
+// Module: Health probe of a running solver, served by the health endpoints
+// - Reaches each configured network's RPC endpoint through the clients the solver already holds
+// - Reports whether the solver's keys can sign on each network
+// - Reports the backlog of orders in flight or queued for settlement
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/health"
+)
+
+// Networks returns the names of the configured networks, sorted
+func (sm *SolverManager) Networks() []string {
+	networks := make([]string, 0, len(config.Networks))
+	for name := range config.Networks {
+		networks = append(networks, name)
+	}
+	sort.Strings(networks)
+	return networks
+}
+
+// ChainHead returns the latest block of network, as reported by its RPC endpoint
+func (sm *SolverManager) ChainHead(ctx context.Context, network string) (uint64, error) {
+	networkConfig, ok := config.Networks[network]
+	if !ok {
+		return 0, fmt.Errorf("network %s not configured", network)
+	}
+	if isStarknetNetwork(network) {
+		client, err := sm.GetStarknetClient()
+		if err != nil {
+			return 0, err
+		}
+		return client.BlockNumber(ctx)
+	}
+	client, err := sm.GetEVMClient(networkConfig.ChainID)
+	if err != nil {
+		return 0, err
+	}
+	return client.BlockNumber(ctx)
+}
+
+// SignerAvailable returns an error when the solver has no usable key for network
+func (sm *SolverManager) SignerAvailable(network string) error {
+	networkConfig, ok := config.Networks[network]
+	if !ok {
+		return fmt.Errorf("network %s not configured", network)
+	}
+	if isStarknetNetwork(network) {
+		_, err := sm.GetStarknetSigner()
+		return err
+	}
+	_, err := sm.GetEVMSigner(networkConfig.ChainID)
+	return err
+}
+
+// Backlog returns how many orders the Hyperlane7683 solver is processing or has queued for settlement
+func (sm *SolverManager) Backlog() int {
+	if sm.hyperlane7683Solver == nil {
+		return 0
+	}
+	return sm.hyperlane7683Solver.Backlog()
+}
+
+// initializeHealth serves /healthz and /readyz when HEALTH_LISTEN_ADDR is set
+func (sm *SolverManager) initializeHealth(ctx context.Context) error {
+	cfg := health.ConfigFromEnv()
+	if !cfg.Enabled() {
+		return nil
+	}
+	return health.NewServer(cfg, sm).Start(ctx)
+}
+
+// isStarknetNetwork reports whether network is a Starknet network; the others are EVM networks
+func isStarknetNetwork(network string) bool {
+	return strings.Contains(strings.ToLower(network), "starknet")
+}
@@ -0,0 +1,304 @@
+package health
+
+// Module: Liveness and readiness endpoints for container orchestration
+// - /healthz fails when a running listener has not finished a poll within HEALTH_MAX_POLL_AGE_MS
+// - /readyz also checks RPC connectivity and signer availability per chain, each listener's
+//   lag behind the chain head and the backlog of orders still being worked on
+// - Both answer with a JSON report; 200 when healthy, 503 otherwise
+// - Served on its own address (HEALTH_LISTEN_ADDR), without authentication
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+)
+
+// Defaults used when the corresponding environment variables are unset
+const (
+	DefaultMaxPollAge   = 2 * time.Minute
+	DefaultMaxLagBlocks = 100
+	DefaultMaxBacklog   = 100
+	DefaultCheckTimeout = 5 * time.Second
+)
+
+const (
+	readHeaderTimeout = 10 * time.Second
+	shutdownTimeout   = 5 * time.Second
+)
+
+// Config configures the health server and its thresholds
+type Config struct {
+	// ListenAddr is the host:port to listen on; empty disables the endpoints
+	ListenAddr string
+	// MaxPollAge is how long a running listener may go without finishing a poll
+	MaxPollAge time.Duration
+	// MaxLagBlocks is how far a running listener may fall behind the chain head
+	MaxLagBlocks uint64
+	// MaxBacklog is how many orders may be in flight or queued for settlement
+	MaxBacklog int
+	// CheckTimeout bounds the RPC calls made by a readiness check
+	CheckTimeout time.Duration
+}
+
+// ConfigFromEnv reads HEALTH_LISTEN_ADDR and the HEALTH_* thresholds
+func ConfigFromEnv() Config {
+	return Config{
+		ListenAddr:   envutil.GetEnvWithDefault("HEALTH_LISTEN_ADDR", ""),
+		MaxPollAge:   time.Duration(envutil.GetEnvUint64("HEALTH_MAX_POLL_AGE_MS", uint64(DefaultMaxPollAge.Milliseconds()))) * time.Millisecond,
+		MaxLagBlocks: envutil.GetEnvUint64("HEALTH_MAX_LAG_BLOCKS", DefaultMaxLagBlocks),
+		MaxBacklog:   envutil.GetEnvInt("HEALTH_MAX_BACKLOG", DefaultMaxBacklog),
+		CheckTimeout: time.Duration(envutil.GetEnvUint64("HEALTH_CHECK_TIMEOUT_MS", uint64(DefaultCheckTimeout.Milliseconds()))) * time.Millisecond,
+	}
+}
+
+// Enabled reports whether the health endpoints should be served
+func (c Config) Enabled() bool {
+	return c.ListenAddr != ""
+}
+
+// Probe reports the state the checks are evaluated against; the SolverManager implements it
+type Probe interface {
+	ListenerStatus() []base.ListenerStatus
+	// Networks returns the configured networks, by name
+	Networks() []string
+	// ChainHead returns the latest block of the network, reaching its RPC endpoint
+	ChainHead(ctx context.Context, network string) (uint64, error)
+	// SignerAvailable returns an error when the solver cannot sign transactions on the network
+	SignerAvailable(network string) error
+	// Backlog returns how many orders are in flight or queued for settlement
+	Backlog() int
+}
+
+// ListenerCheck is the health of one network listener
+type ListenerCheck struct {
+	Network            string    `json:"network"`
+	Paused             bool      `json:"paused"`
+	LastPolledAt       time.Time `json:"lastPolledAt,omitzero"`
+	LastProcessedBlock uint64    `json:"lastProcessedBlock"`
+	LagBlocks          uint64    `json:"lagBlocks,omitempty"`
+	Healthy            bool      `json:"healthy"`
+	Problem            string    `json:"problem,omitempty"`
+}
+
+// ChainCheck is the health of one network's RPC endpoint and signer
+type ChainCheck struct {
+	Network         string `json:"network"`
+	Head            uint64 `json:"head,omitempty"`
+	RPCReachable    bool   `json:"rpcReachable"`
+	SignerAvailable bool   `json:"signerAvailable"`
+	Healthy         bool   `json:"healthy"`
+	Problem         string `json:"problem,omitempty"`
+}
+
+// BacklogCheck compares the order backlog with its threshold
+type BacklogCheck struct {
+	Orders  int  `json:"orders"`
+	Max     int  `json:"max"`
+	Healthy bool `json:"healthy"`
+}
+
+// Report is the body of a health or readiness response
+type Report struct {
+	Healthy   bool            `json:"healthy"`
+	Listeners []ListenerCheck `json:"listeners"`
+	Chains    []ChainCheck    `json:"chains,omitempty"`
+	Backlog   *BacklogCheck   `json:"backlog,omitempty"`
+}
+
+// Server serves the health endpoints
+type Server struct {
+	cfg     Config
+	probe   Probe
+	started time.Time
+}
+
+// NewServer creates a health server checking probe against the thresholds in cfg
+func NewServer(cfg Config, probe Probe) *Server {
+	return &Server{cfg: cfg, probe: probe, started: time.Now()}
+}
+
+// Handler returns the /healthz and /readyz routes
+func (s *Server) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, _ *http.Request) {
+		writeReport(w, s.Liveness())
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		writeReport(w, s.Readiness(r.Context()))
+	})
+	return mux
+}
+
+// Liveness checks that every running listener finished a poll recently. Paused listeners are healthy;
+// a listener that has not polled yet is measured from the server's start so a long backfill has time to begin.
+func (s *Server) Liveness() Report {
+	report := Report{Healthy: true, Listeners: s.checkListeners(nil), Chains: nil, Backlog: nil}
+	for _, check := range report.Listeners {
+		report.Healthy = report.Healthy && check.Healthy
+	}
+	return report
+}
+
+// Readiness runs the liveness checks and also checks every chain's RPC endpoint and signer,
+// each listener's lag behind its chain head and the order backlog
+func (s *Server) Readiness(ctx context.Context) Report {
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.CheckTimeout)
+	defer cancel()
+
+	chains := s.checkChains(ctx)
+	heads := make(map[string]uint64, len(chains))
+	for _, chain := range chains {
+		if chain.RPCReachable {
+			heads[chain.Network] = chain.Head
+		}
+	}
+
+	backlog := s.probe.Backlog()
+	report := Report{
+		Healthy:   true,
+		Listeners: s.checkListeners(heads),
+		Chains:    chains,
+		Backlog:   &BacklogCheck{Orders: backlog, Max: s.cfg.MaxBacklog, Healthy: backlog <= s.cfg.MaxBacklog},
+	}
+	report.Healthy = report.Backlog.Healthy
+	for _, check := range report.Listeners {
+		report.Healthy = report.Healthy && check.Healthy
+	}
+	for _, check := range report.Chains {
+		report.Healthy = report.Healthy && check.Healthy
+	}
+	return report
+}
+
+// checkListeners checks poll age and, for networks in heads, lag behind the chain head
+func (s *Server) checkListeners(heads map[string]uint64) []ListenerCheck {
+	statuses := s.probe.ListenerStatus()
+	checks := make([]ListenerCheck, 0, len(statuses))
+	for _, status := range statuses {
+		check := ListenerCheck{
+			Network:            status.Network,
+			Paused:             status.Paused,
+			LastPolledAt:       status.LastPolledAt,
+			LastProcessedBlock: status.LastProcessedBlock,
+			LagBlocks:          0,
+			Healthy:            true,
+			Problem:            "",
+		}
+		if head, ok := heads[status.Network]; ok && head > status.LastProcessedBlock {
+			check.LagBlocks = head - status.LastProcessedBlock
+		}
+
+		if !status.Paused {
+			since := status.LastPolledAt
+			if since.IsZero() {
+				since = s.started
+			}
+			switch {
+			case time.Since(since) > s.cfg.MaxPollAge:
+				check.Healthy = false
+				check.Problem = fmt.Sprintf("no finished poll for %s", time.Since(since).Round(time.Second))
+			case check.LagBlocks > s.cfg.MaxLagBlocks:
+				check.Healthy = false
+				check.Problem = fmt.Sprintf("%d blocks behind the chain head", check.LagBlocks)
+			}
+		}
+		checks = append(checks, check)
+	}
+	return checks
+}
+
+// checkChains reaches every network's RPC endpoint concurrently and checks its signer
+func (s *Server) checkChains(ctx context.Context) []ChainCheck {
+	networks := s.probe.Networks()
+	checks := make([]ChainCheck, len(networks))
+
+	var wg sync.WaitGroup
+	for i, network := range networks {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			check := ChainCheck{Network: network, Head: 0, RPCReachable: false, SignerAvailable: false, Healthy: false, Problem: ""}
+			problems := make([]error, 0, 2) //nolint:mnd // RPC and signer
+			if head, err := s.probe.ChainHead(ctx, network); err != nil {
+				problems = append(problems, fmt.Errorf("rpc: %w", err))
+			} else {
+				check.Head = head
+				check.RPCReachable = true
+			}
+			if err := s.probe.SignerAvailable(network); err != nil {
+				problems = append(problems, fmt.Errorf("signer: %w", err))
+			} else {
+				check.SignerAvailable = true
+			}
+			check.Healthy = len(problems) == 0
+			if !check.Healthy {
+				check.Problem = errors.Join(problems...).Error()
+			}
+			checks[i] = check
+		}()
+	}
+	wg.Wait()
+	return checks
+}
+
+// Start listens on the configured address and serves until ctx is done
+func (s *Server) Start(ctx context.Context) error {
+	listener, err := net.Listen("tcp", s.cfg.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", s.cfg.ListenAddr, err)
+	}
+	server := &http.Server{
+		Addr:                         s.cfg.ListenAddr,
+		Handler:                      s.Handler(),
+		DisableGeneralOptionsHandler: false,
+		TLSConfig:                    nil,
+		ReadTimeout:                  0,
+		ReadHeaderTimeout:            readHeaderTimeout,
+		WriteTimeout:                 0,
+		IdleTimeout:                  0,
+		MaxHeaderBytes:               0,
+		TLSNextProto:                 nil,
+		ConnState:                    nil,
+		ErrorLog:                     nil,
+		BaseContext:                  func(net.Listener) context.Context { return ctx },
+		ConnContext:                  nil,
+		HTTP2:                        nil,
+		Protocols:                    nil,
+	}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logutil.Default().Error("❌ Health server stopped", logutil.Err(err))
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	logutil.Default().Info(fmt.Sprintf("🩺 Health endpoints listening on %s (/healthz, /readyz)", listener.Addr()))
+	return nil
+}
+
+func writeReport(w http.ResponseWriter, report Report) {
+	status := http.StatusOK
+	if !report.Healthy {
+		status = http.StatusServiceUnavailable
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	if err := json.NewEncoder(w).Encode(report); err != nil {
+		logutil.Default().Warn("⚠️  Failed to write health response", logutil.Err(err))
+	}
+}
@@ -0,0 +1,174 @@
+package health
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+)
+
+// fakeProbe reports fixed listener, chain and backlog state
+type fakeProbe struct {
+	listeners []base.ListenerStatus
+	heads     map[string]uint64
+	rpcErrs   map[string]error
+	signerErr map[string]error
+	backlog   int
+}
+
+func newFakeProbe() *fakeProbe {
+	return &fakeProbe{
+		listeners: []base.ListenerStatus{
+			{Network: "Base", Solver: "hyperlane7683", LastProcessedBlock: 990, LastPolledAt: time.Now()},
+			{Network: "Starknet", Solver: "hyperlane7683", LastProcessedBlock: 500, LastPolledAt: time.Now()},
+		},
+		heads:     map[string]uint64{"Base": 1_000, "Starknet": 510},
+		rpcErrs:   map[string]error{},
+		signerErr: map[string]error{},
+	}
+}
+
+func (p *fakeProbe) ListenerStatus() []base.ListenerStatus { return p.listeners }
+
+func (p *fakeProbe) Networks() []string { return []string{"Base", "Starknet"} }
+
+func (p *fakeProbe) ChainHead(_ context.Context, network string) (uint64, error) {
+	if err := p.rpcErrs[network]; err != nil {
+		return 0, err
+	}
+	return p.heads[network], nil
+}
+
+func (p *fakeProbe) SignerAvailable(network string) error { return p.signerErr[network] }
+
+func (p *fakeProbe) Backlog() int { return p.backlog }
+
+func testConfig() Config {
+	return Config{
+		ListenAddr:   "",
+		MaxPollAge:   time.Minute,
+		MaxLagBlocks: 50,
+		MaxBacklog:   10,
+		CheckTimeout: time.Second,
+	}
+}
+
+func get(t *testing.T, server *Server, path string) (int, Report) {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	server.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, path, nil))
+	var report Report
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &report))
+	return rec.Code, report
+}
+
+func TestHealthy(t *testing.T) {
+	server := NewServer(testConfig(), newFakeProbe())
+
+	code, report := get(t, server, "/healthz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, report.Healthy)
+	require.Len(t, report.Listeners, 2)
+	assert.Empty(t, report.Chains)
+
+	code, report = get(t, server, "/readyz")
+	assert.Equal(t, http.StatusOK, code)
+	assert.True(t, report.Healthy)
+	assert.Equal(t, uint64(10), report.Listeners[0].LagBlocks)
+	require.Len(t, report.Chains, 2)
+	assert.True(t, report.Chains[0].RPCReachable)
+	assert.True(t, report.Chains[0].SignerAvailable)
+	require.NotNil(t, report.Backlog)
+	assert.Equal(t, 10, report.Backlog.Max)
+}
+
+func TestStaleListener(t *testing.T) {
+	probe := newFakeProbe()
+	probe.listeners[1].LastPolledAt = time.Now().Add(-2 * time.Minute)
+	server := NewServer(testConfig(), probe)
+
+	code, report := get(t, server, "/healthz")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+	assert.False(t, report.Healthy)
+	assert.True(t, report.Listeners[0].Healthy)
+	assert.False(t, report.Listeners[1].Healthy)
+	assert.Contains(t, report.Listeners[1].Problem, "no finished poll")
+
+	// A paused listener is expected not to poll
+	probe.listeners[1].Paused = true
+	code, _ = get(t, server, "/healthz")
+	assert.Equal(t, http.StatusOK, code)
+}
+
+func TestListenerNotPolledYet(t *testing.T) {
+	probe := newFakeProbe()
+	probe.listeners[0].LastPolledAt = time.Time{}
+
+	// Measured from the server's start until the first poll finishes
+	assert.True(t, NewServer(testConfig(), probe).Liveness().Healthy)
+
+	server := NewServer(testConfig(), probe)
+	server.started = time.Now().Add(-time.Hour)
+	assert.False(t, server.Liveness().Healthy)
+}
+
+func TestNotReady(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(*fakeProbe)
+	}{
+		{"lagging listener", func(p *fakeProbe) { p.heads["Base"] = 2_000 }},
+		{"unreachable RPC", func(p *fakeProbe) { p.rpcErrs["Starknet"] = errors.New("connection refused") }},
+		{"missing signer", func(p *fakeProbe) {
+			p.signerErr["Base"] = errors.New("SOLVER_PRIVATE_KEY environment variable not set")
+		}},
+		{"backlog", func(p *fakeProbe) { p.backlog = 11 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			probe := newFakeProbe()
+			tt.change(probe)
+			server := NewServer(testConfig(), probe)
+
+			code, report := get(t, server, "/readyz")
+			assert.Equal(t, http.StatusServiceUnavailable, code)
+			assert.False(t, report.Healthy)
+
+			// Liveness does not depend on chains or backlog
+			code, _ = get(t, server, "/healthz")
+			assert.Equal(t, http.StatusOK, code)
+		})
+	}
+
+	probe := newFakeProbe()
+	probe.rpcErrs["Starknet"] = errors.New("connection refused")
+	report := NewServer(testConfig(), probe).Readiness(context.Background())
+	assert.False(t, report.Chains[1].RPCReachable)
+	assert.True(t, report.Chains[1].SignerAvailable)
+	assert.Contains(t, report.Chains[1].Problem, "connection refused")
+	// Without a head the listener's lag is unknown, not counted against it
+	assert.True(t, report.Listeners[1].Healthy)
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("HEALTH_LISTEN_ADDR", "")
+	assert.False(t, ConfigFromEnv().Enabled())
+
+	t.Setenv("HEALTH_LISTEN_ADDR", "0.0.0.0:8081")
+	t.Setenv("HEALTH_MAX_POLL_AGE_MS", "30000")
+	t.Setenv("HEALTH_MAX_LAG_BLOCKS", "20")
+	cfg := ConfigFromEnv()
+	assert.True(t, cfg.Enabled())
+	assert.Equal(t, 30*time.Second, cfg.MaxPollAge)
+	assert.Equal(t, uint64(20), cfg.MaxLagBlocks)
+	assert.Equal(t, DefaultMaxBacklog, cfg.MaxBacklog)
+	assert.Equal(t, DefaultCheckTimeout, cfg.CheckTimeout)
+}
@@ -0,0 +1,380 @@
+package history
+
+// Module: Order history
+// - One record per order ID: route, tokens and amounts, rule verdicts, transactions, timings and expected profit
+// - Kept in an append-only JSON-lines file (ORDER_HISTORY_FILE); the last line for an order wins
+// - The file is compacted when the solver opens it; readers such as the CLI load it without writing
+// - Handlers attach transactions to the orders carried by their context, as they do for trace spans
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/orderlog"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+const (
+	// DefaultFile is where the history is kept when ORDER_HISTORY_FILE is unset
+	DefaultFile = "state/order_history/orders.jsonl"
+
+	// maxLineBytes bounds one record line when reading the file back
+	maxLineBytes = 1 << 20
+)
+
+// Status is where an order stands in the solver
+type Status string
+
+const (
+	StatusReceived  Status = "RECEIVED"  // detected and being processed
+	StatusSkipped   Status = "SKIPPED"   // opened by the solver itself, or already complete
+	StatusRejected  Status = "REJECTED"  // blocked, failed a rule or could not be reserved
+	StatusFilled    Status = "FILLED"    // every leg filled, settlement pending
+	StatusSettled   Status = "SETTLED"   // every leg settled
+	StatusFailed    Status = "FAILED"    // a fill or settlement failed
+	StatusSimulated Status = "SIMULATED" // simulated in dry-run mode instead of filled; Error is set if it would revert
+)
+
+// ErrNotFound is returned when no record exists for an order ID
+var ErrNotFound = errors.New("order not found in history")
+
+// Amount is one token amount of an order
+type Amount struct {
+	Chain  string `json:"chain"`
+	Token  string `json:"token"`
+	Amount string `json:"amount"`
+}
+
+// RuleVerdict is the result of one rule evaluated for the order
+type RuleVerdict struct {
+	Rule   string `json:"rule"`
+	Passed bool   `json:"passed"`
+	Reason string `json:"reason"`
+}
+
+// Tx is a transaction the solver sent for the order
+type Tx struct {
+	Stage  string    `json:"stage"`
+	Chain  string    `json:"chain"`
+	Hash   string    `json:"hash"`
+	SentAt time.Time `json:"sentAt"`
+}
+
+// Record is everything the solver knows about one order
+type Record struct {
+	OrderID      string        `json:"orderId"`
+	Status       Status        `json:"status"`
+	Origin       string        `json:"origin"`
+	Destinations []string      `json:"destinations"`
+	Sender       string        `json:"sender"`
+	MaxSpent     []Amount      `json:"maxSpent"`
+	MinReceived  []Amount      `json:"minReceived"`
+	Rules        []RuleVerdict `json:"rules"`
+	Txs          []Tx          `json:"txs"`
+	// Profit is MinReceived minus MaxSpent in token units, assuming like-for-like tokens as the profitability rule does
+	Profit string `json:"profit"`
+	Error  string `json:"error,omitempty"`
+	// ErrorKind classifies Error: network, validation, revert, deadline, insufficient_funds, competitor_filled or unknown
+	ErrorKind failure.Kind `json:"errorKind,omitempty"`
+	CreatedAt time.Time    `json:"createdAt"`
+	FilledAt  time.Time    `json:"filledAt,omitzero"`
+	SettledAt time.Time    `json:"settledAt,omitzero"`
+	UpdatedAt time.Time    `json:"updatedAt"`
+}
+
+// NewRecord describes a newly detected order
+func NewRecord(args *types.ParsedArgs, now time.Time) Record {
+	origin, destinations := orderlog.Route(args)
+	return Record{
+		OrderID:      args.OrderID,
+		Status:       StatusReceived,
+		Origin:       origin,
+		Destinations: destinations,
+		Sender:       args.SenderAddress,
+		MaxSpent:     amounts(args.ResolvedOrder.MaxSpent),
+		MinReceived:  amounts(args.ResolvedOrder.MinReceived),
+		Rules:        nil,
+		Txs:          nil,
+		Profit:       expectedProfit(args).String(),
+		Error:        "",
+		ErrorKind:    failure.KindNone,
+		CreatedAt:    now,
+		FilledAt:     time.Time{},
+		SettledAt:    time.Time{},
+		UpdatedAt:    now,
+	}
+}
+
+// Duration is how long the order took from detection to settlement, or so far if it is not settled
+func (r Record) Duration() time.Duration {
+	if !r.SettledAt.IsZero() {
+		return r.SettledAt.Sub(r.CreatedAt)
+	}
+	return r.UpdatedAt.Sub(r.CreatedAt)
+}
+
+// involves reports whether the order starts or fills on chain
+func (r Record) involves(chain string) bool {
+	return strings.EqualFold(r.Origin, chain) || slices.ContainsFunc(r.Destinations, func(d string) bool {
+		return strings.EqualFold(d, chain)
+	})
+}
+
+func amounts(outputs []types.Output) []Amount {
+	list := make([]Amount, 0, len(outputs))
+	for _, output := range outputs {
+		amount := "0"
+		if output.Amount != nil {
+			amount = output.Amount.String()
+		}
+		list = append(list, Amount{Chain: orderlog.ChainName(output.ChainID), Token: output.Token, Amount: amount})
+	}
+	return list
+}
+
+func expectedProfit(args *types.ParsedArgs) *big.Int {
+	profit := new(big.Int)
+	for _, received := range args.ResolvedOrder.MinReceived {
+		if received.Amount != nil {
+			profit.Add(profit, received.Amount)
+		}
+	}
+	for _, spent := range args.ResolvedOrder.MaxSpent {
+		if spent.Amount != nil {
+			profit.Sub(profit, spent.Amount)
+		}
+	}
+	return profit
+}
+
+// Filter selects records; zero fields match everything
+type Filter struct {
+	// Chain matches orders whose origin or any destination is this network
+	Chain  string
+	Status Status
+	// Since and Until bound the time the order was first seen
+	Since time.Time
+	Until time.Time
+	// Limit caps the number of records returned, newest first
+	Limit int
+}
+
+// Matches reports whether the record passes the filter
+func (f Filter) Matches(r Record) bool {
+	switch {
+	case f.Chain != "" && !r.involves(f.Chain):
+		return false
+	case f.Status != "" && !strings.EqualFold(string(f.Status), string(r.Status)):
+		return false
+	case !f.Since.IsZero() && r.CreatedAt.Before(f.Since):
+		return false
+	case !f.Until.IsZero() && !r.CreatedAt.Before(f.Until):
+		return false
+	}
+	return true
+}
+
+// Config configures the order history
+type Config = orderlog.FileConfig
+
+// ConfigFromEnv reads ORDER_HISTORY_FILE and ORDER_HISTORY_ENABLED
+func ConfigFromEnv() Config {
+	return orderlog.FileConfigFromEnv("ORDER_HISTORY_FILE", "ORDER_HISTORY_ENABLED", DefaultFile)
+}
+
+// Store holds every order record in memory and appends each change to its file
+type Store struct {
+	mu      sync.RWMutex
+	file    *os.File
+	records map[string]*Record
+	now     func() time.Time
+}
+
+// Open loads the history at path, compacts the file to one line per order and keeps it open for appending
+func Open(path string) (*Store, error) {
+	store, err := Load(path)
+	if err != nil {
+		return nil, err
+	}
+	if err := orderlog.Replace(path, store.sorted()); err != nil {
+		return nil, fmt.Errorf("failed to compact order history file: %w", err)
+	}
+	file, err := orderlog.OpenAppend(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open order history file: %w", err)
+	}
+	store.file = file
+	return store, nil
+}
+
+// Load reads the history at path without writing to it; a missing file is an empty history
+func Load(path string) (*Store, error) {
+	records, err := orderlog.ReadFile(path, maxLineBytes, func(record Record) bool { return record.OrderID != "" })
+	if err != nil {
+		return nil, fmt.Errorf("failed to read order history file: %w", err)
+	}
+	store := &Store{mu: sync.RWMutex{}, file: nil, records: make(map[string]*Record, len(records)), now: time.Now}
+	// The last line for an order wins
+	for i := range records {
+		store.records[records[i].OrderID] = &records[i]
+	}
+	return store, nil
+}
+
+// Close stops appending to the file
+func (s *Store) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.file == nil {
+		return nil
+	}
+	err := s.file.Close()
+	s.file = nil
+	return err
+}
+
+// Begin records a newly detected order; an order seen before (for example on a re-scan) keeps its record
+func (s *Store) Begin(args *types.ParsedArgs) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if record, ok := s.records[args.OrderID]; ok {
+		record.Status = StatusReceived
+		record.Error = ""
+		record.UpdatedAt = s.now()
+		s.appendLocked(record)
+		return
+	}
+	record := NewRecord(args, s.now())
+	s.records[args.OrderID] = &record
+	s.appendLocked(&record)
+}
+
+// Update applies change to the record of orderID and persists it; unknown orders are ignored
+func (s *Store) Update(orderID string, change func(record *Record)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	record, ok := s.records[orderID]
+	if !ok {
+		return
+	}
+	change(record)
+	record.UpdatedAt = s.now()
+	s.appendLocked(record)
+}
+
+// SetStatus moves the order to status, stamping the first fill and settlement times; err, if any, is kept as the reason
+func (s *Store) SetStatus(orderID string, status Status, err error) {
+	s.Update(orderID, func(record *Record) {
+		record.Status = status
+		record.Error = ""
+		record.ErrorKind = failure.KindOf(err)
+		if err != nil {
+			record.Error = err.Error()
+		}
+		if (status == StatusFilled || status == StatusSettled) && record.FilledAt.IsZero() {
+			record.FilledAt = s.now()
+		}
+		if status == StatusSettled && record.SettledAt.IsZero() {
+			record.SettledAt = s.now()
+		}
+	})
+}
+
+// SetRules stores the rule verdicts of the latest evaluation of the order
+func (s *Store) SetRules(orderID string, verdicts []RuleVerdict) {
+	s.Update(orderID, func(record *Record) {
+		record.Rules = verdicts
+	})
+}
+
+// AddTx records a transaction sent for the order at stage on chain
+func (s *Store) AddTx(orderID, stage, chain, hash string) {
+	s.Update(orderID, func(record *Record) {
+		record.Txs = append(record.Txs, Tx{Stage: stage, Chain: chain, Hash: hash, SentAt: s.now()})
+	})
+}
+
+// Get returns the record of orderID
+func (s *Store) Get(orderID string) (Record, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	if record, ok := s.records[orderID]; ok {
+		return *record, nil
+	}
+	for id, record := range s.records {
+		if strings.EqualFold(id, orderID) {
+			return *record, nil
+		}
+	}
+	return Record{}, fmt.Errorf("%w: %s", ErrNotFound, orderID)
+}
+
+// List returns the records passing filter, newest first
+func (s *Store) List(filter Filter) []Record {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	matched := make([]Record, 0, len(s.records))
+	for _, record := range s.sorted() {
+		if filter.Matches(*record) {
+			matched = append(matched, *record)
+		}
+	}
+	slices.Reverse(matched)
+	if filter.Limit > 0 && len(matched) > filter.Limit {
+		matched = matched[:filter.Limit]
+	}
+	return matched
+}
+
+// sorted returns the records oldest first
+func (s *Store) sorted() []*Record {
+	records := make([]*Record, 0, len(s.records))
+	for _, record := range s.records {
+		records = append(records, record)
+	}
+	slices.SortFunc(records, func(a, b *Record) int {
+		if c := a.CreatedAt.Compare(b.CreatedAt); c != 0 {
+			return c
+		}
+		return strings.Compare(a.OrderID, b.OrderID)
+	})
+	return records
+}
+
+// appendLocked writes the record as one line; a failed write is logged, as losing history must not stop a fill
+func (s *Store) appendLocked(record *Record) {
+	if s.file == nil {
+		return
+	}
+	if err := orderlog.WriteLine(s.file, record); err != nil {
+		logutil.Default().Warn("⚠️  Failed to write order history", logutil.OrderID(record.OrderID), logutil.Err(err))
+	}
+}
+
+// WithOrders returns a context whose transactions RecordTx attributes to orders in store
+func WithOrders(ctx context.Context, store *Store, orders ...*types.ParsedArgs) context.Context {
+	if store == nil || len(orders) == 0 {
+		return ctx
+	}
+	return orderlog.WithOrders(ctx, store, orders)
+}
+
+// RecordTx attributes a transaction to the orders carried by ctx; it does nothing without them
+func RecordTx(ctx context.Context, stage, chain, hash string) {
+	store, orders, ok := orderlog.OrdersFrom[*Store](ctx)
+	if !ok {
+		return
+	}
+	for _, order := range orders {
+		store.AddTx(order.OrderID, stage, chain, hash)
+	}
+}
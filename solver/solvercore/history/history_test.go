@@ -0,0 +1,186 @@
+package history
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+const (
+	originChainID      = 11
+	destinationChainID = 22
+)
+
+func order(orderID string) *types.ParsedArgs {
+	return &types.ParsedArgs{
+		OrderID:       orderID,
+		SenderAddress: "0xaaaa",
+		ResolvedOrder: types.ResolvedCrossChainOrder{
+			OriginChainID: big.NewInt(originChainID),
+			MaxSpent: []types.Output{
+				{Token: "0xbb", Amount: big.NewInt(990), Recipient: "0xcc", ChainID: big.NewInt(destinationChainID)},
+			},
+			MinReceived: []types.Output{
+				{Token: "0xdd", Amount: big.NewInt(1000), Recipient: "0x00", ChainID: big.NewInt(originChainID)},
+			},
+			FillInstructions: []types.FillInstruction{
+				{DestinationChainID: big.NewInt(destinationChainID), DestinationSettler: "0xee"},
+			},
+		},
+	}
+}
+
+// openAt opens a store at path whose clock reads *now
+func openAt(t *testing.T, path string, now *time.Time) *Store {
+	t.Helper()
+	store, err := Open(path)
+	require.NoError(t, err)
+	store.now = func() time.Time { return *now }
+	t.Cleanup(func() { _ = store.Close() })
+	return store
+}
+
+func TestStoreRecordsOrderLifecycle(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "history", "orders.jsonl")
+	now := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	store := openAt(t, path, &now)
+
+	store.Begin(order("0x01"))
+	store.SetRules("0x01", []RuleVerdict{{Rule: "Profitability", Passed: true, Reason: ""}})
+	now = now.Add(10 * time.Second)
+	ctx := WithOrders(context.Background(), store, order("0x01"))
+	RecordTx(ctx, "fill", "Base", "0xf111")
+	store.SetStatus("0x01", StatusFilled, nil)
+	now = now.Add(20 * time.Second)
+	RecordTx(ctx, "settle", "Base", "0x5e77")
+	store.SetStatus("0x01", StatusSettled, nil)
+	store.Begin(order("0x02"))
+	store.SetStatus("0x02", StatusRejected, fmt.Errorf("order validation failed: %w", failure.ErrDeadlinePassed))
+	require.NoError(t, store.Close())
+
+	// Everything survives a reload from the file
+	loaded, err := Load(path)
+	require.NoError(t, err)
+	record, err := loaded.Get("0x01")
+	require.NoError(t, err)
+
+	assert.Equal(t, StatusSettled, record.Status)
+	assert.Equal(t, logutil.NetworkNameByChainID(originChainID), record.Origin)
+	assert.Equal(t, []string{logutil.NetworkNameByChainID(destinationChainID)}, record.Destinations)
+	assert.Equal(t, "10", record.Profit)
+	assert.Equal(t, "990", record.MaxSpent[0].Amount)
+	require.Len(t, record.Rules, 1)
+	require.Len(t, record.Txs, 2)
+	assert.Equal(t, "fill", record.Txs[0].Stage)
+	assert.Equal(t, "0x5e77", record.Txs[1].Hash)
+	assert.Equal(t, 30*time.Second, record.Duration())
+	assert.Equal(t, 10*time.Second, record.FilledAt.Sub(record.CreatedAt), "the fill time is kept once settled")
+	assert.Equal(t, failure.KindNone, record.ErrorKind)
+
+	rejected, err := loaded.Get("0x02")
+	require.NoError(t, err)
+	assert.Equal(t, failure.KindDeadline, rejected.ErrorKind)
+}
+
+func TestStoreCompactsOnOpen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "orders.jsonl")
+	now := time.Unix(1_700_000_000, 0).UTC()
+	store := openAt(t, path, &now)
+	store.Begin(order("0x01"))
+	store.SetStatus("0x01", StatusFailed, errors.New("fill reverted"))
+	store.Begin(order("0x02"))
+	require.NoError(t, store.Close())
+
+	// A crash mid-write leaves a torn last line, which is skipped
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	require.NoError(t, err)
+	_, err = file.WriteString(`{"orderId":"0x03","sta`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	reopened := openAt(t, path, &now)
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Len(t, strings.Split(strings.TrimSpace(string(data)), "\n"), 2, "one line per order")
+
+	record, err := reopened.Get("0x01")
+	require.NoError(t, err)
+	assert.Equal(t, StatusFailed, record.Status)
+	assert.Equal(t, "fill reverted", record.Error)
+	assert.Equal(t, failure.KindUnknown, record.ErrorKind)
+	_, err = reopened.Get("0x03")
+	assert.ErrorIs(t, err, ErrNotFound)
+}
+
+func TestStoreList(t *testing.T) {
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	store := openAt(t, filepath.Join(t.TempDir(), "orders.jsonl"), &now)
+	for _, id := range []string{"0x01", "0x02", "0x03"} {
+		store.Begin(order(id))
+		now = now.Add(time.Hour)
+	}
+	store.SetStatus("0x02", StatusFailed, errors.New("reverted"))
+
+	ids := func(records []Record) []string {
+		list := make([]string, len(records))
+		for i, record := range records {
+			list[i] = record.OrderID
+		}
+		return list
+	}
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, []string{"0x03", "0x02", "0x01"}, ids(store.List(Filter{})), "newest first")
+	assert.Equal(t, []string{"0x03", "0x02"}, ids(store.List(Filter{Limit: 2})))
+	assert.Equal(t, []string{"0x02"}, ids(store.List(Filter{Status: "failed"})))
+	assert.Equal(t, []string{"0x02"}, ids(store.List(Filter{Since: start.Add(time.Hour), Until: start.Add(2 * time.Hour)})))
+	assert.Len(t, store.List(Filter{Chain: strings.ToUpper(logutil.NetworkNameByChainID(destinationChainID))}), 3)
+	assert.Empty(t, store.List(Filter{Chain: "nowhere"}))
+}
+
+func TestGetIgnoresCase(t *testing.T) {
+	now := time.Now()
+	store := openAt(t, filepath.Join(t.TempDir(), "orders.jsonl"), &now)
+	store.Begin(order("0xABCD"))
+
+	record, err := store.Get("0xabcd")
+	require.NoError(t, err)
+	assert.Equal(t, "0xABCD", record.OrderID)
+}
+
+func TestRecordTxWithoutOrders(t *testing.T) {
+	now := time.Now()
+	store := openAt(t, filepath.Join(t.TempDir(), "orders.jsonl"), &now)
+	store.Begin(order("0x01"))
+
+	RecordTx(context.Background(), "fill", "Base", "0xf111")
+	RecordTx(WithOrders(context.Background(), nil, order("0x01")), "fill", "Base", "0xf111")
+
+	record, err := store.Get("0x01")
+	require.NoError(t, err)
+	assert.Empty(t, record.Txs)
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("ORDER_HISTORY_FILE", "")
+	t.Setenv("ORDER_HISTORY_ENABLED", "")
+	cfg := ConfigFromEnv()
+	assert.True(t, cfg.Enabled())
+	assert.Equal(t, DefaultFile, cfg.File)
+
+	t.Setenv("ORDER_HISTORY_ENABLED", "false")
+	assert.False(t, ConfigFromEnv().Enabled())
+}
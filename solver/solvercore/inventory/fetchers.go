@@ -0,0 +1,53 @@
+package inventory
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// EVMBalanceFetcher reads ERC20 and native ETH balances of the solver on an EVM chain
+type EVMBalanceFetcher struct {
+	client *ethclient.Client
+	owner  common.Address
+}
+
+// NewEVMBalanceFetcher creates a fetcher for owner's balances using client
+func NewEVMBalanceFetcher(client *ethclient.Client, owner common.Address) *EVMBalanceFetcher {
+	return &EVMBalanceFetcher{client: client, owner: owner}
+}
+
+// Balance returns owner's balance of token; accepts both 20-byte and bytes32-padded addresses
+func (f *EVMBalanceFetcher) Balance(_ context.Context, token string) (*big.Int, error) {
+	if types.IsNativeToken(token) {
+		return ethutil.NativeBalance(f.client, f.owner)
+	}
+	tokenAddr, err := types.ToEVMAddress(token)
+	if err != nil {
+		return nil, fmt.Errorf("invalid EVM token address %s: %w", token, err)
+	}
+	return ethutil.ERC20Balance(f.client, tokenAddr, f.owner)
+}
+
+// StarknetBalanceFetcher reads ERC20 balances of the solver on Starknet; the native asset is the fee token
+type StarknetBalanceFetcher struct {
+	provider *rpc.Provider
+	owner    string
+}
+
+// NewStarknetBalanceFetcher creates a fetcher for owner's balances using provider
+func NewStarknetBalanceFetcher(provider *rpc.Provider, owner string) *StarknetBalanceFetcher {
+	return &StarknetBalanceFetcher{provider: provider, owner: owner}
+}
+
+// Balance returns owner's balance of token
+func (f *StarknetBalanceFetcher) Balance(_ context.Context, token string) (*big.Int, error) {
+	return starknetutil.ERC20Balance(f.provider, starknetutil.TokenContract(token), f.owner)
+}
@@ -0,0 +1,396 @@
+package inventory
+
+// Module: Solver inventory tracking
+// - Caches the solver's token balances per (chain, token)
+// - Reserves MaxSpent amounts for accepted orders so concurrent orders cannot double-spend
+// - Periodically refreshes cached balances from chain
+// - Exposes a query API used by the rules engine instead of raw ERC20 balance calls
+// - Publishes every balance read from chain as a metric
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// DefaultRefreshInterval is used when a non-positive refresh interval is configured
+const DefaultRefreshInterval = 30 * time.Second
+
+// ErrInsufficientInventory is returned when available balance cannot cover a request
+var ErrInsufficientInventory = failure.Mark(errors.New("insufficient inventory"), failure.ErrInsufficientFunds)
+
+// BalanceFetcher reads the solver's on-chain balance of a token on a single chain
+type BalanceFetcher interface {
+	Balance(ctx context.Context, token string) (*big.Int, error)
+}
+
+// Key identifies a token on a specific chain
+type Key struct {
+	ChainID uint64
+	Token   string
+}
+
+// Position is a point-in-time view of a tracked token
+type Position struct {
+	ChainID   uint64    `json:"chainId"`
+	Token     string    `json:"token"`
+	Balance   *big.Int  `json:"balance"`
+	Reserved  *big.Int  `json:"reserved"`
+	Available *big.Int  `json:"available"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+type balanceEntry struct {
+	amount    *big.Int
+	updatedAt time.Time
+}
+
+type reservation struct {
+	key    Key
+	amount *big.Int
+}
+
+// Manager tracks solver balances and outstanding reservations
+type Manager struct {
+	mu              sync.Mutex
+	fetchers        map[uint64]BalanceFetcher
+	balances        map[Key]balanceEntry
+	reserved        map[Key]*big.Int
+	reservations    map[string][]reservation // orderID -> reserved amounts
+	refreshInterval time.Duration
+}
+
+// NewManager creates an inventory manager that refreshes balances every refreshInterval
+func NewManager(refreshInterval time.Duration) *Manager {
+	if refreshInterval <= 0 {
+		refreshInterval = DefaultRefreshInterval
+	}
+	return &Manager{
+		mu:              sync.Mutex{},
+		fetchers:        make(map[uint64]BalanceFetcher),
+		balances:        make(map[Key]balanceEntry),
+		reserved:        make(map[Key]*big.Int),
+		reservations:    make(map[string][]reservation),
+		refreshInterval: refreshInterval,
+	}
+}
+
+// RegisterChain sets the balance fetcher used for a chain
+func (m *Manager) RegisterChain(chainID uint64, fetcher BalanceFetcher) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.fetchers[chainID] = fetcher
+}
+
+// Track adds a token to the refresh set without waiting for an order to reference it
+func (m *Manager) Track(ctx context.Context, chainID uint64, token string) error {
+	_, err := m.balance(ctx, NewKey(chainID, token))
+	return err
+}
+
+// Balance returns the cached on-chain balance, fetching it on first use
+func (m *Manager) Balance(ctx context.Context, chainID uint64, token string) (*big.Int, error) {
+	return m.balance(ctx, NewKey(chainID, token))
+}
+
+// Available returns the balance minus outstanding reservations
+func (m *Manager) Available(ctx context.Context, chainID uint64, token string) (*big.Int, error) {
+	key := NewKey(chainID, token)
+	if _, err := m.balance(ctx, key); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.availableLocked(key), nil
+}
+
+// CanCover checks whether the outputs could be reserved right now, without reserving them
+func (m *Manager) CanCover(ctx context.Context, outputs []types.Output) error {
+	return m.CanCoverFor(ctx, "", outputs)
+}
+
+// CanCoverFor is CanCover for outputs of orderID: the order's own reservation counts as available,
+// since reserving for it again replaces that reservation
+func (m *Manager) CanCoverFor(ctx context.Context, orderID string, outputs []types.Output) error {
+	required, err := m.prepare(ctx, outputs)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.checkLocked(required, m.reservations[orderID])
+}
+
+// Reserve atomically earmarks the outputs for orderID.
+// Reserving again for the same order replaces the previous reservation.
+func (m *Manager) Reserve(ctx context.Context, orderID string, outputs []types.Output) error {
+	required, err := m.prepare(ctx, outputs)
+	if err != nil {
+		return err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.releaseLocked(orderID)
+	if err := m.checkLocked(required, nil); err != nil {
+		return err
+	}
+
+	entries := make([]reservation, 0, len(required))
+	for key, amount := range required {
+		current := m.reserved[key]
+		if current == nil {
+			current = new(big.Int)
+		}
+		m.reserved[key] = new(big.Int).Add(current, amount)
+		entries = append(entries, reservation{key: key, amount: new(big.Int).Set(amount)})
+	}
+	m.reservations[orderID] = entries
+	return nil
+}
+
+// Release drops the reservation for orderID, e.g. after a failed fill
+func (m *Manager) Release(orderID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.releaseLocked(orderID)
+}
+
+// Commit drops the reservation for orderID and deducts the amounts from the cached balance,
+// reflecting tokens that have left the solver's wallet ahead of the next refresh
+func (m *Manager) Commit(orderID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, r := range m.reservations[orderID] {
+		if entry, ok := m.balances[r.key]; ok {
+			remaining := new(big.Int).Sub(entry.amount, r.amount)
+			if remaining.Sign() < 0 {
+				remaining.SetInt64(0)
+			}
+			m.balances[r.key] = balanceEntry{amount: remaining, updatedAt: entry.updatedAt}
+		}
+	}
+	m.releaseLocked(orderID)
+}
+
+// HasReservation reports whether orderID currently holds a reservation
+func (m *Manager) HasReservation(orderID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, ok := m.reservations[orderID]
+	return ok
+}
+
+// Snapshot returns all tracked positions sorted by chain and token
+func (m *Manager) Snapshot() []Position {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	positions := make([]Position, 0, len(m.balances))
+	for key, entry := range m.balances {
+		reserved := new(big.Int)
+		if r := m.reserved[key]; r != nil {
+			reserved.Set(r)
+		}
+		positions = append(positions, Position{
+			ChainID:   key.ChainID,
+			Token:     key.Token,
+			Balance:   new(big.Int).Set(entry.amount),
+			Reserved:  reserved,
+			Available: m.availableLocked(key),
+			UpdatedAt: entry.updatedAt,
+		})
+	}
+
+	sort.Slice(positions, func(i, j int) bool {
+		if positions[i].ChainID != positions[j].ChainID {
+			return positions[i].ChainID < positions[j].ChainID
+		}
+		return positions[i].Token < positions[j].Token
+	})
+	return positions
+}
+
+// Refresh re-reads every tracked balance from chain.
+// All keys are attempted; the first error encountered is returned.
+func (m *Manager) Refresh(ctx context.Context) error {
+	m.mu.Lock()
+	keys := make([]Key, 0, len(m.balances))
+	for key := range m.balances {
+		keys = append(keys, key)
+	}
+	m.mu.Unlock()
+
+	var firstErr error
+	for _, key := range keys {
+		if _, err := m.fetch(ctx, key); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+// RefreshKey re-reads a single balance from chain, e.g. right after the solver moved tokens
+func (m *Manager) RefreshKey(ctx context.Context, chainID uint64, token string) error {
+	_, err := m.fetch(ctx, NewKey(chainID, token))
+	return err
+}
+
+// Start refreshes balances every refresh interval until ctx is cancelled
+func (m *Manager) Start(ctx context.Context) {
+	ticker := time.NewTicker(m.refreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := m.Refresh(ctx); err != nil {
+				logutil.Default().Warn("⚠️  Inventory refresh failed", logutil.Err(err))
+			}
+		}
+	}
+}
+
+// NewKey normalises a token address so that padded bytes32 and short forms map to the same key
+func NewKey(chainID uint64, token string) Key {
+	return Key{ChainID: chainID, Token: normalizeToken(token)}
+}
+
+// normalizeToken agrees with types.IsNativeToken on what is native, so an empty token is keyed as an ERC20 like
+// the handlers treat it rather than against the native balance
+func normalizeToken(token string) string {
+	token = strings.TrimSpace(token)
+	if types.IsNativeToken(token) {
+		return types.NativeToken
+	}
+	trimmed := strings.TrimPrefix(strings.ToLower(token), "0x")
+	if trimmed == "" {
+		return strings.ToLower(token)
+	}
+	value, ok := new(big.Int).SetString(trimmed, 16)
+	if !ok {
+		return strings.ToLower(token)
+	}
+	return "0x" + value.Text(16)
+}
+
+// prepare aggregates outputs per key and makes sure each key has a cached balance
+func (m *Manager) prepare(ctx context.Context, outputs []types.Output) (map[Key]*big.Int, error) {
+	required := make(map[Key]*big.Int)
+	for _, output := range outputs {
+		if output.Amount == nil || output.Amount.Sign() == 0 {
+			continue
+		}
+		if output.ChainID == nil {
+			return nil, fmt.Errorf("output for token %s has no chain ID", output.Token)
+		}
+
+		key := NewKey(output.ChainID.Uint64(), output.Token)
+		if current, ok := required[key]; ok {
+			current.Add(current, output.Amount)
+		} else {
+			required[key] = new(big.Int).Set(output.Amount)
+		}
+	}
+
+	for key := range required {
+		if _, err := m.balance(ctx, key); err != nil {
+			return nil, err
+		}
+	}
+	return required, nil
+}
+
+// checkLocked fails unless every required amount is available once the own reservations are handed back
+func (m *Manager) checkLocked(required map[Key]*big.Int, own []reservation) error {
+	for key, amount := range required {
+		available := m.availableLocked(key)
+		for _, r := range own {
+			if r.key == key {
+				available.Add(available, r.amount)
+			}
+		}
+		if available.Cmp(amount) < 0 {
+			return fmt.Errorf("%w for token %s on %s: available %s, need %s", ErrInsufficientInventory,
+				key.Token, logutil.NetworkNameByChainID(key.ChainID), available.String(), amount.String())
+		}
+	}
+	return nil
+}
+
+func (m *Manager) availableLocked(key Key) *big.Int {
+	available := new(big.Int)
+	if entry, ok := m.balances[key]; ok {
+		available.Set(entry.amount)
+	}
+	if reserved := m.reserved[key]; reserved != nil {
+		available.Sub(available, reserved)
+	}
+	if available.Sign() < 0 {
+		available.SetInt64(0)
+	}
+	return available
+}
+
+func (m *Manager) releaseLocked(orderID string) {
+	for _, r := range m.reservations[orderID] {
+		current := m.reserved[r.key]
+		if current == nil {
+			continue
+		}
+		remaining := new(big.Int).Sub(current, r.amount)
+		if remaining.Sign() <= 0 {
+			delete(m.reserved, r.key)
+		} else {
+			m.reserved[r.key] = remaining
+		}
+	}
+	delete(m.reservations, orderID)
+}
+
+// balance returns the cached balance for key, fetching it when not yet tracked
+func (m *Manager) balance(ctx context.Context, key Key) (*big.Int, error) {
+	m.mu.Lock()
+	entry, ok := m.balances[key]
+	m.mu.Unlock()
+	if ok {
+		return new(big.Int).Set(entry.amount), nil
+	}
+	return m.fetch(ctx, key)
+}
+
+func (m *Manager) fetch(ctx context.Context, key Key) (*big.Int, error) {
+	m.mu.Lock()
+	fetcher, ok := m.fetchers[key.ChainID]
+	m.mu.Unlock()
+	if !ok {
+		return nil, fmt.Errorf("no balance fetcher registered for chain %d", key.ChainID)
+	}
+
+	amount, err := fetcher.Balance(ctx, key.Token)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch balance for token %s on chain %d: %w", key.Token, key.ChainID, err)
+	}
+
+	m.mu.Lock()
+	m.balances[key] = balanceEntry{amount: new(big.Int).Set(amount), updatedAt: time.Now()}
+	m.mu.Unlock()
+	metrics.SetTokenBalance(logutil.NetworkNameByChainID(key.ChainID), key.Token, amount)
+	return amount, nil
+}
@@ -0,0 +1,193 @@
+package inventory
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+const (
+	testChainID = uint64(84532)
+	testToken   = "0x1234567890123456789012345678901234567890"
+	// Same token padded to bytes32, as emitted by Open events
+	testTokenBytes32 = "0x0000000000000000000000001234567890123456789012345678901234567890"
+)
+
+// mockFetcher returns configurable balances and counts calls
+type mockFetcher struct {
+	mu       sync.Mutex
+	balances map[string]*big.Int
+	err      error
+	calls    int
+}
+
+func (f *mockFetcher) Balance(_ context.Context, token string) (*big.Int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls++
+	if f.err != nil {
+		return nil, f.err
+	}
+	if b, ok := f.balances[normalizeToken(token)]; ok {
+		return new(big.Int).Set(b), nil
+	}
+	return big.NewInt(0), nil
+}
+
+func (f *mockFetcher) set(token string, amount int64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.balances[normalizeToken(token)] = big.NewInt(amount)
+}
+
+func newTestManager(balance int64) (*Manager, *mockFetcher) {
+	fetcher := &mockFetcher{balances: map[string]*big.Int{}}
+	fetcher.set(testToken, balance)
+	m := NewManager(0)
+	m.RegisterChain(testChainID, fetcher)
+	return m, fetcher
+}
+
+func outputs(amount int64) []types.Output {
+	return []types.Output{{
+		Token:   testTokenBytes32,
+		Amount:  big.NewInt(amount),
+		ChainID: new(big.Int).SetUint64(testChainID),
+	}}
+}
+
+func TestNormalizeToken(t *testing.T) {
+	assert.Equal(t, normalizeToken(testToken), normalizeToken(testTokenBytes32))
+	assert.NotEqual(t, types.NativeToken, normalizeToken(""))
+	assert.NotEqual(t, types.NativeToken, normalizeToken("0x"))
+	assert.Equal(t, "0x0", normalizeToken("0x0000000000000000000000000000000000000000"))
+	assert.Equal(t, types.NativeToken, normalizeToken(types.NativeToken))
+}
+
+func TestReserveAndRelease(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("reservations reduce availability", func(t *testing.T) {
+		m, _ := newTestManager(1000)
+
+		require.NoError(t, m.Reserve(ctx, "order-1", outputs(600)))
+		available, err := m.Available(ctx, testChainID, testToken)
+		require.NoError(t, err)
+		assert.Equal(t, int64(400), available.Int64())
+
+		err = m.Reserve(ctx, "order-2", outputs(600))
+		require.Error(t, err)
+		assert.True(t, errors.Is(err, ErrInsufficientInventory))
+		assert.False(t, m.HasReservation("order-2"))
+	})
+
+	t.Run("release frees the reservation", func(t *testing.T) {
+		m, _ := newTestManager(1000)
+
+		require.NoError(t, m.Reserve(ctx, "order-1", outputs(600)))
+		m.Release("order-1")
+		assert.False(t, m.HasReservation("order-1"))
+		require.NoError(t, m.Reserve(ctx, "order-2", outputs(1000)))
+	})
+
+	t.Run("re-reserving the same order replaces it", func(t *testing.T) {
+		m, _ := newTestManager(1000)
+
+		require.NoError(t, m.Reserve(ctx, "order-1", outputs(600)))
+		require.NoError(t, m.Reserve(ctx, "order-1", outputs(900)))
+		available, err := m.Available(ctx, testChainID, testToken)
+		require.NoError(t, err)
+		assert.Equal(t, int64(100), available.Int64())
+	})
+
+	t.Run("an order's own reservation counts as available to it", func(t *testing.T) {
+		m, _ := newTestManager(1000)
+
+		require.NoError(t, m.Reserve(ctx, "order-1", outputs(600)))
+		require.NoError(t, m.CanCoverFor(ctx, "order-1", outputs(600)))
+		require.ErrorIs(t, m.CanCover(ctx, outputs(600)), ErrInsufficientInventory)
+		require.ErrorIs(t, m.CanCoverFor(ctx, "order-2", outputs(600)), ErrInsufficientInventory)
+	})
+
+	t.Run("commit deducts from the cached balance", func(t *testing.T) {
+		m, _ := newTestManager(1000)
+
+		require.NoError(t, m.Reserve(ctx, "order-1", outputs(600)))
+		m.Commit("order-1")
+		balance, err := m.Balance(ctx, testChainID, testToken)
+		require.NoError(t, err)
+		assert.Equal(t, int64(400), balance.Int64())
+		assert.False(t, m.HasReservation("order-1"))
+	})
+
+	t.Run("native outputs are reserved against the native balance", func(t *testing.T) {
+		m, fetcher := newTestManager(0)
+		fetcher.set(types.NativeToken, 10)
+		native := []types.Output{{Token: types.NativeToken, Amount: big.NewInt(8), ChainID: new(big.Int).SetUint64(testChainID)}}
+		require.NoError(t, m.Reserve(ctx, "order-1", native))
+
+		// An all-zero bytes32 token is the same native asset
+		native[0].Token = "0x0000000000000000000000000000000000000000000000000000000000000000"
+		require.ErrorIs(t, m.Reserve(ctx, "order-2", native), ErrInsufficientInventory)
+	})
+
+	t.Run("unknown chain fails", func(t *testing.T) {
+		m, _ := newTestManager(1000)
+		out := outputs(1)
+		out[0].ChainID = big.NewInt(1)
+		require.Error(t, m.CanCover(ctx, out))
+	})
+
+	t.Run("concurrent reservations never oversubscribe", func(t *testing.T) {
+		m, _ := newTestManager(1000)
+
+		var wg sync.WaitGroup
+		var mu sync.Mutex
+		accepted := 0
+		for i := 0; i < 20; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				if err := m.Reserve(ctx, big.NewInt(int64(i)).String(), outputs(100)); err == nil {
+					mu.Lock()
+					accepted++
+					mu.Unlock()
+				}
+			}(i)
+		}
+		wg.Wait()
+		assert.Equal(t, 10, accepted)
+	})
+}
+
+func TestRefreshAndSnapshot(t *testing.T) {
+	ctx := context.Background()
+	m, fetcher := newTestManager(1000)
+
+	require.NoError(t, m.Track(ctx, testChainID, testToken))
+	require.NoError(t, m.Reserve(ctx, "order-1", outputs(250)))
+
+	fetcher.set(testToken, 5000)
+	require.NoError(t, m.Refresh(ctx))
+
+	snapshot := m.Snapshot()
+	require.Len(t, snapshot, 1)
+	assert.Equal(t, testChainID, snapshot[0].ChainID)
+	assert.Equal(t, int64(5000), snapshot[0].Balance.Int64())
+	assert.Equal(t, int64(250), snapshot[0].Reserved.Int64())
+	assert.Equal(t, int64(4750), snapshot[0].Available.Int64())
+
+	fetcher.err = errors.New("rpc down")
+	assert.Error(t, m.Refresh(ctx))
+	// Cached balance survives a failed refresh
+	balance, err := m.Balance(ctx, testChainID, testToken)
+	require.NoError(t, err)
+	assert.Equal(t, int64(5000), balance.Int64())
+}
@@ -0,0 +1,261 @@
+package logutil
+
+// Module: Structured logger used throughout solvercore
+// - Logger is injected into the solver manager, listeners, chain handlers and rules
+// - LOG_FORMAT selects json, logfmt or the coloured console renderer (text, the default)
+// - LOG_LEVEL selects debug, info, warn or error
+// - Standard fields name the chain, order ID, block, tx hash and stage of a log line
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// Field keys shared by every component, so log lines can be filtered the same way everywhere
+const (
+	FieldChain            = "chain"
+	FieldDestinationChain = "destination_chain"
+	FieldOrderID          = "order_id"
+	FieldBlock            = "block"
+	FieldTxHash           = "tx_hash"
+	FieldStage            = "stage"
+	FieldError            = "error"
+	FieldErrorKind        = "error_kind"
+)
+
+// Log formats accepted by New
+const (
+	FormatText   = "text" // coloured console lines with network tags
+	FormatLogfmt = "logfmt"
+	FormatJSON   = "json"
+)
+
+// Logger writes leveled log lines with structured fields. Fields are key/value pairs or slog.Attr values,
+// usually built with the helpers below.
+type Logger interface {
+	Debug(msg string, fields ...any)
+	Info(msg string, fields ...any)
+	Warn(msg string, fields ...any)
+	Error(msg string, fields ...any)
+	// With returns a logger that adds fields to every line
+	With(fields ...any) Logger
+}
+
+// New creates a logger writing to w at level ("debug", "info", "warn" or "error") in format
+// ("text", "logfmt" or "json"). Empty values select info and text.
+func New(w io.Writer, level, format string) (Logger, error) {
+	lvl, err := parseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	var handler slog.Handler
+	switch strings.ToLower(format) {
+	case "", FormatText, "console":
+		handler = newConsoleHandler(w, lvl)
+	case FormatLogfmt:
+		handler = slog.NewTextHandler(w, &slog.HandlerOptions{AddSource: false, Level: lvl, ReplaceAttr: nil})
+	case FormatJSON:
+		handler = slog.NewJSONHandler(w, &slog.HandlerOptions{AddSource: false, Level: lvl, ReplaceAttr: nil})
+	default:
+		return nil, fmt.Errorf("unknown log format %q (want %s, %s or %s)", format, FormatText, FormatLogfmt, FormatJSON)
+	}
+	return slogLogger{logger: slog.New(handler)}, nil
+}
+
+func parseLevel(level string) (slog.Level, error) {
+	switch strings.ToLower(level) {
+	case "debug", "trace":
+		return slog.LevelDebug, nil
+	case "", "info":
+		return slog.LevelInfo, nil
+	case "warn", "warning":
+		return slog.LevelWarn, nil
+	case "error":
+		return slog.LevelError, nil
+	default:
+		return slog.LevelInfo, fmt.Errorf("unknown log level %q (want debug, info, warn or error)", level)
+	}
+}
+
+var (
+	defaultMu     sync.RWMutex
+	defaultLogger Logger = slogLogger{logger: slog.New(newConsoleHandler(stdout{}, slog.LevelInfo))}
+)
+
+// Default returns the process-wide logger, used by components that were not given one
+func Default() Logger {
+	defaultMu.RLock()
+	defer defaultMu.RUnlock()
+	return defaultLogger
+}
+
+// SetDefault replaces the process-wide logger
+func SetDefault(logger Logger) {
+	defaultMu.Lock()
+	defer defaultMu.Unlock()
+	defaultLogger = logger
+}
+
+// Chain names the network a log line is about
+func Chain(name string) slog.Attr { return slog.String(FieldChain, name) }
+
+// OrderID names the order a log line is about
+func OrderID(orderID string) slog.Attr { return slog.String(FieldOrderID, orderID) }
+
+// Block names the block a log line is about
+func Block(number uint64) slog.Attr { return slog.Uint64(FieldBlock, number) }
+
+// TxHash names the transaction a log line is about
+func TxHash(hash string) slog.Attr { return slog.String(FieldTxHash, hash) }
+
+// Stage names the step of order processing a log line comes from, e.g. "fill" or "settle"
+func Stage(stage string) slog.Attr { return slog.String(FieldStage, stage) }
+
+// Err attaches an error to a log line
+func Err(err error) slog.Attr {
+	if err == nil {
+		return slog.String(FieldError, "")
+	}
+	return slog.String(FieldError, err.Error())
+}
+
+// ErrKind attaches the failure kind of an error (network, revert, ...) to a log line
+func ErrKind(err error) slog.Attr {
+	return slog.String(FieldErrorKind, string(failure.KindOf(err)))
+}
+
+// Route names an order's origin and destination chains
+func Route(originChainID, destChainID uint64) []any {
+	return []any{
+		Chain(NetworkNameByChainID(originChainID)),
+		slog.String(FieldDestinationChain, NetworkNameByChainID(destChainID)),
+	}
+}
+
+// OrderFields returns the order ID and, when known, the route of args' order (first destination)
+func OrderFields(args *types.ParsedArgs) []any {
+	fields := []any{OrderID(args.OrderID)}
+	if args.ResolvedOrder.OriginChainID != nil && len(args.ResolvedOrder.FillInstructions) > 0 {
+		if dest := args.ResolvedOrder.FillInstructions[0].DestinationChainID; dest != nil {
+			fields = append(fields, Route(args.ResolvedOrder.OriginChainID.Uint64(), dest.Uint64())...)
+		}
+	}
+	return fields
+}
+
+// slogLogger adapts a *slog.Logger to Logger
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+func (l slogLogger) Debug(msg string, fields ...any) { l.logger.Debug(msg, fields...) }
+func (l slogLogger) Info(msg string, fields ...any)  { l.logger.Info(msg, fields...) }
+func (l slogLogger) Warn(msg string, fields ...any)  { l.logger.Warn(msg, fields...) }
+func (l slogLogger) Error(msg string, fields ...any) { l.logger.Error(msg, fields...) }
+
+func (l slogLogger) With(fields ...any) Logger {
+	return slogLogger{logger: l.logger.With(fields...)}
+}
+
+// stdout writes to whatever os.Stdout currently is, so redirecting it also redirects the default logger
+type stdout struct{}
+
+func (stdout) Write(p []byte) (int, error) { return os.Stdout.Write(p) }
+
+// consoleHandler renders lines for people: the network tag, the message, the error, a shortened
+// order ID and then the remaining fields as key=value. Groups are flattened.
+type consoleHandler struct {
+	mu    *sync.Mutex
+	w     io.Writer
+	level slog.Leveler
+	attrs []slog.Attr
+}
+
+func newConsoleHandler(w io.Writer, level slog.Leveler) *consoleHandler {
+	return &consoleHandler{mu: &sync.Mutex{}, w: w, level: level, attrs: nil}
+}
+
+func (h *consoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level >= h.level.Level()
+}
+
+func (h *consoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	merged := make([]slog.Attr, 0, len(h.attrs)+len(attrs))
+	merged = append(merged, h.attrs...)
+	merged = append(merged, attrs...)
+	return &consoleHandler{mu: h.mu, w: h.w, level: h.level, attrs: merged}
+}
+
+func (h *consoleHandler) WithGroup(_ string) slog.Handler {
+	return h
+}
+
+func (h *consoleHandler) Handle(_ context.Context, record slog.Record) error {
+	var chain, destination, orderID, errText string
+	rest := make([]slog.Attr, 0, len(h.attrs)+record.NumAttrs())
+	collect := func(attr slog.Attr) bool {
+		switch attr.Key {
+		case FieldChain:
+			chain = attr.Value.String()
+		case FieldDestinationChain:
+			destination = attr.Value.String()
+		case FieldOrderID:
+			orderID = attr.Value.String()
+		case FieldError:
+			errText = attr.Value.String()
+		default:
+			rest = append(rest, attr)
+		}
+		return true
+	}
+	for _, attr := range h.attrs {
+		collect(attr)
+	}
+	record.Attrs(collect)
+
+	var line strings.Builder
+	switch {
+	case chain != "" && destination != "":
+		line.WriteString(strings.TrimSpace(Prefix(chain)) + " → " + Prefix(destination))
+	case chain != "":
+		line.WriteString(Prefix(chain))
+	}
+	line.WriteString(strings.TrimRight(record.Message, "\n"))
+	if errText != "" {
+		line.WriteString(": " + errText)
+	}
+	if orderID != "" {
+		line.WriteString(" (Order: " + shortOrderID(orderID) + ")")
+	}
+	for _, attr := range rest {
+		value := attr.Value.String()
+		if value == "" || strings.ContainsAny(value, " =\"") {
+			value = strconv.Quote(value)
+		}
+		line.WriteString(" " + attr.Key + "=" + value)
+	}
+	line.WriteString("\n")
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	_, err := io.WriteString(h.w, line.String())
+	return err
+}
+
+// shortOrderID keeps the first bytes of an order ID, enough to tell orders apart on a console
+func shortOrderID(orderID string) string {
+	const shown = 8
+	if len(orderID) <= shown {
+		return orderID
+	}
+	return orderID[:shown] + "..."
+}
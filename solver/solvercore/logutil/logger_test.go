@@ -0,0 +1,107 @@
+package logutil
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewJSON(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "info", FormatJSON)
+	require.NoError(t, err)
+
+	logger.With(Chain("Base")).Info("Fill sent", OrderID("0xabc"), Block(42), TxHash("0xdef"), Stage("fill"),
+		Err(errors.New("boom")))
+
+	var line map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &line))
+	assert.Equal(t, "INFO", line["level"])
+	assert.Equal(t, "Fill sent", line["msg"])
+	assert.Equal(t, "Base", line[FieldChain])
+	assert.Equal(t, "0xabc", line[FieldOrderID])
+	assert.InDelta(t, 42, line[FieldBlock], 0)
+	assert.Equal(t, "0xdef", line[FieldTxHash])
+	assert.Equal(t, "fill", line[FieldStage])
+	assert.Equal(t, "boom", line[FieldError])
+}
+
+func TestNewLogfmt(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "", FormatLogfmt)
+	require.NoError(t, err)
+
+	logger.Warn("Status check failed", Chain("Starknet"), OrderID("0xabc"))
+
+	line := buf.String()
+	assert.Contains(t, line, "level=WARN")
+	assert.Contains(t, line, `msg="Status check failed"`)
+	assert.Contains(t, line, "chain=Starknet")
+	assert.Contains(t, line, "order_id=0xabc")
+}
+
+func TestNewConsole(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "info", FormatText)
+	require.NoError(t, err)
+
+	logger.With(Chain("Base")).Info("✅ block processed", Block(7))
+	logger.Error("❌ Fill failed", append(Route(0, 0), OrderID("0x1234567890abcdef"), Err(errors.New("reverted")))...)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, Prefix("Base")+"✅ block processed block=7", lines[0])
+	assert.Contains(t, lines[1], "❌ Fill failed: reverted (Order: 0x123456...)")
+	assert.Contains(t, lines[1], " → ")
+}
+
+func TestLevelFiltering(t *testing.T) {
+	var buf bytes.Buffer
+	logger, err := New(&buf, "warn", FormatLogfmt)
+	require.NoError(t, err)
+
+	logger.Debug("debug line")
+	logger.Info("info line")
+	logger.Warn("warn line")
+	logger.Error("error line")
+
+	assert.NotContains(t, buf.String(), "debug line")
+	assert.NotContains(t, buf.String(), "info line")
+	assert.Contains(t, buf.String(), "warn line")
+	assert.Contains(t, buf.String(), "error line")
+
+	buf.Reset()
+	logger, err = New(&buf, "debug", FormatText)
+	require.NoError(t, err)
+	logger.Debug("debug line")
+	assert.Contains(t, buf.String(), "debug line")
+}
+
+func TestNewInvalidConfig(t *testing.T) {
+	_, err := New(&bytes.Buffer{}, "loud", FormatJSON)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown log level")
+
+	_, err = New(&bytes.Buffer{}, "info", "xml")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "unknown log format")
+}
+
+func TestSetDefault(t *testing.T) {
+	previous := Default()
+	t.Cleanup(func() { SetDefault(previous) })
+
+	var buf bytes.Buffer
+	logger, err := New(&buf, "info", FormatJSON)
+	require.NoError(t, err)
+	SetDefault(logger)
+
+	LogFillOperation("Base", "0xabc", true)
+	assert.Contains(t, buf.String(), `"chain":"Base"`)
+	assert.Contains(t, buf.String(), `"order_id":"0xabc"`)
+}
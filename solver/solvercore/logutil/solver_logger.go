@@ -32,14 +32,12 @@ func GetNetworkTagByChainID(chainID uint64) string {
 
 // CrossChainOperation logs a cross-chain operation with origin → destination format
 func CrossChainOperation(operation string, originChainID, destChainID uint64, orderID string) {
-	originTag := GetNetworkTagByChainID(originChainID)
-	destTag := GetNetworkTagByChainID(destChainID)
-
-	// Keep the color codes for better readability
-	originClean := strings.TrimSpace(originTag)
-	destClean := strings.TrimSpace(destTag)
+	CrossChain(Default(), operation, originChainID, destChainID, orderID)
+}
 
-	fmt.Printf("%s → %s 🔄 %s (Order: %s)\n", originClean, destClean, operation, orderID[:8]+"...")
+// CrossChain logs a cross-chain operation of an order on logger, with any extra fields
+func CrossChain(logger Logger, operation string, originChainID, destChainID uint64, orderID string, fields ...any) {
+	logger.Info("🔄 "+operation, append(append(Route(originChainID, destChainID), OrderID(orderID)), fields...)...)
 }
 
 // removeColorCodes removes ANSI color codes from a string
@@ -57,104 +55,88 @@ func removeColorCodes(s string) string {
 
 // LogOrderProcessing logs order processing with cross-chain context
 func LogOrderProcessing(args *types.ParsedArgs, operation string) {
-	if args.ResolvedOrder.OriginChainID != nil && len(args.ResolvedOrder.FillInstructions) > 0 {
-		destChainID := args.ResolvedOrder.FillInstructions[0].DestinationChainID
-		if destChainID != nil {
-			CrossChainOperation(operation,
-				args.ResolvedOrder.OriginChainID.Uint64(),
-				destChainID.Uint64(),
-				args.OrderID)
-		} else {
-			fmt.Printf("🔄 %s (Order: %s)\n", operation, args.OrderID[:8]+"...")
-		}
-	} else {
-		fmt.Printf("🔄 %s (Order: %s)\n", operation, args.OrderID[:8]+"...")
-	}
+	OrderProcessing(Default(), args, operation)
+}
+
+// OrderProcessing logs on logger that an operation on args' order started
+func OrderProcessing(logger Logger, args *types.ParsedArgs, operation string) {
+	logger.Info("🔄 "+operation, OrderFields(args)...)
 }
 
 // LogFillOperation logs a fill operation with network context
 func LogFillOperation(networkName, orderID string, success bool) {
-	tag := Prefix(networkName)
 	if success {
-		fmt.Printf("%s✅ Fill completed (Order: %s)\n", tag, orderID[:8]+"...")
+		Default().Info("✅ Fill completed", Chain(networkName), OrderID(orderID))
 	} else {
-		fmt.Printf("%s❌ Fill failed (Order: %s)\n", tag, orderID[:8]+"...")
+		Default().Error("❌ Fill failed", Chain(networkName), OrderID(orderID))
 	}
 }
 
 // LogSettleOperation logs a settlement operation with network context
 func LogSettleOperation(networkName, orderID string, success bool) {
-	tag := Prefix(networkName)
 	if success {
-		fmt.Printf("%s✅ Settlement completed (Order: %s)\n", tag, orderID[:8]+"...")
+		Default().Info("✅ Settlement completed", Chain(networkName), OrderID(orderID))
 	} else {
-		fmt.Printf("%s❌ Settlement failed (Order: %s)\n", tag, orderID[:8]+"...")
+		Default().Error("❌ Settlement failed", Chain(networkName), OrderID(orderID))
 	}
 }
 
 // LogBlockProcessing logs block processing with reduced verbosity
 func LogBlockProcessing(networkName string, fromBlock, toBlock uint64, eventCount int) {
-	tag := Prefix(networkName)
+	BlockProcessing(Default().With(Chain(networkName)), fromBlock, toBlock, eventCount)
+}
+
+// BlockProcessing logs on logger that a block range was processed, skipping empty single blocks
+func BlockProcessing(logger Logger, fromBlock, toBlock uint64, eventCount int) {
 	if eventCount > 0 {
-		fmt.Printf("%s📦 Processed blocks %d-%d: %d events\n", tag, fromBlock, toBlock, eventCount)
+		logger.Info(fmt.Sprintf("📦 Processed blocks %d-%d: %d events", fromBlock, toBlock, eventCount))
 	} else if toBlock-fromBlock > 0 {
 		// Only log if processing multiple blocks to reduce noise
-		fmt.Printf("%s📦 Processed blocks %d-%d\n", tag, fromBlock, toBlock)
+		logger.Info(fmt.Sprintf("📦 Processed blocks %d-%d", fromBlock, toBlock))
 	}
 }
 
 // LogStatusCheck logs order status checks with retry information
 func LogStatusCheck(networkName string, attempt, maxAttempts int, status, expected string) {
-	tag := Prefix(networkName)
+	StatusCheck(Default().With(Chain(networkName)), attempt, maxAttempts, status, expected)
+}
+
+// StatusCheck logs on logger the order status seen on one attempt of a status wait
+func StatusCheck(logger Logger, attempt, maxAttempts int, status, expected string) {
 	if attempt == 1 {
-		fmt.Printf("%s📊 Status: %s (expected: %s)\n", tag, status, expected)
+		logger.Info(fmt.Sprintf("📊 Status: %s (expected: %s)", status, expected))
 	} else {
-		fmt.Printf("%s📊 Retry %d/%d: %s (expected: %s)\n", tag, attempt, maxAttempts, status, expected)
+		logger.Info(fmt.Sprintf("📊 Retry %d/%d: %s (expected: %s)", attempt, maxAttempts, status, expected))
 	}
 }
 
 // LogRetryWait logs retry wait information
 func LogRetryWait(networkName string, attempt, maxAttempts int, delay string) {
-	tag := Prefix(networkName)
-	fmt.Printf("%s⏳ Waiting %s before retry %d/%d...\n", tag, delay, attempt+1, maxAttempts)
+	RetryWait(Default().With(Chain(networkName)), attempt, maxAttempts, delay)
+}
+
+// RetryWait logs on logger the wait before the next attempt
+func RetryWait(logger Logger, attempt, maxAttempts int, delay string) {
+	logger.Info(fmt.Sprintf("⏳ Waiting %s before retry %d/%d...", delay, attempt+1, maxAttempts))
 }
 
 // LogOperationComplete logs the completion of an operation with cross-chain context
 func LogOperationComplete(args *types.ParsedArgs, operation string, success bool) {
-	if args.ResolvedOrder.OriginChainID != nil && len(args.ResolvedOrder.FillInstructions) > 0 {
-		destChainID := args.ResolvedOrder.FillInstructions[0].DestinationChainID
-		if destChainID != nil {
-			originTag := GetNetworkTagByChainID(args.ResolvedOrder.OriginChainID.Uint64())
-			destTag := GetNetworkTagByChainID(destChainID.Uint64())
-
-			originClean := strings.TrimSpace(originTag)
-			destClean := strings.TrimSpace(destTag)
-
-			if success {
-				fmt.Printf("%s → %s ✅ %s completed (Order: %s)\n", originClean, destClean, operation, args.OrderID[:8]+"...")
-			} else {
-				fmt.Printf("%s → %s ❌ %s failed (Order: %s)\n", originClean, destClean, operation, args.OrderID[:8]+"...")
-			}
-		} else {
-			if success {
-				fmt.Printf("✅ %s completed (Order: %s)\n", operation, args.OrderID[:8]+"...")
-			} else {
-				fmt.Printf("❌ %s failed (Order: %s)\n", operation, args.OrderID[:8]+"...")
-			}
-		}
+	OperationComplete(Default(), args, operation, success)
+}
+
+// OperationComplete logs on logger whether an operation on args' order succeeded
+func OperationComplete(logger Logger, args *types.ParsedArgs, operation string, success bool) {
+	if success {
+		logger.Info("✅ "+operation+" completed", OrderFields(args)...)
 	} else {
-		if success {
-			fmt.Printf("✅ %s completed (Order: %s)\n", operation, args.OrderID[:8]+"...")
-		} else {
-			fmt.Printf("❌ %s failed (Order: %s)\n", operation, args.OrderID[:8]+"...")
-		}
+		logger.Error("❌ "+operation+" failed", OrderFields(args)...)
 	}
 }
 
 // LogWithNetworkTagf adds a network tag to any log message
 func LogWithNetworkTagf(networkName, format string, args ...interface{}) {
-	tag := Prefix(networkName)
-	fmt.Printf(tag+format, args...)
+	Default().Info(fmt.Sprintf(format, args...), Chain(networkName))
 }
 
 // LogPersistence logs persistence operations with reduced frequency
@@ -167,7 +149,6 @@ func LogPersistence(networkName string, blockNumber uint64) {
 
 	// Only log every 30 blocks or if it's the first block
 	if counter%30 == 1 || counter == 1 {
-		tag := Prefix(networkName)
-		fmt.Printf("%s💾 Persisted LastIndexedBlock=%d\n", tag, blockNumber)
+		Default().Info(fmt.Sprintf("💾 Persisted LastIndexedBlock=%d", blockNumber), Chain(networkName))
 	}
 }
@@ -0,0 +1,240 @@
+package metrics
+
+// Module: Prometheus metrics for the solver
+// - One registry fed by listeners, the rules engine, chain handlers and the inventory
+// - Series carry chain and protocol labels so dashboards can split them by network and solver
+// - Served on its own address (METRICS_LISTEN_ADDR) at /metrics
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+)
+
+const (
+	namespace         = "solver"
+	readHeaderTimeout = 10 * time.Second
+	shutdownTimeout   = 5 * time.Second
+)
+
+// Error types recorded by RecordError, one per stage an order or listener can fail at
+const (
+	ErrorTypeBlocked    = "blocked"
+	ErrorTypeValidation = "validation"
+	ErrorTypeInventory  = "inventory"
+	ErrorTypeFill       = "fill"
+	ErrorTypeSettle     = "settle"
+	ErrorTypeListener   = "listener"
+)
+
+var (
+	headLag = prometheus.NewGaugeVec(gaugeOpts("head_lag_blocks",
+		"Blocks between the chain head and the last block the listener processed"), []string{"chain", "protocol"})
+	lastProcessedBlock = prometheus.NewGaugeVec(gaugeOpts("last_processed_block",
+		"Last block the listener processed"), []string{"chain", "protocol"})
+	eventsSeen = prometheus.NewCounterVec(counterOpts("events_seen_total",
+		"Order events the listener picked up"), []string{"chain", "protocol"})
+	ruleEvaluations = prometheus.NewCounterVec(counterOpts("rule_evaluations_total",
+		"Rule evaluations by rule name and result, labelled with the order's origin chain"), []string{"chain", "protocol", "rule", "result"})
+	operationDuration = prometheus.NewHistogramVec(histogramOpts("operation_duration_seconds",
+		"Time taken by fill, settle and other chain operations", prometheus.ExponentialBuckets(0.25, 2, 12)), //nolint:mnd // 0.25s to ~8.5m
+		[]string{"chain", "protocol", "operation", "outcome"})
+	txGasUsed = prometheus.NewHistogramVec(histogramOpts("tx_gas_used",
+		"Gas used by transactions the solver sent", prometheus.ExponentialBuckets(10_000, 4, 10)), //nolint:mnd // 10k to ~2.6bn
+		[]string{"chain", "protocol", "operation"})
+	errorsTotal = prometheus.NewCounterVec(counterOpts("errors_total",
+		"Errors by the stage they happened at (type) and their failure kind"), []string{"chain", "protocol", "type", "kind"})
+	tokenBalance = prometheus.NewGaugeVec(gaugeOpts("token_balance",
+		"Solver token balance in the token's smallest unit"), []string{"chain", "token"})
+	fillRaces = prometheus.NewCounterVec(counterOpts("fill_races_total",
+		"Fills won by the solver or lost to another solver, by route"), []string{"origin", "destination", "protocol", "result"})
+)
+
+// registry holds the solver's collectors alongside the Go runtime and process ones
+var registry = newRegistry()
+
+func newRegistry() *prometheus.Registry {
+	r := prometheus.NewRegistry()
+	r.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{PidFn: nil, Namespace: "", ReportErrors: false}),
+		headLag, lastProcessedBlock, eventsSeen, ruleEvaluations, operationDuration, txGasUsed, errorsTotal, tokenBalance,
+		fillRaces,
+	)
+	return r
+}
+
+func counterOpts(name, help string) prometheus.CounterOpts {
+	return prometheus.CounterOpts{Namespace: namespace, Subsystem: "", Name: name, Help: help, ConstLabels: nil}
+}
+
+func gaugeOpts(name, help string) prometheus.GaugeOpts {
+	return prometheus.GaugeOpts{Namespace: namespace, Subsystem: "", Name: name, Help: help, ConstLabels: nil}
+}
+
+func histogramOpts(name, help string, buckets []float64) prometheus.HistogramOpts {
+	return prometheus.HistogramOpts{
+		Namespace:                       namespace,
+		Subsystem:                       "",
+		Name:                            name,
+		Help:                            help,
+		ConstLabels:                     nil,
+		Buckets:                         buckets,
+		NativeHistogramBucketFactor:     0,
+		NativeHistogramZeroThreshold:    0,
+		NativeHistogramMaxBucketNumber:  0,
+		NativeHistogramMinResetDuration: 0,
+		NativeHistogramMaxZeroThreshold: 0,
+		NativeHistogramMaxExemplars:     0,
+		NativeHistogramExemplarTTL:      0,
+	}
+}
+
+// SetListenerProgress records the chain head a listener saw and the last block it processed
+func SetListenerProgress(chain, protocol string, head, lastProcessed uint64) {
+	lag := uint64(0)
+	if head > lastProcessed {
+		lag = head - lastProcessed
+	}
+	headLag.WithLabelValues(chain, protocol).Set(float64(lag))
+	lastProcessedBlock.WithLabelValues(chain, protocol).Set(float64(lastProcessed))
+}
+
+// EventsSeen counts order events a listener picked up
+func EventsSeen(chain, protocol string, count int) {
+	eventsSeen.WithLabelValues(chain, protocol).Add(float64(count))
+}
+
+// RuleEvaluated counts one evaluation of rule for an order from chain
+func RuleEvaluated(chain, protocol, rule string, passed bool) {
+	result := "fail"
+	if passed {
+		result = "pass"
+	}
+	ruleEvaluations.WithLabelValues(chain, protocol, rule, result).Inc()
+}
+
+// FillRace counts a fill from origin to destination the solver won, or lost to another solver filling first
+func FillRace(origin, destination, protocol string, won bool) {
+	result := "lost"
+	if won {
+		result = "won"
+	}
+	fillRaces.WithLabelValues(origin, destination, protocol, result).Inc()
+}
+
+// ObserveOperation records how long a chain operation that began at started took, and whether it failed
+func ObserveOperation(chain, protocol, operation string, started time.Time, err error) {
+	outcome := "success"
+	if err != nil {
+		outcome = "error"
+	}
+	operationDuration.WithLabelValues(chain, protocol, operation, outcome).Observe(time.Since(started).Seconds())
+}
+
+// TxGasUsed records the gas a mined transaction used
+func TxGasUsed(chain, protocol, operation string, gas uint64) {
+	txGasUsed.WithLabelValues(chain, protocol, operation).Observe(float64(gas))
+}
+
+// RecordError counts err as an error of errType, see the ErrorType constants, labelled with its failure kind
+func RecordError(chain, protocol, errType string, err error) {
+	kind := failure.KindOf(err)
+	if kind == failure.KindNone {
+		kind = failure.KindUnknown
+	}
+	errorsTotal.WithLabelValues(chain, protocol, errType, string(kind)).Inc()
+}
+
+// SetTokenBalance records the solver's balance of token on chain
+func SetTokenBalance(chain, token string, balance *big.Int) {
+	value, _ := new(big.Float).SetInt(balance).Float64()
+	tokenBalance.WithLabelValues(chain, token).Set(value)
+}
+
+// Config configures the metrics server
+type Config struct {
+	// ListenAddr is the host:port to serve /metrics on; empty disables the endpoint
+	ListenAddr string
+}
+
+// ConfigFromEnv reads METRICS_LISTEN_ADDR
+func ConfigFromEnv() Config {
+	return Config{ListenAddr: envutil.GetEnvWithDefault("METRICS_LISTEN_ADDR", "")}
+}
+
+// Enabled reports whether the metrics endpoint should be served
+func (c Config) Enabled() bool {
+	return c.ListenAddr != ""
+}
+
+// Handler serves the registry in the Prometheus exposition format
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{
+		ErrorLog:                            nil,
+		ErrorHandling:                       promhttp.ContinueOnError,
+		Registry:                            registry,
+		DisableCompression:                  false,
+		OfferedCompressions:                 nil,
+		MaxRequestsInFlight:                 0,
+		Timeout:                             0,
+		EnableOpenMetrics:                   false,
+		EnableOpenMetricsTextCreatedSamples: false,
+		ProcessStartTime:                    time.Time{},
+	})
+}
+
+// Start serves /metrics on cfg.ListenAddr until ctx is done
+func Start(ctx context.Context, cfg Config) error {
+	listener, err := net.Listen("tcp", cfg.ListenAddr)
+	if err != nil {
+		return fmt.Errorf("failed to listen on %s: %w", cfg.ListenAddr, err)
+	}
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", Handler())
+	server := &http.Server{
+		Addr:                         cfg.ListenAddr,
+		Handler:                      mux,
+		DisableGeneralOptionsHandler: false,
+		TLSConfig:                    nil,
+		ReadTimeout:                  0,
+		ReadHeaderTimeout:            readHeaderTimeout,
+		WriteTimeout:                 0,
+		IdleTimeout:                  0,
+		MaxHeaderBytes:               0,
+		TLSNextProto:                 nil,
+		ConnState:                    nil,
+		ErrorLog:                     nil,
+		BaseContext:                  func(net.Listener) context.Context { return ctx },
+		ConnContext:                  nil,
+		HTTP2:                        nil,
+		Protocols:                    nil,
+	}
+
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logutil.Default().Error("❌ Metrics server stopped", logutil.Err(err))
+		}
+	}()
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		_ = server.Shutdown(shutdownCtx)
+	}()
+
+	logutil.Default().Info(fmt.Sprintf("📈 Metrics listening on %s/metrics", listener.Addr()))
+	return nil
+}
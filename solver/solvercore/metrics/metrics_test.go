@@ -0,0 +1,71 @@
+package metrics
+
+import (
+	"errors"
+	"io"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
+)
+
+func scrape(t *testing.T) string {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	body, err := io.ReadAll(rec.Body)
+	require.NoError(t, err)
+	return string(body)
+}
+
+func TestMetricsExposition(t *testing.T) {
+	SetListenerProgress("Base", "hyperlane7683", 1_000, 990)
+	SetListenerProgress("Starknet", "hyperlane7683", 50, 60)
+	EventsSeen("Base", "hyperlane7683", 3)
+	RuleEvaluated("Base", "hyperlane7683", "ProfitabilityRule", true)
+	RuleEvaluated("Base", "hyperlane7683", "BalanceRule", false)
+	ObserveOperation("Optimism", "hyperlane7683", "fill", time.Now(), nil)
+	ObserveOperation("Optimism", "hyperlane7683", "settle", time.Now(), errors.New("boom"))
+	TxGasUsed("Optimism", "hyperlane7683", "fill", 84_000)
+	RecordError("Base", "hyperlane7683", ErrorTypeValidation, failure.ErrValidation)
+	RecordError("Base", "hyperlane7683", ErrorTypeFill, errors.New("dial tcp: connection refused"))
+	SetTokenBalance("Base", "0xtoken", big.NewInt(1_500))
+	FillRace("Base", "Optimism", "hyperlane7683", true)
+	FillRace("Base", "Optimism", "hyperlane7683", false)
+
+	body := scrape(t)
+	for _, line := range []string{
+		`solver_head_lag_blocks{chain="Base",protocol="hyperlane7683"} 10`,
+		`solver_head_lag_blocks{chain="Starknet",protocol="hyperlane7683"} 0`,
+		`solver_last_processed_block{chain="Base",protocol="hyperlane7683"} 990`,
+		`solver_events_seen_total{chain="Base",protocol="hyperlane7683"} 3`,
+		`solver_rule_evaluations_total{chain="Base",protocol="hyperlane7683",result="pass",rule="ProfitabilityRule"} 1`,
+		`solver_rule_evaluations_total{chain="Base",protocol="hyperlane7683",result="fail",rule="BalanceRule"} 1`,
+		`solver_operation_duration_seconds_count{chain="Optimism",operation="fill",outcome="success",protocol="hyperlane7683"} 1`,
+		`solver_operation_duration_seconds_count{chain="Optimism",operation="settle",outcome="error",protocol="hyperlane7683"} 1`,
+		`solver_tx_gas_used_sum{chain="Optimism",operation="fill",protocol="hyperlane7683"} 84000`,
+		`solver_errors_total{chain="Base",kind="validation",protocol="hyperlane7683",type="validation"} 1`,
+		`solver_errors_total{chain="Base",kind="network",protocol="hyperlane7683",type="fill"} 1`,
+		`solver_token_balance{chain="Base",token="0xtoken"} 1500`,
+		`solver_fill_races_total{destination="Optimism",origin="Base",protocol="hyperlane7683",result="won"} 1`,
+		`solver_fill_races_total{destination="Optimism",origin="Base",protocol="hyperlane7683",result="lost"} 1`,
+		`go_goroutines`,
+	} {
+		assert.Contains(t, body, line)
+	}
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Setenv("METRICS_LISTEN_ADDR", "")
+	assert.False(t, ConfigFromEnv().Enabled())
+
+	t.Setenv("METRICS_LISTEN_ADDR", "127.0.0.1:9100")
+	assert.True(t, ConfigFromEnv().Enabled())
+}
@@ -0,0 +1,179 @@
+package orderlog
+
+// Module: Order logs
+// - What the order history and the PnL ledger share: append-only JSON-lines files of per-order records,
+//   the file and on/off switch each reads from the environment, and the chain and route names they record
+// - Chain operations carry the orders they work on in their context, so handlers deep down can attribute
+//   transactions and fees to them; each log keeps its own scope, keyed by the type of its sink
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"os"
+	"path/filepath"
+	"slices"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+const (
+	dirPerms  = 0755
+	filePerms = 0644
+)
+
+// FileConfig is where a log is kept and whether it is kept at all
+type FileConfig struct {
+	// File is the JSON-lines file the log is appended to
+	File string
+	// Disabled turns the log off
+	Disabled bool
+}
+
+// FileConfigFromEnv reads the log's file from fileKey, defaulting to defaultFile, and turns it off when enabledKey is "false"
+func FileConfigFromEnv(fileKey, enabledKey, defaultFile string) FileConfig {
+	return FileConfig{
+		File:     envutil.GetEnvWithDefault(fileKey, defaultFile),
+		Disabled: envutil.GetEnvWithDefault(enabledKey, "true") == "false",
+	}
+}
+
+// Enabled reports whether the log should be written
+func (c FileConfig) Enabled() bool {
+	return !c.Disabled && c.File != ""
+}
+
+// OpenAppend opens the file at path for appending, creating it and its directory if needed
+func OpenAppend(path string) (*os.File, error) {
+	if err := os.MkdirAll(filepath.Dir(path), dirPerms); err != nil {
+		return nil, fmt.Errorf("failed to create directory of %s: %w", path, err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, filePerms)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	return file, nil
+}
+
+// WriteLine writes v as one JSON line
+func WriteLine(w io.Writer, v any) error {
+	line, err := json.Marshal(v)
+	if err != nil {
+		return fmt.Errorf("failed to marshal line: %w", err)
+	}
+	if _, err := w.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("failed to write line: %w", err)
+	}
+	return nil
+}
+
+// ReadFile decodes the lines of the file at path that valid accepts, in order. A missing file has no lines;
+// lines that do not decode, such as a torn last line left by a crash mid-write, are skipped.
+// Lines longer than maxLineBytes fail the read.
+func ReadFile[T any](path string, maxLineBytes int, valid func(T) bool) ([]T, error) {
+	file, err := os.Open(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to open %s: %w", path, err)
+	}
+	defer file.Close()
+
+	var values []T
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(make([]byte, 0, bufio.MaxScanTokenSize), maxLineBytes)
+	for scanner.Scan() {
+		var value T
+		if err := json.Unmarshal(scanner.Bytes(), &value); err != nil || !valid(value) {
+			continue
+		}
+		values = append(values, value)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// Replace atomically rewrites the file at path with one line per value
+func Replace[T any](path string, values []T) error {
+	dir := filepath.Dir(path)
+	if err := os.MkdirAll(dir, dirPerms); err != nil {
+		return fmt.Errorf("failed to create directory of %s: %w", path, err)
+	}
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+"-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file for %s: %w", path, err)
+	}
+	tmpPath := tmp.Name()
+	defer func() { tmp.Close(); os.Remove(tmpPath) }()
+
+	writer := bufio.NewWriter(tmp)
+	for _, value := range values {
+		if err := WriteLine(writer, value); err != nil {
+			return err
+		}
+	}
+	if err := writer.Flush(); err != nil {
+		return fmt.Errorf("failed to write temp file for %s: %w", path, err)
+	}
+	if err := tmp.Chmod(filePerms); err != nil {
+		return fmt.Errorf("failed to set permissions of temp file for %s: %w", path, err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("failed to sync temp file for %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temp file for %s: %w", path, err)
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("failed to replace %s: %w", path, err)
+	}
+	return nil
+}
+
+// ChainName is the configured network name of chainID, or "unknown" without one
+func ChainName(chainID *big.Int) string {
+	if chainID == nil {
+		return "unknown"
+	}
+	return logutil.NetworkNameByChainID(chainID.Uint64())
+}
+
+// Route names the order's origin and every distinct destination of its fill instructions, in order
+func Route(args *types.ParsedArgs) (string, []string) {
+	destinations := make([]string, 0, len(args.ResolvedOrder.FillInstructions))
+	for _, instruction := range args.ResolvedOrder.FillInstructions {
+		name := ChainName(instruction.DestinationChainID)
+		if !slices.Contains(destinations, name) {
+			destinations = append(destinations, name)
+		}
+	}
+	return ChainName(args.ResolvedOrder.OriginChainID), destinations
+}
+
+// scopeKey carries the orders a chain operation works on to sinks of type S
+type scopeKey[S any] struct{}
+
+type scope[S any] struct {
+	sink   S
+	orders []*types.ParsedArgs
+}
+
+// WithOrders returns a context carrying sink and the orders a chain operation works on; OrdersFrom reads them back
+func WithOrders[S any](ctx context.Context, sink S, orders []*types.ParsedArgs) context.Context {
+	return context.WithValue(ctx, scopeKey[S]{}, scope[S]{sink: sink, orders: orders})
+}
+
+// OrdersFrom returns the sink and orders WithOrders put in ctx, if any
+func OrdersFrom[S any](ctx context.Context) (S, []*types.ParsedArgs, bool) {
+	s, ok := ctx.Value(scopeKey[S]{}).(scope[S])
+	return s.sink, s.orders, ok
+}
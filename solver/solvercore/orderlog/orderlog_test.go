@@ -0,0 +1,67 @@
+package orderlog
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+type record struct {
+	ID string `json:"id"`
+}
+
+type historySink struct{}
+
+type ledgerSink struct{}
+
+func TestRouteListsDistinctDestinations(t *testing.T) {
+	args := &types.ParsedArgs{ResolvedOrder: types.ResolvedCrossChainOrder{
+		OriginChainID: big.NewInt(1),
+		FillInstructions: []types.FillInstruction{
+			{DestinationChainID: big.NewInt(10)}, {DestinationChainID: big.NewInt(8453)}, {DestinationChainID: big.NewInt(10)},
+		},
+	}}
+	origin, destinations := Route(args)
+	assert.Equal(t, ChainName(big.NewInt(1)), origin)
+	assert.Equal(t, []string{ChainName(big.NewInt(10)), ChainName(big.NewInt(8453))}, destinations)
+	assert.Equal(t, "unknown", ChainName(nil))
+}
+
+func TestReplaceThenAppend(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "log", "records.jsonl")
+	require.NoError(t, Replace(path, []record{{ID: "a"}, {ID: "b"}}))
+
+	file, err := OpenAppend(path)
+	require.NoError(t, err)
+	require.NoError(t, WriteLine(file, record{ID: ""}))
+	require.NoError(t, WriteLine(file, record{ID: "c"}))
+	_, err = file.WriteString(`{"id":"d`)
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	records, err := ReadFile(path, 1<<10, func(r record) bool { return r.ID != "" })
+	require.NoError(t, err)
+	assert.Equal(t, []record{{ID: "a"}, {ID: "b"}, {ID: "c"}}, records, "invalid and torn lines are skipped")
+
+	missing, err := ReadFile(filepath.Join(t.TempDir(), "missing.jsonl"), 1<<10, func(record) bool { return true })
+	require.NoError(t, err)
+	assert.Empty(t, missing)
+}
+
+func TestOrderScopesAreKeptPerSink(t *testing.T) {
+	orders := []*types.ParsedArgs{{OrderID: "0x01"}}
+	ctx := WithOrders(context.Background(), &historySink{}, orders)
+
+	_, got, ok := OrdersFrom[*historySink](ctx)
+	require.True(t, ok)
+	assert.Equal(t, orders, got)
+
+	_, _, ok = OrdersFrom[*ledgerSink](ctx)
+	assert.False(t, ok, "each log only sees the orders attached for it")
+}
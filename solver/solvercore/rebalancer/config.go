@@ -0,0 +1,167 @@
+package rebalancer
+
+import (
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"os"
+	"time"
+)
+
+// Defaults applied to zero-valued config fields
+const (
+	DefaultIntervalSeconds     = 300
+	DefaultCooldownSeconds     = 1800
+	DefaultMaxMovesPerRun      = 1
+	DefaultFillDeadlineMinutes = 60
+
+	maxFeeBps = 10000
+)
+
+// Config is the rebalancer configuration, loaded from REBALANCE_CONFIG_FILE
+type Config struct {
+	Enabled bool `json:"enabled"`
+	// DryRun logs planned moves without sending any transaction
+	DryRun          bool `json:"dryRun"`
+	IntervalSeconds int  `json:"intervalSeconds"`
+	MaxMovesPerRun  int  `json:"maxMovesPerRun"`
+	// CooldownSeconds is the minimum time between two moves on the same route,
+	// giving the previous move time to be filled before the planner reconsiders it
+	CooldownSeconds     int           `json:"cooldownSeconds"`
+	FillDeadlineMinutes int           `json:"fillDeadlineMinutes"`
+	Assets              []AssetConfig `json:"assets"`
+}
+
+// AssetConfig describes one logical asset deployed on several networks
+type AssetConfig struct {
+	Name string `json:"name"`
+	// Tokens maps network name (as in config.Networks) to the token address on that network
+	Tokens map[string]string `json:"tokens"`
+	// Targets maps network name to the desired balance band on that network
+	Targets map[string]Target `json:"targets"`
+	// Amounts are decimal strings in the token's base units
+	MaxMoveAmount  string `json:"maxMoveAmount"`
+	MinMoveAmount  string `json:"minMoveAmount"`
+	MaxDailyAmount string `json:"maxDailyAmount"`
+	// FeeBps is the spread offered to fillers: amountOut = amountIn - amountIn*feeBps/10000
+	FeeBps uint64 `json:"feeBps"`
+
+	maxMove  *big.Int
+	minMove  *big.Int
+	maxDaily *big.Int
+}
+
+// Target is the balance band for an asset on one network.
+// Balances below Min are topped up to Target; balances above Max are drained down to Target.
+type Target struct {
+	Min    string `json:"min"`
+	Target string `json:"target"`
+	Max    string `json:"max"`
+
+	min    *big.Int
+	target *big.Int
+	max    *big.Int
+}
+
+// LoadConfig reads and validates a rebalancer config file
+func LoadConfig(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rebalance config %s: %w", path, err)
+	}
+
+	var cfg Config
+	if err := json.Unmarshal(data, &cfg); err != nil {
+		return nil, fmt.Errorf("failed to parse rebalance config %s: %w", path, err)
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid rebalance config %s: %w", path, err)
+	}
+	return &cfg, nil
+}
+
+// Validate applies defaults and parses all amounts
+func (c *Config) Validate() error {
+	if c.IntervalSeconds <= 0 {
+		c.IntervalSeconds = DefaultIntervalSeconds
+	}
+	if c.CooldownSeconds <= 0 {
+		c.CooldownSeconds = DefaultCooldownSeconds
+	}
+	if c.MaxMovesPerRun <= 0 {
+		c.MaxMovesPerRun = DefaultMaxMovesPerRun
+	}
+	if c.FillDeadlineMinutes <= 0 {
+		c.FillDeadlineMinutes = DefaultFillDeadlineMinutes
+	}
+
+	for i := range c.Assets {
+		if err := c.Assets[i].validate(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Interval returns the time between planning runs
+func (c *Config) Interval() time.Duration {
+	return time.Duration(c.IntervalSeconds) * time.Second
+}
+
+// Cooldown returns the minimum time between moves on the same route
+func (c *Config) Cooldown() time.Duration {
+	return time.Duration(c.CooldownSeconds) * time.Second
+}
+
+func (a *AssetConfig) validate() error {
+	if a.Name == "" {
+		return fmt.Errorf("asset name is required")
+	}
+	if a.FeeBps >= maxFeeBps {
+		return fmt.Errorf("asset %s: feeBps must be below %d", a.Name, maxFeeBps)
+	}
+
+	var err error
+	if a.maxMove, err = parseAmount(a.MaxMoveAmount, a.Name, "maxMoveAmount"); err != nil {
+		return err
+	}
+	if a.minMove, err = parseAmount(a.MinMoveAmount, a.Name, "minMoveAmount"); err != nil {
+		return err
+	}
+	if a.maxDaily, err = parseAmount(a.MaxDailyAmount, a.Name, "maxDailyAmount"); err != nil {
+		return err
+	}
+
+	for network, target := range a.Targets {
+		if _, ok := a.Tokens[network]; !ok {
+			return fmt.Errorf("asset %s: target for %s has no token address", a.Name, network)
+		}
+		if target.min, err = parseAmount(target.Min, a.Name, network+".min"); err != nil {
+			return err
+		}
+		if target.target, err = parseAmount(target.Target, a.Name, network+".target"); err != nil {
+			return err
+		}
+		if target.max, err = parseAmount(target.Max, a.Name, network+".max"); err != nil {
+			return err
+		}
+		if target.min.Cmp(target.target) > 0 || (target.max.Sign() > 0 && target.target.Cmp(target.max) > 0) {
+			return fmt.Errorf("asset %s: target for %s must satisfy min <= target <= max", a.Name, network)
+		}
+		a.Targets[network] = target
+	}
+	return nil
+}
+
+// parseAmount parses a decimal base-unit amount; empty means zero (no limit / no bound)
+func parseAmount(value, asset, field string) (*big.Int, error) {
+	if value == "" {
+		return new(big.Int), nil
+	}
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok || amount.Sign() < 0 {
+		return nil, fmt.Errorf("asset %s: invalid %s %q", asset, field, value)
+	}
+	return amount, nil
+}
@@ -0,0 +1,255 @@
+package rebalancer
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/txmanager"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/ethclient"
+)
+
+// OrderMover moves inventory by opening a Hyperlane7683 order from the solver to itself:
+// the solver pays AmountIn on the origin and any filler delivers AmountOut to the solver on the destination
+type OrderMover struct {
+	getEVMClient         func(chainID uint64) (*ethclient.Client, error)
+	getEVMTxManager      func(chainID uint64) (*txmanager.EVM, error)
+	getStarknetTxManager func() (*txmanager.Starknet, error)
+	fillDeadline         time.Duration
+}
+
+// NewOrderMover creates a mover using the solver's clients and transaction managers
+func NewOrderMover(
+	getEVMClient func(chainID uint64) (*ethclient.Client, error),
+	getEVMTxManager func(chainID uint64) (*txmanager.EVM, error),
+	getStarknetTxManager func() (*txmanager.Starknet, error),
+	fillDeadline time.Duration,
+) *OrderMover {
+	return &OrderMover{
+		getEVMClient:         getEVMClient,
+		getEVMTxManager:      getEVMTxManager,
+		getStarknetTxManager: getStarknetTxManager,
+		fillDeadline:         fillDeadline,
+	}
+}
+
+// Move opens the order on the origin network and waits for it to be included
+func (m *OrderMover) Move(ctx context.Context, move Move) (string, error) {
+	origin, err := config.GetNetworkConfig(move.FromNetwork)
+	if err != nil {
+		return "", err
+	}
+	destination, err := config.GetNetworkConfig(move.ToNetwork)
+	if err != nil {
+		return "", err
+	}
+
+	order, err := m.baseOrder(move, &origin, &destination)
+	if err != nil {
+		return "", err
+	}
+
+	if isStarknetNetwork(move.FromNetwork) {
+		return m.openOnStarknet(ctx, move, order)
+	}
+	return m.openOnEVM(ctx, move, &origin, order)
+}
+
+// baseOrder fills every OrderData field except Sender and SenderNonce, which depend on the origin chain
+func (m *OrderMover) baseOrder(move Move, origin, destination *config.NetworkConfig) (*orderutil.OrderData, error) {
+	recipient, err := m.ownAddress(move.ToNetwork, move.ToChainID)
+	if err != nil {
+		return nil, err
+	}
+	inputToken, err := orderutil.HexToBytes32(move.InputToken)
+	if err != nil {
+		return nil, err
+	}
+	outputToken, err := orderutil.HexToBytes32(move.OutputToken)
+	if err != nil {
+		return nil, err
+	}
+	settler, err := hyperlaneSettler(destination)
+	if err != nil {
+		return nil, err
+	}
+
+	return &orderutil.OrderData{
+		Sender:             [32]byte{},
+		Recipient:          recipient,
+		InputToken:         inputToken,
+		OutputToken:        outputToken,
+		AmountIn:           move.AmountIn,
+		AmountOut:          move.AmountOut,
+		SenderNonce:        nil,
+		OriginDomain:       uint32(origin.HyperlaneDomain),
+		DestinationDomain:  uint32(destination.HyperlaneDomain),
+		DestinationSettler: settler,
+		FillDeadline:       uint32(time.Now().Add(m.fillDeadline).Unix()),
+		Data:               []byte{},
+	}, nil
+}
+
+func (m *OrderMover) openOnEVM(ctx context.Context, move Move, origin *config.NetworkConfig,
+	order *orderutil.OrderData) (string, error) {
+	client, err := m.getEVMClient(move.FromChainID)
+	if err != nil {
+		return "", err
+	}
+	txm, err := m.getEVMTxManager(move.FromChainID)
+	if err != nil {
+		return "", err
+	}
+	from := txm.From()
+
+	token, err := types.ToEVMAddress(move.InputToken)
+	if err != nil {
+		return "", fmt.Errorf("invalid input token %s: %w", move.InputToken, err)
+	}
+
+	allowance, err := ethutil.ERC20Allowance(client, token, from, origin.HyperlaneAddress)
+	if err != nil {
+		return "", fmt.Errorf("failed to read allowance: %w", err)
+	}
+	if allowance.Cmp(move.AmountIn) < 0 {
+		erc20ABI, err := abi.JSON(strings.NewReader(ethutil.ERC20ABI))
+		if err != nil {
+			return "", fmt.Errorf("failed to parse ERC20 ABI: %w", err)
+		}
+		approveData, err := erc20ABI.Pack("approve", origin.HyperlaneAddress, move.AmountIn)
+		if err != nil {
+			return "", fmt.Errorf("failed to pack approve call: %w", err)
+		}
+		if _, err := txm.Send(ctx, txmanager.EVMTx{To: token, Data: approveData, Value: nil, GasLimit: 0}); err != nil {
+			return "", fmt.Errorf("failed to approve input token: %w", err)
+		}
+	}
+
+	// The contract's localDomain is authoritative for originDomain
+	localDomain, err := orderutil.LocalDomain(ctx, client, origin.HyperlaneAddress)
+	if err != nil {
+		return "", fmt.Errorf("failed to read localDomain: %w", err)
+	}
+	nonce, err := orderutil.PickValidSenderNonce(ctx, client, origin.HyperlaneAddress, from)
+	if err != nil {
+		return "", err
+	}
+	order.OriginDomain = localDomain
+	order.Sender = orderutil.AddressToBytes32(from)
+	order.SenderNonce = nonce
+
+	encoded, err := orderutil.EncodeOrderData(order)
+	if err != nil {
+		return "", err
+	}
+
+	hyperlaneABI, err := contracts.Hyperlane7683MetaData.GetAbi()
+	if err != nil {
+		return "", fmt.Errorf("failed to load Hyperlane7683 ABI: %w", err)
+	}
+	openData, err := hyperlaneABI.Pack("open", contracts.OnchainCrossChainOrder{
+		FillDeadline:  order.FillDeadline,
+		OrderDataType: orderutil.OrderDataTypeHash(),
+		OrderData:     encoded,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to pack open call: %w", err)
+	}
+
+	receipt, err := txm.Send(ctx, txmanager.EVMTx{To: origin.HyperlaneAddress, Data: openData, Value: nil, GasLimit: 0})
+	if err != nil {
+		return "", fmt.Errorf("open transaction failed: %w", err)
+	}
+	return receipt.TxHash.Hex(), nil
+}
+
+func (m *OrderMover) openOnStarknet(ctx context.Context, move Move, order *orderutil.OrderData) (string, error) {
+	txm, err := m.getStarknetTxManager()
+	if err != nil {
+		return "", err
+	}
+
+	hyperlaneAddr := envutil.GetEnvWithDefault("STARKNET_HYPERLANE_ADDRESS", "")
+	if hyperlaneAddr == "" {
+		return "", fmt.Errorf("STARKNET_HYPERLANE_ADDRESS not set")
+	}
+	hyperlaneFelt, err := utils.HexToFelt(hyperlaneAddr)
+	if err != nil {
+		return "", fmt.Errorf("invalid STARKNET_HYPERLANE_ADDRESS: %w", err)
+	}
+
+	order.Sender = txm.Address().Bytes()
+	order.SenderNonce = big.NewInt(time.Now().UnixNano())
+
+	orderData, err := orderutil.EncodeCairoOrderData(order)
+	if err != nil {
+		return "", err
+	}
+	typeLow, typeHigh := orderutil.SplitU256(orderutil.OrderDataTypeHash())
+
+	// open(fill_deadline: u64, order_data_type: u256, order_data: Bytes)
+	calldata := make([]*felt.Felt, 0, 3+len(orderData))
+	calldata = append(calldata, utils.Uint64ToFelt(uint64(order.FillDeadline)), typeLow, typeHigh)
+	calldata = append(calldata, orderData...)
+
+	approveCall, err := starknetutil.ERC20Approve(move.InputToken, hyperlaneAddr, move.AmountIn)
+	if err != nil {
+		return "", fmt.Errorf("failed to build approve call: %w", err)
+	}
+
+	// Approve and open in a single multicall
+	receipt, err := txm.Send(ctx, []rpc.InvokeFunctionCall{
+		*approveCall,
+		{ContractAddress: hyperlaneFelt, FunctionName: "open", CallData: calldata},
+	})
+	if err != nil {
+		return "", fmt.Errorf("open transaction failed: %w", err)
+	}
+	return receipt.Hash.String(), nil
+}
+
+// ownAddress returns the solver's address on network as bytes32, used as the order recipient
+func (m *OrderMover) ownAddress(network string, chainID uint64) ([32]byte, error) {
+	if isStarknetNetwork(network) {
+		addr := envutil.GetStarknetSolverAddress()
+		if addr == "" {
+			return [32]byte{}, fmt.Errorf("STARKNET_SOLVER_ADDRESS not set")
+		}
+		return orderutil.HexToBytes32(addr)
+	}
+
+	txm, err := m.getEVMTxManager(chainID)
+	if err != nil {
+		return [32]byte{}, err
+	}
+	return orderutil.AddressToBytes32(txm.From()), nil
+}
+
+// hyperlaneSettler returns the Hyperlane7683 contract that settles fills on network
+func hyperlaneSettler(network *config.NetworkConfig) ([32]byte, error) {
+	if isStarknetNetwork(network.Name) {
+		addr := envutil.GetEnvWithDefault("STARKNET_HYPERLANE_ADDRESS", "")
+		if addr == "" {
+			return [32]byte{}, fmt.Errorf("STARKNET_HYPERLANE_ADDRESS not set")
+		}
+		return orderutil.HexToBytes32(addr)
+	}
+	return orderutil.AddressToBytes32(network.HyperlaneAddress), nil
+}
+
+func isStarknetNetwork(name string) bool {
+	return strings.Contains(strings.ToLower(name), "starknet")
+}
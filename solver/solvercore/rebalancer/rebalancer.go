@@ -0,0 +1,316 @@
+package rebalancer
+
+// Module: Cross-chain inventory rebalancer
+// - Compares per-network available balances against configured target bands
+// - Plans moves from networks above their band to networks below it
+// - Enforces per-move, per-run, per-route cooldown and rolling daily limits
+// - Executes moves through a Mover (Hyperlane7683 orders by default), or only logs them in dry-run mode
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/inventory"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+)
+
+const dailyWindow = 24 * time.Hour
+
+// Move is a single planned transfer of an asset between two networks
+type Move struct {
+	Asset       string
+	FromNetwork string
+	ToNetwork   string
+	FromChainID uint64
+	ToChainID   uint64
+	InputToken  string
+	OutputToken string
+	// AmountIn leaves the solver on FromNetwork; AmountOut arrives on ToNetwork
+	AmountIn  *big.Int
+	AmountOut *big.Int
+}
+
+// String describes the move for logs
+func (m Move) String() string {
+	return fmt.Sprintf("%s %s → %s (in %s, out %s)", m.Asset, m.FromNetwork, m.ToNetwork,
+		m.AmountIn.String(), m.AmountOut.String())
+}
+
+// Mover executes a move and returns an identifier of the submitted transfer (e.g. a tx hash)
+type Mover interface {
+	Move(ctx context.Context, move Move) (string, error)
+}
+
+type executedMove struct {
+	asset  string
+	amount *big.Int
+	at     time.Time
+}
+
+// Rebalancer periodically moves inventory between networks toward configured targets
+type Rebalancer struct {
+	mu        sync.Mutex
+	cfg       *Config
+	inventory *inventory.Manager
+	mover     Mover
+	chainIDs  map[string]uint64 // network name -> chain ID
+	lastMove  map[string]time.Time
+	history   []executedMove
+	now       func() time.Time
+}
+
+// NewRebalancer creates a rebalancer; chainIDs maps the network names used in cfg to chain IDs
+func NewRebalancer(cfg *Config, inv *inventory.Manager, mover Mover, chainIDs map[string]uint64) *Rebalancer {
+	return &Rebalancer{
+		mu:        sync.Mutex{},
+		cfg:       cfg,
+		inventory: inv,
+		mover:     mover,
+		chainIDs:  chainIDs,
+		lastMove:  make(map[string]time.Time),
+		history:   make([]executedMove, 0),
+		now:       time.Now,
+	}
+}
+
+// Start runs a rebalance pass immediately and then every configured interval until ctx is cancelled
+func (r *Rebalancer) Start(ctx context.Context) {
+	mode := "live"
+	if r.cfg.DryRun {
+		mode = "dry-run"
+	}
+	logutil.Default().Info(fmt.Sprintf("⚖️  Rebalancer started (%s, every %s, %d assets)", mode, r.cfg.Interval(), len(r.cfg.Assets)))
+
+	ticker := time.NewTicker(r.cfg.Interval())
+	defer ticker.Stop()
+
+	for {
+		if _, err := r.RunOnce(ctx); err != nil {
+			logutil.Default().Warn("⚠️  Rebalance run failed", logutil.Err(err))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RunOnce plans and executes (or, in dry-run mode, logs) one round of moves.
+// It returns the moves that were executed or would have been executed.
+func (r *Rebalancer) RunOnce(ctx context.Context) ([]Move, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	moves, err := r.planLocked(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if len(moves) == 0 {
+		return nil, nil
+	}
+
+	done := make([]Move, 0, len(moves))
+	for _, move := range moves {
+		if r.cfg.DryRun {
+			logutil.Default().Info(fmt.Sprintf("🧪 [dry-run] Would rebalance %s", move))
+			done = append(done, move)
+			continue
+		}
+
+		logutil.Default().Info(fmt.Sprintf("⚖️  Rebalancing %s", move))
+		ref, err := r.mover.Move(ctx, move)
+		if err != nil {
+			logutil.Default().Error(fmt.Sprintf("❌ Rebalance move failed (%s)", move), logutil.Err(err))
+			continue
+		}
+		logutil.Default().Info(fmt.Sprintf("✅ Rebalance move submitted: %s", ref))
+
+		now := r.now()
+		r.lastMove[routeKey(move.Asset, move.FromNetwork, move.ToNetwork)] = now
+		r.history = append(r.history, executedMove{asset: move.Asset, amount: new(big.Int).Set(move.AmountIn), at: now})
+		done = append(done, move)
+
+		// The input tokens have left the wallet; re-read so the next plan sees the new balance
+		if err := r.inventory.RefreshKey(ctx, move.FromChainID, move.InputToken); err != nil {
+			logutil.Default().Warn(fmt.Sprintf("⚠️  Failed to refresh %s balance on %s", move.Asset, move.FromNetwork), logutil.Err(err))
+		}
+	}
+	return done, nil
+}
+
+// Plan returns the moves the next run would make, without executing them
+func (r *Rebalancer) Plan(ctx context.Context) ([]Move, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.planLocked(ctx)
+}
+
+type imbalance struct {
+	network string
+	chainID uint64
+	token   string
+	amount  *big.Int
+}
+
+func (r *Rebalancer) planLocked(ctx context.Context) ([]Move, error) {
+	r.pruneHistoryLocked()
+
+	moves := make([]Move, 0)
+	for i := range r.cfg.Assets {
+		asset := &r.cfg.Assets[i]
+		remaining := r.cfg.MaxMovesPerRun - len(moves)
+		if remaining <= 0 {
+			break
+		}
+
+		deficits, surpluses, err := r.imbalances(ctx, asset)
+		if err != nil {
+			return nil, err
+		}
+		moves = append(moves, r.pairLocked(asset, deficits, surpluses, remaining)...)
+	}
+	return moves, nil
+}
+
+// imbalances returns networks below their band (amount = need) and above it (amount = excess),
+// each sorted largest first
+func (r *Rebalancer) imbalances(ctx context.Context, asset *AssetConfig) (deficits, surpluses []imbalance, err error) {
+	networks := make([]string, 0, len(asset.Targets))
+	for network := range asset.Targets {
+		networks = append(networks, network)
+	}
+	sort.Strings(networks)
+
+	for _, network := range networks {
+		target := asset.Targets[network]
+		chainID, ok := r.chainIDs[network]
+		if !ok {
+			return nil, nil, fmt.Errorf("asset %s: unknown network %s", asset.Name, network)
+		}
+		token := asset.Tokens[network]
+
+		available, err := r.inventory.Available(ctx, chainID, token)
+		if err != nil {
+			return nil, nil, fmt.Errorf("asset %s on %s: %w", asset.Name, network, err)
+		}
+
+		switch {
+		case available.Cmp(target.min) < 0:
+			need := new(big.Int).Sub(target.target, available)
+			deficits = append(deficits, imbalance{network: network, chainID: chainID, token: token, amount: need})
+		case target.max.Sign() > 0 && available.Cmp(target.max) > 0:
+			excess := new(big.Int).Sub(available, target.target)
+			surpluses = append(surpluses, imbalance{network: network, chainID: chainID, token: token, amount: excess})
+		}
+	}
+
+	largestFirst := func(list []imbalance) {
+		sort.SliceStable(list, func(i, j int) bool { return list[i].amount.Cmp(list[j].amount) > 0 })
+	}
+	largestFirst(deficits)
+	largestFirst(surpluses)
+	return deficits, surpluses, nil
+}
+
+// pairLocked greedily matches the largest deficits with the largest surpluses within the asset limits
+func (r *Rebalancer) pairLocked(asset *AssetConfig, deficits, surpluses []imbalance, limit int) []Move {
+	moves := make([]Move, 0)
+	dailyLeft := r.dailyRemainingLocked(asset)
+
+	for _, deficit := range deficits {
+		for s := range surpluses {
+			if len(moves) >= limit || (dailyLeft != nil && dailyLeft.Sign() <= 0) {
+				return moves
+			}
+
+			surplus := &surpluses[s]
+			if surplus.amount.Sign() <= 0 || deficit.amount.Sign() <= 0 {
+				continue
+			}
+			if r.coolingDownLocked(asset.Name, surplus.network, deficit.network) {
+				continue
+			}
+
+			amount := minAmount(deficit.amount, surplus.amount, asset.maxMove, dailyLeft)
+			if amount.Cmp(asset.minMove) < 0 || amount.Sign() == 0 {
+				continue
+			}
+
+			fee := new(big.Int).Mul(amount, new(big.Int).SetUint64(asset.FeeBps))
+			fee.Div(fee, big.NewInt(maxFeeBps))
+
+			moves = append(moves, Move{
+				Asset:       asset.Name,
+				FromNetwork: surplus.network,
+				ToNetwork:   deficit.network,
+				FromChainID: surplus.chainID,
+				ToChainID:   deficit.chainID,
+				InputToken:  surplus.token,
+				OutputToken: deficit.token,
+				AmountIn:    amount,
+				AmountOut:   new(big.Int).Sub(amount, fee),
+			})
+
+			surplus.amount = new(big.Int).Sub(surplus.amount, amount)
+			deficit.amount = new(big.Int).Sub(deficit.amount, amount)
+			if dailyLeft != nil {
+				dailyLeft.Sub(dailyLeft, amount)
+			}
+		}
+	}
+	return moves
+}
+
+// dailyRemainingLocked returns how much of the asset may still move in the rolling window, or nil when unlimited
+func (r *Rebalancer) dailyRemainingLocked(asset *AssetConfig) *big.Int {
+	if asset.maxDaily.Sign() == 0 {
+		return nil
+	}
+	remaining := new(big.Int).Set(asset.maxDaily)
+	for _, executed := range r.history {
+		if executed.asset == asset.Name {
+			remaining.Sub(remaining, executed.amount)
+		}
+	}
+	return remaining
+}
+
+func (r *Rebalancer) coolingDownLocked(asset, from, to string) bool {
+	last, ok := r.lastMove[routeKey(asset, from, to)]
+	return ok && r.now().Sub(last) < r.cfg.Cooldown()
+}
+
+func (r *Rebalancer) pruneHistoryLocked() {
+	cutoff := r.now().Add(-dailyWindow)
+	kept := r.history[:0]
+	for _, executed := range r.history {
+		if executed.at.After(cutoff) {
+			kept = append(kept, executed)
+		}
+	}
+	r.history = kept
+}
+
+func routeKey(asset, from, to string) string {
+	return asset + "|" + from + "|" + to
+}
+
+// minAmount returns the smallest of the given amounts, ignoring nil and zero (unlimited) caps
+func minAmount(need, have *big.Int, caps ...*big.Int) *big.Int {
+	result := need
+	if have.Cmp(result) < 0 {
+		result = have
+	}
+	for _, c := range caps {
+		if c != nil && c.Sign() > 0 && c.Cmp(result) < 0 {
+			result = c
+		}
+	}
+	return new(big.Int).Set(result)
+}
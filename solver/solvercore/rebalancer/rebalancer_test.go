@@ -0,0 +1,237 @@
+package rebalancer
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/inventory"
+)
+
+const (
+	baseToken     = "0x1111111111111111111111111111111111111111"
+	optimismToken = "0x2222222222222222222222222222222222222222"
+	starknetToken = "0x3333"
+)
+
+var testChainIDs = map[string]uint64{"Base": 84532, "Optimism": 11155420, "Starknet": 23448591}
+
+// staticFetcher returns a fixed balance for every token
+type staticFetcher struct{ balance *big.Int }
+
+func (f *staticFetcher) Balance(_ context.Context, _ string) (*big.Int, error) {
+	return new(big.Int).Set(f.balance), nil
+}
+
+// recordingMover records moves and optionally fails
+type recordingMover struct {
+	moves []Move
+	err   error
+}
+
+func (m *recordingMover) Move(_ context.Context, move Move) (string, error) {
+	if m.err != nil {
+		return "", m.err
+	}
+	m.moves = append(m.moves, move)
+	return "0xabc", nil
+}
+
+func newTestInventory(balances map[string]int64) (*inventory.Manager, map[string]*staticFetcher) {
+	inv := inventory.NewManager(0)
+	fetchers := make(map[string]*staticFetcher)
+	for network, balance := range balances {
+		fetcher := &staticFetcher{balance: big.NewInt(balance)}
+		fetchers[network] = fetcher
+		inv.RegisterChain(testChainIDs[network], fetcher)
+	}
+	return inv, fetchers
+}
+
+func testConfig() *Config {
+	cfg := &Config{
+		Enabled:             true,
+		DryRun:              false,
+		IntervalSeconds:     0,
+		MaxMovesPerRun:      5,
+		CooldownSeconds:     600,
+		FillDeadlineMinutes: 0,
+		Assets: []AssetConfig{{
+			Name:   "DOG",
+			Tokens: map[string]string{"Base": baseToken, "Optimism": optimismToken, "Starknet": starknetToken},
+			Targets: map[string]Target{
+				"Base":     {Min: "300", Target: "500", Max: "800"},
+				"Optimism": {Min: "300", Target: "500", Max: "800"},
+				"Starknet": {Min: "300", Target: "500", Max: "800"},
+			},
+			MaxMoveAmount:  "",
+			MinMoveAmount:  "10",
+			MaxDailyAmount: "",
+			FeeBps:         100,
+		}},
+	}
+	if err := cfg.Validate(); err != nil {
+		panic(err)
+	}
+	return cfg
+}
+
+func TestPlan(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("balanced inventory plans nothing", func(t *testing.T) {
+		inv, _ := newTestInventory(map[string]int64{"Base": 500, "Optimism": 400, "Starknet": 700})
+		r := NewRebalancer(testConfig(), inv, &recordingMover{}, testChainIDs)
+
+		moves, err := r.Plan(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, moves)
+	})
+
+	t.Run("moves surplus to deficit down to target", func(t *testing.T) {
+		inv, _ := newTestInventory(map[string]int64{"Base": 100, "Optimism": 1000, "Starknet": 500})
+		r := NewRebalancer(testConfig(), inv, &recordingMover{}, testChainIDs)
+
+		moves, err := r.Plan(ctx)
+		require.NoError(t, err)
+		require.Len(t, moves, 1)
+		assert.Equal(t, "Optimism", moves[0].FromNetwork)
+		assert.Equal(t, "Base", moves[0].ToNetwork)
+		assert.Equal(t, optimismToken, moves[0].InputToken)
+		assert.Equal(t, baseToken, moves[0].OutputToken)
+		// need 400, excess 500 -> 400, minus 1% fee for the filler
+		assert.Equal(t, int64(400), moves[0].AmountIn.Int64())
+		assert.Equal(t, int64(396), moves[0].AmountOut.Int64())
+	})
+
+	t.Run("respects max move and daily caps", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.Assets[0].MaxMoveAmount = "150"
+		cfg.Assets[0].MaxDailyAmount = "200"
+		require.NoError(t, cfg.Validate())
+
+		inv, _ := newTestInventory(map[string]int64{"Base": 0, "Optimism": 1000, "Starknet": 0})
+		r := NewRebalancer(cfg, inv, &recordingMover{}, testChainIDs)
+
+		moves, err := r.Plan(ctx)
+		require.NoError(t, err)
+		require.Len(t, moves, 2)
+		assert.Equal(t, int64(150), moves[0].AmountIn.Int64())
+		assert.Equal(t, int64(50), moves[1].AmountIn.Int64())
+	})
+
+	t.Run("skips moves below the minimum", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.Assets[0].MinMoveAmount = "1000"
+		require.NoError(t, cfg.Validate())
+
+		inv, _ := newTestInventory(map[string]int64{"Base": 100, "Optimism": 1000, "Starknet": 500})
+		r := NewRebalancer(cfg, inv, &recordingMover{}, testChainIDs)
+
+		moves, err := r.Plan(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, moves)
+	})
+
+	t.Run("unknown network fails", func(t *testing.T) {
+		inv, _ := newTestInventory(map[string]int64{"Base": 100, "Optimism": 1000})
+		r := NewRebalancer(testConfig(), inv, &recordingMover{}, map[string]uint64{"Base": 84532, "Optimism": 11155420})
+
+		_, err := r.Plan(ctx)
+		assert.Error(t, err)
+	})
+}
+
+func TestRunOnce(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("dry run never calls the mover", func(t *testing.T) {
+		cfg := testConfig()
+		cfg.DryRun = true
+		inv, _ := newTestInventory(map[string]int64{"Base": 100, "Optimism": 1000, "Starknet": 500})
+		mover := &recordingMover{}
+		r := NewRebalancer(cfg, inv, mover, testChainIDs)
+
+		moves, err := r.RunOnce(ctx)
+		require.NoError(t, err)
+		assert.Len(t, moves, 1)
+		assert.Empty(t, mover.moves)
+	})
+
+	t.Run("cooldown blocks repeating a route", func(t *testing.T) {
+		inv, fetchers := newTestInventory(map[string]int64{"Base": 100, "Optimism": 1000, "Starknet": 500})
+		mover := &recordingMover{}
+		r := NewRebalancer(testConfig(), inv, mover, testChainIDs)
+		now := time.Unix(1_700_000_000, 0)
+		r.now = func() time.Time { return now }
+
+		moves, err := r.RunOnce(ctx)
+		require.NoError(t, err)
+		require.Len(t, moves, 1)
+		require.Len(t, mover.moves, 1)
+
+		// The fill has not landed yet: Base is still short, but the route is cooling down
+		fetchers["Optimism"].balance = big.NewInt(1000)
+		require.NoError(t, inv.Refresh(ctx))
+		moves, err = r.RunOnce(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, moves)
+
+		now = now.Add(11 * time.Minute)
+		moves, err = r.RunOnce(ctx)
+		require.NoError(t, err)
+		assert.Len(t, moves, 1)
+	})
+
+	t.Run("failed moves are not recorded", func(t *testing.T) {
+		inv, _ := newTestInventory(map[string]int64{"Base": 100, "Optimism": 1000, "Starknet": 500})
+		mover := &recordingMover{err: errors.New("rpc down")}
+		r := NewRebalancer(testConfig(), inv, mover, testChainIDs)
+
+		moves, err := r.RunOnce(ctx)
+		require.NoError(t, err)
+		assert.Empty(t, moves)
+		assert.Empty(t, r.lastMove)
+	})
+}
+
+func TestLoadConfig(t *testing.T) {
+	dir := t.TempDir()
+
+	t.Run("applies defaults", func(t *testing.T) {
+		path := filepath.Join(dir, "ok.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"enabled":true,"assets":[{"name":"DOG",
+			"tokens":{"Base":"0x1"},"targets":{"Base":{"min":"1","target":"2","max":"3"}}}]}`), 0o600))
+
+		cfg, err := LoadConfig(path)
+		require.NoError(t, err)
+		assert.Equal(t, DefaultIntervalSeconds, cfg.IntervalSeconds)
+		assert.Equal(t, DefaultMaxMovesPerRun, cfg.MaxMovesPerRun)
+		assert.Equal(t, DefaultFillDeadlineMinutes, cfg.FillDeadlineMinutes)
+	})
+
+	t.Run("rejects inverted bands", func(t *testing.T) {
+		path := filepath.Join(dir, "bad.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"assets":[{"name":"DOG",
+			"tokens":{"Base":"0x1"},"targets":{"Base":{"min":"5","target":"2","max":"3"}}}]}`), 0o600))
+
+		_, err := LoadConfig(path)
+		assert.Error(t, err)
+	})
+
+	t.Run("rejects targets without a token", func(t *testing.T) {
+		path := filepath.Join(dir, "missing.json")
+		require.NoError(t, os.WriteFile(path, []byte(`{"assets":[{"name":"DOG",
+			"tokens":{},"targets":{"Base":{"min":"1","target":"2","max":"3"}}}]}`), 0o600))
+
+		_, err := LoadConfig(path)
+		assert.Error(t, err)
+	})
+}
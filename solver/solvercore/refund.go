@@ -0,0 +1,228 @@
+package solvercore
+
+// Module: One-shot refunds for the refund subcommand
+// - Reads an order's data back from openOrders on its origin chain
+// - Refunds it from its destination chain once its fill deadline has passed
+// - Optionally waits until the origin marks the order REFUNDED
+
+import (
+	"context"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// refundPollInterval is how often RefundOrder checks the origin while waiting for the refund to arrive
+const refundPollInterval = 10 * time.Second
+
+// RefundOrder refunds an expired, unfilled order opened on originNetwork from its destination chain.
+// With wait set it blocks until the origin marks the order REFUNDED or ctx is done.
+func (sm *SolverManager) RefundOrder(ctx context.Context, originNetwork, orderID string, wait bool) error {
+	networkName, network, err := networkByName(originNetwork)
+	if err != nil {
+		return err
+	}
+	if err := sm.initializeEVMClients(); err != nil {
+		return fmt.Errorf("failed to initialize EVM clients: %w", err)
+	}
+	if err := sm.initializeStarknetClients(); err != nil {
+		return fmt.Errorf("failed to initialize Starknet client: %w", err)
+	}
+
+	orderData, err := sm.readOpenOrder(ctx, networkName, network, orderID)
+	if err != nil {
+		return err
+	}
+	args, err := refundArgs(orderID, network, orderData)
+	if err != nil {
+		return err
+	}
+
+	solver := sm.newOneShotSolver()
+
+	logger := sm.logger.With(logutil.Chain(networkName), logutil.OrderID(orderID), logutil.Stage("refund"))
+	logger.Info(fmt.Sprintf("💸 Refunding order (fill deadline %d)", args.ResolvedOrder.FillDeadline))
+	if err := solver.RefundOrder(ctx, args); err != nil {
+		return fmt.Errorf("refund failed: %w", err)
+	}
+	logger.Info("✅ Refund sent; the origin releases funds once the Hyperlane message is delivered")
+	if !wait {
+		return nil
+	}
+
+	ticker := time.NewTicker(refundPollInterval)
+	defer ticker.Stop()
+	for {
+		status, err := solver.OrderStatusOnOrigin(ctx, args)
+		if err != nil {
+			logger.Warn("⚠️  Failed to read origin status", logutil.Err(err))
+		} else if status == "REFUNDED" {
+			logger.Info("💸 Order refunded")
+			return nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("stopped waiting for refund of order %s: %w", orderID, ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// newOneShotSolver builds a Hyperlane7683 solver for single CLI operations, without listeners or background workers
+func (sm *SolverManager) newOneShotSolver() *hyperlane7683.Hyperlane7683Solver {
+	solver := hyperlane7683.NewHyperlane7683Solver(
+		sm.GetEVMClient,
+		sm.GetStarknetClient,
+		sm.GetEVMSigner,
+		sm.GetStarknetSigner,
+		sm.allowBlockLists,
+		sm.inventory,
+	)
+	solver.SetLogger(sm.logger)
+	solver.SetEVMTxManagers(sm.GetEVMTxManager)
+	solver.SetStarknetTxManager(sm.GetStarknetTxManager)
+	if sm.dryRun {
+		solver.EnableDryRun()
+	}
+	return solver
+}
+
+// readOpenOrder returns the ABI-encoded order data stored for orderID on the origin chain
+func (sm *SolverManager) readOpenOrder(ctx context.Context, networkName string, network config.NetworkConfig,
+	orderID string,
+) ([]byte, error) {
+	if strings.Contains(strings.ToLower(networkName), "starknet") {
+		return sm.readStarknetOpenOrder(ctx, orderID)
+	}
+
+	client, err := sm.GetEVMClient(network.ChainID)
+	if err != nil {
+		return nil, err
+	}
+	contract, err := contracts.NewHyperlane7683(network.HyperlaneAddress, client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind contract at %s: %w", network.HyperlaneAddress.Hex(), err)
+	}
+
+	var id [32]byte
+	copy(id[:], common.FromHex(orderID))
+	orderData, err := contract.OpenOrders(&bind.CallOpts{
+		Pending:     false,
+		From:        common.Address{},
+		BlockNumber: nil,
+		BlockHash:   common.Hash{},
+		Context:     ctx,
+	}, id)
+	if err != nil {
+		return nil, fmt.Errorf("openOrders failed on %s: %w", networkName, err)
+	}
+	if len(orderData) == 0 {
+		return nil, fmt.Errorf("order %s is not open on %s", orderID, networkName)
+	}
+	return orderData, nil
+}
+
+func (sm *SolverManager) readStarknetOpenOrder(ctx context.Context, orderID string) ([]byte, error) {
+	provider, err := sm.GetStarknetClient()
+	if err != nil {
+		return nil, err
+	}
+	hyperlaneAddr, err := getStarknetHyperlaneAddress(nil)
+	if err != nil {
+		return nil, err
+	}
+	contract, err := utils.HexToFelt(hyperlaneAddr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Starknet Hyperlane address: %w", err)
+	}
+	idLow, idHigh, err := starknetutil.ConvertSolidityOrderIDForStarknet(orderID)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := provider.Call(ctx, rpc.FunctionCall{
+		ContractAddress:    contract,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("open_orders"),
+		Calldata:           []*felt.Felt{idLow, idHigh},
+	}, rpc.WithBlockTag("latest"))
+	if err != nil {
+		return nil, fmt.Errorf("open_orders failed on Starknet: %w", err)
+	}
+	orderData, err := orderutil.DecodeCairoBytes(resp)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode open_orders response: %w", err)
+	}
+	if len(orderData) == 0 {
+		return nil, fmt.Errorf("order %s is not open on Starknet", orderID)
+	}
+	return orderData, nil
+}
+
+// refundArgs rebuilds the single-instruction order the refund path needs from the origin's stored order data
+func refundArgs(orderID string, origin config.NetworkConfig, orderData []byte) (*types.ParsedArgs, error) {
+	order, err := orderutil.DecodeOrderData(orderData)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode order data: %w", err)
+	}
+
+	var destination *config.NetworkConfig
+	for _, network := range config.Networks {
+		if uint32(network.HyperlaneDomain) == order.DestinationDomain {
+			destination = &network
+			break
+		}
+	}
+	if destination == nil {
+		return nil, fmt.Errorf("no network configured for destination domain %d", order.DestinationDomain)
+	}
+
+	var id [32]byte
+	copy(id[:], common.FromHex(orderID))
+
+	return &types.ParsedArgs{
+		OrderID:       orderID,
+		SenderAddress: "0x" + hex.EncodeToString(order.Sender[:]),
+		Recipients:    []types.Recipient{},
+		ResolvedOrder: types.ResolvedCrossChainOrder{
+			User:          "0x" + hex.EncodeToString(order.Sender[:]),
+			OriginChainID: new(big.Int).SetUint64(origin.ChainID),
+			OpenDeadline:  0,
+			FillDeadline:  order.FillDeadline,
+			OrderID:       id,
+			MaxSpent:      []types.Output{},
+			MinReceived:   []types.Output{},
+			FillInstructions: []types.FillInstruction{{
+				DestinationChainID: new(big.Int).SetUint64(destination.ChainID),
+				DestinationSettler: "0x" + hex.EncodeToString(order.DestinationSettler[:]),
+				OriginData:         orderData,
+			}},
+		},
+	}, nil
+}
+
+// networkByName looks a network up by name, ignoring case
+func networkByName(name string) (string, config.NetworkConfig, error) {
+	for networkName, network := range config.Networks {
+		if strings.EqualFold(networkName, name) {
+			return networkName, network, nil
+		}
+	}
+	return "", config.NetworkConfig{}, fmt.Errorf("unknown network %q (available: %s)", name,
+		strings.Join(config.GetNetworkNames(), ", "))
+}
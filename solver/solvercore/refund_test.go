@@ -0,0 +1,45 @@
+package solvercore
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRefundArgs(t *testing.T) {
+	config.InitializeNetworks()
+	origin := config.Networks["Ethereum"]
+	destination := config.Networks["Base"]
+
+	settler := orderutil.AddressToBytes32(destination.HyperlaneAddress)
+	orderData, err := orderutil.EncodeOrderData(&orderutil.OrderData{
+		Sender:             orderutil.AddressToBytes32(common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")),
+		AmountIn:           big.NewInt(1001),
+		AmountOut:          big.NewInt(1000),
+		SenderNonce:        big.NewInt(1),
+		OriginDomain:       uint32(origin.HyperlaneDomain),
+		DestinationDomain:  uint32(destination.HyperlaneDomain),
+		DestinationSettler: settler,
+		FillDeadline:       1700000000,
+		Data:               []byte{},
+	})
+	require.NoError(t, err)
+
+	args, err := refundArgs("0x01", origin, orderData)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1700000000), args.ResolvedOrder.FillDeadline)
+	assert.Equal(t, origin.ChainID, args.ResolvedOrder.OriginChainID.Uint64())
+	require.Len(t, args.ResolvedOrder.FillInstructions, 1)
+	instruction := args.ResolvedOrder.FillInstructions[0]
+	assert.Equal(t, destination.ChainID, instruction.DestinationChainID.Uint64())
+	assert.Equal(t, common.BytesToHash(settler[:]).Hex(), instruction.DestinationSettler)
+	assert.Equal(t, orderData, instruction.OriginData)
+
+	_, err = refundArgs("0x01", origin, []byte{0x01})
+	assert.Error(t, err)
+}
@@ -4,27 +4,44 @@ import (
 	"context"
 	"fmt"
 	"math/big"
-
 	"strings"
+	"sync"
+	"time"
 
 	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/accounting"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/admin"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/api"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/history"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/inventory"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/rebalancer"
 	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/solvers/hyperlane7683"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/tracing"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/txmanager"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 	"github.com/NethermindEth/starknet.go/account"
 	"github.com/NethermindEth/starknet.go/rpc"
 	"github.com/NethermindEth/starknet.go/utils"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
 // Module: Solver Manager for Hyperlane7683 Protocol
 // - Manages multiple protocol solvers (EVM and Starknet)
-// - Provides centralized client and signer management
+// - Provides centralized client, signer and per-chain transaction manager management
+// - Owns the shared inventory of solver balances and the optional rebalancer
+// - Serves the optional public HTTP API (off-chain gasless order intake) and the authenticated admin API
 // - Coordinates solver initialization and lifecycle
 
+// defaultInventoryRefreshMs is how often cached balances are re-read when INVENTORY_REFRESH_INTERVAL_MS is unset
+const defaultInventoryRefreshMs = 30000
+
 // SolverConfig defines configuration for a solver
 type SolverConfig struct {
 	Enabled bool                   `json:"enabled"`
@@ -42,6 +59,24 @@ type SolverManager struct {
 	activeShutdowns []func()
 	solverRegistry  SolverRegistry
 	allowBlockLists types.AllowBlockLists
+	inventory       *inventory.Manager
+	// Order history, when ORDER_HISTORY_ENABLED is on; shared by the solver and the API
+	history *history.Store
+	// Profit and loss ledger, when ACCOUNTING_ENABLED is on; hypothetical in dry-run mode
+	ledger *accounting.Ledger
+	// Simulate orders instead of filling them (DRY_RUN)
+	dryRun bool
+	// Running Hyperlane7683 solver, set once initialized; the API hands it off-chain orders
+	hyperlane7683Solver *contracts.Hyperlane7683Solver
+	// Shared transaction managers; txManagersMu guards both
+	evmTxManagers     map[uint64]*txmanager.EVM
+	starknetTxManager *txmanager.Starknet
+	txManagersMu      sync.Mutex
+	// Running listeners by network name; controlMu guards them, solverRegistry and allowBlockLists
+	listeners map[string]*networkListener
+	controlMu sync.RWMutex
+	// Logger handed to the solvers and their listeners
+	logger logutil.Logger
 }
 
 // NewSolverManager creates a new solver manager
@@ -63,23 +98,58 @@ func NewSolverManager(cfg *config.Config) *SolverManager {
 			AllowList: []types.AllowBlockListItem{},
 			BlockList: []types.AllowBlockListItem{},
 		},
+		inventory: inventory.NewManager(
+			time.Duration(envutil.GetEnvUint64("INVENTORY_REFRESH_INTERVAL_MS", defaultInventoryRefreshMs)) * time.Millisecond,
+		),
+		history:             nil,
+		ledger:              nil,
+		dryRun:              contracts.DryRunEnabledFromEnv(),
+		hyperlane7683Solver: nil,
+		evmTxManagers:       make(map[uint64]*txmanager.EVM),
+		starknetTxManager:   nil,
+		txManagersMu:        sync.Mutex{},
+		listeners:           make(map[string]*networkListener),
+		controlMu:           sync.RWMutex{},
+		logger:              logutil.Default(),
 	}
 }
 
+// SetLogger replaces the logger handed to solvers and listeners started from now on
+func (sm *SolverManager) SetLogger(logger logutil.Logger) {
+	sm.logger = logger
+}
+
+// GetInventory returns the shared inventory manager
+func (sm *SolverManager) GetInventory() *inventory.Manager {
+	return sm.inventory
+}
+
 // SetAllowBlockLists configures the allow/block lists for the solver manager
-// This allows runtime configuration of which orders to process
+// This allows runtime configuration of which orders to process; a running solver picks them up immediately
 func (sm *SolverManager) SetAllowBlockLists(allowBlockLists types.AllowBlockLists) {
+	sm.controlMu.Lock()
+	defer sm.controlMu.Unlock()
 	sm.allowBlockLists = allowBlockLists
+	if sm.hyperlane7683Solver != nil {
+		sm.hyperlane7683Solver.SetAllowBlockLists(allowBlockLists)
+	}
 }
 
 // GetAllowBlockLists returns the current allow/block lists configuration
 func (sm *SolverManager) GetAllowBlockLists() types.AllowBlockLists {
+	sm.controlMu.RLock()
+	defer sm.controlMu.RUnlock()
 	return sm.allowBlockLists
 }
 
 // InitializeSolvers starts all enabled solvers
 func (sm *SolverManager) InitializeSolvers(ctx context.Context) error {
-	fmt.Printf("🚀 Initializing solvers...\n")
+	sm.logger.Info("🚀 Initializing solvers...")
+
+	// Export order traces, if configured, before any listener can detect an order
+	if err := sm.initializeTracing(ctx); err != nil {
+		return fmt.Errorf("failed to start tracing: %w", err)
+	}
 
 	// Initialize EVM clients for all EVM networks
 	if err := sm.initializeEVMClients(); err != nil {
@@ -91,10 +161,28 @@ func (sm *SolverManager) InitializeSolvers(ctx context.Context) error {
 		return fmt.Errorf("failed to initialize Starknet client: %w", err)
 	}
 
+	// Register balance sources and start periodic refresh
+	sm.initializeInventory(ctx)
+
+	// Open the order history, if enabled, before any order is processed
+	if err := sm.initializeHistory(ctx); err != nil {
+		return fmt.Errorf("failed to open order history: %w", err)
+	}
+
+	// Open the profit and loss ledger, if enabled
+	if err := sm.initializeAccounting(ctx); err != nil {
+		return fmt.Errorf("failed to open accounting ledger: %w", err)
+	}
+
+	// Start moving inventory toward configured targets, if configured
+	if err := sm.initializeRebalancer(ctx); err != nil {
+		return fmt.Errorf("failed to initialize rebalancer: %w", err)
+	}
+
 	// Initialize individual solvers
-	for solverName, config := range sm.solverRegistry {
+	for solverName, config := range sm.solverSnapshot() {
 		if !config.Enabled {
-			fmt.Printf("   ⏭️  Solver %s is disabled, skipping...\n", solverName)
+			sm.logger.Info(fmt.Sprintf("   ⏭️  Solver %s is disabled, skipping...", solverName))
 			continue
 		}
 
@@ -103,10 +191,112 @@ func (sm *SolverManager) InitializeSolvers(ctx context.Context) error {
 		}
 	}
 
-	fmt.Printf("✅ All solvers initialized successfully\n")
+	// Serve the public API, if configured
+	if err := sm.initializeAPI(ctx); err != nil {
+		return fmt.Errorf("failed to start API: %w", err)
+	}
+
+	// Serve the admin API, if configured
+	if err := sm.initializeAdmin(ctx); err != nil {
+		return fmt.Errorf("failed to start admin API: %w", err)
+	}
+
+	// Serve Prometheus metrics, if configured
+	if err := sm.initializeMetrics(ctx); err != nil {
+		return fmt.Errorf("failed to start metrics server: %w", err)
+	}
+
+	// Serve health and readiness endpoints, if configured
+	if err := sm.initializeHealth(ctx); err != nil {
+		return fmt.Errorf("failed to start health server: %w", err)
+	}
+
+	// Watch for conditions an operator must be told about, if configured
+	if err := sm.initializeAlerting(ctx); err != nil {
+		return fmt.Errorf("failed to start alerting: %w", err)
+	}
+
+	sm.logger.Info("✅ All solvers initialized successfully")
+	return nil
+}
+
+// initializeAPI starts the public HTTP API when API_LISTEN_ADDR is set
+func (sm *SolverManager) initializeAPI(ctx context.Context) error {
+	cfg := api.ConfigFromEnv()
+	if !cfg.Enabled() {
+		return nil
+	}
+	if sm.hyperlane7683Solver == nil {
+		return fmt.Errorf("the API needs the hyperlane7683 solver to be enabled")
+	}
+	server := api.NewServer(cfg, sm.hyperlane7683Solver)
+	if sm.history != nil {
+		server.SetHistory(sm.history)
+	}
+	return server.Start(ctx)
+}
+
+// initializeAccounting opens the ledger when ACCOUNTING_ENABLED is on and closes it once ctx is done
+func (sm *SolverManager) initializeAccounting(ctx context.Context) error {
+	cfg := accounting.ConfigFromEnv()
+	if !cfg.Enabled() {
+		return nil
+	}
+	path := cfg.Path(sm.dryRun)
+	ledger, err := accounting.Open(path)
+	if err != nil {
+		return err
+	}
+	sm.ledger = ledger
+	go func() {
+		<-ctx.Done()
+		if err := ledger.Close(); err != nil {
+			sm.logger.Warn("⚠️  Failed to close accounting ledger", logutil.Err(err))
+		}
+	}()
+	sm.logger.Info(fmt.Sprintf("   🧾 Recording profit and loss to %s", path))
 	return nil
 }
 
+// initializeHistory opens the order history when ORDER_HISTORY_ENABLED is on and closes it once ctx is done
+func (sm *SolverManager) initializeHistory(ctx context.Context) error {
+	cfg := history.ConfigFromEnv()
+	if !cfg.Enabled() {
+		return nil
+	}
+	store, err := history.Open(cfg.File)
+	if err != nil {
+		return err
+	}
+	sm.history = store
+	go func() {
+		<-ctx.Done()
+		if err := store.Close(); err != nil {
+			sm.logger.Warn("⚠️  Failed to close order history", logutil.Err(err))
+		}
+	}()
+	sm.logger.Info(fmt.Sprintf("   📒 Recording order history to %s", cfg.File))
+	return nil
+}
+
+// initializeMetrics serves /metrics when METRICS_LISTEN_ADDR is set
+func (sm *SolverManager) initializeMetrics(ctx context.Context) error {
+	cfg := metrics.ConfigFromEnv()
+	if !cfg.Enabled() {
+		return nil
+	}
+	return metrics.Start(ctx, cfg)
+}
+
+// initializeTracing exports order spans when TRACING_OTLP_ENDPOINT is set
+func (sm *SolverManager) initializeTracing(ctx context.Context) error {
+	cfg := tracing.ConfigFromEnv()
+	if !cfg.Enabled() {
+		return nil
+	}
+	return tracing.Start(ctx, cfg)
+}
+
 // initializeSolver starts a specific solver
 func (sm *SolverManager) initializeSolver(ctx context.Context, name string) error {
 	switch name {
@@ -119,7 +309,7 @@ func (sm *SolverManager) initializeSolver(ctx context.Context, name string) erro
 
 // initializeEVMClients initializes EVM RPC connections for all EVM networks
 func (sm *SolverManager) initializeEVMClients() error {
-	fmt.Printf("🔗 Initializing EVM clients...\n")
+	sm.logger.Info("🔗 Initializing EVM clients...")
 
 	evmCount := 0
 	for networkName, networkConfig := range config.Networks {
@@ -127,8 +317,8 @@ func (sm *SolverManager) initializeEVMClients() error {
 		if strings.Contains(strings.ToLower(networkName), "starknet") {
 			continue
 		}
-		
-		fmt.Printf("   🔗 Initializing EVM client for %s (Chain ID: %d)\n", networkName, networkConfig.ChainID)
+
+		sm.logger.Info(fmt.Sprintf("   🔗 Initializing EVM client (Chain ID: %d)", networkConfig.ChainID), logutil.Chain(networkName))
 
 		client, err := ethclient.Dial(networkConfig.RPCURL)
 		if err != nil {
@@ -136,25 +326,25 @@ func (sm *SolverManager) initializeEVMClients() error {
 		}
 
 		sm.evmClients[networkConfig.ChainID] = client
-		fmt.Printf("   ✅ EVM client initialized for %s\n", networkName)
+		sm.logger.Info("   ✅ EVM client initialized", logutil.Chain(networkName))
 		evmCount++
 	}
 
-	fmt.Printf("✅ All EVM clients initialized (%d networks)\n", evmCount)
+	sm.logger.Info(fmt.Sprintf("✅ All EVM clients initialized (%d networks)", evmCount))
 	return nil
 }
 
 // initializeStarknetClients initializes Starknet RPC connection for the first Starknet network found
 func (sm *SolverManager) initializeStarknetClients() error {
-	fmt.Printf("🔗 Initializing Starknet client...\n")
+	sm.logger.Info("🔗 Initializing Starknet client...")
 
 	for networkName, networkConfig := range config.Networks {
 		// Check if this is a Starknet network
 		if !strings.Contains(strings.ToLower(networkName), "starknet") {
 			continue
 		}
-		
-		fmt.Printf("   🔗 Initializing Starknet client for %s (Chain ID: %d)\n", networkName, networkConfig.ChainID)
+
+		sm.logger.Info(fmt.Sprintf("   🔗 Initializing Starknet client (Chain ID: %d)", networkConfig.ChainID), logutil.Chain(networkName))
 
 		provider, err := rpc.NewProvider(networkConfig.RPCURL)
 		if err != nil {
@@ -162,11 +352,64 @@ func (sm *SolverManager) initializeStarknetClients() error {
 		}
 
 		sm.starknetClient = provider
-		fmt.Printf("✅ Starknet client initialized successfully\n")
+		sm.logger.Info("✅ Starknet client initialized successfully", logutil.Chain(networkName))
 		return nil // Only need one Starknet client
 	}
 
-	fmt.Printf("⚠️  No Starknet networks found in config\n")
+	sm.logger.Warn("⚠️  No Starknet networks found in config")
+	return nil
+}
+
+// initializeInventory registers a balance fetcher for every connected chain and starts the refresh loop
+func (sm *SolverManager) initializeInventory(ctx context.Context) {
+	if solverAddr := envutil.GetSolverPublicKey(); solverAddr != "" {
+		for chainID, client := range sm.evmClients {
+			sm.inventory.RegisterChain(chainID, inventory.NewEVMBalanceFetcher(client, common.HexToAddress(solverAddr)))
+		}
+	} else {
+		sm.logger.Warn("⚠️  SOLVER_PUB_KEY not set, EVM inventory disabled")
+	}
+
+	if sm.starknetClient != nil {
+		for _, networkConfig := range config.Networks {
+			if strings.Contains(strings.ToLower(networkConfig.Name), "starknet") {
+				sm.inventory.RegisterChain(networkConfig.ChainID,
+					inventory.NewStarknetBalanceFetcher(sm.starknetClient, envutil.GetStarknetSolverAddress()))
+			}
+		}
+	}
+
+	go sm.inventory.Start(ctx)
+}
+
+// initializeRebalancer starts the rebalancer when REBALANCE_CONFIG_FILE points to an enabled config
+func (sm *SolverManager) initializeRebalancer(ctx context.Context) error {
+	path := envutil.GetEnvWithDefault("REBALANCE_CONFIG_FILE", "")
+	if path == "" {
+		return nil
+	}
+
+	cfg, err := rebalancer.LoadConfig(path)
+	if err != nil {
+		return err
+	}
+	if !cfg.Enabled {
+		sm.logger.Info(fmt.Sprintf("   ⏭️  Rebalancer disabled in %s", path))
+		return nil
+	}
+	if sm.dryRun && !cfg.DryRun {
+		sm.logger.Info("   🧪 Dry run: the rebalancer only logs the moves it would make")
+		cfg.DryRun = true
+	}
+
+	chainIDs := make(map[string]uint64, len(config.Networks))
+	for name, networkConfig := range config.Networks {
+		chainIDs[name] = networkConfig.ChainID
+	}
+
+	mover := rebalancer.NewOrderMover(sm.GetEVMClient, sm.GetEVMTxManager, sm.GetStarknetTxManager,
+		time.Duration(cfg.FillDeadlineMinutes)*time.Minute)
+	go rebalancer.NewRebalancer(cfg, sm.inventory, mover, chainIDs).Start(ctx)
 	return nil
 }
 
@@ -211,6 +454,64 @@ func (sm *SolverManager) GetEVMSigner(chainID uint64) (*bind.TransactOpts, error
 	return signer, nil
 }
 
+// GetEVMTxManager returns the transaction manager for the solver's key on chainID.
+// All EVM transactions from the solver go through it so nonces stay consistent across components.
+func (sm *SolverManager) GetEVMTxManager(chainID uint64) (*txmanager.EVM, error) {
+	sm.txManagersMu.Lock()
+	defer sm.txManagersMu.Unlock()
+
+	if txm, exists := sm.evmTxManagers[chainID]; exists {
+		return txm, nil
+	}
+
+	client, err := sm.GetEVMClient(chainID)
+	if err != nil {
+		return nil, err
+	}
+	signer, err := sm.GetEVMSigner(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg := txmanager.EVMConfigFromEnv()
+	if confirmations, err := config.GetTxConfirmationsByChainID(chainID); err == nil {
+		cfg.Confirmations = confirmations
+	}
+	txm := txmanager.NewEVM(client, signer, chainID, cfg)
+	sm.evmTxManagers[chainID] = txm
+	return txm, nil
+}
+
+// GetStarknetTxManager returns the transaction manager for the solver's Starknet account.
+// All Starknet invokes from the solver go through it so nonces stay consistent across components.
+func (sm *SolverManager) GetStarknetTxManager() (*txmanager.Starknet, error) {
+	sm.txManagersMu.Lock()
+	defer sm.txManagersMu.Unlock()
+
+	if sm.starknetTxManager != nil {
+		return sm.starknetTxManager, nil
+	}
+
+	var chainID uint64
+	for networkName, networkConfig := range config.Networks {
+		if strings.Contains(strings.ToLower(networkName), "starknet") {
+			chainID = networkConfig.ChainID
+			break
+		}
+	}
+	if chainID == 0 {
+		return nil, fmt.Errorf("no Starknet network found in config")
+	}
+
+	acct, err := sm.GetStarknetSigner()
+	if err != nil {
+		return nil, err
+	}
+
+	sm.starknetTxManager = txmanager.NewStarknet(acct, chainID, txmanager.StarknetConfigFromEnv())
+	return sm.starknetTxManager, nil
+}
+
 // GetStarknetSigner returns the Starknet signer
 func (sm *SolverManager) GetStarknetSigner() (*account.Account, error) {
 	// For now, create a new signer each time
@@ -250,35 +551,81 @@ func (sm *SolverManager) GetStarknetSigner() (*account.Account, error) {
 
 // initializeHyperlane7683 starts the Hyperlane 7683 solver
 func (sm *SolverManager) initializeHyperlane7683(ctx context.Context) error {
-	fmt.Printf("   🔧 Setting up Hyperlane7683 solver components...\n")
+	sm.logger.Info("   🔧 Setting up Hyperlane7683 solver components...")
 
 	// Create solver with client and signer getter functions
 	hyperlane7683Solver := contracts.NewHyperlane7683Solver(
-		sm.GetEVMClient,      // EVM client getter
-		sm.GetStarknetClient, // Starknet client getter
-		sm.GetEVMSigner,      // EVM signer getter
-		sm.GetStarknetSigner, // Starknet signer getter
-		sm.allowBlockLists,   // Allow/block lists
+		sm.GetEVMClient,         // EVM client getter
+		sm.GetStarknetClient,    // Starknet client getter
+		sm.GetEVMSigner,         // EVM signer getter
+		sm.GetStarknetSigner,    // Starknet signer getter
+		sm.GetAllowBlockLists(), // Allow/block lists
+		sm.inventory,            // Balances checked and reserved for each order
 	)
+	hyperlane7683Solver.SetLogger(sm.logger)
+	if sm.history != nil {
+		hyperlane7683Solver.SetHistory(sm.history)
+	}
+	if sm.ledger != nil {
+		hyperlane7683Solver.SetLedger(sm.ledger)
+	}
+	hyperlane7683Solver.SetEVMTxManagers(sm.GetEVMTxManager)
+	hyperlane7683Solver.SetStarknetTxManager(sm.GetStarknetTxManager)
+	hyperlane7683Solver.SetOwnAddresses(envutil.GetSolverPublicKey(), envutil.GetStarknetSolverAddress())
+	hyperlane7683Solver.SetFillerAddresses(contracts.FillerAddresses{
+		EVM:      envutil.GetSolverPublicKey(),
+		Starknet: envutil.GetStarknetSolverAddress(),
+	})
+	hyperlane7683Solver.SetQuoteConfig(contracts.QuoteConfigFromEnv())
+	if sm.dryRun {
+		// Nothing is filled, so there is nothing to settle in batches and no refund may be sent
+		sm.logger.Warn("   🧪 Dry run: orders are simulated, not filled; settlement batching and refund watching are off")
+		hyperlane7683Solver.EnableDryRun()
+	} else {
+		if batchCfg := contracts.SettlementBatchConfigFromEnv(); batchCfg.Enabled() {
+			sm.logger.Info(fmt.Sprintf("   📦 Settlement batching enabled (size %d, max age %s)", batchCfg.MaxSize, batchCfg.MaxAge))
+			hyperlane7683Solver.EnableSettlementBatching(ctx, batchCfg)
+		}
+		if refundCfg := contracts.RefundConfigFromEnv(); refundCfg.Enabled() {
+			sm.logger.Info(fmt.Sprintf("   💸 Refund watcher enabled for %d account(s) (check every %s)", len(refundCfg.Accounts), refundCfg.CheckInterval))
+			hyperlane7683Solver.EnableRefundWatching(ctx, refundCfg)
+		}
+	}
 	hyperlane7683Solver.AddDefaultRules()
+	sm.hyperlane7683Solver = hyperlane7683Solver
 
 	// Event handler that processes intents
-	eventHandler := func(args types.ParsedArgs, originChainName string, blockNumber uint64) (bool, error) {
-		return hyperlane7683Solver.ProcessIntent(ctx, &args)
+	eventHandler := func(eventCtx context.Context, args types.ParsedArgs, originChainName string, blockNumber uint64) (bool, error) {
+		return hyperlane7683Solver.ProcessIntent(eventCtx, &args)
+	}
+
+	// Listeners also report Filled events, so fills another solver wins first are aborted
+	watchFills := func(listener base.ControllableListener) {
+		if watcher, ok := listener.(contracts.FillWatcher); ok {
+			watcher.WatchFills(hyperlane7683Solver.ObserveFill)
+		}
+	}
+
+	// Orders of watched accounts opened before a restart are found again on the settlers
+	restoreRefundWatch := func(source string, listener base.ControllableListener) {
+		if scanner, ok := listener.(contracts.OpenOrderScanner); ok {
+			go hyperlane7683Solver.RestoreRefundWatch(ctx, source, scanner)
+		}
 	}
 
 	// Start listeners for each intent source
-	fmt.Printf("   📡 Starting network listeners...\n")
+	sm.logger.Info("   📡 Starting network listeners...")
 	listenerCount := 0
 
 	for _, source := range []string{"Base", "Optimism", "Arbitrum", "Ethereum", "Starknet"} {
 		networkConfig, exists := config.Networks[source]
 		if !exists {
-			fmt.Printf("     ⚠️  Network %s not found in config, skipping...\n", source)
+			sm.logger.Warn("     ⚠️  Network not found in config, skipping...", logutil.Chain(source))
 			continue
 		}
 
 		var shutdown base.ShutdownFunc
+		var listener base.ControllableListener
 
 		// Create appropriate listener based on chain type
 		if source == "Starknet" {
@@ -294,18 +641,20 @@ func (sm *SolverManager) initializeHyperlane7683(ctx context.Context) error {
 				source,
 				big.NewInt(networkConfig.SolverStartBlock), // pass original value (can be negative)
 				networkConfig.PollInterval,                 // poll interval from config
-				networkConfig.ConfirmationBlocks,   // confirmation blocks from config
+				networkConfig.ConfirmationBlocks,           // confirmation blocks from config
 				networkConfig.MaxBlockRange,                // max block range from config
 			)
 
-			starknetListener, err := contracts.NewStarknetListener(listenerConfig, networkConfig.RPCURL)
+			starknetListener, err := contracts.NewStarknetListener(listenerConfig, networkConfig.RPCURL, sm.logger)
 			if err != nil {
 				return fmt.Errorf("failed to create Starknet listener: %w", err)
 			}
+			watchFills(starknetListener)
 			shutdown, err = starknetListener.Start(ctx, eventHandler)
 			if err != nil {
 				return fmt.Errorf("failed to start Starknet listener for %s: %w", source, err)
 			}
+			listener = starknetListener
 		} else {
 			// Create EVM listener config with original solver start block
 			// The listener will handle negative value resolution
@@ -314,52 +663,77 @@ func (sm *SolverManager) initializeHyperlane7683(ctx context.Context) error {
 				source,
 				big.NewInt(networkConfig.SolverStartBlock), // pass original value (can be negative)
 				networkConfig.PollInterval,                 // poll interval from config
-				networkConfig.ConfirmationBlocks,   // confirmation blocks from config
+				networkConfig.ConfirmationBlocks,           // confirmation blocks from config
 				networkConfig.MaxBlockRange,                // max block range from config
 			)
 
-			evmListener, err := contracts.NewEVMListener(listenerConfig, networkConfig.RPCURL)
+			evmListener, err := contracts.NewEVMListener(listenerConfig, networkConfig.RPCURL, sm.logger)
 			if err != nil {
 				return fmt.Errorf("failed to create EVM listener: %w", err)
 			}
+			watchFills(evmListener)
 			shutdown, err = evmListener.Start(ctx, eventHandler)
 			if err != nil {
 				return fmt.Errorf("failed to start EVM listener for %s: %w", source, err)
 			}
+			listener = evmListener
 		}
 
+		restoreRefundWatch(source, listener)
 		sm.activeShutdowns = append(sm.activeShutdowns, shutdown)
+		sm.registerListener(source, "hyperlane7683", listener)
 		listenerCount++
-		fmt.Printf("     ✅ Started listener for %s\n", source)
+		sm.logger.Info("     ✅ Started listener", logutil.Chain(source))
 	}
 
-	fmt.Printf("   📡 All network listeners started (%d networks)\n", listenerCount)
+	sm.logger.Info(fmt.Sprintf("   📡 All network listeners started (%d networks)", listenerCount))
 	return nil
 }
 
 // AddSolver dynamically adds a new solver to the registry
 func (sm *SolverManager) AddSolver(name string, config SolverConfig) {
+	sm.controlMu.Lock()
+	defer sm.controlMu.Unlock()
 	sm.solverRegistry[name] = config
 }
 
-// EnableSolver enables a solver
+// EnableSolver enables a solver; its running listeners resume unless an operator paused them
 func (sm *SolverManager) EnableSolver(name string) error {
-	if config, exists := sm.solverRegistry[name]; exists {
-		config.Enabled = true
-		sm.solverRegistry[name] = config
-		return nil
-	}
-	return fmt.Errorf("solver %s not found", name)
+	return sm.setSolverEnabled(name, true)
 }
 
-// DisableSolver disables a solver
+// DisableSolver disables a solver; its running listeners pause where they are until it is enabled again
 func (sm *SolverManager) DisableSolver(name string) error {
-	if config, exists := sm.solverRegistry[name]; exists {
-		config.Enabled = false
-		sm.solverRegistry[name] = config
-		return nil
+	return sm.setSolverEnabled(name, false)
+}
+
+func (sm *SolverManager) setSolverEnabled(name string, enabled bool) error {
+	sm.controlMu.Lock()
+	defer sm.controlMu.Unlock()
+
+	config, exists := sm.solverRegistry[name]
+	if !exists {
+		return fmt.Errorf("solver %s %w", name, admin.ErrNotFound)
+	}
+	config.Enabled = enabled
+	sm.solverRegistry[name] = config
+	for _, entry := range sm.listeners {
+		if entry.solver == name {
+			sm.applyListenerStateLocked(entry)
+		}
+	}
+	return nil
+}
+
+// solverSnapshot returns a copy of the solver registry
+func (sm *SolverManager) solverSnapshot() SolverRegistry {
+	sm.controlMu.RLock()
+	defer sm.controlMu.RUnlock()
+	snapshot := make(SolverRegistry, len(sm.solverRegistry))
+	for name, config := range sm.solverRegistry {
+		snapshot[name] = config
 	}
-	return fmt.Errorf("solver %s not found", name)
+	return snapshot
 }
 
 // Start initializes and runs all solvers
@@ -379,33 +753,32 @@ func (sm *SolverManager) Start(ctx context.Context) error {
 
 // Shutdown stops all active solvers
 func (sm *SolverManager) Shutdown() {
-	fmt.Printf("🛑 Shutting down solvers...\n")
+	sm.logger.Info("🛑 Shutting down solvers...")
 
 	listenerCount := len(sm.activeShutdowns)
 	for i, shutdown := range sm.activeShutdowns {
-		fmt.Printf("   📡 Stopping listener %d/%d\n", i+1, listenerCount)
+		sm.logger.Info(fmt.Sprintf("   📡 Stopping listener %d/%d", i+1, listenerCount))
 		shutdown()
 	}
 
 	sm.activeShutdowns = make([]func(), 0)
-	fmt.Printf("✅ All solvers shut down successfully (%d listeners stopped)\n", listenerCount)
+	sm.logger.Info(fmt.Sprintf("✅ All solvers shut down successfully (%d listeners stopped)", listenerCount))
 }
 
 // GetSolverStatus returns the status of all solvers
 func (sm *SolverManager) GetSolverStatus() map[string]bool {
 	status := make(map[string]bool)
-	for name, config := range sm.solverRegistry {
+	for name, config := range sm.solverSnapshot() {
 		status[name] = config.Enabled
 	}
 	return status
 }
 
-
 // getStarknetHyperlaneAddress gets the Starknet Hyperlane address from environment
 func getStarknetHyperlaneAddress(_ *config.NetworkConfig) (string, error) {
 	envAddr := envutil.GetEnvWithDefault("STARKNET_HYPERLANE_ADDRESS", "")
 	if envAddr != "" {
-		fmt.Printf("   🔄 Using Starknet Hyperlane address from .env: %s\n", envAddr)
+		logutil.Default().Info(fmt.Sprintf("   🔄 Using Starknet Hyperlane address from .env: %s", envAddr))
 		return envAddr, nil
 	} else {
 		return "", fmt.Errorf("no STARKNET_HYPERLANE_ADDRESS set in .env")
@@ -2,7 +2,10 @@ package hyperlane7683
 
 import (
 	"context"
+	"errors"
+	"math/big"
 
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderutil"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 )
 
@@ -13,8 +16,13 @@ const (
 	OrderActionSettle   OrderAction = iota // Order needs settlement
 	OrderActionComplete                    // Order is 100% complete (filled + settled)
 	OrderActionError                       // Error occurred during fill
+	OrderActionFilled                      // This call broadcast the fill; the order needs settlement
 )
 
+// ErrSettlementDeferred is returned by Settle and SettleBatch for filled orders that cannot be settled yet,
+// such as Starknet-origin orders on live EVM networks until the Starknet domain is registered there
+var ErrSettlementDeferred = errors.New("settlement deferred")
+
 // ChainHandler defines the interface that all chain-specific handlers must implement.
 // This allows easy extension to new blockchains (Cosmos, Solana, etc.) by simply
 // implementing this interface and registering the handler in the solver.
@@ -26,7 +34,8 @@ const (
 //  4. Register in solver manager - that's it!
 type ChainHandler interface {
 	// Fill executes a fill operation on the chain
-	// Returns OrderAction indicating next step (settle, complete, or error)
+	// Returns OrderAction indicating next step (settle, complete, or error); OrderActionFilled instead of
+	// OrderActionSettle tells the solver this call sent the fill rather than finding it already done
 	Fill(ctx context.Context, args *types.ParsedArgs) (OrderAction, error)
 
 	// Settle executes settlement on the chain after successful fill
@@ -38,6 +47,60 @@ type ChainHandler interface {
 	GetOrderStatus(ctx context.Context, args *types.ParsedArgs) (string, error)
 }
 
+// BatchSettler is implemented by handlers that can settle several orders in one transaction.
+// All orders in a batch must share the destination chain, destination settler and origin domain.
+type BatchSettler interface {
+	// SettleBatch settles every order with a single settle call and interchain gas payment
+	SettleBatch(ctx context.Context, orders []*types.ParsedArgs) error
+}
+
+// Refunder is implemented by handlers that can refund expired, unfilled orders from their destination chain.
+// All orders in a call must share the destination settler and origin domain.
+type Refunder interface {
+	// Refund dispatches a refund for every order with a single refund call and interchain gas payment
+	Refund(ctx context.Context, orders []*types.ParsedArgs) error
+}
+
+// GaslessOpener is implemented by handlers whose origin settler accepts signed gasless orders via openFor.
+type GaslessOpener interface {
+	// VerifyGaslessOrder checks the order against the origin settler: signature, nonce, deadlines and domain.
+	// Errors caused by the order itself wrap ErrOrderRejected.
+	VerifyGaslessOrder(ctx context.Context, order *orderutil.SignedGaslessOrder) error
+
+	// OpenFor opens the order on the user's behalf; the settler pulls the user's inputs through Permit2
+	OpenFor(ctx context.Context, order *orderutil.SignedGaslessOrder) error
+}
+
+// FillCostEstimator is implemented by handlers that can price a fill and its settlement in the chain's native token,
+// which on Starknet is the fee token.
+type FillCostEstimator interface {
+	// EstimateFillCost returns the native cost of one fill plus settlement at current gas prices.
+	// It fails with ErrOrderRejected if the solver's native balance cannot pay it.
+	EstimateFillCost(ctx context.Context) (*big.Int, error)
+}
+
+// TokenDecimalsReader is implemented by handlers that can read how many decimals a token uses on their chain.
+type TokenDecimalsReader interface {
+	TokenDecimals(ctx context.Context, token string) (uint8, error)
+}
+
+// Simulator is implemented by handlers that can run an order's transactions against the latest state without sending them.
+type Simulator interface {
+	// SimulateFill runs the approvals and fill the order would send, reporting whether they would succeed and their fee
+	SimulateFill(ctx context.Context, args *types.ParsedArgs) (*Simulation, error)
+
+	// SimulateSettle quotes the interchain gas payment and runs the settle once the order is filled on chain;
+	// before that it can only estimate the settle's fee
+	SimulateSettle(ctx context.Context, args *types.ParsedArgs) (*Simulation, error)
+}
+
+// FillerChecker is implemented by handlers that can read from the destination settler who filled an order.
+type FillerChecker interface {
+	// CheckFiller fails with failure.ErrCompetitorFilled when another solver filled the order;
+	// it returns nil for orders not filled yet and for the solver's own fills
+	CheckFiller(ctx context.Context, args *types.ParsedArgs) error
+}
+
 // ChainHandlerFactory creates chain handlers for specific networks
 // This allows the solver to create handlers on-demand for different chains
 type ChainHandlerFactory interface {
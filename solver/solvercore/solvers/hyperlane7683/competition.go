@@ -0,0 +1,183 @@
+package hyperlane7683
+
+// Module: Competitive fills for Hyperlane7683
+// - Names the solver as filler of its fills, through the fillerData receiver the origin settler pays out to
+// - Tells the solver's fills from other solvers' by the filler recorded for the order on the destination settler
+// - Aborts in-flight fills once a listener sees another solver's Filled event for the order
+// - Counts won and lost fill races per route
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// receiverLength is the size of the fillerData receiver: a bytes32 address on both EVM and Starknet origins
+const receiverLength = 32
+
+// FillerAddresses are the solver's accounts paid on the origin chain once a fill is settled: the EVM account for
+// orders opened on EVM chains and the Starknet account for orders opened on Starknet
+type FillerAddresses struct {
+	EVM      string
+	Starknet string
+}
+
+// receiver returns the fillerData naming the solver as filler of an order from originChainID,
+// or nil when no address is configured for the origin
+func (a FillerAddresses) receiver(originChainID *big.Int) ([]byte, error) {
+	address := a.EVM
+	if originChainID != nil && isStarknetChainID(originChainID) {
+		address = a.Starknet
+	}
+	if address == "" {
+		return nil, nil
+	}
+	digits := strings.TrimPrefix(strings.TrimPrefix(address, "0x"), "0X")
+	if len(digits)%2 == 1 {
+		digits = "0" + digits
+	}
+	raw, err := hex.DecodeString(digits)
+	if err != nil || len(raw) > receiverLength {
+		return nil, fmt.Errorf("invalid filler address %q", address)
+	}
+	return common.LeftPadBytes(raw, receiverLength), nil
+}
+
+// owns reports whether fillerData names the solver as filler of an order from originChainID.
+// Fills that cannot be told apart, because they carry no fillerData or no address is configured for the origin,
+// count as the solver's own.
+func (a FillerAddresses) owns(originChainID *big.Int, fillerData []byte) bool {
+	receiver, err := a.receiver(originChainID)
+	if err != nil || receiver == nil || len(fillerData) == 0 {
+		return true
+	}
+	return len(fillerData) >= receiverLength && bytes.Equal(fillerData[:receiverLength], receiver)
+}
+
+// withDefaults fills the addresses left empty from defaults
+func (a FillerAddresses) withDefaults(defaults FillerAddresses) FillerAddresses {
+	if a.EVM == "" {
+		a.EVM = defaults.EVM
+	}
+	if a.Starknet == "" {
+		a.Starknet = defaults.Starknet
+	}
+	return a
+}
+
+// competitorFilled is the error for an order another solver filled, naming the filler it recorded
+func competitorFilled(orderID string, fillerData []byte) error {
+	return fmt.Errorf("%w: order %s, filler data 0x%s", failure.ErrCompetitorFilled, orderID, hex.EncodeToString(fillerData))
+}
+
+// FilledEvent is a Filled event emitted by a Hyperlane7683 settler
+type FilledEvent struct {
+	OrderID    string
+	ChainName  string
+	Block      uint64
+	FillerData []byte
+}
+
+// FillObserver is told about the Filled events a listener sees
+type FillObserver func(ctx context.Context, fill FilledEvent)
+
+// FillWatcher is implemented by listeners that can report the Filled events of their settler
+// alongside the Open events they hand to the solver
+type FillWatcher interface {
+	WatchFills(observer FillObserver)
+}
+
+// SetFillerAddresses sets the accounts the solver's fills pay out to; handlers default to their own signer
+func (f *Hyperlane7683Solver) SetFillerAddresses(addresses FillerAddresses) {
+	f.fillers = addresses
+}
+
+// ObserveFill aborts the order's in-flight fill, unless it was already broadcast, when the Filled event was emitted
+// for another solver's fill
+func (f *Hyperlane7683Solver) ObserveFill(_ context.Context, fill FilledEvent) {
+	order, ok := f.inFlight.get(fill.OrderID)
+	if !ok {
+		return
+	}
+	if f.fillers.owns(new(big.Int).SetUint64(order.OriginChainID), fill.FillerData) {
+		return
+	}
+	if f.inFlight.abort(fill.OrderID, competitorFilled(fill.OrderID, fill.FillerData)) {
+		f.log().Info("🏁 Another solver filled the order, aborting our fill",
+			logutil.OrderID(fill.OrderID), logutil.Chain(fill.ChainName), logutil.Block(fill.Block))
+	}
+}
+
+// lostRace tells whether a failed fill lost the race to another solver and returns the error to report.
+// The fill was lost when it was aborted on another solver's Filled event, or when it reverted and the destination
+// settler records another filler for the order; other errors are returned unchanged.
+func (f *Hyperlane7683Solver) lostRace(ctx context.Context, leg *types.ParsedArgs, chainID *big.Int, err error) (bool, error) {
+	if errors.Is(err, failure.ErrCompetitorFilled) {
+		return true, err
+	}
+	if cause := context.Cause(ctx); errors.Is(cause, failure.ErrCompetitorFilled) {
+		return true, cause
+	}
+	if failure.KindOf(err) != failure.KindRevert {
+		return false, err
+	}
+
+	// A fill that passed pre-flight usually reverts because another solver's fill was mined first
+	var lost error
+	_, _ = f.executeChainOperation(ctx, leg, chainID, "status", func(ctx context.Context, handler ChainHandler) (OrderAction, error) {
+		if checker, ok := handler.(FillerChecker); ok {
+			lost = checker.CheckFiller(ctx, leg)
+		}
+		return OrderActionComplete, nil
+	})
+	if errors.Is(lost, failure.ErrCompetitorFilled) {
+		return true, lost
+	}
+	return false, err
+}
+
+// abortedBeforeBroadcast returns why ctx was cancelled, if it was. Aborts are only honoured before a transaction is
+// broadcast: once sent it is handed to the transaction manager under context.WithoutCancel, so it keeps being bumped
+// and tracked to its receipt and its gas is recorded even when another solver won the race.
+func abortedBeforeBroadcast(ctx context.Context) error {
+	if ctx.Err() == nil {
+		return nil
+	}
+	return context.Cause(ctx)
+}
+
+// recordFillRace counts a fill of the leg as won or lost on the leg's route
+func recordFillRace(leg *types.ParsedArgs, won bool) {
+	destination := "unknown"
+	if len(leg.ResolvedOrder.FillInstructions) > 0 {
+		destination = chainLabel(leg.ResolvedOrder.FillInstructions[0].DestinationChainID)
+	}
+	metrics.FillRace(chainLabel(leg.ResolvedOrder.OriginChainID), destination, protocolLabel, won)
+}
+
+// isStarknetChainID reports whether chainID belongs to a configured network with "starknet" in its name
+func isStarknetChainID(chainID *big.Int) bool {
+	// Ensure config is initialized to prevent segfault
+	config.InitializeNetworks()
+
+	// Find any network with "Starknet" in the name that matches this chain ID
+	for networkName, network := range config.Networks {
+		if network.ChainID == chainID.Uint64() {
+			// Check if network name contains "Starknet" (case insensitive)
+			return strings.Contains(strings.ToLower(networkName), "starknet")
+		}
+	}
+	return false
+}
@@ -0,0 +1,164 @@
+package hyperlane7683
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/history"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/txmanager"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+const (
+	testEVMFiller      = "0x1111111111111111111111111111111111111111"
+	testStarknetFiller = "0x2222"
+	otherFiller        = "0x3333333333333333333333333333333333333333"
+)
+
+var testFillers = FillerAddresses{EVM: testEVMFiller, Starknet: testStarknetFiller}
+
+// racingHandler is a legHandler that can also tell who filled an order
+type racingHandler struct {
+	legHandler
+	fillerErr error
+}
+
+func (h *racingHandler) CheckFiller(_ context.Context, _ *types.ParsedArgs) error {
+	return h.fillerErr
+}
+
+func chainID(name string) *big.Int {
+	config.InitializeNetworks()
+	return new(big.Int).SetUint64(config.Networks[name].ChainID)
+}
+
+func TestFillerAddressesReceiver(t *testing.T) {
+	receiver, err := testFillers.receiver(chainID("Ethereum"))
+	require.NoError(t, err)
+	assert.Equal(t, common.LeftPadBytes(common.FromHex(testEVMFiller), 32), receiver)
+
+	receiver, err = testFillers.receiver(chainID("Starknet"))
+	require.NoError(t, err)
+	assert.Equal(t, common.LeftPadBytes([]byte{0x22, 0x22}, 32), receiver)
+
+	receiver, err = FillerAddresses{EVM: "", Starknet: testStarknetFiller}.receiver(chainID("Base"))
+	require.NoError(t, err)
+	assert.Nil(t, receiver, "no address configured for the origin")
+
+	_, err = FillerAddresses{EVM: "0xnothex", Starknet: ""}.receiver(chainID("Base"))
+	require.Error(t, err)
+}
+
+func TestFillerAddressesOwns(t *testing.T) {
+	ours := common.LeftPadBytes(common.FromHex(testEVMFiller), 32)
+	theirs := common.LeftPadBytes(common.FromHex(otherFiller), 32)
+
+	assert.True(t, testFillers.owns(chainID("Base"), ours))
+	assert.False(t, testFillers.owns(chainID("Base"), theirs))
+	assert.False(t, testFillers.owns(chainID("Starknet"), ours), "EVM receiver on a Starknet origin")
+	assert.True(t, testFillers.owns(chainID("Base"), nil), "fills without filler data cannot be told apart")
+	assert.True(t, FillerAddresses{EVM: "", Starknet: ""}.owns(chainID("Base"), theirs), "no address to compare with")
+}
+
+func TestObserveFillAbortsOrdersFilledByOthers(t *testing.T) {
+	solver := newMultiLegSolver(&legHandler{}, &legHandler{})
+	solver.SetFillerAddresses(testFillers)
+	order := multiLegOrder()
+	orderID := common.BytesToHash(common.FromHex(order.OrderID)).Hex()
+	order.OrderID = orderID
+
+	ctx, cancel := context.WithCancelCause(context.Background())
+	defer cancel(nil)
+	solver.inFlight.begin(order, cancel)
+
+	// Our own fill, and fills of orders not in flight, leave the order alone
+	solver.ObserveFill(ctx, FilledEvent{OrderID: orderID, ChainName: "Base", Block: 1,
+		FillerData: common.LeftPadBytes(common.FromHex(testEVMFiller), 32)})
+	solver.ObserveFill(ctx, FilledEvent{OrderID: "0xother", ChainName: "Base", Block: 1,
+		FillerData: common.LeftPadBytes(common.FromHex(otherFiller), 32)})
+	require.NoError(t, ctx.Err())
+
+	solver.ObserveFill(ctx, FilledEvent{OrderID: orderID, ChainName: "Base", Block: 2,
+		FillerData: common.LeftPadBytes(common.FromHex(otherFiller), 32)})
+	require.Error(t, ctx.Err())
+	assert.ErrorIs(t, context.Cause(ctx), failure.ErrCompetitorFilled)
+
+	solver.inFlight.end(orderID)
+	assert.False(t, solver.inFlight.abort(orderID, errors.New("late")))
+}
+
+func TestLostFillRaceIsSkipped(t *testing.T) {
+	store, err := history.Open(filepath.Join(t.TempDir(), "orders.jsonl"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+
+	reverted := failure.Mark(errors.New("execution reverted"), failure.ErrReverted)
+	competitor := competitorFilled("0xlost", common.LeftPadBytes(common.FromHex(otherFiller), 32))
+	solver := NewHyperlane7683Solver(nil, nil, nil, nil, types.AllowBlockLists{}, nil)
+	solver.evmHandlers[chainID("Base").Uint64()] = &racingHandler{legHandler: legHandler{fillErr: reverted}, fillerErr: competitor}
+	solver.SetHistory(store)
+	solver.rulesEngine = &RulesEngine{rules: []Rule{&MockRule{name: "mock", shouldPass: true}}}
+	order := multiLegOrder()
+	order.OrderID = "0xlost"
+	order.ResolvedOrder.FillInstructions = order.ResolvedOrder.FillInstructions[:1]
+
+	ok, err := solver.ProcessIntent(context.Background(), order)
+	require.NoError(t, err, "losing a race is not a failure")
+	assert.False(t, ok)
+
+	record, err := store.Get(order.OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, history.StatusSkipped, record.Status)
+	assert.Equal(t, failure.KindCompetitorFilled, record.ErrorKind)
+}
+
+func TestRevertNotCausedByCompetitorStillFails(t *testing.T) {
+	reverted := failure.Mark(errors.New("execution reverted"), failure.ErrReverted)
+	solver := NewHyperlane7683Solver(nil, nil, nil, nil, types.AllowBlockLists{}, nil)
+	solver.evmHandlers[chainID("Base").Uint64()] = &racingHandler{legHandler: legHandler{fillErr: reverted}, fillerErr: nil}
+	order := multiLegOrder()
+	order.ResolvedOrder.FillInstructions = order.ResolvedOrder.FillInstructions[:1]
+
+	_, err := solver.Fill(context.Background(), order)
+	require.Error(t, err)
+	assert.ErrorIs(t, err, failure.ErrReverted)
+	assert.NotErrorIs(t, err, failure.ErrCompetitorFilled)
+}
+
+func TestAbortedBeforeBroadcast(t *testing.T) {
+	ctx, cancel := context.WithCancelCause(context.Background())
+	require.NoError(t, abortedBeforeBroadcast(ctx))
+
+	cancel(competitorFilled("0x01", nil))
+	assert.ErrorIs(t, abortedBeforeBroadcast(ctx), failure.ErrCompetitorFilled)
+	assert.NoError(t, context.WithoutCancel(ctx).Err(), "broadcast transactions are waited for regardless of the abort")
+}
+
+func TestInvalidOrderStatusRevertDefersToFiller(t *testing.T) {
+	invalidStatus := newRevertError("fill", chainID("Base").Uint64(), "fill", &txmanager.Simulation{Reverted: true,
+		Reason: "Invalid order status", RevertData: nil, Gas: 0, Fee: nil, Unit: txmanager.UnitWei})
+	order := multiLegOrder()
+	order.ResolvedOrder.FillInstructions = order.ResolvedOrder.FillInstructions[:1]
+
+	// The solver's own earlier fill mined in the meantime: not a lost race
+	solver := NewHyperlane7683Solver(nil, nil, nil, nil, types.AllowBlockLists{}, nil)
+	solver.evmHandlers[chainID("Base").Uint64()] = &racingHandler{legHandler: legHandler{fillErr: invalidStatus}, fillerErr: nil}
+	_, err := solver.Fill(context.Background(), order)
+	require.Error(t, err)
+	assert.NotErrorIs(t, err, failure.ErrCompetitorFilled)
+
+	competitor := competitorFilled(order.OrderID, common.LeftPadBytes(common.FromHex(otherFiller), 32))
+	solver = NewHyperlane7683Solver(nil, nil, nil, nil, types.AllowBlockLists{}, nil)
+	solver.evmHandlers[chainID("Base").Uint64()] = &racingHandler{legHandler: legHandler{fillErr: invalidStatus}, fillerErr: competitor}
+	_, err = solver.Fill(context.Background(), order)
+	assert.ErrorIs(t, err, failure.ErrCompetitorFilled)
+}
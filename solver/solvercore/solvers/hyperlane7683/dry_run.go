@@ -0,0 +1,211 @@
+package hyperlane7683
+
+// Module: Dry-run (shadow) mode
+// - Orders that pass the rules are simulated leg by leg instead of filled and settled; nothing is sent
+// - Logs the calls each fill and settle would send, whether they would succeed and what they would cost
+// - Records the order as SIMULATED in the history and its hypothetical PnL in the ledger attached to the solver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/accounting"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/history"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/txmanager"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// ErrDryRun is returned for actions that would send a transaction while the solver runs in dry-run mode
+var ErrDryRun = errors.New("solver is running in dry-run mode")
+
+// SimulationOutcome is what a simulated operation would have done
+type SimulationOutcome string
+
+const (
+	SimulationSucceeded SimulationOutcome = "success"   // every call executed without reverting
+	SimulationReverted  SimulationOutcome = "revert"    // a call would revert; Reason says why
+	SimulationEstimated SimulationOutcome = "estimated" // the calls cannot run against the current state; the fee is a typical one
+)
+
+// Simulation is the result of simulating one fill or settle on a destination chain
+type Simulation struct {
+	Operation string
+	ChainID   uint64
+	// Calls are the calls that would be sent, in order, e.g. approve(0x…) then fill
+	Calls   []string
+	Outcome SimulationOutcome
+	Reason  string
+	// Revert is the first call that would revert, decoded; nil if none did or the revert was found without executing
+	Revert *RevertError
+	// Gas and Fee are the network gas and fee, in FeeUnit, of the calls that did not revert; Fee is nil when unknown
+	Gas     uint64
+	Fee     *big.Int
+	FeeUnit string
+	// InterchainFee is the Hyperlane gas payment a settle would carry, in wei
+	InterchainFee *big.Int
+}
+
+// DryRunEnabledFromEnv reports whether DRY_RUN asks for orders to be simulated instead of filled
+func DryRunEnabledFromEnv() bool {
+	return envutil.GetEnvWithDefault("DRY_RUN", "false") == "true"
+}
+
+func newSimulation(operation string, chainID uint64, feeUnit string) *Simulation {
+	return &Simulation{
+		Operation:     operation,
+		ChainID:       chainID,
+		Calls:         nil,
+		Outcome:       SimulationSucceeded,
+		Reason:        "",
+		Revert:        nil,
+		Gas:           0,
+		Fee:           nil,
+		FeeUnit:       feeUnit,
+		InterchainFee: nil,
+	}
+}
+
+// revert marks the simulation as reverted, keeping the first reason
+func (s *Simulation) revert(reason string) {
+	if s.Outcome == SimulationReverted {
+		return
+	}
+	s.Outcome = SimulationReverted
+	s.Reason = reason
+}
+
+// addTx adds the simulated execution of call to the simulation, decoding its revert if it failed
+func (s *Simulation) addTx(call string, tx *txmanager.Simulation) {
+	s.Calls = append(s.Calls, call)
+	if tx.Reverted {
+		if s.Revert == nil {
+			s.Revert = newRevertError(s.Operation, s.ChainID, call, tx)
+		}
+		s.revert(fmt.Sprintf("%s: %s", call, s.Revert.Reason))
+		return
+	}
+	s.Gas += tx.Gas
+	if tx.Fee != nil {
+		if s.Fee == nil {
+			s.Fee = new(big.Int)
+		}
+		s.Fee.Add(s.Fee, tx.Fee)
+	}
+	s.FeeUnit = tx.Unit
+}
+
+// Err returns the decoded revert, if a call would revert
+func (s *Simulation) Err() error {
+	if s.Revert == nil {
+		return nil
+	}
+	return s.Revert
+}
+
+// estimate marks the simulation as estimated at gas units priced at gasPrice
+func (s *Simulation) estimate(reason string, gas uint64, gasPrice *big.Int) {
+	s.Outcome = SimulationEstimated
+	s.Reason = reason
+	s.Gas = gas
+	s.Fee = new(big.Int).Mul(new(big.Int).SetUint64(gas), gasPrice)
+}
+
+// EnableDryRun makes the solver simulate the orders that pass its rules instead of filling and settling them
+func (f *Hyperlane7683Solver) EnableDryRun() {
+	f.dryRun = true
+}
+
+// DryRun reports whether the solver simulates orders instead of filling them
+func (f *Hyperlane7683Solver) DryRun() bool {
+	return f.dryRun
+}
+
+// simulateOrder simulates the fill and settle of every leg and records the outcome.
+// It returns an error when a leg would revert or could not be simulated.
+func (f *Hyperlane7683Solver) simulateOrder(ctx context.Context, args *types.ParsedArgs) (bool, error) {
+	// Nothing is spent in a dry run, so no tokens stay earmarked
+	defer f.releaseInventory(args.OrderID)
+	logger := f.log().With(logutil.OrderID(args.OrderID), logutil.Stage("simulate"))
+
+	simulations := make([]*Simulation, 0, 2*len(args.ResolvedOrder.FillInstructions))
+	var reverted error
+	for i, instruction := range args.ResolvedOrder.FillInstructions {
+		leg := args.ForInstruction(i)
+		for _, operation := range []string{"fill", "settle"} {
+			var sim *Simulation
+			_, err := f.executeChainOperation(ctx, leg, instruction.DestinationChainID, "simulate_"+operation,
+				func(ctx context.Context, handler ChainHandler) (OrderAction, error) {
+					simulator, ok := handler.(Simulator)
+					if !ok {
+						return OrderActionError, fmt.Errorf("handler for chain %s cannot simulate", instruction.DestinationChainID)
+					}
+					var err error
+					if operation == "fill" {
+						sim, err = simulator.SimulateFill(ctx, leg)
+					} else {
+						sim, err = simulator.SimulateSettle(ctx, leg)
+					}
+					return OrderActionComplete, err
+				})
+			if err != nil {
+				logutil.OperationComplete(logger.With(logutil.Err(err)), args, "Order simulation", false)
+				err = fmt.Errorf("%s simulation of instruction %d failed: %w", operation, i+1, err)
+				f.recordStatus(args.OrderID, history.StatusFailed, err)
+				return false, err
+			}
+
+			logSimulation(logger, i+1, sim)
+			simulations = append(simulations, sim)
+			if sim.Outcome == SimulationReverted && reverted == nil {
+				reverted = fmt.Errorf("%s of instruction %d would revert: %s", operation, i+1, sim.Reason)
+			}
+		}
+	}
+
+	f.recordStatus(args.OrderID, history.StatusSimulated, reverted)
+	if reverted != nil {
+		logutil.OperationComplete(logger, args, "Order simulation (would revert)", false)
+		return false, reverted
+	}
+
+	// Hypothetical PnL: what the fill would spend, the fees it would pay and what settlement would release
+	if f.ledger != nil {
+		f.ledger.RecordFill(args)
+		for _, sim := range simulations {
+			chain := logutil.NetworkNameByChainID(sim.ChainID)
+			accounting.RecordFee(ctx, accounting.KindGas, sim.Operation, chain, sim.FeeUnit, sim.Fee)
+			accounting.RecordFee(ctx, accounting.KindInterchainFee, sim.Operation, chain, accounting.UnitWei, sim.InterchainFee)
+		}
+		f.ledger.RecordSettlement(args)
+	}
+	logutil.OperationComplete(logger, args, "Order simulation", true)
+	return true, nil
+}
+
+// logSimulation logs what a simulated operation would have sent and whether it would have succeeded
+func logSimulation(logger logutil.Logger, instruction int, sim *Simulation) {
+	fee := "unknown"
+	if sim.Fee != nil {
+		fee = fmt.Sprintf("%s %s", sim.Fee, sim.FeeUnit)
+	}
+	msg := fmt.Sprintf("🧪 Dry run: %s of instruction %d on %s would send [%s]: %s (gas=%d, fee=%s",
+		sim.Operation, instruction, logutil.NetworkNameByChainID(sim.ChainID), strings.Join(sim.Calls, ", "), sim.Outcome, sim.Gas, fee)
+	if sim.InterchainFee != nil {
+		msg += fmt.Sprintf(", interchain fee=%s wei", sim.InterchainFee)
+	}
+	msg += ")"
+	if sim.Reason != "" {
+		msg += " - " + sim.Reason
+	}
+
+	if sim.Outcome == SimulationReverted {
+		logger.Warn(msg)
+		return
+	}
+	logger.Info(msg)
+}
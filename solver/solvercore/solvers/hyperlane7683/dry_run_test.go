@@ -0,0 +1,165 @@
+package hyperlane7683
+
+import (
+	"context"
+	"math/big"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/accounting"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/history"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/txmanager"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// simulatingHandler is a legHandler that also simulates, reverting the fill when fillRevert is set
+type simulatingHandler struct {
+	legHandler
+	chainID    uint64
+	fillRevert string
+	simulated  []string
+}
+
+func (h *simulatingHandler) SimulateFill(_ context.Context, args *types.ParsedArgs) (*Simulation, error) {
+	h.simulated = append(h.simulated, "fill")
+	sim := newSimulation("fill", h.chainID, txmanager.UnitWei)
+	sim.addTx("fill", &txmanager.Simulation{Reverted: h.fillRevert != "", Reason: h.fillRevert, RevertData: nil,
+		Gas: 100_000, Fee: big.NewInt(1_000), Unit: txmanager.UnitWei})
+	return sim, nil
+}
+
+func (h *simulatingHandler) SimulateSettle(_ context.Context, args *types.ParsedArgs) (*Simulation, error) {
+	h.simulated = append(h.simulated, "settle")
+	sim := newSimulation("settle", h.chainID, txmanager.UnitWei)
+	sim.Calls = []string{"settle"}
+	sim.estimate("order is UNKNOWN on chain", settleGasEstimate, big.NewInt(2))
+	sim.InterchainFee = big.NewInt(500)
+	return sim, nil
+}
+
+func newDryRunSolver(t *testing.T, base, optimism ChainHandler) (*Hyperlane7683Solver, *history.Store, string) {
+	t.Helper()
+	config.InitializeNetworks()
+	solver := NewHyperlane7683Solver(nil, nil, nil, nil, types.AllowBlockLists{}, nil)
+	solver.evmHandlers[config.Networks["Base"].ChainID] = base
+	solver.evmHandlers[config.Networks["Optimism"].ChainID] = optimism
+	solver.rulesEngine = &RulesEngine{rules: []Rule{&MockRule{name: "mock", shouldPass: true}}}
+	solver.EnableDryRun()
+
+	store, err := history.Open(filepath.Join(t.TempDir(), "orders.jsonl"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+	solver.SetHistory(store)
+
+	path := filepath.Join(t.TempDir(), "dry_run_ledger.jsonl")
+	ledger, err := accounting.Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ledger.Close() })
+	solver.SetLedger(ledger)
+	return solver, store, path
+}
+
+func dryRunOrder() *types.ParsedArgs {
+	order := multiLegOrder()
+	order.ResolvedOrder.MaxSpent = []types.Output{
+		{Token: "0xtoken", Amount: big.NewInt(100), Recipient: "0xrecipient", ChainID: new(big.Int).SetUint64(config.Networks["Base"].ChainID)},
+	}
+	order.ResolvedOrder.MinReceived = []types.Output{
+		{Token: "0xtoken", Amount: big.NewInt(101), Recipient: "0xsolver", ChainID: new(big.Int).SetUint64(config.Networks["Ethereum"].ChainID)},
+	}
+	return order
+}
+
+func TestDryRunSimulatesInsteadOfFilling(t *testing.T) {
+	base := &simulatingHandler{chainID: 8453}
+	optimism := &simulatingHandler{chainID: 10}
+	solver, store, path := newDryRunSolver(t, base, optimism)
+	order := dryRunOrder()
+
+	ok, err := solver.ProcessIntent(context.Background(), order)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	assert.Empty(t, base.fills)
+	assert.Empty(t, base.settles)
+	assert.Equal(t, []string{"fill", "settle"}, base.simulated)
+	assert.Equal(t, []string{"fill", "settle"}, optimism.simulated)
+
+	record, err := store.Get(order.OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, history.StatusSimulated, record.Status)
+	assert.Empty(t, record.Error)
+	assert.Empty(t, record.Txs)
+
+	entries, err := accounting.ReadEntries(path)
+	require.NoError(t, err)
+	amounts := make(map[accounting.Kind][]string)
+	for _, entry := range entries {
+		amounts[entry.Kind] = append(amounts[entry.Kind], entry.Amount)
+	}
+	assert.Equal(t, []string{"100"}, amounts[accounting.KindSpent])
+	assert.Equal(t, []string{"101"}, amounts[accounting.KindReceived])
+	assert.Equal(t, []string{"1000", "300000", "1000", "300000"}, amounts[accounting.KindGas])
+	assert.Equal(t, []string{"500", "500"}, amounts[accounting.KindInterchainFee])
+}
+
+func TestDryRunReportsReverts(t *testing.T) {
+	base := &simulatingHandler{chainID: 8453}
+	optimism := &simulatingHandler{chainID: 10, fillRevert: "execution reverted: InvalidOrderStatus"}
+	solver, store, path := newDryRunSolver(t, base, optimism)
+	order := dryRunOrder()
+
+	ok, err := solver.ProcessIntent(context.Background(), order)
+	require.Error(t, err)
+	assert.False(t, ok)
+	assert.Contains(t, err.Error(), "fill of instruction 2 would revert")
+
+	record, err := store.Get(order.OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, history.StatusSimulated, record.Status)
+	assert.Contains(t, record.Error, "InvalidOrderStatus")
+
+	// Nothing hypothetical is recorded for an order that would not go through
+	entries, err := accounting.ReadEntries(path)
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestDryRunNeedsSimulatingHandlers(t *testing.T) {
+	solver, store, _ := newDryRunSolver(t, &simulatingHandler{chainID: 8453}, &legHandler{})
+	order := dryRunOrder()
+
+	_, err := solver.ProcessIntent(context.Background(), order)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "cannot simulate")
+
+	record, err := store.Get(order.OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, history.StatusFailed, record.Status)
+}
+
+func TestDryRunRefusesToSend(t *testing.T) {
+	solver, _, _ := newDryRunSolver(t, &simulatingHandler{chainID: 8453}, &simulatingHandler{chainID: 10})
+	assert.ErrorIs(t, solver.RefundOrder(context.Background(), dryRunOrder()), ErrDryRun)
+}
+
+func TestSimulationAddTx(t *testing.T) {
+	sim := newSimulation("fill", 8453, txmanager.UnitWei)
+	sim.addTx("approve(0xtoken)", &txmanager.Simulation{Reverted: false, Reason: "", RevertData: nil,
+		Gas: 46_000, Fee: big.NewInt(460), Unit: txmanager.UnitWei})
+	sim.addTx("fill", &txmanager.Simulation{Reverted: false, Reason: "", RevertData: nil,
+		Gas: 120_000, Fee: big.NewInt(1_200), Unit: txmanager.UnitWei})
+	assert.Equal(t, SimulationSucceeded, sim.Outcome)
+	assert.Equal(t, uint64(166_000), sim.Gas)
+	assert.Equal(t, big.NewInt(1_660), sim.Fee)
+	assert.Equal(t, []string{"approve(0xtoken)", "fill"}, sim.Calls)
+
+	sim.addTx("fill", &txmanager.Simulation{Reverted: true, Reason: "first", RevertData: nil, Gas: 0, Fee: nil, Unit: txmanager.UnitWei})
+	sim.addTx("fill", &txmanager.Simulation{Reverted: true, Reason: "second", RevertData: nil, Gas: 0, Fee: nil, Unit: txmanager.UnitWei})
+	assert.Equal(t, SimulationReverted, sim.Outcome)
+	assert.Equal(t, "fill: first", sim.Reason)
+}
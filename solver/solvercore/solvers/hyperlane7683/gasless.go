@@ -0,0 +1,149 @@
+package hyperlane7683
+
+// Module: Gasless order intake for Hyperlane7683
+// - Accepts orders the user signed off-chain (Permit2 witness over the resolved order)
+// - Shared by the submit-gasless subcommand and the HTTP intake API
+// - Applies the same allow/block lists, rules and inventory reservation as on-chain orders
+// - Submits openFor on the origin chain; the listener then picks up the Open event and fills the order as usual
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/inventory"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrOrderRejected marks errors caused by the order itself rather than by the solver or its RPCs
+var ErrOrderRejected = failure.Mark(errors.New("order rejected"), failure.ErrValidation)
+
+// GaslessQuote is what the solver commits to for an accepted gasless order
+type GaslessQuote struct {
+	OrderID      string `json:"orderId"`
+	FillDeadline uint32 `json:"fillDeadline"`
+	// MaxSpent is what the solver pays out on the destination chain(s)
+	MaxSpent []types.Output `json:"maxSpent"`
+	// MinReceived is what the solver is repaid on the origin chain once the order settles
+	MinReceived []types.Output `json:"minReceived"`
+}
+
+// SubmitGaslessOrder validates a signed gasless order, reserves the inventory needed to fill it and opens it
+// on its origin chain. Errors caused by the order wrap ErrOrderRejected.
+func (f *Hyperlane7683Solver) SubmitGaslessOrder(ctx context.Context, signed *orderutil.SignedGaslessOrder,
+) (*GaslessQuote, error) {
+	args, err := GaslessParsedArgs(&signed.Order)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrOrderRejected, err)
+	}
+	logutil.OrderProcessing(f.log().With(logutil.Stage("open")), args, "Processing Gasless Order")
+
+	originChainID := args.ResolvedOrder.OriginChainID
+	settler, err := f.originSettler(originChainID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrOrderRejected, err)
+	}
+	if normalizeAddress(settler) != normalizeAddress(signed.Order.OriginSettler.Hex()) {
+		return nil, fmt.Errorf("%w: origin settler %s is not the configured settler %s", ErrOrderRejected,
+			signed.Order.OriginSettler.Hex(), settler)
+	}
+
+	if !f.isAllowedIntent(args) {
+		return nil, fmt.Errorf("%w: blocked by allow/block lists", ErrOrderRejected)
+	}
+	if result := f.rulesEngine.EvaluateAll(ctx, args); !result.Passed {
+		return nil, fmt.Errorf("%w: validation failed: %s", ErrOrderRejected, result.Reason)
+	}
+	// Opening the order would send a transaction on the origin chain
+	if f.dryRun {
+		return nil, ErrDryRun
+	}
+
+	// Held until the Open event is processed, which replaces this reservation with its own
+	if f.inventory != nil {
+		if err := f.inventory.Reserve(ctx, args.OrderID, spentOutputs(args)); err != nil {
+			if errors.Is(err, inventory.ErrInsufficientInventory) {
+				err = fmt.Errorf("%w: %w", ErrOrderRejected, err)
+			}
+			return nil, fmt.Errorf("inventory reservation failed: %w", err)
+		}
+	}
+
+	_, err = f.executeChainOperation(ctx, args, originChainID, "openFor", func(ctx context.Context, handler ChainHandler) (OrderAction, error) {
+		opener, ok := handler.(GaslessOpener)
+		if !ok {
+			return OrderActionError, fmt.Errorf("%w: gasless orders are not supported on this chain", ErrOrderRejected)
+		}
+		if err := opener.VerifyGaslessOrder(ctx, signed); err != nil {
+			return OrderActionError, err
+		}
+		return OrderActionComplete, opener.OpenFor(ctx, signed)
+	})
+	if err != nil {
+		f.releaseInventory(args.OrderID)
+		logutil.OperationComplete(f.log().With(logutil.Stage("open"), logutil.Err(err)), args, "Gasless order opening", false)
+		return nil, err
+	}
+
+	logutil.OperationComplete(f.log().With(logutil.Stage("open")), args, "Gasless order opening", true)
+	return &GaslessQuote{
+		OrderID:      args.OrderID,
+		FillDeadline: args.ResolvedOrder.FillDeadline,
+		MaxSpent:     args.ResolvedOrder.MaxSpent,
+		MinReceived:  args.ResolvedOrder.MinReceived,
+	}, nil
+}
+
+// GaslessParsedArgs builds the ParsedArgs the Open event for the order will carry once it is opened
+func GaslessParsedArgs(order *orderutil.GaslessOrder) (*types.ParsedArgs, error) {
+	resolved, err := order.Resolve()
+	if err != nil {
+		return nil, fmt.Errorf("invalid gasless order: %w", err)
+	}
+
+	ro := types.ResolvedCrossChainOrder{
+		User:             resolved.User.Hex(),
+		OriginChainID:    resolved.OriginChainID,
+		OpenDeadline:     resolved.OpenDeadline,
+		FillDeadline:     resolved.FillDeadline,
+		OrderID:          resolved.OrderID,
+		MaxSpent:         gaslessOutputs(resolved.MaxSpent),
+		MinReceived:      gaslessOutputs(resolved.MinReceived),
+		FillInstructions: make([]types.FillInstruction, 0, len(resolved.FillInstructions)),
+	}
+	for _, fi := range resolved.FillInstructions {
+		ro.FillInstructions = append(ro.FillInstructions, types.FillInstruction{
+			DestinationChainID: fi.DestinationChainId,
+			DestinationSettler: "0x" + hex.EncodeToString(fi.DestinationSettler[:]),
+			OriginData:         fi.OriginData,
+		})
+	}
+
+	return &types.ParsedArgs{
+		OrderID:       common.BytesToHash(resolved.OrderID[:]).Hex(),
+		SenderAddress: ro.User,
+		Recipients: []types.Recipient{{
+			DestinationChainName: logutil.NetworkNameByChainID(resolved.OriginChainID.Uint64()),
+			RecipientAddress:     "*",
+		}},
+		ResolvedOrder: ro,
+	}, nil
+}
+
+func gaslessOutputs(outputs []orderutil.Output) []types.Output {
+	converted := make([]types.Output, 0, len(outputs))
+	for _, o := range outputs {
+		converted = append(converted, types.Output{
+			Token:     types.CanonicalToken("0x" + hex.EncodeToString(o.Token[:])),
+			Amount:    o.Amount,
+			Recipient: "0x" + hex.EncodeToString(o.Recipient[:]),
+			ChainID:   o.ChainId,
+		})
+	}
+	return converted
+}
@@ -0,0 +1,140 @@
+package hyperlane7683
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/inventory"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// gaslessHandler is a legHandler that also opens gasless orders
+type gaslessHandler struct {
+	legHandler
+	opened int
+}
+
+func (h *gaslessHandler) VerifyGaslessOrder(context.Context, *orderutil.SignedGaslessOrder) error {
+	return nil
+}
+
+func (h *gaslessHandler) OpenFor(context.Context, *orderutil.SignedGaslessOrder) error {
+	h.opened++
+	return nil
+}
+
+func TestGaslessOrderKeepsItsReservationThroughTheOpenEvent(t *testing.T) {
+	config.InitializeNetworks()
+	origin := config.Networks["Ethereum"]
+	destination := config.Networks["Optimism"]
+	outputToken := common.HexToAddress("0xB844EEd1581f3fB810FFb6Dd6C5E30C049cF23F4")
+	encoded, err := orderutil.EncodeOrderData(&orderutil.OrderData{
+		Sender:             orderutil.AddressToBytes32(common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")),
+		Recipient:          orderutil.AddressToBytes32(common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")),
+		InputToken:         orderutil.AddressToBytes32(common.HexToAddress("0x76878654a2D96dDdF8cF0CFe8FA608aB4CE0D499")),
+		OutputToken:        orderutil.AddressToBytes32(outputToken),
+		AmountIn:           big.NewInt(1001),
+		AmountOut:          big.NewInt(1000),
+		SenderNonce:        big.NewInt(7),
+		OriginDomain:       uint32(origin.HyperlaneDomain),
+		DestinationDomain:  uint32(destination.HyperlaneDomain),
+		DestinationSettler: orderutil.AddressToBytes32(destination.HyperlaneAddress),
+		FillDeadline:       1,
+		Data:               []byte{},
+	})
+	require.NoError(t, err)
+	signed := &orderutil.SignedGaslessOrder{Order: orderutil.GaslessOrder{
+		OriginSettler: origin.HyperlaneAddress,
+		User:          common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC"),
+		Nonce:         big.NewInt(7),
+		OriginChainID: new(big.Int).SetUint64(origin.ChainID),
+		OpenDeadline:  1800000000,
+		FillDeadline:  1850000000,
+		OrderDataType: orderutil.OrderDataTypeHash(),
+		OrderData:     encoded,
+	}}
+
+	// The balance covers the order once, but not on top of its own reservation
+	inv := inventory.NewManager(0)
+	inv.RegisterChain(destination.ChainID, fixedBalance(1500))
+	handler := &gaslessHandler{}
+	solver := NewHyperlane7683Solver(nil, nil, nil, nil, types.AllowBlockLists{}, inv)
+	solver.evmHandlers[origin.ChainID] = handler
+	solver.evmHandlers[destination.ChainID] = handler
+	solver.rulesEngine = &RulesEngine{rules: []Rule{NewBalanceRule(inv)}}
+
+	ctx := context.Background()
+	quote, err := solver.SubmitGaslessOrder(ctx, signed)
+	require.NoError(t, err)
+	assert.Equal(t, 1, handler.opened)
+	assert.True(t, inv.HasReservation(quote.OrderID))
+
+	// The listener picks up the Open event for the same order
+	args, err := GaslessParsedArgs(&signed.Order)
+	require.NoError(t, err)
+	_, err = solver.ProcessIntent(ctx, args)
+	require.NoError(t, err, "the order is not rejected for the tokens reserved for itself")
+	assert.Len(t, handler.fills, 1)
+}
+
+func TestGaslessParsedArgs(t *testing.T) {
+	user := common.HexToAddress("0x3C44CdDdB6a900fa2b585dd299e03d12FA4293BC")
+	settler := common.HexToAddress("0xf614c6bF94b022E16BEF7dBecF7614FFD2b201d3")
+	encoded, err := orderutil.EncodeOrderData(&orderutil.OrderData{
+		Sender:             orderutil.AddressToBytes32(common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")),
+		Recipient:          orderutil.AddressToBytes32(user),
+		InputToken:         orderutil.AddressToBytes32(common.HexToAddress("0x76878654a2D96dDdF8cF0CFe8FA608aB4CE0D499")),
+		OutputToken:        orderutil.AddressToBytes32(common.HexToAddress("0xB844EEd1581f3fB810FFb6Dd6C5E30C049cF23F4")),
+		AmountIn:           big.NewInt(1001),
+		AmountOut:          big.NewInt(1000),
+		SenderNonce:        big.NewInt(7),
+		OriginDomain:       11155111,
+		DestinationDomain:  11155420,
+		DestinationSettler: orderutil.AddressToBytes32(settler),
+		FillDeadline:       1,
+		Data:               []byte{},
+	})
+	require.NoError(t, err)
+
+	args, err := GaslessParsedArgs(&orderutil.GaslessOrder{
+		OriginSettler: settler,
+		User:          user,
+		Nonce:         big.NewInt(7),
+		OriginChainID: big.NewInt(11155111),
+		OpenDeadline:  1800000000,
+		FillDeadline:  1850000000,
+		OrderDataType: orderutil.OrderDataTypeHash(),
+		OrderData:     encoded,
+	})
+	require.NoError(t, err)
+
+	// Matches what the listener will build from the Open event
+	instruction := args.ResolvedOrder.FillInstructions[0]
+	assert.Equal(t, crypto.Keccak256Hash(instruction.OriginData).Hex(), args.OrderID)
+	assert.Equal(t, user.Hex(), args.SenderAddress)
+	assert.Equal(t, uint32(1850000000), args.ResolvedOrder.FillDeadline)
+	assert.Equal(t, uint64(11155420), instruction.DestinationChainID.Uint64())
+	require.Len(t, args.ResolvedOrder.MaxSpent, 1)
+	assert.Equal(t, big.NewInt(1000), args.ResolvedOrder.MaxSpent[0].Amount)
+	assert.Equal(t, "0x000000000000000000000000b844eed1581f3fb810ffb6dd6c5e30c049cf23f4", args.ResolvedOrder.MaxSpent[0].Token)
+
+	_, err = GaslessParsedArgs(&orderutil.GaslessOrder{
+		OriginSettler: settler,
+		User:          user,
+		Nonce:         big.NewInt(7),
+		OriginChainID: big.NewInt(11155111),
+		OpenDeadline:  0,
+		FillDeadline:  0,
+		OrderDataType: common.Hash{},
+		OrderData:     encoded,
+	})
+	assert.ErrorContains(t, err, "invalid gasless order")
+}
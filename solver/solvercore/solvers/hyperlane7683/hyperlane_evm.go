@@ -2,7 +2,9 @@ package hyperlane7683
 
 // Module: EVM chain handler for Hyperlane7683
 // - Executes fill/settle/status calls against EVM Hyperlane7683 contracts
-// - Manages ERC20 approvals and gas/value handling for calls
+// - Manages ERC20 approvals and native value for calls
+// - Sends every transaction through the chain's txmanager.EVM (nonces, EIP-1559 fees, rebroadcasts)
+// - Names the solver as filler of its fills and reads who filled an order from filledOrders
 //
 // Interface Contract:
 // - Fill(): Must acquire mutex, setup approvals, execute fill, return OrderAction
@@ -11,6 +13,7 @@ package hyperlane7683
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"strings"
@@ -18,9 +21,17 @@ import (
 	"time"
 
 	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/accounting"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
 	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/history"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/tracing"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/txmanager"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
@@ -32,36 +43,70 @@ import (
 
 const (
 	// Order status constants
-	orderStatusFilled  = "FILLED"
-	orderStatusSettled = "SETTLED"
-	orderStatusUnknown = "UNKNOWN"
+	orderStatusFilled   = "FILLED"
+	orderStatusSettled  = "SETTLED"
+	orderStatusUnknown  = "UNKNOWN"
+	orderStatusOpened   = "OPENED"
+	orderStatusRefunded = "REFUNDED"
 )
 
 const (
-	// Maximum retry attempts for order status checks
-	maxRetryAttempts = 5
-	// Gas limit for approve transactions
-	approveGasLimit = 200000
+	// Typical gas used by a single-order fill and settle, for quoting before any order exists
+	fillGasEstimate   = 200_000
+	settleGasEstimate = 150_000
+
+	// nativeTokenDecimals is the number of decimals of the native token on every supported EVM chain
+	nativeTokenDecimals = 18
 )
 
 // HyperlaneEVM contains all EVM-specific logic for the Hyperlane7683 protocol
 type HyperlaneEVM struct {
 	client  *ethclient.Client
 	signer  *bind.TransactOpts
+	txm     *txmanager.EVM
 	chainID uint64
-	mu      sync.Mutex // Serialize operations to prevent nonce conflicts
+	logger  logutil.Logger
+	// Accounts named in fillerData as the filler paid on the origin chain
+	fillers FillerAddresses
+	// Serializes approve+fill so concurrent orders cannot overwrite each other's allowance;
+	// nonces are handled by txm
+	mu sync.Mutex
+}
+
+// NewHyperlaneEVM creates a new EVM handler with its own transaction manager configured from env
+func NewHyperlaneEVM(client *ethclient.Client, signer *bind.TransactOpts, chainID uint64, logger logutil.Logger) *HyperlaneEVM {
+	cfg := txmanager.EVMConfigFromEnv()
+	if confirmations, err := config.GetTxConfirmationsByChainID(chainID); err == nil {
+		cfg.Confirmations = confirmations
+	}
+	return NewHyperlaneEVMWithTxManager(client, signer, chainID, txmanager.NewEVM(client, signer, chainID, cfg), logger)
 }
 
-// NewHyperlaneEVM creates a new EVM handler for Hyperlane operations
-func NewHyperlaneEVM(client *ethclient.Client, signer *bind.TransactOpts, chainID uint64) *HyperlaneEVM {
+// NewHyperlaneEVMWithTxManager creates a new EVM handler that sends through a shared transaction manager
+func NewHyperlaneEVMWithTxManager(client *ethclient.Client, signer *bind.TransactOpts, chainID uint64,
+	txm *txmanager.EVM, logger logutil.Logger) *HyperlaneEVM {
+	fillers := FillerAddresses{EVM: "", Starknet: ""}
+	if signer != nil {
+		fillers.EVM = signer.From.Hex()
+	}
 	return &HyperlaneEVM{
 		client:  client,
 		signer:  signer,
+		txm:     txm,
 		chainID: chainID,
+		logger:  logger,
+		fillers: fillers,
 		mu:      sync.Mutex{},
 	}
 }
 
+// SetFillerAddresses sets the accounts named as filler of this handler's fills; empty addresses keep the current ones
+func (h *HyperlaneEVM) SetFillerAddresses(addresses FillerAddresses) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.fillers = addresses.withDefaults(h.fillers)
+}
+
 // Fill executes a fill operation on an EVM chain
 func (h *HyperlaneEVM) Fill(ctx context.Context, args *types.ParsedArgs) (OrderAction, error) {
 	h.mu.Lock()
@@ -87,97 +132,137 @@ func (h *HyperlaneEVM) Fill(ctx context.Context, args *types.ParsedArgs) (OrderA
 	// Pre-check: skip if order is already filled or settled
 	status, err := h.GetOrderStatus(ctx, args)
 	if err != nil {
-		fmt.Printf("   ⚠️  Status check failed: %v\n", err)
+		h.chainLogger().Warn("   ⚠️  Status check failed", logutil.OrderID(args.OrderID), logutil.Err(err))
 		return OrderActionError, err
 	}
 
-	// Get network name for logging
-	networkName := logutil.NetworkNameByChainID(h.chainID)
-	logutil.LogStatusCheck(networkName, 1, 1, status, "UNKNOWN")
+	logutil.StatusCheck(h.chainLogger(), 1, 1, status, "UNKNOWN")
 
+	// An order another solver filled is theirs to settle
+	if status == orderStatusFilled || status == orderStatusSettled {
+		if err := h.checkFiller(ctx, args); err != nil {
+			return OrderActionError, err
+		}
+	}
 	if status == orderStatusFilled {
-		fmt.Printf("⏭️  Order already filled, proceeding to settlement\n")
+		h.chainLogger().Info("⏭️  Order already filled, proceeding to settlement", logutil.OrderID(args.OrderID))
 		return OrderActionSettle, nil
 	}
 	if status == orderStatusSettled {
-		fmt.Printf("🎉  Order already settled, nothing to do\n")
-		return OrderActionSettle, nil
-	}
-
-	// Handle max spent approvals if needed
-	if err := h.setupApprovals(ctx, args, destinationSettlerAddr); err != nil {
-		return OrderActionError, fmt.Errorf("failed to setup approvals: %w", err)
+		h.chainLogger().Info("🎉  Order already settled, nothing to do", logutil.OrderID(args.OrderID))
+		return OrderActionComplete, nil
 	}
 
-	// Execute the fill transaction
 	// Get chain IDs for cross-chain logging
 	originChainID := args.ResolvedOrder.OriginChainID.Uint64()
 	destChainID := instruction.DestinationChainID.Uint64()
-	logutil.CrossChainOperation(fmt.Sprintf("Executing fill call to contract %s", destinationSettlerAddr.Hex()), originChainID, destChainID, args.OrderID)
 
-	// Set native token value if needed
-	originalValue := h.signer.Value
-	if len(args.ResolvedOrder.MaxSpent) > 0 && args.ResolvedOrder.MaxSpent[0].Token == "" {
-		h.signer.Value = new(big.Int).Set(args.ResolvedOrder.MaxSpent[0].Amount)
+	// Native outputs are paid as msg.value; the settler requires it to match amountOut exactly
+	value := nativeValue(args, destChainID)
+	if err := h.ensureNativeBalance(ctx, value); err != nil {
+		return OrderActionError, err
 	}
-	defer func() { h.signer.Value = originalValue }()
 
-	contract, err := contracts.NewHyperlane7683(destinationSettlerAddr, h.client)
+	fillerData, err := h.fillers.receiver(args.ResolvedOrder.OriginChainID)
 	if err != nil {
-		return OrderActionError, fmt.Errorf("failed to bind contract at %s: %w", instruction.DestinationSettler, err)
+		return OrderActionError, err
 	}
-
-	var fillerDataBytes []byte
-	tx, err := contract.Fill(h.signer, orderID, instruction.OriginData, fillerDataBytes)
+	callData, err := packHyperlaneCall("fill", orderID, instruction.OriginData, fillerData)
 	if err != nil {
-		return OrderActionError, fmt.Errorf("fill transaction failed: %w", err)
+		return OrderActionError, err
 	}
+	fill := txmanager.EVMTx{To: destinationSettlerAddr, Data: callData, Value: value, GasLimit: 0}
 
-	logutil.CrossChainOperation(fmt.Sprintf("Fill transaction sent: %s", tx.Hash().Hex()), originChainID, destChainID, args.OrderID)
+	// Pre-flight: simulate the approvals and fill together so a fill that would revert costs no gas
+	approvals, err := h.approvalTxs(ctx, args, destinationSettlerAddr)
+	if err != nil {
+		return OrderActionError, fmt.Errorf("failed to setup approvals: %w", err)
+	}
+	checked, err := h.preflight(ctx, "fill", append(approvals, fill))
+	if err != nil {
+		return OrderActionError, err
+	}
 
-	// Wait for confirmation
-	receipt, err := bind.WaitMined(ctx, h.client, tx)
+	// Handle max spent approvals if needed
+	approveCtx, span := tracing.StartOrderSpan(ctx, tracing.SpanApprove, args.OrderID)
+	err = h.setupApprovals(approveCtx, args, approvals)
+	tracing.End(span, err)
 	if err != nil {
-		return OrderActionError, fmt.Errorf("failed to wait for fill confirmation: %w", err)
+		return OrderActionError, fmt.Errorf("failed to setup approvals: %w", err)
+	}
+	if !checked {
+		// The node could not simulate the bundle; the fill alone can be checked now that the approvals are mined
+		if _, err := h.preflight(ctx, "fill", []txmanager.EVMTx{fill}); err != nil {
+			return OrderActionError, err
+		}
 	}
 
-	if receipt.Status == 1 {
-		logutil.CrossChainOperation(fmt.Sprintf("EVM Fill successful! Gas used: %d", receipt.GasUsed), originChainID, destChainID, args.OrderID)
-		return OrderActionSettle, nil // Need to settle this order
-	} else {
-		return OrderActionError, fmt.Errorf("fill transaction failed with status: %d", receipt.Status)
+	// Execute the fill transaction
+	logutil.CrossChain(h.logger, fmt.Sprintf("Executing fill call to contract %s", destinationSettlerAddr.Hex()), originChainID, destChainID, args.OrderID,
+		logutil.Stage("fill"))
+	if err := abortedBeforeBroadcast(ctx); err != nil {
+		return OrderActionError, err
+	}
+	receipt, err := h.txm.Send(context.WithoutCancel(ctx), fill)
+	if err != nil {
+		return OrderActionError, fmt.Errorf("fill transaction failed: %w", err)
 	}
+	h.recordGasUsed(ctx, "fill", receipt)
+	tracing.Annotate(ctx, tracing.TxHash(receipt.TxHash.Hex()))
+	history.RecordTx(ctx, "fill", logutil.NetworkNameByChainID(h.chainID), receipt.TxHash.Hex())
+
+	logutil.CrossChain(h.logger, fmt.Sprintf("EVM Fill successful! Gas used: %d", receipt.GasUsed),
+		originChainID, destChainID, args.OrderID, logutil.Stage("fill"), logutil.TxHash(receipt.TxHash.Hex()))
+	return OrderActionFilled, nil // Need to settle this order
 }
 
 // Settle executes settlement on an EVM chain
 func (h *HyperlaneEVM) Settle(ctx context.Context, args *types.ParsedArgs) error {
+	return h.SettleBatch(ctx, []*types.ParsedArgs{args})
+}
+
+// SettleBatch settles several filled orders sharing a destination settler and origin domain in one transaction
+func (h *HyperlaneEVM) SettleBatch(ctx context.Context, orders []*types.ParsedArgs) error {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
+	if len(orders) == 0 {
+		return nil
+	}
+	args := orders[0]
 	if len(args.ResolvedOrder.FillInstructions) == 0 {
 		return fmt.Errorf("no fill instructions found")
 	}
 
 	instruction := args.ResolvedOrder.FillInstructions[0]
 
-	// Use the order ID from the event
-	var orderID [32]byte
-	orderIDBytes := common.FromHex(args.OrderID)
-	copy(orderID[:], orderIDBytes)
-
 	// Convert destination settler string to EVM address for contract operations
 	destinationSettler, err := types.ToEVMAddress(instruction.DestinationSettler)
 	if err != nil {
 		return fmt.Errorf("failed to convert destination settler to EVM address: %w", err)
 	}
 
-	// Pre-settle check: ensure order is FILLED with retry logic
-	status, err := h.waitForOrderStatus(ctx, args, orderStatusFilled, maxRetryAttempts, 2*time.Second)
-	if err != nil {
-		return fmt.Errorf("failed to get order status after retries: %w", err)
-	}
-	if status != orderStatusFilled {
-		return fmt.Errorf("order status must be filled in order to settle, got: %s", status)
+	// Pre-settle check: ensure every order is FILLED, waiting for lagging nodes to see the fill
+	orderIDs := make([][32]byte, 0, len(orders))
+	for _, order := range orders {
+		waitCtx, span := tracing.StartOrderSpan(ctx, tracing.SpanWaitStatus, order.OrderID, tracing.KeyStatus.String(orderStatusFilled))
+		status, err := h.waitForOrderStatus(waitCtx, order, orderStatusFilled)
+		tracing.End(span, err)
+		if err != nil {
+			return fmt.Errorf("failed to get status of order %s: %w", order.OrderID, err)
+		}
+		if status != orderStatusFilled {
+			return fmt.Errorf("order %s status must be filled in order to settle, got: %s", order.OrderID, status)
+		}
+		// Settling another solver's fill would pay for their interchain message
+		if err := h.checkFiller(ctx, order); err != nil {
+			return err
+		}
+
+		// Use the order ID from the event
+		var orderID [32]byte
+		copy(orderID[:], common.FromHex(order.OrderID))
+		orderIDs = append(orderIDs, orderID)
 	}
 
 	// Get the contract instance using the EVM address
@@ -197,20 +282,22 @@ func (h *HyperlaneEVM) Settle(ctx context.Context, args *types.ParsedArgs) error
 	if originDomain == starknetDomain {
 		if !envutil.IsDevnet() {
 			// Live networks: Skip settlement until Starknet domain is registered
-			fmt.Printf("   ⚠️  Skipping EVM settlement for Starknet origin (domain %d) on live network\n", originDomain)
-			fmt.Printf("   ⏳ Starknet domain not yet registered on EVM contracts - waiting for Hyperlane team\n")
-			fmt.Printf("   📝 Order filled successfully, settlement will be available once domain is registered\n")
-			return nil // Skip settlement but don't treat as error
+			h.chainLogger().Warn(fmt.Sprintf("   ⚠️  Skipping EVM settlement for Starknet origin (domain %d) on live network", originDomain),
+				logutil.OrderID(args.OrderID), logutil.Stage("settle"))
+			h.chainLogger().Info("   ⏳ Starknet domain not yet registered on EVM contracts - waiting for Hyperlane team")
+			h.chainLogger().Info("   📝 Order filled successfully, settlement will be available once domain is registered")
+			return fmt.Errorf("%w: Starknet domain %d is not registered on live EVM settlers", ErrSettlementDeferred, originDomain)
 		} else {
 			// Fork mode: Continue with settlement (domains are mocked/registered)
-			fmt.Printf("   🔧 Fork mode detected - proceeding with Starknet settlement (domain %d registered)\n", originDomain)
+			h.chainLogger().Info(fmt.Sprintf("   🔧 Fork mode detected - proceeding with Starknet settlement (domain %d registered)", originDomain))
 		}
 	}
 
 	// Get chain IDs for cross-chain logging
 	originChainID := args.ResolvedOrder.OriginChainID.Uint64()
 	destChainID := args.ResolvedOrder.FillInstructions[0].DestinationChainID.Uint64()
-	logutil.CrossChainOperation(fmt.Sprintf("Quoting gas payment for origin domain: %d", originDomain), originChainID, destChainID, args.OrderID)
+	logutil.CrossChain(h.logger, fmt.Sprintf("Quoting gas payment for origin domain: %d", originDomain), originChainID, destChainID, args.OrderID,
+		logutil.Stage("settle"))
 	gasPayment, err := contract.QuoteGasPayment(&bind.CallOpts{
 		Pending:     false,
 		From:        common.Address{},
@@ -222,41 +309,476 @@ func (h *HyperlaneEVM) Settle(ctx context.Context, args *types.ParsedArgs) error
 		return fmt.Errorf("quoteGasPayment failed on %s: %w", destinationSettler, err)
 	}
 
-	// Prepare order IDs array (contract expects array)
-	orderIDs := make([][32]byte, 1)
-	orderIDs[0] = orderID
+	// Execute the settle transaction for all order IDs, paying the quoted gas payment once as value
+	callData, err := packHyperlaneCall("settle", orderIDs)
+	if err != nil {
+		return err
+	}
 
-	// Execute the settle transaction
+	settle := txmanager.EVMTx{To: destinationSettler, Data: callData, Value: gasPayment, GasLimit: 0}
+	if _, err := h.preflight(ctx, "settle", []txmanager.EVMTx{settle}); err != nil {
+		return err
+	}
+	receipt, err := h.txm.Send(ctx, settle)
+	if err != nil {
+		return fmt.Errorf("settle tx failed on %s: %w", destinationSettler, err)
+	}
+	h.recordGasUsed(ctx, "settle", receipt)
+	accounting.RecordFee(ctx, accounting.KindInterchainFee, "settle", logutil.NetworkNameByChainID(h.chainID),
+		accounting.UnitWei, gasPayment)
+	tracing.Annotate(ctx, tracing.TxHash(receipt.TxHash.Hex()))
+	history.RecordTx(ctx, "settle", logutil.NetworkNameByChainID(h.chainID), receipt.TxHash.Hex())
+
+	logutil.CrossChain(h.logger,
+		fmt.Sprintf("Settle transaction for %d order(s) confirmed at block %d (gasUsed=%d)", len(orderIDs), receipt.BlockNumber, receipt.GasUsed),
+		originChainID, destChainID, args.OrderID,
+		logutil.Stage("settle"), logutil.TxHash(receipt.TxHash.Hex()),
+	)
+	return nil
+}
 
-	// Set txn value
-	originalValue := h.signer.Value
-	h.signer.Value = new(big.Int).Set(gasPayment)
-	defer func() { h.signer.Value = originalValue }()
+// SimulateFill runs the approvals and fill Fill would send against the latest state, without sending them.
+// When approvals are needed they are simulated together with the fill through eth_simulateV1; nodes without it
+// can only check the approvals, and the fill is estimated at a typical gas use.
+func (h *HyperlaneEVM) SimulateFill(ctx context.Context, args *types.ParsedArgs) (*Simulation, error) {
+	if len(args.ResolvedOrder.FillInstructions) == 0 {
+		return nil, fmt.Errorf("no fill instructions found")
+	}
+	instruction := args.ResolvedOrder.FillInstructions[0]
+	destinationSettlerAddr, err := types.ToEVMAddress(instruction.DestinationSettler)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert destination settler to EVM address: %w", err)
+	}
 
+	sim := newSimulation("fill", h.chainID, txmanager.UnitWei)
+	status, err := h.GetOrderStatus(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	if status != orderStatusUnknown {
+		sim.Calls = []string{"fill"}
+		sim.revert(fmt.Sprintf("order is already %s on chain", status))
+		return sim, nil
+	}
 
-	tx, err := contract.Settle(h.signer, orderIDs)
+	value := nativeValue(args, instruction.DestinationChainID.Uint64())
+	if err := h.ensureNativeBalance(ctx, value); err != nil {
+		sim.Calls = []string{"fill"}
+		sim.revert(err.Error())
+		return sim, nil
+	}
+
+	approvals, err := h.approvalTxs(ctx, args, destinationSettlerAddr)
 	if err != nil {
-		return fmt.Errorf("settle tx failed on %s: %w", destinationSettler, err)
+		return nil, err
+	}
+	var orderID [32]byte
+	copy(orderID[:], common.FromHex(args.OrderID))
+	fillerData, err := h.fillers.receiver(args.ResolvedOrder.OriginChainID)
+	if err != nil {
+		return nil, err
 	}
-	logutil.CrossChainOperation(fmt.Sprintf("Settle transaction sent: %s", tx.Hash().Hex()), originChainID, destChainID, args.OrderID)
+	callData, err := packHyperlaneCall("fill", orderID, instruction.OriginData, fillerData)
+	if err != nil {
+		return nil, err
+	}
+	fill := txmanager.EVMTx{To: destinationSettlerAddr, Data: callData, Value: value, GasLimit: 0}
 
-	// Wait for confirmation
-	receipt, err := bind.WaitMined(ctx, h.client, tx)
+	if len(approvals) == 0 {
+		result, err := h.txm.Simulate(ctx, fill)
+		if err != nil {
+			return nil, err
+		}
+		sim.addTx("fill", result)
+		return sim, nil
+	}
+
+	txs := append(append(make([]txmanager.EVMTx, 0, len(approvals)+1), approvals...), fill)
+	results, err := h.txm.SimulateBundle(ctx, txs)
+	if err == nil {
+		for i := range results {
+			sim.addTx(evmCallName(txs, i, "fill"), &results[i])
+		}
+		return sim, nil
+	}
+	if !errors.Is(err, txmanager.ErrSimulationUnsupported) {
+		return nil, err
+	}
+
+	// The fill would revert on the missing allowance if run alone, so only the approvals are executed
+	var gas uint64
+	for _, approval := range approvals {
+		result, err := h.txm.Simulate(ctx, approval)
+		if err != nil {
+			return nil, err
+		}
+		sim.addTx(fmt.Sprintf("approve(%s)", approval.To.Hex()), result)
+		gas += result.Gas
+	}
+	sim.Calls = append(sim.Calls, "fill")
+	if sim.Outcome == SimulationReverted {
+		return sim, nil
+	}
+	gasPrice, err := h.client.SuggestGasPrice(ctx)
 	if err != nil {
-		return fmt.Errorf("waiting settle failed on %s: %w", destinationSettler, err)
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
 	}
+	sim.estimate("the fill needs approvals first and the node does not support eth_simulateV1", gas+fillGasEstimate, gasPrice)
+	return sim, nil
+}
 
-	if receipt.Status == 0 {
-		return fmt.Errorf("settle transaction failed on %s at block %d", destinationSettler, receipt.BlockNumber)
+// SimulateSettle quotes the interchain gas payment and, once the order is filled, runs the settle Settle would send.
+// Before the fill the settle would revert, so it is estimated at a typical gas use.
+func (h *HyperlaneEVM) SimulateSettle(ctx context.Context, args *types.ParsedArgs) (*Simulation, error) {
+	if len(args.ResolvedOrder.FillInstructions) == 0 {
+		return nil, fmt.Errorf("no fill instructions found")
+	}
+	destinationSettler, err := types.ToEVMAddress(args.ResolvedOrder.FillInstructions[0].DestinationSettler)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert destination settler to EVM address: %w", err)
+	}
+	originDomain, err := h.getOriginDomain(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get origin domain: %w", err)
+	}
+	contract, err := contracts.NewHyperlane7683(destinationSettler, h.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind contract at %s: %w", destinationSettler, err)
 	}
 
-	logutil.CrossChainOperation(
-		fmt.Sprintf("Settle transaction confirmed at block %d (gasUsed=%d)", receipt.BlockNumber, receipt.GasUsed),
-		originChainID, destChainID, args.OrderID,
+	sim := newSimulation("settle", h.chainID, txmanager.UnitWei)
+	gasPayment, err := contract.QuoteGasPayment(&bind.CallOpts{
+		Pending:     false,
+		From:        common.Address{},
+		BlockNumber: nil,
+		BlockHash:   common.Hash{},
+		Context:     ctx,
+	}, originDomain)
+	if err != nil {
+		return nil, fmt.Errorf("quoteGasPayment failed on %s: %w", destinationSettler, err)
+	}
+	sim.InterchainFee = gasPayment
+
+	status, err := h.GetOrderStatus(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	if status != orderStatusFilled {
+		gasPrice, err := h.client.SuggestGasPrice(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get gas price: %w", err)
+		}
+		sim.Calls = []string{"settle"}
+		sim.estimate(fmt.Sprintf("order is %s on chain, so the settle cannot run before the fill", status), settleGasEstimate, gasPrice)
+		return sim, nil
+	}
+
+	var orderID [32]byte
+	copy(orderID[:], common.FromHex(args.OrderID))
+	callData, err := packHyperlaneCall("settle", [][32]byte{orderID})
+	if err != nil {
+		return nil, err
+	}
+	result, err := h.txm.Simulate(ctx, txmanager.EVMTx{To: destinationSettler, Data: callData, Value: gasPayment, GasLimit: 0})
+	if err != nil {
+		return nil, err
+	}
+	sim.addTx("settle", result)
+	return sim, nil
+}
+
+// preflight simulates the transactions of operation, approvals first, and returns a *RevertError if one would revert.
+// Several transactions are simulated together through eth_simulateV1; on nodes without it nothing is checked
+// and checked is false.
+func (h *HyperlaneEVM) preflight(ctx context.Context, operation string, txs []txmanager.EVMTx) (bool, error) {
+	ctx, span := tracing.StartSpan(ctx, tracing.SpanPreflight, tracing.KeyOperation.String(operation))
+	sim := newSimulation(operation, h.chainID, txmanager.UnitWei)
+	var err error
+	if len(txs) == 1 {
+		var result *txmanager.Simulation
+		if result, err = h.txm.Simulate(ctx, txs[0]); err == nil {
+			sim.addTx(operation, result)
+		}
+	} else {
+		var results []txmanager.Simulation
+		if results, err = h.txm.SimulateBundle(ctx, txs); err == nil {
+			for i := range results {
+				sim.addTx(evmCallName(txs, i, operation), &results[i])
+			}
+		}
+	}
+	if errors.Is(err, txmanager.ErrSimulationUnsupported) {
+		tracing.End(span, nil)
+		return false, nil
+	}
+	if err == nil {
+		err = sim.Err()
+	} else {
+		err = fmt.Errorf("pre-flight simulation of %s failed: %w", operation, err)
+	}
+	tracing.End(span, err)
+	if sim.Revert != nil {
+		h.chainLogger().Warn(fmt.Sprintf("   🛑 Pre-flight: %s would revert, not sending", operation), logutil.Err(sim.Revert))
+	}
+	return true, err
+}
+
+// evmCallName describes the i-th of txs: the operation for the last one, approve(token) for the approvals before it
+func evmCallName(txs []txmanager.EVMTx, i int, operation string) string {
+	if i == len(txs)-1 {
+		return operation
+	}
+	return fmt.Sprintf("approve(%s)", txs[i].To.Hex())
+}
+
+// nativeValue sums the native outputs spent on chainID, which the fill must carry as msg.value
+func nativeValue(args *types.ParsedArgs, chainID uint64) *big.Int {
+	value := new(big.Int)
+	for _, output := range args.ResolvedOrder.MaxSpent {
+		if !output.IsNative() || output.Amount == nil {
+			continue
+		}
+		if output.ChainID != nil && output.ChainID.Uint64() != chainID {
+			continue
+		}
+		value.Add(value, output.Amount)
+	}
+	return value
+}
+
+// ensureNativeBalance checks the solver holds value in native ETH before sending it with the fill
+func (h *HyperlaneEVM) ensureNativeBalance(ctx context.Context, value *big.Int) error {
+	if value.Sign() == 0 {
+		return nil
+	}
+	balance, err := h.client.BalanceAt(ctx, h.signer.From, nil)
+	if err != nil {
+		return failure.Mark(fmt.Errorf("failed to get native balance: %w", err), failure.ErrNetwork)
+	}
+	if balance.Cmp(value) < 0 {
+		return failure.Mark(fmt.Errorf("insufficient native balance for fill: have %s, need %s", balance, value),
+			failure.ErrInsufficientFunds)
+	}
+	return nil
+}
+
+// EstimateFillCost prices a typical fill plus settle at the current gas price and checks the solver can pay for it
+func (h *HyperlaneEVM) EstimateFillCost(ctx context.Context) (*big.Int, error) {
+	gasPrice, err := h.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, failure.Mark(fmt.Errorf("failed to get gas price: %w", err), failure.ErrNetwork)
+	}
+	cost := new(big.Int).Mul(gasPrice, big.NewInt(fillGasEstimate+settleGasEstimate))
+
+	balance, err := h.client.BalanceAt(ctx, h.signer.From, nil)
+	if err != nil {
+		return nil, failure.Mark(fmt.Errorf("failed to get native balance: %w", err), failure.ErrNetwork)
+	}
+	if balance.Cmp(cost) < 0 {
+		return cost, failure.Mark(fmt.Errorf("%w: insufficient native balance for gas: have %s, need %s", ErrOrderRejected, balance, cost),
+			failure.ErrInsufficientFunds)
+	}
+	return cost, nil
+}
+
+// TokenDecimals reads the decimals of token; the native token has 18
+func (h *HyperlaneEVM) TokenDecimals(_ context.Context, token string) (uint8, error) {
+	if types.IsNativeToken(token) {
+		return nativeTokenDecimals, nil
+	}
+	tokenAddr, err := types.ToEVMAddress(token)
+	if err != nil {
+		return 0, fmt.Errorf("invalid token address %s: %w", token, err)
+	}
+	decimals, err := ethutil.ERC20Decimals(h.client, tokenAddr)
+	if err != nil {
+		return 0, failure.Mark(fmt.Errorf("failed to read decimals of %s: %w", token, err), failure.ErrNetwork)
+	}
+	return decimals, nil
+}
+
+// Refund refunds expired, unfilled orders sharing a destination settler and origin domain in one transaction.
+// The destination dispatches a refund message; the origin then returns each order's input to its sender.
+func (h *HyperlaneEVM) Refund(ctx context.Context, orders []*types.ParsedArgs) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(orders) == 0 {
+		return nil
+	}
+	args := orders[0]
+	if len(args.ResolvedOrder.FillInstructions) == 0 {
+		return fmt.Errorf("no fill instructions found")
+	}
+
+	destinationSettler, err := types.ToEVMAddress(args.ResolvedOrder.FillInstructions[0].DestinationSettler)
+	if err != nil {
+		return fmt.Errorf("failed to convert destination settler to EVM address: %w", err)
+	}
+
+	// The destination only refunds orders it has never seen filled
+	refundOrders := make([]contracts.OnchainCrossChainOrder, 0, len(orders))
+	for _, order := range orders {
+		onchainOrder, err := refundableOrder(order)
+		if err != nil {
+			return err
+		}
+		status, err := h.GetOrderStatus(ctx, order)
+		if err != nil {
+			return fmt.Errorf("failed to get status of order %s: %w", order.OrderID, err)
+		}
+		if status != orderStatusUnknown {
+			return fmt.Errorf("order %s cannot be refunded with destination status %s", order.OrderID, status)
+		}
+		refundOrders = append(refundOrders, onchainOrder)
+	}
+
+	contract, err := contracts.NewHyperlane7683(destinationSettler, h.client)
+	if err != nil {
+		return fmt.Errorf("failed to bind contract at %s: %w", destinationSettler, err)
+	}
+	originDomain, err := h.getOriginDomain(args)
+	if err != nil {
+		return fmt.Errorf("failed to get origin domain: %w", err)
+	}
+
+	originChainID := args.ResolvedOrder.OriginChainID.Uint64()
+	logutil.CrossChain(h.logger, fmt.Sprintf("Quoting refund gas payment for origin domain: %d", originDomain),
+		originChainID, h.chainID, args.OrderID, logutil.Stage("refund"))
+	gasPayment, err := contract.QuoteGasPayment(&bind.CallOpts{
+		Pending:     false,
+		From:        common.Address{},
+		BlockNumber: nil,
+		BlockHash:   common.Hash{},
+		Context:     ctx,
+	}, originDomain)
+	if err != nil {
+		return fmt.Errorf("quoteGasPayment failed on %s: %w", destinationSettler, err)
+	}
+
+	// "refund" is the OnchainCrossChainOrder[] overload; "refund0" takes gasless orders
+	callData, err := packHyperlaneCall("refund", refundOrders)
+	if err != nil {
+		return err
+	}
+
+	receipt, err := h.txm.Send(ctx, txmanager.EVMTx{To: destinationSettler, Data: callData, Value: gasPayment, GasLimit: 0})
+	if err != nil {
+		return fmt.Errorf("refund tx failed on %s: %w", destinationSettler, err)
+	}
+	h.recordGasUsed(ctx, "refund", receipt)
+	accounting.RecordFee(ctx, accounting.KindInterchainFee, "refund", logutil.NetworkNameByChainID(h.chainID),
+		accounting.UnitWei, gasPayment)
+
+	logutil.CrossChain(h.logger,
+		fmt.Sprintf("Refund transaction for %d order(s) confirmed at block %d (gasUsed=%d)", len(refundOrders), receipt.BlockNumber, receipt.GasUsed),
+		originChainID, h.chainID, args.OrderID,
+		logutil.Stage("refund"), logutil.TxHash(receipt.TxHash.Hex()),
 	)
 	return nil
 }
 
+// VerifyGaslessOrder checks a signed gasless order against its origin settler on this chain
+// so openFor is only sent for orders that would be accepted. Problems with the order wrap ErrOrderRejected.
+func (h *HyperlaneEVM) VerifyGaslessOrder(ctx context.Context, signed *orderutil.SignedGaslessOrder) error {
+	order := signed.Order
+	if now := time.Now().Unix(); int64(order.OpenDeadline) <= now {
+		return fmt.Errorf("%w: %w: open deadline %d", ErrOrderRejected, failure.ErrDeadlinePassed, order.OpenDeadline)
+	}
+	if order.FillDeadline <= order.OpenDeadline {
+		return fmt.Errorf("%w: fill deadline %d is not after open deadline %d", ErrOrderRejected,
+			order.FillDeadline, order.OpenDeadline)
+	}
+
+	contract, err := contracts.NewHyperlane7683(order.OriginSettler, h.client)
+	if err != nil {
+		return fmt.Errorf("failed to bind contract at %s: %w", order.OriginSettler.Hex(), err)
+	}
+	callOpts := &bind.CallOpts{
+		Pending:     false,
+		From:        common.Address{},
+		BlockNumber: nil,
+		BlockHash:   common.Hash{},
+		Context:     ctx,
+	}
+
+	localDomain, err := contract.LocalDomain(callOpts)
+	if err != nil {
+		return fmt.Errorf("localDomain failed on %s: %w", order.OriginSettler.Hex(), err)
+	}
+	if order.OriginChainID == nil || order.OriginChainID.Uint64() != uint64(localDomain) {
+		return fmt.Errorf("%w: origin chain ID %v does not match settler domain %d", ErrOrderRejected,
+			order.OriginChainID, localDomain)
+	}
+
+	// Permit2's EIP-712 domain uses the EVM chain ID, which may differ from the Hyperlane domain
+	chainID, err := h.client.ChainID(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get chain ID: %w", err)
+	}
+	permit2, err := contract.PERMIT2(callOpts)
+	if err != nil {
+		return fmt.Errorf("PERMIT2 failed on %s: %w", order.OriginSettler.Hex(), err)
+	}
+	if err := signed.Verify(permit2, chainID); err != nil {
+		return fmt.Errorf("%w: invalid signature: %w", ErrOrderRejected, err)
+	}
+
+	senderNonce, err := order.SenderNonce()
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrOrderRejected, err)
+	}
+	valid, err := contract.IsValidNonce(callOpts, order.User, senderNonce)
+	if err != nil {
+		return fmt.Errorf("isValidNonce failed on %s: %w", order.OriginSettler.Hex(), err)
+	}
+	if !valid {
+		return fmt.Errorf("%w: sender nonce %s already used by %s", ErrOrderRejected, senderNonce, order.User.Hex())
+	}
+	return nil
+}
+
+// OpenFor submits openFor for a signed gasless order; the Open event it emits is filled like any other order
+func (h *HyperlaneEVM) OpenFor(ctx context.Context, signed *orderutil.SignedGaslessOrder) error {
+	order := signed.Order
+	resolved, err := order.Resolve()
+	if err != nil {
+		return err
+	}
+	orderID := common.BytesToHash(resolved.OrderID[:]).Hex()
+
+	var originFillerData []byte
+	callData, err := packHyperlaneCall("openFor", contracts.GaslessCrossChainOrder{
+		OriginSettler: order.OriginSettler,
+		User:          order.User,
+		Nonce:         order.Nonce,
+		OriginChainId: order.OriginChainID,
+		OpenDeadline:  order.OpenDeadline,
+		FillDeadline:  order.FillDeadline,
+		OrderDataType: order.OrderDataType,
+		OrderData:     order.OrderData,
+	}, []byte(signed.Signature), originFillerData)
+	if err != nil {
+		return err
+	}
+
+	originChainID := order.OriginChainID.Uint64()
+	destinationChainID := resolved.FillInstructions[0].DestinationChainId.Uint64()
+	logutil.CrossChain(h.logger, fmt.Sprintf("Opening gasless order for %s via %s", order.User.Hex(), order.OriginSettler.Hex()),
+		originChainID, destinationChainID, orderID, logutil.Stage("open"))
+
+	receipt, err := h.txm.Send(ctx, txmanager.EVMTx{To: order.OriginSettler, Data: callData, Value: nil, GasLimit: 0})
+	if err != nil {
+		return fmt.Errorf("openFor transaction failed: %w", err)
+	}
+	h.recordGasUsed(ctx, "openFor", receipt)
+
+	logutil.CrossChain(h.logger, fmt.Sprintf("openFor successful! Gas used: %d", receipt.GasUsed),
+		originChainID, destinationChainID, orderID,
+		logutil.Stage("open"), logutil.TxHash(receipt.TxHash.Hex()))
+	return nil
+}
+
 // GetOrderStatus returns the current status of an order
 func (h *HyperlaneEVM) GetOrderStatus(ctx context.Context, args *types.ParsedArgs) (string, error) {
 	if len(args.ResolvedOrder.FillInstructions) == 0 {
@@ -269,7 +791,6 @@ func (h *HyperlaneEVM) GetOrderStatus(ctx context.Context, args *types.ParsedArg
 	orderIDBytes := common.FromHex(args.OrderID)
 	copy(orderIDArr[:], orderIDBytes)
 
-
 	// Check order status
 	orderStatusABI := `[{
 		"type": "function",
@@ -296,21 +817,21 @@ func (h *HyperlaneEVM) GetOrderStatus(ctx context.Context, args *types.ParsedArg
 
 	dummyFrom := common.HexToAddress("0x1000000000000000000000000000000000000000")
 	res, err := h.client.CallContract(ctx, ethereum.CallMsg{
-		From:            dummyFrom,
-		To:              &destinationSettlerAddr,
-		Gas:             0,
-		GasPrice:        nil,
-		GasFeeCap:       nil,
-		GasTipCap:       nil,
-		Value:           nil,
-		Data:            callData,
-		AccessList:      nil,
-		BlobGasFeeCap:   nil,
-		BlobHashes:      nil,
+		From:              dummyFrom,
+		To:                &destinationSettlerAddr,
+		Gas:               0,
+		GasPrice:          nil,
+		GasFeeCap:         nil,
+		GasTipCap:         nil,
+		Value:             nil,
+		Data:              callData,
+		AccessList:        nil,
+		BlobGasFeeCap:     nil,
+		BlobHashes:        nil,
 		AuthorizationList: nil,
 	}, nil)
 	if err != nil {
-		return orderStatusUnknown, fmt.Errorf("orderStatus call failed: %w", err)
+		return orderStatusUnknown, failure.Mark(fmt.Errorf("orderStatus call failed: %w", err), failure.ErrNetwork)
 	}
 
 	if len(res) < 32 {
@@ -322,6 +843,46 @@ func (h *HyperlaneEVM) GetOrderStatus(ctx context.Context, args *types.ParsedArg
 	return status, nil
 }
 
+// CheckFiller reads who filled the order from the destination settler's filledOrders and fails with
+// failure.ErrCompetitorFilled when it was another solver
+func (h *HyperlaneEVM) CheckFiller(ctx context.Context, args *types.ParsedArgs) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.checkFiller(ctx, args)
+}
+
+// checkFiller is CheckFiller for callers already holding h.mu
+func (h *HyperlaneEVM) checkFiller(ctx context.Context, args *types.ParsedArgs) error {
+	if len(args.ResolvedOrder.FillInstructions) == 0 {
+		return fmt.Errorf("no fill instructions found")
+	}
+	destinationSettler, err := types.ToEVMAddress(args.ResolvedOrder.FillInstructions[0].DestinationSettler)
+	if err != nil {
+		return fmt.Errorf("failed to convert destination settler to EVM address: %w", err)
+	}
+	contract, err := contracts.NewHyperlane7683(destinationSettler, h.client)
+	if err != nil {
+		return fmt.Errorf("failed to bind contract at %s: %w", destinationSettler, err)
+	}
+
+	var orderID [32]byte
+	copy(orderID[:], common.FromHex(args.OrderID))
+	filled, err := contract.FilledOrders(&bind.CallOpts{
+		Pending:     false,
+		From:        common.Address{},
+		BlockNumber: nil,
+		BlockHash:   common.Hash{},
+		Context:     ctx,
+	}, orderID)
+	if err != nil {
+		return failure.Mark(fmt.Errorf("filledOrders call failed: %w", err), failure.ErrNetwork)
+	}
+	if h.fillers.owns(args.ResolvedOrder.OriginChainID, filled.FillerData) {
+		return nil
+	}
+	return competitorFilled(args.OrderID, filled.FillerData)
+}
+
 // getOriginDomainFromArgs extracts the origin domain using the config system
 func (h *HyperlaneEVM) getOriginDomain(args *types.ParsedArgs) (uint32, error) {
 	if args.ResolvedOrder.OriginChainID == nil {
@@ -340,50 +901,74 @@ func (h *HyperlaneEVM) getOriginDomain(args *types.ParsedArgs) (uint32, error) {
 	return 0, fmt.Errorf("no domain found for chain ID %d in config (check your .env file)", chainID)
 }
 
-// setupApprovals handles all ERC20 approvals needed for the fill operation
-func (h *HyperlaneEVM) setupApprovals(ctx context.Context, args *types.ParsedArgs, destinationSettlerAddr common.Address) error {
+// setupApprovals sends the ERC20 approvals built by approvalTxs for the fill operation
+func (h *HyperlaneEVM) setupApprovals(ctx context.Context, args *types.ParsedArgs, approvals []txmanager.EVMTx) error {
 	if len(args.ResolvedOrder.MaxSpent) == 0 {
 		return nil
 	}
 
+	for _, approval := range approvals {
+		if err := h.sendApproval(ctx, approval); err != nil {
+			return fmt.Errorf("approval failed for token %s: %w", approval.To.Hex(), err)
+		}
+	}
+
+	originChainID := args.ResolvedOrder.OriginChainID.Uint64()
+	destinationChainID := args.ResolvedOrder.FillInstructions[0].DestinationChainID.Uint64()
+	logutil.CrossChain(h.logger, "EVM token approvals set", originChainID, destinationChainID, args.OrderID, logutil.Stage("approve"))
+
+	return nil
+}
+
+// approvalTxs builds the ERC20 approve transactions the fill needs, skipping tokens whose allowance already suffices
+func (h *HyperlaneEVM) approvalTxs(ctx context.Context, args *types.ParsedArgs, destinationSettlerAddr common.Address) (
+	[]txmanager.EVMTx, error) {
 	// Get destination chain ID from fill instruction
 	if len(args.ResolvedOrder.FillInstructions) == 0 {
-		return fmt.Errorf("no fill instructions found")
+		return nil, fmt.Errorf("no fill instructions found")
 	}
 	destinationChainID := args.ResolvedOrder.FillInstructions[0].DestinationChainID.Uint64()
 
-	// Get origin chain ID for cross-chain logging
-	originChainID := args.ResolvedOrder.OriginChainID.Uint64()
-
+	// Outputs in the same token are summed so a single approve covers all of them
+	var tokens []common.Address
+	totals := make(map[common.Address]*big.Int)
 	for _, maxSpent := range args.ResolvedOrder.MaxSpent {
-		// Skip native ETH (empty string)
-		if maxSpent.Token == "" {
+		// Native ETH is sent as value with the fill, not approved
+		if maxSpent.IsNative() {
 			continue
 		}
 
 		// Only approve tokens that belong to this chain (destination chain)
 		if maxSpent.ChainID.Uint64() != destinationChainID {
-			fmt.Printf("   ⚠️  Skipping approval for token %s on chain %d (this handler is for chain %d)\n",
-				maxSpent.Token, maxSpent.ChainID.Uint64(), destinationChainID)
+			h.chainLogger().Warn(fmt.Sprintf("   ⚠️  Skipping approval for token %s on chain %d (this handler is for chain %d)",
+				maxSpent.Token, maxSpent.ChainID.Uint64(), destinationChainID), logutil.OrderID(args.OrderID))
 			continue
 		}
 
 		// Convert token address string to EVM address for approval
 		tokenAddr, err := types.ToEVMAddress(maxSpent.Token)
 		if err != nil {
-			return fmt.Errorf("failed to convert token address for approval: %w", err)
+			return nil, fmt.Errorf("failed to convert token address for approval: %w", err)
 		}
 
-		if err := h.ensureTokenApproval(ctx, tokenAddr, destinationSettlerAddr, maxSpent.Amount); err != nil {
-			return fmt.Errorf("approval failed for token %s: %w", maxSpent.Token, err)
+		if totals[tokenAddr] == nil {
+			tokens = append(tokens, tokenAddr)
+			totals[tokenAddr] = new(big.Int)
 		}
+		totals[tokenAddr].Add(totals[tokenAddr], maxSpent.Amount)
 	}
-	logutil.CrossChainOperation("EVM token approvals set", originChainID, destinationChainID, args.OrderID)
-
-	// Add a small delay to ensure blockchain state is updated after approvals
-	time.Sleep(1 * time.Second)
 
-	return nil
+	var approvals []txmanager.EVMTx
+	for _, tokenAddr := range tokens {
+		approval, err := h.approvalTx(ctx, tokenAddr, destinationSettlerAddr, totals[tokenAddr])
+		if err != nil {
+			return nil, fmt.Errorf("approval failed for token %s: %w", tokenAddr.Hex(), err)
+		}
+		if approval != nil {
+			approvals = append(approvals, *approval)
+		}
+	}
+	return approvals, nil
 }
 
 func (h *HyperlaneEVM) interpretStatusHash(_ context.Context, statusHash common.Hash) string {
@@ -402,14 +987,23 @@ func (h *HyperlaneEVM) interpretStatusHash(_ context.Context, statusHash common.
 	// Check hardcoded SETTLED constant
 	settledHash := common.HexToHash("0x534554544c454400000000000000000000000000000000000000000000000000")
 	if statusHash == settledHash {
-		return "SETTLED"
+		return orderStatusSettled
+	}
+
+	// Origin-side statuses, seen when tracking refunds
+	switch statusHash {
+	case common.HexToHash("0x4f50454e45440000000000000000000000000000000000000000000000000000"):
+		return orderStatusOpened
+	case common.HexToHash("0x524546554e444544000000000000000000000000000000000000000000000000"):
+		return orderStatusRefunded
 	}
 
 	return statusHash.Hex()
 }
 
-// ensureTokenApproval ensures the solver has approved an arbitrary ERC20 token for the Hyperlane contract
-func (h *HyperlaneEVM) ensureTokenApproval(ctx context.Context, tokenAddr, spender common.Address, amount *big.Int) error {
+// approvalTx returns the approve transaction needed for spender to pull amount of an arbitrary ERC20 token,
+// or nil when the current allowance suffices
+func (h *HyperlaneEVM) approvalTx(ctx context.Context, tokenAddr, spender common.Address, amount *big.Int) (*txmanager.EVMTx, error) {
 	// Check current allowance
 	allowanceABI := `[{
 		"type": "function",
@@ -423,51 +1017,51 @@ func (h *HyperlaneEVM) ensureTokenApproval(ctx context.Context, tokenAddr, spend
 	}]`
 	parsedABI, err := abi.JSON(strings.NewReader(allowanceABI))
 	if err != nil {
-		return fmt.Errorf("failed to parse allowance ABI: %w", err)
+		return nil, fmt.Errorf("failed to parse allowance ABI: %w", err)
 	}
 
 	callData, err := parsedABI.Pack("allowance", h.signer.From, spender)
 	if err != nil {
-		return fmt.Errorf("failed to pack allowance call: %w", err)
+		return nil, fmt.Errorf("failed to pack allowance call: %w", err)
 	}
 
 	result, err := h.client.CallContract(ctx, ethereum.CallMsg{
-		From:            common.Address{},
-		To:              &tokenAddr,
-		Gas:             0,
-		GasPrice:        nil,
-		GasFeeCap:       nil,
-		GasTipCap:       nil,
-		Value:           nil,
-		Data:            callData,
-		AccessList:      nil,
-		BlobGasFeeCap:   nil,
-		BlobHashes:      nil,
+		From:              common.Address{},
+		To:                &tokenAddr,
+		Gas:               0,
+		GasPrice:          nil,
+		GasFeeCap:         nil,
+		GasTipCap:         nil,
+		Value:             nil,
+		Data:              callData,
+		AccessList:        nil,
+		BlobGasFeeCap:     nil,
+		BlobHashes:        nil,
 		AuthorizationList: nil,
 	}, nil)
 	if err != nil {
-		return fmt.Errorf("allowance call failed: %w", err)
+		return nil, failure.Mark(fmt.Errorf("allowance call failed: %w", err), failure.ErrNetwork)
 	}
 
 	if len(result) == 0 {
 		// Token doesn't exist on this chain (likely cross-chain order) - skip approval
-		fmt.Printf("   ⚠️  Token %s not found on this chain, skipping approval (cross-chain order)\n", tokenAddr.Hex())
+		h.chainLogger().Warn(fmt.Sprintf("   ⚠️  Token %s not found on this chain, skipping approval (cross-chain order)", tokenAddr.Hex()))
 		chainID, err := h.client.ChainID(ctx)
 		if err == nil {
-			fmt.Printf("   ⚠️  This chain ID: %s\n", chainID.String())
+			h.chainLogger().Warn(fmt.Sprintf("   ⚠️  This chain ID: %s", chainID.String()))
 		}
-		return nil
+		return nil, nil
 	}
 
 	if len(result) < 32 {
-		return fmt.Errorf("invalid allowance result length: %d", len(result))
+		return nil, fmt.Errorf("invalid allowance result length: %d", len(result))
 	}
 
 	currentAllowance := new(big.Int).SetBytes(result)
 
 	// If allowance is sufficient, no approval needed
 	if currentAllowance.Cmp(amount) >= 0 {
-		return nil
+		return nil, nil
 	}
 
 	// Approve exact amount needed
@@ -483,104 +1077,68 @@ func (h *HyperlaneEVM) ensureTokenApproval(ctx context.Context, tokenAddr, spend
 	}]`
 	parsedApproveABI, err := abi.JSON(strings.NewReader(approveABI))
 	if err != nil {
-		return fmt.Errorf("failed to parse approve ABI: %w", err)
+		return nil, fmt.Errorf("failed to parse approve ABI: %w", err)
 	}
 
 	approveData, err := parsedApproveABI.Pack("approve", spender, amount)
 	if err != nil {
-		return fmt.Errorf("failed to pack approve call: %w", err)
+		return nil, fmt.Errorf("failed to pack approve call: %w", err)
 	}
 
-	// Get current nonce
-	nonce, err := h.client.PendingNonceAt(ctx, h.signer.From)
-	if err != nil {
-		return fmt.Errorf("failed to get nonce: %w", err)
-	}
+	return &txmanager.EVMTx{To: tokenAddr, Data: approveData, Value: nil, GasLimit: 0}, nil
+}
 
-	// Get gas price
-	gasPrice, err := h.client.SuggestGasPrice(ctx)
-	if err != nil {
-		return fmt.Errorf("failed to get gas price: %w", err)
+// sendApproval sends an approve transaction built by approvalTx and records it
+func (h *HyperlaneEVM) sendApproval(ctx context.Context, approval txmanager.EVMTx) error {
+	if err := abortedBeforeBroadcast(ctx); err != nil {
+		return err
 	}
-
-	// Create approve transaction
-	approveTx := gethtypes.NewTransaction(
-		nonce,
-		tokenAddr,
-		big.NewInt(0),
-		approveGasLimit, // Gas limit for approve
-		gasPrice,
-		approveData,
-	)
-
-	// Sign transaction
-	signedTx, err := h.signer.Signer(h.signer.From, approveTx)
+	receipt, err := h.txm.Send(context.WithoutCancel(ctx), approval)
 	if err != nil {
-		return fmt.Errorf("failed to sign approve transaction: %w", err)
+		return fmt.Errorf("approve transaction failed: %w", err)
 	}
+	h.recordGasUsed(ctx, "approve", receipt)
+	tracing.Event(ctx, "approval confirmed", tracing.TxHash(receipt.TxHash.Hex()))
+	history.RecordTx(ctx, "approve", logutil.NetworkNameByChainID(h.chainID), receipt.TxHash.Hex())
 
-	// Send transaction
-	if err := h.client.SendTransaction(ctx, signedTx); err != nil {
-		return fmt.Errorf("failed to send approve transaction: %w", err)
-	}
+	h.chainLogger().Info(fmt.Sprintf("   ✅ Approval confirmed! Gas used: %d", receipt.GasUsed),
+		logutil.Stage("approve"), logutil.TxHash(receipt.TxHash.Hex()))
+	return nil
+}
 
-	fmt.Printf("   🚀 Approve transaction sent: %s\n", signedTx.Hash().Hex())
+// waitForOrderStatus re-reads the order status on every new block until it becomes the expected value
+func (h *HyperlaneEVM) waitForOrderStatus(ctx context.Context, args *types.ParsedArgs, expectedStatus string) (string, error) {
+	wait := newStatusWait(h.client, h.chainID, h.chainLogger().With(logutil.OrderID(args.OrderID)))
+	return wait.until(ctx, expectedStatus, func(ctx context.Context) (string, error) {
+		return h.GetOrderStatus(ctx, args)
+	})
+}
 
-	// Wait for confirmation
-	receipt, err := bind.WaitMined(ctx, h.client, signedTx)
+// packHyperlaneCall ABI-encodes a call to the Hyperlane7683 contract
+func packHyperlaneCall(method string, args ...interface{}) ([]byte, error) {
+	parsedABI, err := contracts.Hyperlane7683MetaData.GetAbi()
 	if err != nil {
-		return fmt.Errorf("failed to wait for approve confirmation: %w", err)
+		return nil, fmt.Errorf("failed to load Hyperlane7683 ABI: %w", err)
 	}
-
-	if receipt.Status != 1 {
-		return fmt.Errorf("approve transaction failed with status: %d", receipt.Status)
+	data, err := parsedABI.Pack(method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s call: %w", method, err)
 	}
-
-	fmt.Printf("   ✅ Approval confirmed! Gas used: %d\n", receipt.GasUsed)
-	return nil
+	return data, nil
 }
 
-// waitForOrderStatus waits for the order status to become the expected value with retry logic
-func (h *HyperlaneEVM) waitForOrderStatus(
-	ctx context.Context,
-	args *types.ParsedArgs,
-	expectedStatus string,
-	maxRetries int,
-	initialDelay time.Duration,
-) (string, error) {
-	delay := initialDelay
-
-	networkName := logutil.NetworkNameByChainID(h.chainID)
-
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		status, err := h.GetOrderStatus(ctx, args)
-		if err != nil {
-			fmt.Printf("   ⚠️  Status check attempt %d failed: %v\n", attempt, err)
-		} else {
-			logutil.LogStatusCheck(networkName, attempt, maxRetries, status, expectedStatus)
-			if status == expectedStatus {
-				return status, nil
-			}
-		}
-
-		// Don't wait after the last attempt
-		if attempt < maxRetries {
-			logutil.LogRetryWait(networkName, attempt, maxRetries, delay.String())
-			select {
-			case <-ctx.Done():
-				return "", ctx.Err()
-			case <-time.After(delay):
-				// Exponential backoff: double the delay for next attempt
-				delay *= 2
-			}
-		}
-	}
+// chainLogger returns the handler's logger tagged with its chain, for lines that are not about an order's route
+func (h *HyperlaneEVM) chainLogger() logutil.Logger {
+	return h.logger.With(logutil.Chain(logutil.NetworkNameByChainID(h.chainID)))
+}
 
-	// Final attempt to get the current status
-	finalStatus, err := h.GetOrderStatus(ctx, args)
-	if err != nil {
-		return orderStatusUnknown, fmt.Errorf("final status check failed after %d attempts: %w", maxRetries, err)
+// recordGasUsed records the gas a mined transaction of this handler used and charges its fee to the orders in ctx.
+// The fee is gasUsed at the effective gas price; rollup L1 data fees are not part of the receipt and are left out.
+func (h *HyperlaneEVM) recordGasUsed(ctx context.Context, operation string, receipt *gethtypes.Receipt) {
+	chain := logutil.NetworkNameByChainID(h.chainID)
+	metrics.TxGasUsed(chain, protocolLabel, operation, receipt.GasUsed)
+	if receipt.EffectiveGasPrice != nil {
+		fee := new(big.Int).Mul(new(big.Int).SetUint64(receipt.GasUsed), receipt.EffectiveGasPrice)
+		accounting.RecordFee(ctx, accounting.KindGas, operation, chain, accounting.UnitWei, fee)
 	}
-
-	return finalStatus, nil
 }
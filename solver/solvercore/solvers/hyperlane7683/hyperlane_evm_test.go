@@ -0,0 +1,29 @@
+package hyperlane7683
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+func TestNativeValue(t *testing.T) {
+	const chainID = uint64(11155420)
+	args := &types.ParsedArgs{
+		ResolvedOrder: types.ResolvedCrossChainOrder{
+			MaxSpent: []types.Output{
+				{Token: types.NativeToken, Amount: big.NewInt(700), ChainID: new(big.Int).SetUint64(chainID)},
+				{Token: "0x0000000000000000000000001234567890123456789012345678901234567890", Amount: big.NewInt(5),
+					ChainID: new(big.Int).SetUint64(chainID)},
+				{Token: types.NativeToken, Amount: big.NewInt(9), ChainID: big.NewInt(84532)},
+			},
+		},
+	}
+
+	// Only native outputs on the fill's own chain are sent as value
+	assert.Equal(t, int64(700), nativeValue(args, chainID).Int64())
+	assert.Equal(t, int64(9), nativeValue(args, 84532).Int64())
+	assert.Zero(t, nativeValue(args, 1).Sign())
+}
@@ -3,10 +3,13 @@ package hyperlane7683
 // Module: Starknet chain handler for Hyperlane7683
 // - Executes fill/settle/status calls against EVM Hyperlane7683 contracts
 // - Manages ERC20 approvals and gas/value handling for calls
+// - Sends invokes through a txmanager.Starknet (fee bounds, nonces, finality errors)
+// - Batches missing approvals with the fill/settle call into a single multicall invoke
+// - Names the solver as filler of its fills and reads who filled an order from filled_orders
 //
 // Interface Contract:
-// - Fill(): Must acquire mutex, setup approvals, execute fill, return OrderAction
-// - Settle(): Must acquire mutex, quote gas, ensure ETH approval, execute settle
+// - Fill(): Must acquire mutex, batch approvals with fill in one invoke, return OrderAction
+// - Settle(): Must acquire mutex, quote gas, batch ETH approval with settle in one invoke
 // - getOrderStatus(): Must check order status and return human-readable status
 // - All methods should use consistent logging patterns and error handling
 
@@ -14,13 +17,20 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"strings"
 	"sync"
-	"time"
 
 	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderutil"
 	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/accounting"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/history"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/tracing"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/txmanager"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 
 	"github.com/NethermindEth/juno/core/felt"
@@ -36,24 +46,34 @@ const (
 	evmOriginDataSize = 448
 )
 
+const (
+	// Typical resources used by a single-order fill and settle invoke, for quoting before any order exists
+	starknetFillL2GasEstimate   = 4_000_000
+	starknetSettleL2GasEstimate = 3_000_000
+	starknetL1DataGasEstimate   = 2 * 500
+)
+
 // HyperlaneStarknet contains all Starknet-specific logic for the Hyperlane 7683 protocol
 type HyperlaneStarknet struct {
 	// Client
 	provider *rpc.Provider
 	// Signer
-	account    *account.Account
+	txm        *txmanager.Starknet
 	solverAddr *felt.Felt
 	chainID    uint64
+	logger     logutil.Logger
+	// Accounts named in filler_data as the filler paid on the origin chain
+	fillers FillerAddresses
 
 	// hyperlaneAddr *felt.Felt
 	mu sync.Mutex // Serialize operations to prevent nonce conflicts
 }
 
 // NewHyperlaneStarknet creates a new Starknet handler for Hyperlane operations
-func NewHyperlaneStarknet(rpcURL string, chainID uint64) *HyperlaneStarknet {
+func NewHyperlaneStarknet(rpcURL string, chainID uint64, logger logutil.Logger) *HyperlaneStarknet {
 	provider, err := rpc.NewProvider(rpcURL)
 	if err != nil {
-		fmt.Printf("failed to create Starknet provider: %v", err)
+		logger.Error("failed to create Starknet provider", logutil.Err(err))
 		return nil
 	}
 
@@ -63,39 +83,55 @@ func NewHyperlaneStarknet(rpcURL string, chainID uint64) *HyperlaneStarknet {
 	priv := envutil.GetStarknetSolverPrivateKey()
 
 	if pub == "" || addrHex == "" || priv == "" {
-		fmt.Printf("missing STARKNET_SOLVER_* env vars for Starknet signer")
+		logger.Error("missing STARKNET_SOLVER_* env vars for Starknet signer")
 		return nil
 	}
 
 	addrF, err := utils.HexToFelt(addrHex)
 	if err != nil {
-		fmt.Printf("invalid STARKNET_SOLVER_ADDRESS: %v", err)
+		logger.Error("invalid STARKNET_SOLVER_ADDRESS", logutil.Err(err))
 		return nil
 	}
 
 	ks := account.NewMemKeystore()
 	privBI, ok := new(big.Int).SetString(priv, 0)
 	if !ok {
-		fmt.Printf("failed to parse STARKNET_SOLVER_PRIVATE_KEY")
+		logger.Error("failed to parse STARKNET_SOLVER_PRIVATE_KEY")
 		return nil
 	}
 
 	ks.Put(pub, privBI)
 	acct, err := account.NewAccount(provider, addrF, pub, ks, account.CairoV2)
 	if err != nil {
-		fmt.Printf("failed to create Starknet account: %v", err)
+		logger.Error("failed to create Starknet account", logutil.Err(err))
 		return nil
 	}
 
+	txm := txmanager.NewStarknet(acct, chainID, txmanager.StarknetConfigFromEnv())
+	return NewHyperlaneStarknetWithTxManager(provider, txm, chainID, logger)
+}
+
+// NewHyperlaneStarknetWithTxManager creates a new Starknet handler that sends through a shared transaction manager
+func NewHyperlaneStarknetWithTxManager(provider *rpc.Provider, txm *txmanager.Starknet, chainID uint64,
+	logger logutil.Logger) *HyperlaneStarknet {
 	return &HyperlaneStarknet{
-		account:    acct,
+		txm:        txm,
 		provider:   provider,
-		solverAddr: addrF,
+		solverAddr: txm.Address(),
 		chainID:    chainID,
+		logger:     logger,
+		fillers:    FillerAddresses{EVM: "", Starknet: txm.Address().String()},
 		mu:         sync.Mutex{},
 	}
 }
 
+// SetFillerAddresses sets the accounts named as filler of this handler's fills; empty addresses keep the current ones
+func (h *HyperlaneStarknet) SetFillerAddresses(addresses FillerAddresses) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.fillers = addresses.withDefaults(h.fillers)
+}
+
 // Fill executes a fill operation on Starknet
 func (h *HyperlaneStarknet) Fill(ctx context.Context, args *types.ParsedArgs) (OrderAction, error) {
 	h.mu.Lock()
@@ -119,95 +155,156 @@ func (h *HyperlaneStarknet) Fill(ctx context.Context, args *types.ParsedArgs) (O
 	// Pre-check: skip if order is already filled or settled
 	status, err := h.GetOrderStatus(ctx, args)
 	if err != nil {
-		fmt.Printf("   ⚠️  Status check failed: %v\n", err)
+		h.chainLogger().Warn("   ⚠️  Status check failed", logutil.OrderID(args.OrderID), logutil.Err(err))
 		return OrderActionError, err
 	}
-	networkName := logutil.NetworkNameByChainID(h.chainID)
-	logutil.LogStatusCheck(networkName, 1, 1, status, orderStatusUnknown)
+	logutil.StatusCheck(h.chainLogger(), 1, 1, status, orderStatusUnknown)
+	// An order another solver filled is theirs to settle
+	if status == orderStatusFilled || status == orderStatusSettled {
+		if err := h.checkFiller(ctx, args); err != nil {
+			return OrderActionError, err
+		}
+	}
 	if status == orderStatusFilled {
-		fmt.Printf("⏭️  Order already filled, proceeding to settlement\n")
+		h.chainLogger().Info("⏭️  Order already filled, proceeding to settlement", logutil.OrderID(args.OrderID))
 		return OrderActionSettle, nil
 	}
 	if status == orderStatusSettled {
-		fmt.Printf("🎉  Order already settled, nothing to do\n")
-		return OrderActionSettle, nil
+		h.chainLogger().Info("🎉  Order already settled, nothing to do", logutil.OrderID(args.OrderID))
+		return OrderActionComplete, nil
 	}
 
-	// Handle max spent approvals if needed
-	if err := h.setupApprovals(ctx, args, destinationSettlerAddr); err != nil {
+	// Approvals for max spent tokens are sent in the same invoke as the fill
+	approveCtx, span := tracing.StartOrderSpan(ctx, tracing.SpanApprove, args.OrderID)
+	calls, err := h.setupApprovals(approveCtx, args, destinationSettlerAddr)
+	tracing.End(span, err)
+	if err != nil {
 		return OrderActionError, fmt.Errorf("failed to setup approvals: %w", err)
 	}
 
-	// Prepare calldata; has a capacity of 6 + len(words)
+	// Execute the fill transaction
+	fillerData, err := h.fillers.receiver(args.ResolvedOrder.OriginChainID)
+	if err != nil {
+		return OrderActionError, err
+	}
+	invoke, err := fillCall(args, destinationSettlerAddr, fillerData)
+	if err != nil {
+		return OrderActionError, err
+	}
+	calls = append(calls, invoke)
+	if err := h.preflight(ctx, "fill", calls); err != nil {
+		return OrderActionError, err
+	}
+	if err := abortedBeforeBroadcast(ctx); err != nil {
+		return OrderActionError, err
+	}
+	receipt, err := h.txm.Send(context.WithoutCancel(ctx), calls)
+	if err != nil {
+		return OrderActionError, fmt.Errorf("starknet fill failed: %w", err)
+	}
+	h.recordGasUsed(ctx, "fill", receipt)
+	tracing.Annotate(ctx, tracing.TxHash(receipt.Hash.String()), tracing.Block(uint64(receipt.BlockNumber)))
+	history.RecordTx(ctx, "fill", logutil.NetworkNameByChainID(h.chainID), receipt.Hash.String())
+	// Get chain IDs for cross-chain logging
+	originChainID := args.ResolvedOrder.OriginChainID.Uint64()
+	destChainID := instruction.DestinationChainID.Uint64()
+	logutil.CrossChain(h.logger, fmt.Sprintf("Fill transaction confirmed (%d calls)", len(calls)),
+		originChainID, destChainID, orderID, logutil.Stage("fill"), logutil.TxHash(receipt.Hash.String()), logutil.Block(uint64(receipt.BlockNumber)))
+
+	return OrderActionFilled, nil
+}
+
+// fillCall builds the fill call for the order's first fill instruction, naming the filler through fillerData
+func fillCall(args *types.ParsedArgs, destinationSettler *felt.Felt, fillerData []byte) (rpc.InvokeFunctionCall, error) {
+	// Prepare calldata; has a capacity of 6 + len(words) + len(fillerWords)
 	// - Order ID: 2 felts (u256)
 	// - Origin data: 1 felt for size (usize), 1 felt for length (usize), 1 felt for each element
-	// - Filler data: 1 felt for size (usize), 1 felt for length (usize), 0 elements
-	originData := instruction.OriginData
+	// - Filler data: 1 felt for size (usize), 1 felt for length (usize), 1 felt for each element
+	originData := args.ResolvedOrder.FillInstructions[0].OriginData
 	words := starknetutil.BytesToU128Felts(originData)
+	fillerWords := starknetutil.BytesToU128Felts(fillerData)
 
 	// Convert bytes32 representation of orderID to u256 (2 felts)
-	orderIDLow, orderIDHigh, err := starknetutil.ConvertSolidityOrderIDForStarknet(orderID)
+	orderIDLow, orderIDHigh, err := starknetutil.ConvertSolidityOrderIDForStarknet(args.OrderID)
 	if err != nil {
-		return OrderActionError, fmt.Errorf("failed to convert solidity order ID for starknet: %w", err)
+		return rpc.InvokeFunctionCall{}, fmt.Errorf("failed to convert solidity order ID for starknet: %w", err)
 	}
 
-	calldata := make([]*felt.Felt, 0, calldataBaseSize+len(words))
-	calldata = append(calldata, 
+	calldata := make([]*felt.Felt, 0, calldataBaseSize+len(words)+len(fillerWords))
+	calldata = append(calldata,
 		orderIDLow, orderIDHigh,
 		utils.Uint64ToFelt(uint64(len(originData))),
 		utils.Uint64ToFelt(uint64(len(words))),
 	)
 	calldata = append(calldata, words...)
-	calldata = append(calldata, utils.Uint64ToFelt(0), utils.Uint64ToFelt(0)) // empty (size=0, len=0)
+	calldata = append(calldata,
+		utils.Uint64ToFelt(uint64(len(fillerData))),
+		utils.Uint64ToFelt(uint64(len(fillerWords))),
+	)
+	calldata = append(calldata, fillerWords...)
 
-	// Execute the fill transaction
-	invoke := rpc.InvokeFunctionCall{ContractAddress: destinationSettlerAddr, FunctionName: "fill", CallData: calldata}
-	tx, err := h.account.BuildAndSendInvokeTxn(ctx, []rpc.InvokeFunctionCall{invoke}, nil)
-	if err != nil {
-		return OrderActionError, fmt.Errorf("starknet fill send failed: %w", err)
-	}
-	// Get chain IDs for cross-chain logging
-	originChainID := args.ResolvedOrder.OriginChainID.Uint64()
-	destChainID := instruction.DestinationChainID.Uint64()
-	logutil.CrossChainOperation(fmt.Sprintf("Fill transaction sent: %s", tx.Hash.String()), originChainID, destChainID, orderID)
+	return rpc.InvokeFunctionCall{ContractAddress: destinationSettler, FunctionName: "fill", CallData: calldata}, nil
+}
 
-	// Wait for confirmation
-	_, waitErr := h.account.WaitForTransactionReceipt(ctx, tx.Hash, 2*time.Second)
-	if waitErr != nil {
-		return OrderActionError, fmt.Errorf("starknet fill wait failed: %w", waitErr)
+// settleCall builds the settle call for orders, paying gasPayment for the settlement message
+func settleCall(orders []*types.ParsedArgs, destinationSettler *felt.Felt, gasPayment *big.Int) (rpc.InvokeFunctionCall, error) {
+	// Calldata: order ID array length, order IDs (u256 low/high), gas amount (u256 low/high)
+	calldata := make([]*felt.Felt, 0, 3+2*len(orders))
+	calldata = append(calldata, utils.Uint64ToFelt(uint64(len(orders))))
+	for _, order := range orders {
+		orderIDLow, orderIDHigh, err := starknetutil.ConvertSolidityOrderIDForStarknet(order.OrderID)
+		if err != nil {
+			return rpc.InvokeFunctionCall{}, fmt.Errorf("failed to convert solidity order ID for starknet: %w", err)
+		}
+		calldata = append(calldata, orderIDLow, orderIDHigh)
 	}
-	logutil.CrossChainOperation("Fill transaction confirmed", originChainID, destChainID, orderID)
+	gasLow, gasHigh := starknetutil.ConvertBigIntToU256Felts(gasPayment)
+	calldata = append(calldata, gasLow, gasHigh)
 
-	return OrderActionSettle, nil
+	return rpc.InvokeFunctionCall{ContractAddress: destinationSettler, FunctionName: "settle", CallData: calldata}, nil
 }
 
 // Settle executes settlement on Starknet
 func (h *HyperlaneStarknet) Settle(ctx context.Context, args *types.ParsedArgs) error {
+	return h.SettleBatch(ctx, []*types.ParsedArgs{args})
+}
+
+// SettleBatch settles several filled orders sharing a destination settler and origin domain in one invoke
+func (h *HyperlaneStarknet) SettleBatch(ctx context.Context, orders []*types.ParsedArgs) error {
 	h.mu.Lock()
 	defer h.mu.Unlock()
 
+	if len(orders) == 0 {
+		return nil
+	}
+	args := orders[0]
 	if len(args.ResolvedOrder.FillInstructions) == 0 {
 		return fmt.Errorf("no fill instructions found")
 	}
 
 	instruction := args.ResolvedOrder.FillInstructions[0]
 
-	// Use the order ID from the event
-	orderID := args.OrderID
-
 	// Convert destination settler string to Starknet address (felt) for contract operations
 	destinationSettler, err := types.ToStarknetAddress(instruction.DestinationSettler)
 	if err != nil {
 		return fmt.Errorf("failed to convert destination settler to felt: %w", err)
 	}
 
-	// Pre-settle check: ensure order is FILLED with retry logic
-	status, err := h.waitForOrderStatus(ctx, args, orderStatusFilled, 5, 2*time.Second)
-	if err != nil {
-		return fmt.Errorf("failed to get order status after retries: %w", err)
-	}
-	if status != orderStatusFilled {
-		return fmt.Errorf("order status must be filled in order to settle, got: %s", status)
+	// Pre-settle check: ensure every order is FILLED, waiting for lagging nodes to see the fill
+	for _, order := range orders {
+		waitCtx, span := tracing.StartOrderSpan(ctx, tracing.SpanWaitStatus, order.OrderID, tracing.KeyStatus.String(orderStatusFilled))
+		status, err := h.waitForOrderStatus(waitCtx, order, orderStatusFilled)
+		tracing.End(span, err)
+		if err != nil {
+			return fmt.Errorf("failed to get status of order %s: %w", order.OrderID, err)
+		}
+		if status != orderStatusFilled {
+			return fmt.Errorf("order %s status must be filled in order to settle, got: %s", order.OrderID, status)
+		}
+		// Settling another solver's fill would pay for their interchain message
+		if err := h.checkFiller(ctx, order); err != nil {
+			return err
+		}
 	}
 
 	// Get gas payment (protocol fee) that must be sent with settlement
@@ -219,50 +316,301 @@ func (h *HyperlaneStarknet) Settle(ctx context.Context, args *types.ParsedArgs)
 	// Get chain IDs for cross-chain logging
 	originChainID := args.ResolvedOrder.OriginChainID.Uint64()
 	destChainID := args.ResolvedOrder.FillInstructions[0].DestinationChainID.Uint64()
-	logutil.CrossChainOperation(fmt.Sprintf("Quoting gas payment for origin domain: %d", originDomain), originChainID, destChainID, args.OrderID)
+	logutil.CrossChain(h.logger, fmt.Sprintf("Quoting gas payment for origin domain: %d", originDomain), originChainID, destChainID, args.OrderID,
+		logutil.Stage("settle"))
 	gasPayment, err := h.quoteGasPayment(ctx, originDomain, destinationSettler)
 	if err != nil {
 		return fmt.Errorf("failed to quote gas payment: %w", err)
 	}
 
-	// Approve ETH for the quoted gas amount
-	if err := h.ensureETHApproval(ctx, gasPayment, destinationSettler); err != nil {
+	// Approve ETH for the quoted gas amount in the same invoke as the settle
+	var calls []rpc.InvokeFunctionCall
+	approveCall, err := h.ethApprovalCall(ctx, gasPayment, destinationSettler)
+	if err != nil {
 		return fmt.Errorf("ETH approval failed for settlement gas: %w", err)
 	}
-	logutil.CrossChainOperation(fmt.Sprintf("ETH approved for settlement gas payment: %s wei", gasPayment.String()), originChainID, destChainID, args.OrderID)
+	if approveCall != nil {
+		calls = append(calls, *approveCall)
+		logutil.CrossChain(h.logger, fmt.Sprintf("Batching ETH approval for settlement gas payment: %s wei", gasPayment.String()),
+			originChainID, destChainID, args.OrderID, logutil.Stage("settle"))
+	}
 
-	// Prepare calldata
-	orderIDLow, orderIDHigh, err := starknetutil.ConvertSolidityOrderIDForStarknet(orderID)
+	// Execute the settle transaction
+	invoke, err := settleCall(orders, destinationSettler, gasPayment)
 	if err != nil {
-		return fmt.Errorf("failed to convert solidity order ID for starknet: %w", err)
+		return err
 	}
-	gasLow, gasHigh := starknetutil.ConvertBigIntToU256Felts(gasPayment)
-	calldata := []*felt.Felt{
-		utils.Uint64ToFelt(1),   // order ID array length
-		orderIDLow, orderIDHigh, // order ID (u256) low and high
-		gasLow, gasHigh, // gas amount (u256) low and high
+	calls = append(calls, invoke)
+	if err := h.preflight(ctx, "settle", calls); err != nil {
+		return err
+	}
+	receipt, err := h.txm.Send(ctx, calls)
+	if err != nil {
+		return fmt.Errorf("starknet settle failed: %w", err)
 	}
+	h.recordGasUsed(ctx, "settle", receipt)
+	accounting.RecordFee(ctx, accounting.KindInterchainFee, "settle", logutil.NetworkNameByChainID(h.chainID),
+		accounting.UnitWei, gasPayment)
+	tracing.Annotate(ctx, tracing.TxHash(receipt.Hash.String()), tracing.Block(uint64(receipt.BlockNumber)))
+	history.RecordTx(ctx, "settle", logutil.NetworkNameByChainID(h.chainID), receipt.Hash.String())
 
-	// Execute the settle transaction
-	invoke := rpc.InvokeFunctionCall{
-		ContractAddress: destinationSettler,
-		FunctionName:    "settle",
-		CallData:        calldata,
+	logutil.CrossChain(h.logger, fmt.Sprintf("Starknet settle transaction for %d order(s) confirmed", len(orders)),
+		originChainID, destChainID, args.OrderID, logutil.Stage("settle"), logutil.TxHash(receipt.Hash.String()), logutil.Block(uint64(receipt.BlockNumber)))
+	return nil
+}
+
+// SimulateFill estimates the approvals and fill Fill would send as one invoke, without sending it
+func (h *HyperlaneStarknet) SimulateFill(ctx context.Context, args *types.ParsedArgs) (*Simulation, error) {
+	if len(args.ResolvedOrder.FillInstructions) == 0 {
+		return nil, fmt.Errorf("no fill instructions found")
+	}
+	destinationSettler, err := types.ToStarknetAddress(args.ResolvedOrder.FillInstructions[0].DestinationSettler)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert destination settler to felt: %w", err)
+	}
+
+	sim := newSimulation("fill", h.chainID, txmanager.UnitFri)
+	status, err := h.GetOrderStatus(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	if status != orderStatusUnknown {
+		sim.Calls = []string{"fill"}
+		sim.revert(fmt.Sprintf("order is already %s on chain", status))
+		return sim, nil
+	}
+
+	calls, err := h.setupApprovals(ctx, args, destinationSettler)
+	if err != nil {
+		return nil, fmt.Errorf("failed to setup approvals: %w", err)
+	}
+	fillerData, err := h.fillers.receiver(args.ResolvedOrder.OriginChainID)
+	if err != nil {
+		return nil, err
+	}
+	invoke, err := fillCall(args, destinationSettler, fillerData)
+	if err != nil {
+		return nil, err
+	}
+	calls = append(calls, invoke)
+
+	result, err := h.txm.Simulate(ctx, calls)
+	if err != nil {
+		return nil, err
+	}
+	sim.addTx(starknetCallNames(calls), result)
+	return sim, nil
+}
+
+// SimulateSettle quotes the interchain gas payment and, once the order is filled, estimates the settle invoke.
+// Before the fill the settle would revert, so only the interchain fee is reported.
+func (h *HyperlaneStarknet) SimulateSettle(ctx context.Context, args *types.ParsedArgs) (*Simulation, error) {
+	if len(args.ResolvedOrder.FillInstructions) == 0 {
+		return nil, fmt.Errorf("no fill instructions found")
+	}
+	destinationSettler, err := types.ToStarknetAddress(args.ResolvedOrder.FillInstructions[0].DestinationSettler)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert destination settler to felt: %w", err)
+	}
+	originDomain, err := h.getOriginDomain(args)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get origin domain: %w", err)
+	}
+
+	sim := newSimulation("settle", h.chainID, txmanager.UnitFri)
+	gasPayment, err := h.quoteGasPayment(ctx, originDomain, destinationSettler)
+	if err != nil {
+		return nil, fmt.Errorf("failed to quote gas payment: %w", err)
+	}
+	sim.InterchainFee = gasPayment
+
+	var calls []rpc.InvokeFunctionCall
+	approveCall, err := h.ethApprovalCall(ctx, gasPayment, destinationSettler)
+	if err != nil {
+		return nil, fmt.Errorf("ETH approval failed for settlement gas: %w", err)
+	}
+	if approveCall != nil {
+		calls = append(calls, *approveCall)
 	}
+	invoke, err := settleCall([]*types.ParsedArgs{args}, destinationSettler, gasPayment)
+	if err != nil {
+		return nil, err
+	}
+	calls = append(calls, invoke)
 
-	// Wait for confirmation
-	tx, err := h.account.BuildAndSendInvokeTxn(ctx, []rpc.InvokeFunctionCall{invoke}, nil)
+	status, err := h.GetOrderStatus(ctx, args)
 	if err != nil {
-		return fmt.Errorf("starknet settle send failed: %w", err)
+		return nil, err
+	}
+	if status != orderStatusFilled {
+		sim.Calls = []string{starknetCallNames(calls)}
+		sim.Outcome = SimulationEstimated
+		sim.Reason = fmt.Sprintf("order is %s on chain, so the settle cannot be estimated before the fill", status)
+		return sim, nil
 	}
 
-	logutil.CrossChainOperation(fmt.Sprintf("Starknet settle tx sent: %s", tx.Hash.String()), originChainID, destChainID, args.OrderID)
-	_, waitErr := h.account.WaitForTransactionReceipt(ctx, tx.Hash, 2*time.Second)
-	if waitErr != nil {
-		return fmt.Errorf("starknet settle wait failed: %w", waitErr)
+	result, err := h.txm.Simulate(ctx, calls)
+	if err != nil {
+		return nil, err
 	}
+	sim.addTx(starknetCallNames(calls), result)
+	return sim, nil
+}
 
-	logutil.CrossChainOperation("Starknet settle transaction confirmed", originChainID, destChainID, args.OrderID)
+// preflight simulates the invoke of operation and returns a *RevertError if it would revert
+func (h *HyperlaneStarknet) preflight(ctx context.Context, operation string, calls []rpc.InvokeFunctionCall) error {
+	ctx, span := tracing.StartSpan(ctx, tracing.SpanPreflight, tracing.KeyOperation.String(operation))
+	result, err := h.txm.Simulate(ctx, calls)
+	if err != nil {
+		err = fmt.Errorf("pre-flight simulation of %s failed: %w", operation, err)
+		tracing.End(span, err)
+		return err
+	}
+	sim := newSimulation(operation, h.chainID, txmanager.UnitFri)
+	sim.addTx(starknetCallNames(calls), result)
+	err = sim.Err()
+	tracing.End(span, err)
+	if err != nil {
+		h.chainLogger().Warn(fmt.Sprintf("   🛑 Pre-flight: %s would revert, not sending", operation), logutil.Err(err))
+	}
+	return err
+}
+
+// starknetCallNames describes a multicall invoke, naming the token of each approve
+func starknetCallNames(calls []rpc.InvokeFunctionCall) string {
+	names := make([]string, len(calls))
+	for i, call := range calls {
+		names[i] = call.FunctionName
+		if call.FunctionName == "approve" {
+			names[i] = fmt.Sprintf("approve(%s)", call.ContractAddress)
+		}
+	}
+	return "invoke[" + strings.Join(names, ", ") + "]"
+}
+
+// TokenDecimals reads the decimals of token; those of the native token are the fee token's
+func (h *HyperlaneStarknet) TokenDecimals(_ context.Context, token string) (uint8, error) {
+	decimals, err := starknetutil.ERC20Decimals(h.provider, starknetutil.TokenContract(token))
+	if err != nil {
+		return 0, failure.Mark(fmt.Errorf("failed to read decimals of %s: %w", token, err), failure.ErrNetwork)
+	}
+	return decimals, nil
+}
+
+// EstimateFillCost prices a typical fill plus settle, in FRI, at the latest block's gas prices and checks the solver's
+// fee token balance can pay for it
+func (h *HyperlaneStarknet) EstimateFillCost(ctx context.Context) (*big.Int, error) {
+	block, err := h.provider.BlockWithTxHashes(ctx, rpc.WithBlockTag("latest"))
+	if err != nil {
+		return nil, failure.Mark(fmt.Errorf("failed to get latest block: %w", err), failure.ErrNetwork)
+	}
+	var l2GasPrice, l1DataGasPrice rpc.ResourcePrice
+	switch block := block.(type) {
+	case *rpc.BlockTxHashes:
+		l2GasPrice, l1DataGasPrice = block.L2GasPrice, block.L1DataGasPrice
+	case *rpc.PreConfirmedBlockTxHashes:
+		l2GasPrice, l1DataGasPrice = block.L2GasPrice, block.L1DataGasPrice
+	default:
+		return nil, fmt.Errorf("unexpected block type %T", block)
+	}
+	if l2GasPrice.PriceInFRI == nil || l1DataGasPrice.PriceInFRI == nil {
+		return nil, fmt.Errorf("latest block has no gas prices in FRI")
+	}
+	cost := new(big.Int).Mul(utils.FeltToBigInt(l2GasPrice.PriceInFRI),
+		big.NewInt(starknetFillL2GasEstimate+starknetSettleL2GasEstimate))
+	cost.Add(cost, new(big.Int).Mul(utils.FeltToBigInt(l1DataGasPrice.PriceInFRI), big.NewInt(starknetL1DataGasEstimate)))
+
+	balance, err := starknetutil.ERC20Balance(h.provider, starknetutil.FeeTokenAddress(), h.solverAddr.String())
+	if err != nil {
+		return nil, failure.Mark(fmt.Errorf("failed to get fee token balance: %w", err), failure.ErrNetwork)
+	}
+	if balance.Cmp(cost) < 0 {
+		return cost, failure.Mark(fmt.Errorf("%w: insufficient fee token balance for gas: have %s, need %s", ErrOrderRejected, balance, cost),
+			failure.ErrInsufficientFunds)
+	}
+	return cost, nil
+}
+
+// Refund refunds expired, unfilled orders sharing a destination settler and origin domain in one invoke.
+// The destination dispatches a refund message; the origin then returns each order's input to its sender.
+func (h *HyperlaneStarknet) Refund(ctx context.Context, orders []*types.ParsedArgs) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if len(orders) == 0 {
+		return nil
+	}
+	args := orders[0]
+	if len(args.ResolvedOrder.FillInstructions) == 0 {
+		return fmt.Errorf("no fill instructions found")
+	}
+
+	destinationSettler, err := types.ToStarknetAddress(args.ResolvedOrder.FillInstructions[0].DestinationSettler)
+	if err != nil {
+		return fmt.Errorf("failed to convert destination settler to felt: %w", err)
+	}
+
+	// Calldata: orders array length, then per order fill_deadline, order_data_type (u256) and order_data (Bytes)
+	calldata := []*felt.Felt{utils.Uint64ToFelt(uint64(len(orders)))}
+	for _, order := range orders {
+		onchainOrder, err := refundableOrder(order)
+		if err != nil {
+			return err
+		}
+		status, err := h.GetOrderStatus(ctx, order)
+		if err != nil {
+			return fmt.Errorf("failed to get status of order %s: %w", order.OrderID, err)
+		}
+		if status != orderStatusUnknown {
+			return fmt.Errorf("order %s cannot be refunded with destination status %s", order.OrderID, status)
+		}
+
+		typeLow, typeHigh := orderutil.SplitU256(onchainOrder.OrderDataType)
+		calldata = append(calldata, utils.Uint64ToFelt(uint64(onchainOrder.FillDeadline)), typeLow, typeHigh)
+		calldata = append(calldata, orderutil.CairoBytes(onchainOrder.OrderData)...)
+	}
+
+	originDomain, err := h.getOriginDomain(args)
+	if err != nil {
+		return fmt.Errorf("failed to get origin domain: %w", err)
+	}
+
+	originChainID := args.ResolvedOrder.OriginChainID.Uint64()
+	logutil.CrossChain(h.logger, fmt.Sprintf("Quoting refund gas payment for origin domain: %d", originDomain),
+		originChainID, h.chainID, args.OrderID, logutil.Stage("refund"))
+	gasPayment, err := h.quoteGasPayment(ctx, originDomain, destinationSettler)
+	if err != nil {
+		return fmt.Errorf("failed to quote gas payment: %w", err)
+	}
+
+	// Approve ETH for the quoted gas amount in the same invoke as the refund
+	var calls []rpc.InvokeFunctionCall
+	approveCall, err := h.ethApprovalCall(ctx, gasPayment, destinationSettler)
+	if err != nil {
+		return fmt.Errorf("ETH approval failed for refund gas: %w", err)
+	}
+	if approveCall != nil {
+		calls = append(calls, *approveCall)
+	}
+
+	gasLow, gasHigh := starknetutil.ConvertBigIntToU256Felts(gasPayment)
+	calldata = append(calldata, gasLow, gasHigh)
+	calls = append(calls, rpc.InvokeFunctionCall{
+		ContractAddress: destinationSettler,
+		FunctionName:    "refund_onchain_cross_chain_order",
+		CallData:        calldata,
+	})
+
+	receipt, err := h.txm.Send(ctx, calls)
+	if err != nil {
+		return fmt.Errorf("starknet refund failed: %w", err)
+	}
+	h.recordGasUsed(ctx, "refund", receipt)
+	accounting.RecordFee(ctx, accounting.KindInterchainFee, "refund", logutil.NetworkNameByChainID(h.chainID),
+		accounting.UnitWei, gasPayment)
+
+	logutil.CrossChain(h.logger, fmt.Sprintf("Starknet refund transaction for %d order(s) confirmed", len(orders)),
+		originChainID, h.chainID, args.OrderID, logutil.Stage("refund"), logutil.TxHash(receipt.Hash.String()), logutil.Block(uint64(receipt.BlockNumber)))
 	return nil
 }
 
@@ -287,19 +635,63 @@ func (h *HyperlaneStarknet) GetOrderStatus(ctx context.Context, args *types.Pars
 	}
 
 	call := rpc.FunctionCall{
-		ContractAddress:      destinationSettlerAddr,
-		EntryPointSelector:   utils.GetSelectorFromNameFelt("order_status"),
-		Calldata:             []*felt.Felt{orderIDLow, orderIDHigh},
+		ContractAddress:    destinationSettlerAddr,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("order_status"),
+		Calldata:           []*felt.Felt{orderIDLow, orderIDHigh},
 	}
 	resp, err := h.provider.Call(ctx, call, rpc.WithBlockTag("latest"))
-	if err != nil || len(resp) == 0 {
-		return orderStatusUnknown, err
+	if err != nil {
+		return orderStatusUnknown, failure.Mark(fmt.Errorf("order_status call failed: %w", err), failure.ErrNetwork)
+	}
+	if len(resp) == 0 {
+		return orderStatusUnknown, nil
 	}
 	status := resp[0].String()
 
 	return h.interpretStarknetStatus(status), nil
 }
 
+// CheckFiller reads who filled the order from the destination settler's filled_orders and fails with
+// failure.ErrCompetitorFilled when it was another solver
+func (h *HyperlaneStarknet) CheckFiller(ctx context.Context, args *types.ParsedArgs) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.checkFiller(ctx, args)
+}
+
+// checkFiller is CheckFiller for callers already holding h.mu
+func (h *HyperlaneStarknet) checkFiller(ctx context.Context, args *types.ParsedArgs) error {
+	if len(args.ResolvedOrder.FillInstructions) == 0 {
+		return fmt.Errorf("no fill instructions found")
+	}
+	destinationSettler, err := types.ToStarknetAddress(args.ResolvedOrder.FillInstructions[0].DestinationSettler)
+	if err != nil {
+		return fmt.Errorf("failed to convert destination settler to felt: %w", err)
+	}
+	orderIDLow, orderIDHigh, err := starknetutil.ConvertSolidityOrderIDForStarknet(args.OrderID)
+	if err != nil {
+		return fmt.Errorf("failed to convert solidity order id for cairo: %w", err)
+	}
+
+	call := rpc.FunctionCall{
+		ContractAddress:    destinationSettler,
+		EntryPointSelector: utils.GetSelectorFromNameFelt("filled_orders"),
+		Calldata:           []*felt.Felt{orderIDLow, orderIDHigh},
+	}
+	resp, err := h.provider.Call(ctx, call, rpc.WithBlockTag("latest"))
+	if err != nil {
+		return failure.Mark(fmt.Errorf("filled_orders call failed: %w", err), failure.ErrNetwork)
+	}
+	fillerData, err := decodeFilledOrder(resp)
+	if err != nil {
+		return fmt.Errorf("failed to decode filled_orders result: %w", err)
+	}
+	if h.fillers.owns(args.ResolvedOrder.OriginChainID, fillerData) {
+		return nil
+	}
+	return competitorFilled(args.OrderID, fillerData)
+}
+
 // getOriginDomain returns the hyperlane domain of the order's origin chain
 func (h *HyperlaneStarknet) getOriginDomain(args *types.ParsedArgs) (uint32, error) {
 	if args.ResolvedOrder.OriginChainID == nil {
@@ -318,46 +710,62 @@ func (h *HyperlaneStarknet) getOriginDomain(args *types.ParsedArgs) (uint32, err
 	return 0, fmt.Errorf("no domain found for chain ID %d in config (check your .env file)", chainID)
 }
 
-// setupApprovals ensures each MaxSpent token allowances are set
-func (h *HyperlaneStarknet) setupApprovals(ctx context.Context, args *types.ParsedArgs, destinationSettler *felt.Felt) error {
+// setupApprovals returns the approve calls needed for each MaxSpent token allowance
+func (h *HyperlaneStarknet) setupApprovals(ctx context.Context, args *types.ParsedArgs, destinationSettler *felt.Felt) (
+	[]rpc.InvokeFunctionCall, error) {
 	if len(args.ResolvedOrder.MaxSpent) == 0 {
-		return nil
+		return nil, nil
 	}
 
 	// Get destination chain ID from fill instruction
 	if len(args.ResolvedOrder.FillInstructions) == 0 {
-		return fmt.Errorf("no fill instructions found")
+		return nil, fmt.Errorf("no fill instructions found")
 	}
 	destinationChainID := args.ResolvedOrder.FillInstructions[0].DestinationChainID.Uint64()
 
 	// Get origin chain ID for cross-chain logging
 	originChainID := args.ResolvedOrder.OriginChainID.Uint64()
 
+	// Outputs in the same token are summed so a single approve covers all of them
+	var tokens []string
+	totals := make(map[string]*big.Int)
 	for _, maxSpent := range args.ResolvedOrder.MaxSpent {
-		// Skip native ETH (empty string)
-		if maxSpent.Token == "" {
-			continue
-		}
-
 		// Only approve tokens that belong to this chain (destination chain)
 		if maxSpent.ChainID.Uint64() != destinationChainID {
-			fmt.Printf("   ⚠️  Skipping approval for token %s on chain %d (this handler is for chain %d)\n",
-				maxSpent.Token, maxSpent.ChainID.Uint64(), destinationChainID)
+			h.chainLogger().Warn(fmt.Sprintf("   ⚠️  Skipping approval for token %s on chain %d (this handler is for chain %d)",
+				maxSpent.Token, maxSpent.ChainID.Uint64(), destinationChainID), logutil.OrderID(args.OrderID))
 			continue
 		}
 
-		// Convert token address to Starknet format
-		if err := h.ensureTokenApproval(ctx, maxSpent.Token, maxSpent.Amount, destinationSettler); err != nil {
-			return fmt.Errorf("starknet approval failed for token %s: %w", maxSpent.Token, err)
+		// The settler pays outputs with ERC20 transfer_from, so native outputs are approved in the fee token
+		tokenFelt, err := utils.HexToFelt(starknetutil.TokenContract(maxSpent.Token))
+		if err != nil {
+			return nil, fmt.Errorf("starknet approval failed for token %s: invalid Starknet token address: %w", maxSpent.Token, err)
+		}
+		token := tokenFelt.String()
+		if totals[token] == nil {
+			tokens = append(tokens, token)
+			totals[token] = new(big.Int)
 		}
+		totals[token].Add(totals[token], maxSpent.Amount)
 	}
 
-	logutil.CrossChainOperation("Set token approvals", originChainID, destinationChainID, args.OrderID)
-
-	// Add a small delay to ensure blockchain state is updated after approvals
-	time.Sleep(1 * time.Second)
+	var calls []rpc.InvokeFunctionCall
+	for _, token := range tokens {
+		call, err := h.tokenApprovalCall(ctx, token, totals[token], destinationSettler)
+		if err != nil {
+			return nil, fmt.Errorf("starknet approval failed for token %s: %w", token, err)
+		}
+		if call != nil {
+			calls = append(calls, *call)
+		}
+	}
 
-	return nil
+	if len(calls) > 0 {
+		logutil.CrossChain(h.logger, fmt.Sprintf("Batching %d token approval(s) with fill", len(calls)),
+			originChainID, destinationChainID, args.OrderID, logutil.Stage("approve"))
+	}
+	return calls, nil
 }
 
 // interpretStarknetStatus returns the string representation of the order status
@@ -369,6 +777,10 @@ func (h *HyperlaneStarknet) interpretStarknetStatus(status string) string {
 		return orderStatusFilled
 	case "0x534554544c4544":
 		return orderStatusSettled
+	case "0x4f50454e4544":
+		return orderStatusOpened
+	case "0x524546554e444544":
+		return orderStatusRefunded
 	default:
 		return status
 	}
@@ -388,7 +800,7 @@ func (h *HyperlaneStarknet) quoteGasPayment(ctx context.Context, originDomain ui
 
 	resp, err := h.provider.Call(ctx, call, rpc.WithBlockTag("latest"))
 	if err != nil {
-		return nil, fmt.Errorf("starknet quote_gas_payment call failed: %w", err)
+		return nil, failure.Mark(fmt.Errorf("starknet quote_gas_payment call failed: %w", err), failure.ErrNetwork)
 	}
 
 	if len(resp) < 2 {
@@ -406,13 +818,12 @@ func (h *HyperlaneStarknet) quoteGasPayment(ctx context.Context, originDomain ui
 	return result, nil
 }
 
-// EnsureETHApproval ensures the solver has approved the ETH address for settlement
-func (h *HyperlaneStarknet) ensureETHApproval(ctx context.Context, amount *big.Int, hyperlaneAddress *felt.Felt) error {
-	// Hard-coded ETH address on Starknet
-	ethAddress := "0x49d36570d4e46f48e99674bd3fcc84644ddd6b96f7c741b1562b82f9e004dc7"
-	ethFelt, err := utils.HexToFelt(ethAddress)
+// ethApprovalCall returns the ETH approve call for the settlement gas payment, or nil when the allowance already covers amount
+func (h *HyperlaneStarknet) ethApprovalCall(ctx context.Context, amount *big.Int, hyperlaneAddress *felt.Felt) (
+	*rpc.InvokeFunctionCall, error) {
+	ethFelt, err := utils.HexToFelt(starknetutil.ETHTokenAddress)
 	if err != nil {
-		return fmt.Errorf("failed to convert ETH address to felt: %w", err)
+		return nil, fmt.Errorf("failed to convert ETH address to felt: %w", err)
 	}
 
 	// Check current allowance
@@ -424,11 +835,11 @@ func (h *HyperlaneStarknet) ensureETHApproval(ctx context.Context, amount *big.I
 
 	resp, err := h.provider.Call(ctx, call, rpc.WithBlockTag("latest"))
 	if err != nil {
-		return fmt.Errorf("starknet ETH allowance call failed: %w", err)
+		return nil, failure.Mark(fmt.Errorf("starknet ETH allowance call failed: %w", err), failure.ErrNetwork)
 	}
 
 	if len(resp) < 2 {
-		return fmt.Errorf("starknet ETH allowance returned insufficient data: expected 2 felts, got %d", len(resp))
+		return nil, fmt.Errorf("starknet ETH allowance returned insufficient data: expected 2 felts, got %d", len(resp))
 	}
 
 	// Convert two felts (low, high) back to u256
@@ -439,7 +850,7 @@ func (h *HyperlaneStarknet) ensureETHApproval(ctx context.Context, amount *big.I
 
 	// If allowance is sufficient, no need to approve
 	if currentAllowance.Cmp(amount) >= 0 {
-		return nil
+		return nil, nil
 	}
 
 	// Need to approve - convert amount to two felts (low, high)
@@ -458,26 +869,15 @@ func (h *HyperlaneStarknet) ensureETHApproval(ctx context.Context, amount *big.I
 		CallData:        approveCalldata,
 	}
 
-	tx, err := h.account.BuildAndSendInvokeTxn(ctx, []rpc.InvokeFunctionCall{invoke}, nil)
-	if err != nil {
-		return fmt.Errorf("starknet ETH approve send failed: %w", err)
-	}
-
-	fmt.Printf("   🔄 Starknet ETH approve tx sent: %s\n", tx.Hash.String())
-	_, waitErr := h.account.WaitForTransactionReceipt(ctx, tx.Hash, 2*time.Second)
-	if waitErr != nil {
-		return fmt.Errorf("starknet ETH approve wait failed: %w", waitErr)
-	}
-
-	fmt.Printf("   ✅ Starknet ETH approval confirmed\n")
-	return nil
+	return &invoke, nil
 }
 
-// ensureTokenApproval ensures the solver has approved an arbitrary ERC20 token for the Hyperlane contract
-func (h *HyperlaneStarknet) ensureTokenApproval(ctx context.Context, tokenHex string, amount *big.Int, hyperlaneAddress *felt.Felt) error {
+// tokenApprovalCall returns the approve call for an arbitrary ERC20 token, or nil when the allowance already covers amount
+func (h *HyperlaneStarknet) tokenApprovalCall(ctx context.Context, tokenHex string, amount *big.Int, hyperlaneAddress *felt.Felt) (
+	*rpc.InvokeFunctionCall, error) {
 	tokenFelt, err := utils.HexToFelt(tokenHex)
 	if err != nil {
-		return fmt.Errorf("invalid Starknet token address: %w", err)
+		return nil, fmt.Errorf("invalid Starknet token address: %w", err)
 	}
 
 	// allowance(owner=solverAddr, spender=hyperlaneAddr) -> (low, high)
@@ -489,17 +889,17 @@ func (h *HyperlaneStarknet) ensureTokenApproval(ctx context.Context, tokenHex st
 
 	resp, err := h.provider.Call(ctx, call, rpc.WithBlockTag("latest"))
 	if err != nil {
-		return fmt.Errorf("starknet allowance call failed: %w", err)
+		return nil, failure.Mark(fmt.Errorf("starknet allowance call failed: %w", err), failure.ErrNetwork)
 	}
 	if len(resp) < 2 {
-		return fmt.Errorf("starknet allowance response too short: %d", len(resp))
+		return nil, fmt.Errorf("starknet allowance response too short: %d", len(resp))
 	}
 
 	low := utils.FeltToBigInt(resp[0])
 	high := utils.FeltToBigInt(resp[1])
 	current := new(big.Int).Add(low, new(big.Int).Lsh(high, 128))
 	if current.Cmp(amount) >= 0 {
-		return nil
+		return nil, nil
 	}
 
 	// Approve exact amount: approve(spender: felt, amount: u256)
@@ -514,57 +914,30 @@ func (h *HyperlaneStarknet) ensureTokenApproval(ctx context.Context, tokenHex st
 		CallData:        []*felt.Felt{hyperlaneAddress, lowF, highF},
 	}
 
-	tx, err := h.account.BuildAndSendInvokeTxn(ctx, []rpc.InvokeFunctionCall{invoke}, nil)
-	if err != nil {
-		return fmt.Errorf("starknet token approve send failed: %w", err)
-	}
-
-	_, waitErr := h.account.WaitForTransactionReceipt(ctx, tx.Hash, 2*time.Second)
-	if waitErr != nil {
-		return fmt.Errorf("starknet token approve wait failed: %w", waitErr)
-	}
-	return nil
+	return &invoke, nil
 }
 
-// waitForOrderStatus waits for the order status to become the expected value with retry logic
-func (h *HyperlaneStarknet) waitForOrderStatus(
-	ctx context.Context,
-	args *types.ParsedArgs,
-	expectedStatus string,
-	maxRetries int,
-	initialDelay time.Duration,
-) (string, error) {
-	delay := initialDelay
-
-	for attempt := 1; attempt <= maxRetries; attempt++ {
-		status, err := h.GetOrderStatus(ctx, args)
-		if err != nil {
-			fmt.Printf("   ⚠️  Status check attempt %d failed: %v\n", attempt, err)
-		} else {
-			fmt.Printf("   📊 Status check attempt %d: %s (expected: %s)\n", attempt, status, expectedStatus)
-			if status == expectedStatus {
-				return status, nil
-			}
-		}
+// waitForOrderStatus re-reads the order status on every new block until it becomes the expected value
+func (h *HyperlaneStarknet) waitForOrderStatus(ctx context.Context, args *types.ParsedArgs, expectedStatus string) (string, error) {
+	wait := newStatusWait(h.provider, h.chainID, h.chainLogger().With(logutil.OrderID(args.OrderID)))
+	return wait.until(ctx, expectedStatus, func(ctx context.Context) (string, error) {
+		return h.GetOrderStatus(ctx, args)
+	})
+}
 
-		// Don't wait after the last attempt
-		if attempt < maxRetries {
-			fmt.Printf("   ⏳ Waiting %v before retry %d/%d...\n", delay, attempt+1, maxRetries)
-			select {
-			case <-ctx.Done():
-				return "", ctx.Err()
-			case <-time.After(delay):
-				// Exponential backoff: double the delay for next attempt
-				delay *= 2
-			}
-		}
-	}
+// chainLogger returns the handler's logger tagged with its chain, for lines that are not about an order's route
+func (h *HyperlaneStarknet) chainLogger() logutil.Logger {
+	return h.logger.With(logutil.Chain(logutil.NetworkNameByChainID(h.chainID)))
+}
 
-	// Final attempt to get the current status
-	finalStatus, err := h.GetOrderStatus(ctx, args)
-	if err != nil {
-		return orderStatusUnknown, fmt.Errorf("final status check failed after %d attempts: %w", maxRetries, err)
+// recordGasUsed records the gas a mined transaction of this handler used, summed over L1, L1 data and L2 gas,
+// and charges the fee it actually paid to the orders in ctx
+func (h *HyperlaneStarknet) recordGasUsed(ctx context.Context, operation string, receipt *rpc.TransactionReceiptWithBlockInfo) {
+	chain := logutil.NetworkNameByChainID(h.chainID)
+	resources := receipt.ExecutionResources
+	metrics.TxGasUsed(chain, protocolLabel, operation, uint64(resources.L1Gas+resources.L1DataGas+resources.L2Gas))
+	if receipt.ActualFee.Amount != nil {
+		accounting.RecordFee(ctx, accounting.KindGas, operation, chain, string(receipt.ActualFee.Unit),
+			receipt.ActualFee.Amount.BigInt(new(big.Int)))
 	}
-
-	return finalStatus, nil
 }
@@ -0,0 +1,123 @@
+package hyperlane7683
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// starknetNode serves the Starknet JSON-RPC calls a handler makes from canned results and records the contracts called
+type starknetNode struct {
+	mu sync.Mutex
+	// results answers starknet_call by entry point name, and every other method by method name
+	results map[string]any
+	called  []string
+}
+
+func newStarknetProvider(t *testing.T, node *starknetNode) *rpc.Provider {
+	t.Helper()
+	selectors := make(map[string]string)
+	for name := range node.results {
+		selectors[utils.GetSelectorFromNameFelt(name).String()] = name
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		name := req.Method
+		if req.Method == "starknet_call" {
+			var call struct {
+				ContractAddress    string `json:"contract_address"`
+				EntryPointSelector string `json:"entry_point_selector"`
+			}
+			require.NoError(t, json.Unmarshal(req.Params[0], &call))
+			name = selectors[call.EntryPointSelector]
+			node.mu.Lock()
+			node.called = append(node.called, call.ContractAddress)
+			node.mu.Unlock()
+		}
+		result, ok := node.results[name]
+		if req.Method == "starknet_specVersion" {
+			result, ok = "0.9.0", true
+		}
+		response := map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": result}
+		if !ok {
+			response = map[string]any{"jsonrpc": "2.0", "id": req.ID, "error": map[string]any{"code": -32601, "message": "no result for " + name}}
+		}
+		assert.NoError(t, json.NewEncoder(w).Encode(response))
+	}))
+	t.Cleanup(server.Close)
+
+	provider, err := rpc.NewProvider(server.URL)
+	require.NoError(t, err)
+	return provider
+}
+
+func TestStarknetFillsNativeOutputsInTheFeeToken(t *testing.T) {
+	const chainID = uint64(23448591)
+	args := &types.ParsedArgs{
+		OrderID: "0x0123456789abcdef",
+		ResolvedOrder: types.ResolvedCrossChainOrder{
+			OriginChainID: big.NewInt(84532),
+			MaxSpent: []types.Output{
+				{Token: types.NativeToken, Amount: big.NewInt(5), ChainID: new(big.Int).SetUint64(chainID)},
+			},
+			FillInstructions: []types.FillInstruction{{DestinationChainID: new(big.Int).SetUint64(chainID)}},
+		},
+	}
+	strk, err := utils.HexToFelt(starknetutil.STRKTokenAddress)
+	require.NoError(t, err)
+
+	node := &starknetNode{results: map[string]any{"allowance": []string{"0x0", "0x0"}, "decimals": []string{"0x12"}}}
+	h := &HyperlaneStarknet{provider: newStarknetProvider(t, node), chainID: chainID, solverAddr: new(felt.Felt).SetUint64(2),
+		logger: logutil.Default()}
+
+	calls, err := h.setupApprovals(context.Background(), args, new(felt.Felt).SetUint64(1))
+	require.NoError(t, err)
+	require.Len(t, calls, 1)
+	assert.Equal(t, strk, calls[0].ContractAddress, "the native output is approved in STRK")
+	assert.Equal(t, "approve", calls[0].FunctionName)
+	assert.Equal(t, []*felt.Felt{new(felt.Felt).SetUint64(1), new(felt.Felt).SetUint64(5), new(felt.Felt)}, calls[0].CallData)
+
+	decimals, err := h.TokenDecimals(context.Background(), types.NativeToken)
+	require.NoError(t, err)
+	assert.Equal(t, uint8(18), decimals)
+	assert.Equal(t, []string{strk.String(), strk.String()}, node.called)
+
+	t.Setenv("STARKNET_FEE_TOKEN_ADDRESS", "0x1234")
+	calls, err = h.setupApprovals(context.Background(), args, new(felt.Felt).SetUint64(1))
+	require.NoError(t, err)
+	require.Len(t, calls, 1)
+	assert.Equal(t, new(felt.Felt).SetUint64(0x1234), calls[0].ContractAddress, "a configured fee token replaces STRK")
+}
+
+func TestStarknetDecoderCanonicalisesNativeTokens(t *testing.T) {
+	output := newFeltDecoder([]*felt.Felt{
+		new(felt.Felt),              // token: zero address
+		new(felt.Felt).SetUint64(5), // amount low
+		new(felt.Felt),              // amount high
+		new(felt.Felt).SetUint64(7), // recipient
+		new(felt.Felt),              // chain domain
+	}).readOutput()
+
+	assert.Equal(t, types.NativeToken, output.Token)
+	assert.True(t, output.IsNative())
+	assert.Equal(t, int64(5), output.Amount.Int64())
+}
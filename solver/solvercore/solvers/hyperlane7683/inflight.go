@@ -0,0 +1,127 @@
+package hyperlane7683
+
+// Module: In-flight orders of the Hyperlane7683 solver
+// - Records every order between the start and the end of ProcessIntent
+// - Lets operators see what the solver is working on, with per-leg progress
+// - Aborts the processing of an order, e.g. once another solver filled it
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// InFlightOrder is an order the solver is currently processing
+type InFlightOrder struct {
+	OrderID       string      `json:"orderId"`
+	OriginChainID uint64      `json:"originChainId"`
+	Sender        string      `json:"sender"`
+	StartedAt     time.Time   `json:"startedAt"`
+	Legs          []LegStatus `json:"legs"`
+}
+
+// inFlightTracker holds the orders being processed, by order ID; the zero value is ready to use
+type inFlightTracker struct {
+	mu      sync.Mutex
+	orders  map[string]InFlightOrder
+	cancels map[string]context.CancelCauseFunc
+}
+
+// begin records that args is being processed; cancel, when not nil, aborts the processing
+func (t *inFlightTracker) begin(args *types.ParsedArgs, cancel context.CancelCauseFunc) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.orders == nil {
+		t.orders = make(map[string]InFlightOrder)
+	}
+	if t.cancels == nil {
+		t.cancels = make(map[string]context.CancelCauseFunc)
+	}
+	if cancel != nil {
+		t.cancels[args.OrderID] = cancel
+	}
+	order := InFlightOrder{
+		OrderID:       args.OrderID,
+		OriginChainID: 0,
+		Sender:        args.SenderAddress,
+		StartedAt:     time.Now(),
+		Legs:          nil,
+	}
+	if args.ResolvedOrder.OriginChainID != nil {
+		order.OriginChainID = args.ResolvedOrder.OriginChainID.Uint64()
+	}
+	t.orders[args.OrderID] = order
+}
+
+// end records that processing of the order finished, successfully or not
+func (t *inFlightTracker) end(orderID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.orders, orderID)
+	delete(t.cancels, orderID)
+}
+
+// get returns the order if it is being processed
+func (t *inFlightTracker) get(orderID string) (InFlightOrder, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	order, ok := t.orders[orderID]
+	return order, ok
+}
+
+// abort cancels the processing of the order with cause and reports whether it was being processed
+func (t *inFlightTracker) abort(orderID string, cause error) bool {
+	t.mu.Lock()
+	cancel, ok := t.cancels[orderID]
+	t.mu.Unlock()
+	if ok {
+		cancel(cause)
+	}
+	return ok
+}
+
+// count returns how many orders are being processed
+func (t *inFlightTracker) count() int {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return len(t.orders)
+}
+
+// list returns the orders being processed, oldest first
+func (t *inFlightTracker) list() []InFlightOrder {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	orders := make([]InFlightOrder, 0, len(t.orders))
+	for _, order := range t.orders {
+		orders = append(orders, order)
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].StartedAt.Before(orders[j].StartedAt)
+	})
+	return orders
+}
+
+// InFlightOrders returns the orders the solver is processing, oldest first, with the progress of their legs
+func (f *Hyperlane7683Solver) InFlightOrders() []InFlightOrder {
+	orders := f.inFlight.list()
+	for i := range orders {
+		if progress, ok := f.legProgress.get(orders[i].OrderID); ok {
+			orders[i].Legs = progress.Legs
+		}
+	}
+	return orders
+}
+
+// Backlog returns how many orders are still being worked on: those being processed plus those queued for settlement
+func (f *Hyperlane7683Solver) Backlog() int {
+	backlog := f.inFlight.count()
+	if f.settlementBatcher != nil {
+		backlog += f.settlementBatcher.Pending()
+	}
+	return backlog
+}
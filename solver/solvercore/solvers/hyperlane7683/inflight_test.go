@@ -0,0 +1,31 @@
+package hyperlane7683
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInFlightOrders(t *testing.T) {
+	solver := newMultiLegSolver(&legHandler{}, &legHandler{})
+	order := multiLegOrder()
+	assert.Empty(t, solver.InFlightOrders())
+
+	solver.inFlight.begin(order, nil)
+	assert.Equal(t, 1, solver.Backlog())
+	_, _ = solver.Fill(context.Background(), order)
+
+	orders := solver.InFlightOrders()
+	require.Len(t, orders, 1)
+	assert.Equal(t, order.OrderID, orders[0].OrderID)
+	assert.Equal(t, order.ResolvedOrder.OriginChainID.Uint64(), orders[0].OriginChainID)
+	require.Len(t, orders[0].Legs, 2)
+	assert.True(t, orders[0].Legs[0].Filled)
+	assert.False(t, orders[0].Legs[0].Settled)
+
+	solver.inFlight.end(order.OrderID)
+	assert.Empty(t, solver.InFlightOrders())
+	assert.Zero(t, solver.Backlog())
+}
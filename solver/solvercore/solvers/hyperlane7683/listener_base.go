@@ -3,10 +3,16 @@ package hyperlane7683
 import (
 	"context"
 	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 )
 
 // BlockNumberProvider defines the interface for getting the current block number
@@ -15,24 +21,101 @@ type BlockNumberProvider interface {
 	BlockNumber(ctx context.Context) (uint64, error)
 }
 
+// pausedCheckInterval is how often a paused backfill checks whether it was resumed
+const pausedCheckInterval = time.Second
+
+const (
+	// intentMaxAttempts bounds how often an order whose processing failed with a network error is handled
+	intentMaxAttempts = 3
+	// intentRetryBackoff is the wait before the first retry; it doubles for every further one
+	intentRetryBackoff = 2 * time.Second
+)
+
 // BaseListener provides common functionality for both EVM and Starknet listeners
 type BaseListener struct {
-	config             base.ListenerConfig
+	config base.ListenerConfig
+	// mu guards lastProcessedBlock and rescanned; the backfill holds it for each block range it processes,
+	// so a re-scan requested meanwhile takes effect between ranges instead of being overwritten
+	mu                 sync.Mutex
 	lastProcessedBlock uint64
+	rescanned          bool
 	blockProvider      BlockNumberProvider
 	networkType        string // "EVM" or "Starknet" for logging
+	paused             atomic.Bool
+	lastPolled         atomic.Int64 // unix nanoseconds of the last finished poll, 0 before the first
+	logger             logutil.Logger
+	// Told about Filled events when set
+	fillObserver   FillObserver
+	fillObserverMu sync.RWMutex
+	// Wait before the first retry of an order that failed with a network error
+	retryBackoff time.Duration
+	// Retries running in the background
+	retries sync.WaitGroup
+	// Blocks processed before a restart, from the resolved start block to the block the listener resumed from
+	startBlock   uint64
+	resumedBlock uint64
 }
 
-// NewBaseListener creates a new base listener with common functionality
-func NewBaseListener(config base.ListenerConfig, blockProvider BlockNumberProvider, networkType string) *BaseListener {
+// NewBaseListener creates a new base listener with common functionality; logger gets the chain field added
+func NewBaseListener(config base.ListenerConfig, blockProvider BlockNumberProvider, networkType string, logger logutil.Logger) *BaseListener {
 	return &BaseListener{
 		config:             config,
+		mu:                 sync.Mutex{},
 		lastProcessedBlock: 0,
+		rescanned:          false,
 		blockProvider:      blockProvider,
 		networkType:        networkType,
+		paused:             atomic.Bool{},
+		lastPolled:         atomic.Int64{},
+		logger:             logger.With(logutil.Chain(config.ChainName)),
+		fillObserver:       nil,
+		fillObserverMu:     sync.RWMutex{},
+		retryBackoff:       intentRetryBackoff,
+		retries:            sync.WaitGroup{},
+		startBlock:         0,
+		resumedBlock:       0,
 	}
 }
 
+// handleIntent hands an order to handler and returns its result. An order that failed with a network error or
+// timeout is handed to handler again in the background, up to intentMaxAttempts attempts in all with exponential
+// backoff, so waiting for a retry never holds up the chain's poll loop.
+func (bl *BaseListener) handleIntent(ctx context.Context, handler base.EventHandler, args types.ParsedArgs, block uint64) (bool, error) {
+	settled, err := handler(ctx, args, bl.config.ChainName, block)
+	if failure.Transient(err) && intentMaxAttempts > 1 {
+		bl.retries.Add(1)
+		go func() {
+			defer bl.retries.Done()
+			bl.retryIntent(ctx, handler, args, block, err)
+		}()
+	}
+	return settled, err
+}
+
+// retryIntent hands an order that failed with err to handler again while it keeps failing with transient errors
+func (bl *BaseListener) retryIntent(ctx context.Context, handler base.EventHandler, args types.ParsedArgs, block uint64, err error) {
+	delay := bl.retryBackoff
+	for attempt := 2; attempt <= intentMaxAttempts; attempt++ {
+		bl.logger.Warn(fmt.Sprintf("🔁 Order failed with a network error, retrying in %s (%d/%d)", delay, attempt, intentMaxAttempts),
+			logutil.OrderID(args.OrderID), logutil.Block(block), logutil.Err(err))
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(delay):
+		}
+
+		if _, err = handler(ctx, args, bl.config.ChainName, block); !failure.Transient(err) {
+			if err != nil {
+				bl.logger.Error("❌ Order retry failed", logutil.OrderID(args.OrderID), logutil.Block(block), logutil.Err(err), logutil.ErrKind(err))
+			}
+			return
+		}
+		delay *= 2
+	}
+	bl.logger.Error(fmt.Sprintf("❌ Giving up on order after %d attempts", intentMaxAttempts),
+		logutil.OrderID(args.OrderID), logutil.Block(block), logutil.Err(err), logutil.ErrKind(err))
+}
+
 // ResolveSolverStartBlock resolves the actual start block based on solver start block configuration
 // - Positive number: start at that specific block
 // - Zero: start at current block (live)
@@ -46,7 +129,7 @@ func ResolveSolverStartBlock(ctx context.Context, solverStartBlock int64, blockP
 	// Zero or negative number - need current block
 	currentBlock, err := blockProvider.BlockNumber(ctx)
 	if err != nil {
-		return 0, fmt.Errorf("failed to get current block number: %v", err)
+		return 0, failure.Mark(fmt.Errorf("failed to get current block number: %w", err), failure.ErrNetwork)
 	}
 
 	if solverStartBlock == 0 {
@@ -75,11 +158,12 @@ func ProcessCurrentBlockRangeCommon(
 	listenerConfig *base.ListenerConfig,
 	lastProcessedBlock *uint64,
 	networkType string,
+	logger logutil.Logger,
 	processBlockRange func(context.Context, uint64, uint64, base.EventHandler) (uint64, error),
 ) error {
 	currentBlock, err := blockProvider.BlockNumber(ctx)
 	if err != nil {
-		return fmt.Errorf("failed to get current block number: %v", err)
+		return failure.Mark(fmt.Errorf("failed to get current block number: %w", err), failure.ErrNetwork)
 	}
 
 	// Apply confirmations window if configured
@@ -94,6 +178,7 @@ func ProcessCurrentBlockRangeCommon(
 	// Check if we have any new blocks to process
 	if fromBlock > toBlock {
 		// No new blocks to process, we're up to date
+		metrics.SetListenerProgress(listenerConfig.ChainName, protocolLabel, currentBlock, *lastProcessedBlock)
 		return nil
 	}
 
@@ -107,35 +192,49 @@ func ProcessCurrentBlockRangeCommon(
 			end = toBlock
 		}
 
-		logutil.LogWithNetworkTagf(listenerConfig.ChainName, "🧭 %s range: from=%d to=%d (current=%d, conf=%d)\n",
-			networkType, start, end, currentBlock, listenerConfig.ConfirmationBlocks)
+		logger.Debug(fmt.Sprintf("🧭 %s range: from=%d to=%d (current=%d, conf=%d)",
+			networkType, start, end, currentBlock, listenerConfig.ConfirmationBlocks), logutil.Block(end))
 
 		chunkLast, err := processBlockRange(ctx, start, end, handler)
 		if err != nil {
-			return fmt.Errorf("failed to process blocks %d-%d: %v", start, end, err)
+			return fmt.Errorf("failed to process blocks %d-%d: %w", start, end, err)
 		}
 
 		newLast = chunkLast
 		if err := config.UpdateLastIndexedBlock(listenerConfig.ChainName, newLast); err != nil {
-			fmt.Printf("⚠️  Failed to persist LastIndexedBlock for %s: %v\n", listenerConfig.ChainName, err)
+			logger.Warn("⚠️  Failed to persist LastIndexedBlock", logutil.Block(newLast), logutil.Err(err))
 		}
 	}
 
 	// Block processing complete
 	*lastProcessedBlock = newLast
+	metrics.SetListenerProgress(listenerConfig.ChainName, protocolLabel, currentBlock, newLast)
 	return nil
 }
 
 // GetLastProcessedBlock returns the last processed block number
 func (bl *BaseListener) GetLastProcessedBlock() uint64 {
+	bl.mu.Lock()
+	defer bl.mu.Unlock()
 	return bl.lastProcessedBlock
 }
 
 // SetLastProcessedBlock sets the last processed block number
 func (bl *BaseListener) SetLastProcessedBlock(block uint64) {
+	bl.mu.Lock()
+	defer bl.mu.Unlock()
 	bl.lastProcessedBlock = block
 }
 
+// resumeFrom continues after the last processed block of the resolved config and remembers the blocks before it
+func (bl *BaseListener) resumeFrom(common *CommonListenerConfig) {
+	bl.mu.Lock()
+	defer bl.mu.Unlock()
+	bl.lastProcessedBlock = common.LastProcessedBlock
+	bl.startBlock = common.StartBlock
+	bl.resumedBlock = common.LastProcessedBlock
+}
+
 // GetConfig returns the listener configuration
 func (bl *BaseListener) GetConfig() base.ListenerConfig {
 	return bl.config
@@ -147,12 +246,11 @@ func (bl *BaseListener) CatchUpHistoricalBlocks(
 	handler base.EventHandler,
 	processBlockRange func(context.Context, uint64, uint64, base.EventHandler) (uint64, error),
 ) error {
-	p := logutil.Prefix(bl.config.ChainName)
-	fmt.Printf("%s🔄 Catching up on historical blocks...\n", p)
+	bl.logger.Info("🔄 Catching up on historical blocks...")
 
 	currentBlock, err := bl.blockProvider.BlockNumber(ctx)
 	if err != nil {
-		return fmt.Errorf("%sfailed to get current block number: %v", p, err)
+		return failure.Mark(fmt.Errorf("%sfailed to get current block number: %w", logutil.Prefix(bl.config.ChainName), err), failure.ErrNetwork)
 	}
 
 	// Apply confirmations during backfill as well
@@ -162,38 +260,172 @@ func (bl *BaseListener) CatchUpHistoricalBlocks(
 	}
 
 	// Start from the last processed block + 1 (which should be the solver start block)
-	fromBlock := bl.lastProcessedBlock + 1
+	fromBlock := bl.GetLastProcessedBlock() + 1
 	toBlock := safeBlock
 	if fromBlock >= toBlock {
-		fmt.Printf("%s✅ Already up to date, no historical blocks to process\n", p)
+		bl.logger.Info("✅ Already up to date, no historical blocks to process")
+		bl.markPolled()
 		return nil
 	}
 
 	chunkSize := bl.config.MaxBlockRange
 	for start := fromBlock; start < toBlock; start += chunkSize {
+		if err := bl.waitWhilePaused(ctx); err != nil {
+			return err
+		}
+
+		bl.mu.Lock()
+		if bl.rescanned {
+			// A re-scan rewound the listener since the last range; continue from where it asked
+			start = bl.lastProcessedBlock + 1
+			bl.rescanned = false
+		}
 		end := start + chunkSize
 		if end > toBlock {
 			end = toBlock
 		}
-
 		newLast, err := processBlockRange(ctx, start, end, handler)
 		if err != nil {
-			return fmt.Errorf("%sfailed to process historical blocks %d-%d: %v", p, start, end, err)
+			bl.mu.Unlock()
+			return fmt.Errorf("%sfailed to process historical blocks %d-%d: %w", logutil.Prefix(bl.config.ChainName), start, end, err)
 		}
-
 		bl.lastProcessedBlock = newLast
 		if err := config.UpdateLastIndexedBlock(bl.config.ChainName, newLast); err != nil {
-			fmt.Printf("%s⚠️  Failed to persist LastIndexedBlock: %v\n", p, err)
+			bl.logger.Warn("⚠️  Failed to persist LastIndexedBlock", logutil.Block(newLast), logutil.Err(err))
 		}
+		bl.mu.Unlock()
+
+		bl.markPolled()
+		metrics.SetListenerProgress(bl.config.ChainName, protocolLabel, currentBlock, newLast)
+	}
+
+	bl.logger.Info("✅ Historical block processing complete", logutil.Block(bl.GetLastProcessedBlock()))
+	return nil
+}
+
+// Pause stops the listener from processing new blocks; a running block range finishes first
+func (bl *BaseListener) Pause() {
+	if !bl.paused.Swap(true) {
+		bl.logger.Info("⏸️  Listener paused")
+	}
+}
+
+// Resume continues processing from the last processed block
+func (bl *BaseListener) Resume() {
+	if bl.paused.Swap(false) {
+		bl.logger.Info("▶️  Listener resumed")
 	}
+}
+
+// Paused reports whether the listener is paused
+func (bl *BaseListener) Paused() bool {
+	return bl.paused.Load()
+}
 
-	fmt.Printf("%s✅ Historical block processing complete\n", p)
+// WatchFills has the listener report the Filled events of its settler to observer, from the next block range on
+func (bl *BaseListener) WatchFills(observer FillObserver) {
+	bl.fillObserverMu.Lock()
+	defer bl.fillObserverMu.Unlock()
+	bl.fillObserver = observer
+}
+
+// watchingFills reports whether Filled events have an observer
+func (bl *BaseListener) watchingFills() bool {
+	bl.fillObserverMu.RLock()
+	defer bl.fillObserverMu.RUnlock()
+	return bl.fillObserver != nil
+}
+
+// observeFill hands a Filled event to the fill observer, if one is set
+func (bl *BaseListener) observeFill(ctx context.Context, fill FilledEvent) {
+	bl.fillObserverMu.RLock()
+	observer := bl.fillObserver
+	bl.fillObserverMu.RUnlock()
+	if observer != nil {
+		observer(ctx, fill)
+	}
+}
+
+// LastPolled returns when the listener last finished a poll of the chain; zero until it first does
+func (bl *BaseListener) LastPolled() time.Time {
+	nanos := bl.lastPolled.Load()
+	if nanos == 0 {
+		return time.Time{}
+	}
+	return time.Unix(0, nanos)
+}
+
+// markPolled records that a poll of the chain, or a backfill chunk, finished successfully
+func (bl *BaseListener) markPolled() {
+	bl.lastPolled.Store(time.Now().UnixNano())
+}
+
+// waitWhilePaused blocks until the listener is resumed or ctx is done
+func (bl *BaseListener) waitWhilePaused(ctx context.Context) error {
+	for bl.paused.Load() {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(pausedCheckInterval):
+		}
+	}
+	return nil
+}
+
+// scanOpenOrders hands observe the orders opened between the start block and the block the listener resumed from,
+// reading them with openOrders in ranges of at most MaxBlockRange blocks; a fresh start has no such blocks
+func (bl *BaseListener) scanOpenOrders(
+	ctx context.Context,
+	observe OpenOrderObserver,
+	openOrders func(context.Context, uint64, uint64) ([]types.ParsedArgs, error),
+) error {
+	if bl.resumedBlock <= bl.startBlock {
+		return nil
+	}
+	bl.logger.Info(fmt.Sprintf("🔎 Scanning blocks %d-%d for orders opened before the restart", bl.startBlock, bl.resumedBlock))
+
+	chunkSize := max(bl.config.MaxBlockRange, 1)
+	for start := bl.startBlock; start <= bl.resumedBlock; start += chunkSize {
+		end := min(start+chunkSize-1, bl.resumedBlock)
+		orders, err := openOrders(ctx, start, end)
+		if err != nil {
+			return fmt.Errorf("%sfailed to scan blocks %d-%d for open orders: %w", logutil.Prefix(bl.config.ChainName), start, end, err)
+		}
+		for i := range orders {
+			observe(ctx, &orders[i])
+		}
+	}
+	return nil
+}
+
+// rescanFrom rewinds lastProcessedBlock so that processing resumes at block, and persists it; a running backfill
+// continues from block after its current range.
+// Moving forward would skip unprocessed blocks, so block may be at most one past the last processed block.
+func (bl *BaseListener) rescanFrom(block uint64, lastProcessedBlock *uint64) error {
+	if block > *lastProcessedBlock+1 {
+		return fmt.Errorf("%w: block %d, last processed %d", base.ErrRescanAhead, block, *lastProcessedBlock)
+	}
+	newLast := uint64(0)
+	if block > 0 {
+		newLast = block - 1
+	}
+	*lastProcessedBlock = newLast
+	bl.mu.Lock()
+	defer bl.mu.Unlock()
+	bl.lastProcessedBlock = newLast
+	bl.rescanned = true
+	if err := config.UpdateLastIndexedBlock(bl.config.ChainName, newLast); err != nil {
+		bl.logger.Warn("⚠️  Failed to persist LastIndexedBlock", logutil.Block(newLast), logutil.Err(err))
+	}
+	bl.logger.Info("⏪ Re-scanning", logutil.Block(block))
 	return nil
 }
 
 // CommonListenerConfig holds common configuration for both EVM and Starknet listeners
 type CommonListenerConfig struct {
 	ListenerConfig *base.ListenerConfig
+	// StartBlock is the configured solver start block, resolved against the current block
+	StartBlock         uint64
 	LastProcessedBlock uint64
 }
 
@@ -202,7 +434,9 @@ func ResolveCommonListenerConfig(
 	ctx context.Context,
 	listenerConfig *base.ListenerConfig,
 	blockProvider BlockNumberProvider,
+	logger logutil.Logger,
 ) (*CommonListenerConfig, error) {
+	logger = logger.With(logutil.Chain(listenerConfig.ChainName))
 	configStartBlock := listenerConfig.InitialBlock.Int64()
 	var resolvedStartBlock uint64
 
@@ -213,14 +447,13 @@ func ResolveCommonListenerConfig(
 		// Zero or negative number - need current block
 		currentBlock, err := blockProvider.BlockNumber(ctx)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get current block number: %v", err)
+			return nil, failure.Mark(fmt.Errorf("failed to get current block number: %w", err), failure.ErrNetwork)
 		}
 
 		if configStartBlock == 0 {
 			// Zero - start at current block (live)
 			resolvedStartBlock = currentBlock
-			fmt.Printf("%s📚 Start block was 0, using current block %d\n",
-				logutil.Prefix(listenerConfig.ChainName), currentBlock)
+			logger.Info(fmt.Sprintf("📚 Start block was 0, using current block %d", currentBlock))
 		} else {
 			// Negative number - start N blocks before current block
 			// Calculate start block: current - abs(configStartBlock)
@@ -231,8 +464,8 @@ func ResolveCommonListenerConfig(
 				resolvedStartBlock = 0
 			}
 
-			fmt.Printf("%s📚 Start block was %d, using current block %d - %d = %d\n",
-				logutil.Prefix(listenerConfig.ChainName), configStartBlock, currentBlock, -configStartBlock, resolvedStartBlock)
+			logger.Info(fmt.Sprintf("📚 Start block was %d, using current block %d - %d = %d",
+				configStartBlock, currentBlock, -configStartBlock, resolvedStartBlock))
 		}
 	}
 
@@ -246,12 +479,12 @@ func ResolveCommonListenerConfig(
 		deploymentStateBlock := networkState.LastIndexedBlock
 		if deploymentStateBlock > resolvedStartBlock {
 			lastProcessedBlock = deploymentStateBlock
-			fmt.Printf("%s📚 Using deployment state block %d (higher than config start block %d)\n",
-				logutil.Prefix(listenerConfig.ChainName), deploymentStateBlock, resolvedStartBlock)
+			logger.Info(fmt.Sprintf("📚 Using deployment state block %d (higher than config start block %d)",
+				deploymentStateBlock, resolvedStartBlock))
 		} else {
 			lastProcessedBlock = resolvedStartBlock
-			fmt.Printf("%s📚 Using config start block %d (deployment state block %d is lower)\n",
-				logutil.Prefix(listenerConfig.ChainName), resolvedStartBlock, deploymentStateBlock)
+			logger.Info(fmt.Sprintf("📚 Using config start block %d (deployment state block %d is lower)",
+				resolvedStartBlock, deploymentStateBlock))
 		}
 	} else {
 		return nil, fmt.Errorf("network %s not found in solver state", listenerConfig.ChainName)
@@ -259,6 +492,7 @@ func ResolveCommonListenerConfig(
 
 	return &CommonListenerConfig{
 		ListenerConfig:     listenerConfig,
+		StartBlock:         resolvedStartBlock,
 		LastProcessedBlock: lastProcessedBlock,
 	}, nil
 }
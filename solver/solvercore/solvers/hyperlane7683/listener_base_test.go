@@ -0,0 +1,195 @@
+package hyperlane7683
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+func TestBaseListenerPause(t *testing.T) {
+	listener := NewBaseListener(base.ListenerConfig{ChainName: "Base"}, nil, "EVM", logutil.Default())
+	assert.False(t, listener.Paused())
+	require.NoError(t, listener.waitWhilePaused(context.Background()))
+
+	listener.Pause()
+	assert.True(t, listener.Paused())
+
+	// A paused backfill waits until resumed or cancelled
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	assert.ErrorIs(t, listener.waitWhilePaused(ctx), context.DeadlineExceeded)
+
+	listener.Resume()
+	assert.False(t, listener.Paused())
+}
+
+func TestBaseListenerRescanFrom(t *testing.T) {
+	listener := NewBaseListener(base.ListenerConfig{ChainName: "Base"}, nil, "EVM", logutil.Default())
+	last := uint64(500)
+
+	require.NoError(t, listener.rescanFrom(400, &last))
+	assert.Equal(t, uint64(399), last)
+	assert.Equal(t, uint64(399), listener.GetLastProcessedBlock())
+
+	// Resuming right after the last processed block is allowed; skipping blocks is not
+	require.NoError(t, listener.rescanFrom(400, &last))
+	assert.ErrorIs(t, listener.rescanFrom(401, &last), base.ErrRescanAhead)
+	assert.Equal(t, uint64(399), last)
+
+	require.NoError(t, listener.rescanFrom(0, &last))
+	assert.Equal(t, uint64(0), last)
+}
+
+// headBlock is a BlockNumberProvider whose chain head never moves
+type headBlock uint64
+
+func (b headBlock) BlockNumber(context.Context) (uint64, error) { return uint64(b), nil }
+
+func TestBaseListenerRescanDuringBackfill(t *testing.T) {
+	listener := NewBaseListener(base.ListenerConfig{ChainName: "Base", MaxBlockRange: 10}, headBlock(80), "EVM", logutil.Default())
+	listener.resumeFrom(&CommonListenerConfig{StartBlock: 0, LastProcessedBlock: 50})
+	last := uint64(50)
+
+	var ranges [][2]uint64
+	rescanned := make(chan error, 1)
+	processBlockRange := func(_ context.Context, from, to uint64, _ base.EventHandler) (uint64, error) {
+		ranges = append(ranges, [2]uint64{from, to})
+		if len(ranges) == 1 {
+			// The re-scan waits for this range; the backfill waits for the re-scan before the next one
+			listener.Pause()
+			go func() {
+				rescanned <- listener.rescanFrom(21, &last)
+				listener.Resume()
+			}()
+		}
+		return to, nil
+	}
+
+	require.NoError(t, listener.CatchUpHistoricalBlocks(context.Background(), nil, processBlockRange))
+	require.NoError(t, <-rescanned)
+	assert.Equal(t, [][2]uint64{{51, 61}, {21, 31}, {31, 41}, {41, 51}, {51, 61}, {61, 71}, {71, 80}}, ranges,
+		"the backfill continues from the re-scanned block instead of overwriting it")
+	assert.Equal(t, uint64(80), listener.GetLastProcessedBlock())
+}
+
+func TestBaseListenerScanOpenOrders(t *testing.T) {
+	listener := NewBaseListener(base.ListenerConfig{ChainName: "Base", MaxBlockRange: 10}, nil, "EVM", logutil.Default())
+	listener.resumeFrom(&CommonListenerConfig{StartBlock: 100, LastProcessedBlock: 125})
+	assert.Equal(t, uint64(125), listener.GetLastProcessedBlock())
+
+	var ranges [][2]uint64
+	openOrders := func(_ context.Context, from, to uint64) ([]types.ParsedArgs, error) {
+		ranges = append(ranges, [2]uint64{from, to})
+		return []types.ParsedArgs{{OrderID: fmt.Sprintf("0x%d", from)}}, nil
+	}
+	var seen []string
+	observe := func(_ context.Context, args *types.ParsedArgs) { seen = append(seen, args.OrderID) }
+
+	require.NoError(t, listener.scanOpenOrders(context.Background(), observe, openOrders))
+	assert.Equal(t, [][2]uint64{{100, 109}, {110, 119}, {120, 125}}, ranges)
+	assert.Equal(t, []string{"0x100", "0x110", "0x120"}, seen)
+
+	failing := func(context.Context, uint64, uint64) ([]types.ParsedArgs, error) {
+		return nil, errors.New("rpc unavailable")
+	}
+	require.Error(t, listener.scanOpenOrders(context.Background(), observe, failing))
+
+	// A fresh start has processed nothing before the start block
+	listener.resumeFrom(&CommonListenerConfig{StartBlock: 200, LastProcessedBlock: 200})
+	ranges = nil
+	require.NoError(t, listener.scanOpenOrders(context.Background(), observe, openOrders))
+	assert.Empty(t, ranges)
+}
+
+func TestBaseListenerLastPolled(t *testing.T) {
+	listener := NewBaseListener(base.ListenerConfig{ChainName: "Base"}, nil, "EVM", logutil.Default())
+	assert.True(t, listener.LastPolled().IsZero())
+
+	listener.markPolled()
+	assert.WithinDuration(t, time.Now(), listener.LastPolled(), time.Second)
+}
+
+func TestBaseListenerWatchFills(t *testing.T) {
+	listener := NewBaseListener(base.ListenerConfig{ChainName: "Base"}, nil, "EVM", logutil.Default())
+	assert.False(t, listener.watchingFills())
+	listener.observeFill(context.Background(), FilledEvent{OrderID: "0x01", ChainName: "Base", Block: 1, FillerData: nil})
+
+	var seen []FilledEvent
+	listener.WatchFills(func(_ context.Context, fill FilledEvent) {
+		seen = append(seen, fill)
+	})
+	assert.True(t, listener.watchingFills())
+	listener.observeFill(context.Background(), FilledEvent{OrderID: "0x02", ChainName: "Base", Block: 2, FillerData: []byte{0x01}})
+	require.Len(t, seen, 1)
+	assert.Equal(t, "0x02", seen[0].OrderID)
+}
+
+func TestBaseListenerHandleIntentRetries(t *testing.T) {
+	listener := NewBaseListener(base.ListenerConfig{ChainName: "Base"}, nil, "EVM", logutil.Default())
+	listener.retryBackoff = time.Millisecond
+	order := types.ParsedArgs{OrderID: "0x01"}
+
+	failing := func(errs ...error) (base.EventHandler, *atomic.Int32) {
+		calls := &atomic.Int32{}
+		return func(context.Context, types.ParsedArgs, string, uint64) (bool, error) {
+			call := int(calls.Add(1))
+			if call <= len(errs) {
+				return false, errs[call-1]
+			}
+			return true, nil
+		}, calls
+	}
+	timeout := fmt.Errorf("rpc: %w", failure.ErrNetwork)
+
+	t.Run("network errors are retried in the background", func(t *testing.T) {
+		handler, calls := failing(timeout)
+		settled, err := listener.handleIntent(context.Background(), handler, order, 1)
+		assert.ErrorIs(t, err, failure.ErrNetwork, "the first attempt's result is returned right away")
+		assert.False(t, settled)
+		listener.retries.Wait()
+		assert.Equal(t, int32(2), calls.Load())
+	})
+
+	t.Run("retries are capped", func(t *testing.T) {
+		handler, calls := failing(timeout, timeout, timeout, timeout)
+		_, err := listener.handleIntent(context.Background(), handler, order, 1)
+		assert.ErrorIs(t, err, failure.ErrNetwork)
+		listener.retries.Wait()
+		assert.Equal(t, int32(intentMaxAttempts), calls.Load())
+	})
+
+	t.Run("other errors are not retried", func(t *testing.T) {
+		for _, err := range []error{
+			fmt.Errorf("%w: blocked", failure.ErrValidation),
+			fmt.Errorf("fill: %w", failure.ErrReverted),
+			errors.New("status must be filled"),
+		} {
+			handler, calls := failing(err)
+			_, got := listener.handleIntent(context.Background(), handler, order, 1)
+			assert.ErrorIs(t, got, err)
+			listener.retries.Wait()
+			assert.Equal(t, int32(1), calls.Load(), err.Error())
+		}
+	})
+
+	t.Run("cancellation stops retrying", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		handler, calls := failing(timeout, timeout)
+		_, err := listener.handleIntent(ctx, handler, order, 1)
+		require.Error(t, err)
+		listener.retries.Wait()
+		assert.Equal(t, int32(1), calls.Load())
+	})
+}
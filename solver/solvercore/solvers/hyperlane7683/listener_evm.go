@@ -4,19 +4,25 @@ package hyperlane7683
 // - Polls/backfills block ranges on EVM networks
 // - Parses Hyperlane7683 Open events via abigen bindings
 // - Translates to types.ParsedArgs and invokes the solver
+// - Reports Filled events, with the filler they name, to the fill observer when one is set
 // - Persists last processed block via deployment state
+// - Can be paused, resumed and rewound to an earlier block at runtime
 
 import (
 	"context"
 	"encoding/hex"
 	"fmt"
+	"log/slog"
 	"math/big"
 	"sync"
 	"time"
 
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
 	contracts "github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/tracing"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/common"
@@ -27,6 +33,9 @@ import (
 // Open event topic
 var openEventTopic = common.HexToHash("0x3448bbc2203c608599ad448eeb1007cea04b788ac631f9f558e8dd01a3c27b3d")
 
+// Filled event topic
+var filledEventTopic = common.HexToHash("0x57f1f65270c1c2c1771948825ee86f8d23d11ab44b16eb9c213056e042d06e59")
+
 // evmListener implements listener.Listener for EVM chains
 type evmListener struct {
 	config             *base.ListenerConfig
@@ -36,9 +45,10 @@ type evmListener struct {
 	stopChan           chan struct{}
 	mu                 sync.RWMutex
 	baseListener       *BaseListener
+	logger             logutil.Logger
 }
 
-func NewEVMListener(listenerConfig *base.ListenerConfig, rpcURL string) (base.Listener, error) {
+func NewEVMListener(listenerConfig *base.ListenerConfig, rpcURL string, logger logutil.Logger) (base.ControllableListener, error) {
 	client, err := ethclient.Dial(rpcURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to dial RPC: %w", err)
@@ -50,14 +60,14 @@ func NewEVMListener(listenerConfig *base.ListenerConfig, rpcURL string) (base.Li
 	}
 
 	ctx := context.Background()
-	commonConfig, err := ResolveCommonListenerConfig(ctx, listenerConfig, client)
+	commonConfig, err := ResolveCommonListenerConfig(ctx, listenerConfig, client, logger)
 	if err != nil {
 		return nil, err
 	}
 
-	baseListener := NewBaseListener(*listenerConfig, client, "EVM")
-	baseListener.SetLastProcessedBlock(commonConfig.LastProcessedBlock)
-	
+	baseListener := NewBaseListener(*listenerConfig, client, "EVM", logger)
+	baseListener.resumeFrom(commonConfig)
+
 	return &evmListener{
 		config:             listenerConfig,
 		client:             client,
@@ -66,6 +76,7 @@ func NewEVMListener(listenerConfig *base.ListenerConfig, rpcURL string) (base.Li
 		stopChan:           make(chan struct{}),
 		mu:                 sync.RWMutex{},
 		baseListener:       baseListener,
+		logger:             baseListener.logger,
 	}, nil
 }
 
@@ -94,16 +105,15 @@ func (l *evmListener) MarkBlockFullyProcessed(blockNumber uint64) error {
 		return fmt.Errorf("cannot mark block %d as processed, expected %d", blockNumber, l.lastProcessedBlock+1)
 	}
 	l.lastProcessedBlock = blockNumber
-	fmt.Printf("%s✅ block %d processed\n", logutil.Prefix(l.config.ChainName), blockNumber)
+	l.logger.Info("✅ block processed", logutil.Block(blockNumber))
 	return nil
 }
 
 func (l *evmListener) startEventLoop(ctx context.Context, handler base.EventHandler) {
-	p := logutil.Prefix(l.config.ChainName)
 	if err := l.catchUpHistoricalBlocks(ctx, handler); err != nil {
-		fmt.Printf("%s❌ backfill failed: %v\n", p, err)
+		l.logger.Error("❌ backfill failed", logutil.Err(err))
 	}
-	fmt.Printf("%s🔄 backfill complete\n", p)
+	l.logger.Info("🔄 backfill complete")
 	l.startPolling(ctx, handler)
 }
 
@@ -112,54 +122,104 @@ func (l *evmListener) catchUpHistoricalBlocks(ctx context.Context, handler base.
 }
 
 func (l *evmListener) startPolling(ctx context.Context, handler base.EventHandler) {
-	fmt.Printf("%s📭 Starting event polling...\n", logutil.Prefix(l.config.ChainName))
+	l.logger.Info("📭 Starting event polling...")
 
 	for {
 		select {
 		case <-ctx.Done():
-			fmt.Printf("🔄 Context canceled, stopping event polling\n")
+			l.logger.Info("🔄 Context canceled, stopping event polling")
 			return
 		case <-l.stopChan:
-			fmt.Printf("🔄 Stop signal received, stopping event polling\n")
+			l.logger.Info("🔄 Stop signal received, stopping event polling")
 			return
 		default:
 			if err := l.processCurrentBlockRange(ctx, handler); err != nil {
-				fmt.Printf("%s❌ Failed to process current block range: %v\n", logutil.Prefix(l.config.ChainName), err)
+				metrics.RecordError(l.config.ChainName, protocolLabel, metrics.ErrorTypeListener, err)
+				l.logger.Error("❌ Failed to process current block range", logutil.Err(err))
 			}
 			time.Sleep(time.Duration(l.config.PollInterval) * time.Millisecond)
 		}
 	}
 }
 
+// Pause stops the listener from processing new blocks until Resume is called
+func (l *evmListener) Pause() {
+	l.baseListener.Pause()
+}
+
+// Resume continues processing from the last processed block
+func (l *evmListener) Resume() {
+	l.baseListener.Resume()
+}
+
+// Paused reports whether the listener is paused
+func (l *evmListener) Paused() bool {
+	return l.baseListener.Paused()
+}
+
+// WatchFills has the listener report the Filled events of its settler to observer
+func (l *evmListener) WatchFills(observer FillObserver) {
+	l.baseListener.WatchFills(observer)
+}
+
+// LastPolled returns when the listener last finished a poll of the chain
+func (l *evmListener) LastPolled() time.Time {
+	return l.baseListener.LastPolled()
+}
+
+// ScanOpenOrders hands observe the orders opened on the settler before the block the listener resumed from
+func (l *evmListener) ScanOpenOrders(ctx context.Context, observe OpenOrderObserver) error {
+	return l.baseListener.scanOpenOrders(ctx, observe, l.openOrders)
+}
+
+// RescanFrom makes the listener process blocks again starting at block, once the current range is done
+func (l *evmListener) RescanFrom(block uint64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.baseListener.rescanFrom(block, &l.lastProcessedBlock)
+}
+
 func (l *evmListener) processCurrentBlockRange(ctx context.Context, handler base.EventHandler) error {
+	if l.baseListener.Paused() {
+		return nil
+	}
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	return ProcessCurrentBlockRangeCommon(ctx, handler, l.client, l.config, &l.lastProcessedBlock, "EVM", l.processBlockRange)
+	if err := ProcessCurrentBlockRangeCommon(ctx, handler, l.client, l.config, &l.lastProcessedBlock, "EVM", l.logger, l.processBlockRange); err != nil {
+		return err
+	}
+	l.baseListener.markPolled()
+	return nil
 }
 
 // processBlockRange processes logs in [fromBlock, toBlock] and returns the highest contiguous block fully processed
 func (l *evmListener) processBlockRange(ctx context.Context, fromBlock, toBlock uint64, handler base.EventHandler) (uint64, error) {
 	if fromBlock > toBlock {
-		fmt.Printf("⚠️  Invalid block range (%s) in processBlockRange: fromBlock (%d) > toBlock (%d), skipping\n", l.config.ChainName, fromBlock, toBlock)
+		l.logger.Warn(fmt.Sprintf("⚠️  Invalid block range in processBlockRange: fromBlock (%d) > toBlock (%d), skipping", fromBlock, toBlock))
 		return l.lastProcessedBlock, nil
 	}
 
-	// Fetch events for the block range
+	// Fetch events for the block range; Filled events only while fills are watched
+	topics := []common.Hash{openEventTopic}
+	if l.baseListener.watchingFills() {
+		topics = append(topics, filledEventTopic)
+	}
 	query := ethereum.FilterQuery{
-		FromBlock:  big.NewInt(int64(fromBlock)),
-		ToBlock:    big.NewInt(int64(toBlock)),
-		Addresses:  []common.Address{l.contractAddress},
-		Topics:     [][]common.Hash{{openEventTopic}},
-		BlockHash:  nil,
+		FromBlock: big.NewInt(int64(fromBlock)),
+		ToBlock:   big.NewInt(int64(toBlock)),
+		Addresses: []common.Address{l.contractAddress},
+		Topics:    [][]common.Hash{topics},
+		BlockHash: nil,
 	}
 
 	logs, err := l.client.FilterLogs(ctx, query)
 	if err != nil {
-		return l.lastProcessedBlock, fmt.Errorf("failed to filter logs: %w", err)
+		return l.lastProcessedBlock, failure.Mark(fmt.Errorf("failed to filter logs: %w", err), failure.ErrNetwork)
 	}
 
 	// Use the new logging system for reduced verbosity
-	logutil.LogBlockProcessing(l.config.ChainName, fromBlock, toBlock, len(logs))
+	logutil.BlockProcessing(l.logger, fromBlock, toBlock, len(logs))
+	metrics.EventsSeen(l.config.ChainName, protocolLabel, len(logs))
 
 	// Group logs by block
 	byBlock := make(map[uint64][]ethtypes.Log)
@@ -179,21 +239,37 @@ func (l *evmListener) processBlockRange(ctx context.Context, fromBlock, toBlock
 			// Use generated binding to parse Open events
 			filterer, err := contracts.NewHyperlane7683Filterer(l.contractAddress, l.client)
 			if err != nil {
-				fmt.Printf("❌ Failed to bind filterer: %v\n", err)
+				l.logger.Error("❌ Failed to bind filterer", logutil.Block(b), logutil.Err(err))
+				continue
+			}
+
+			// Filled events only go to the fill observer
+			if len(logEvent.Topics) > 0 && logEvent.Topics[0] == filledEventTopic {
+				filled, err := filterer.ParseFilled(*logEvent)
+				if err != nil {
+					l.logger.Error("❌ Failed to parse Filled event", logutil.Block(b), logutil.TxHash(logEvent.TxHash.Hex()), logutil.Err(err))
+					continue
+				}
+				l.baseListener.observeFill(ctx, FilledEvent{
+					OrderID:    common.BytesToHash(filled.OrderId[:]).Hex(),
+					ChainName:  l.config.ChainName,
+					Block:      b,
+					FillerData: filled.FillerData,
+				})
 				continue
 			}
 
 			// Parse Open event
 			event, err := filterer.ParseOpen(*logEvent)
 			if err != nil {
-				fmt.Printf("❌ Failed to parse Open event: %v\n", err)
+				l.logger.Error("❌ Failed to parse Open event", logutil.Block(b), logutil.TxHash(logEvent.TxHash.Hex()), logutil.Err(err))
 				continue
 			}
 
 			// Handle the event
-			_, err = l.handleParsedOpenEvent(event, handler)
+			_, err = l.handleParsedOpenEvent(ctx, event, handler)
 			if err != nil {
-				fmt.Printf("❌ Failed to handle Open event: %v\n", err)
+				l.logger.Error("❌ Failed to handle Open event", logutil.Block(b), logutil.TxHash(logEvent.TxHash.Hex()), logutil.Err(err))
 				continue
 			}
 		}
@@ -203,17 +279,59 @@ func (l *evmListener) processBlockRange(ctx context.Context, fromBlock, toBlock
 
 		// Only log individual blocks if there are events
 		if len(events) > 0 {
-			logutil.LogWithNetworkTagf(l.config.ChainName, "   ✅ Block %d processed: %d events\n", b, len(events))
+			l.logger.Info(fmt.Sprintf("   ✅ Block %d processed: %d events", b, len(events)), logutil.Block(b))
 		}
 	}
 
 	return newLast, nil
 }
 
+// openOrders reads the orders opened in [fromBlock, toBlock] without handing them to the solver
+func (l *evmListener) openOrders(ctx context.Context, fromBlock, toBlock uint64) ([]types.ParsedArgs, error) {
+	filterer, err := contracts.NewHyperlane7683Filterer(l.contractAddress, l.client)
+	if err != nil {
+		return nil, fmt.Errorf("failed to bind filterer: %w", err)
+	}
+	logs, err := l.client.FilterLogs(ctx, ethereum.FilterQuery{
+		FromBlock: new(big.Int).SetUint64(fromBlock),
+		ToBlock:   new(big.Int).SetUint64(toBlock),
+		Addresses: []common.Address{l.contractAddress},
+		Topics:    [][]common.Hash{{openEventTopic}},
+		BlockHash: nil,
+	})
+	if err != nil {
+		return nil, failure.Mark(fmt.Errorf("failed to filter logs: %w", err), failure.ErrNetwork)
+	}
+
+	orders := make([]types.ParsedArgs, 0, len(logs))
+	for i := range logs {
+		event, err := filterer.ParseOpen(logs[i])
+		if err != nil {
+			l.logger.Error("❌ Failed to parse Open event", logutil.Block(logs[i].BlockNumber), logutil.TxHash(logs[i].TxHash.Hex()), logutil.Err(err))
+			continue
+		}
+		orders = append(orders, l.openOrderArgs(event))
+	}
+	return orders, nil
+}
+
 // handleParsedOpenEvent converts a typed binding event into our internal ParsedArgs and dispatches the handler
-func (l *evmListener) handleParsedOpenEvent(ev *contracts.Hyperlane7683Open, handler base.EventHandler) (bool, error) {
-	p := logutil.Prefix(l.config.ChainName)
+func (l *evmListener) handleParsedOpenEvent(ctx context.Context, ev *contracts.Hyperlane7683Open, handler base.EventHandler) (bool, error) {
+	parsedArgs := l.openOrderArgs(ev)
+
+	l.logger.Info("📜 Open order", logutil.OrderID(parsedArgs.OrderID), logutil.Block(ev.Raw.BlockNumber), logutil.TxHash(ev.Raw.TxHash.Hex()))
+	l.logger.Debug("📊 Order details", logutil.OrderID(parsedArgs.OrderID), slog.String("user", parsedArgs.ResolvedOrder.User))
+
+	// Just pass to handler, let the solver decide what to do; the order is traced from its detection
+	ctx, span := tracing.StartOrderSpan(ctx, tracing.SpanOrderDetected, parsedArgs.OrderID,
+		tracing.Chain(l.config.ChainName), tracing.Block(ev.Raw.BlockNumber), tracing.TxHash(ev.Raw.TxHash.Hex()))
+	settled, err := l.baseListener.handleIntent(ctx, handler, parsedArgs, ev.Raw.BlockNumber)
+	tracing.End(span, err)
+	return settled, err
+}
 
+// openOrderArgs converts a typed binding event into our internal ParsedArgs
+func (l *evmListener) openOrderArgs(ev *contracts.Hyperlane7683Open) types.ParsedArgs {
 	// Parse to ResolvedCrossChainOrder
 	ro := types.ResolvedCrossChainOrder{
 		User:             ev.ResolvedOrder.User.Hex(),
@@ -228,7 +346,7 @@ func (l *evmListener) handleParsedOpenEvent(ev *contracts.Hyperlane7683Open, han
 
 	for _, o := range ev.ResolvedOrder.MaxSpent {
 		ro.MaxSpent = append(ro.MaxSpent, types.Output{
-			Token:     bytes32ToHexString(o.Token),
+			Token:     types.CanonicalToken(bytes32ToHexString(o.Token)),
 			Amount:    o.Amount,
 			Recipient: bytes32ToHexString(o.Recipient),
 			ChainID:   o.ChainId,
@@ -236,7 +354,7 @@ func (l *evmListener) handleParsedOpenEvent(ev *contracts.Hyperlane7683Open, han
 	}
 	for _, o := range ev.ResolvedOrder.MinReceived {
 		ro.MinReceived = append(ro.MinReceived, types.Output{
-			Token:     bytes32ToHexString(o.Token),
+			Token:     types.CanonicalToken(bytes32ToHexString(o.Token)),
 			Amount:    o.Amount,
 			Recipient: bytes32ToHexString(o.Recipient),
 			ChainID:   o.ChainId,
@@ -250,7 +368,7 @@ func (l *evmListener) handleParsedOpenEvent(ev *contracts.Hyperlane7683Open, han
 		})
 	}
 
-	parsedArgs := types.ParsedArgs{
+	return types.ParsedArgs{
 		OrderID:       common.BytesToHash(ev.OrderId[:]).Hex(),
 		SenderAddress: ro.User,
 		Recipients: []types.Recipient{{
@@ -259,12 +377,6 @@ func (l *evmListener) handleParsedOpenEvent(ev *contracts.Hyperlane7683Open, han
 		}},
 		ResolvedOrder: ro,
 	}
-
-	fmt.Printf("%s📜 Open order: OrderID=%s\n", p, parsedArgs.OrderID)
-	fmt.Printf("%s📊 Order details: User=%s\n", p, ro.User)
-
-	// Just pass to handler, let the solver decide what to do
-	return handler(parsedArgs, l.config.ChainName, ev.Raw.BlockNumber)
 }
 
 // bytes32ToHexString converts a bytes32 address to a hex string
@@ -6,6 +6,7 @@ import (
 	"testing"
 
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/stretchr/testify/assert"
 )
@@ -18,7 +19,7 @@ func TestEVMListener(t *testing.T) {
 			InitialBlock:    big.NewInt(1000),
 		}
 
-		_, err := NewEVMListener(config, "invalid-rpc-url")
+		_, err := NewEVMListener(config, "invalid-rpc-url", logutil.Default())
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to dial RPC")
 	})
@@ -29,7 +30,7 @@ func TestEVMListener(t *testing.T) {
 			InitialBlock:    big.NewInt(1000),
 		}
 
-		_, err := NewEVMListener(config, "http://localhost:8545")
+		_, err := NewEVMListener(config, "http://localhost:8545", logutil.Default())
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "invalid EVM contract address")
 	})
@@ -114,7 +115,7 @@ func TestEVMListenerErrorHandling(t *testing.T) {
 			InitialBlock:    big.NewInt(1000),
 		}
 
-		_, err := NewEVMListener(config, "http://nonexistent:8545")
+		_, err := NewEVMListener(config, "http://nonexistent:8545", logutil.Default())
 		assert.Error(t, err)
 	})
 
@@ -123,7 +124,7 @@ func TestEVMListenerErrorHandling(t *testing.T) {
 			ContractAddress: "not-a-valid-address",
 		}
 
-		_, err := NewEVMListener(config, "http://localhost:8545")
+		_, err := NewEVMListener(config, "http://localhost:8545", logutil.Default())
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "invalid EVM contract address")
 	})
@@ -143,7 +144,7 @@ func TestEVMListenerConcurrency(t *testing.T) {
 
 		for i := 0; i < 5; i++ {
 			go func(index int) {
-				listener, err := NewEVMListener(config, "http://localhost:8545")
+				listener, err := NewEVMListener(config, "http://localhost:8545", logutil.Default())
 				listeners[index] = listener
 				errors[index] = err
 			}(i)
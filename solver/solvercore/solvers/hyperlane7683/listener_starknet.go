@@ -4,7 +4,9 @@ package hyperlane7683
 // - Polls/backfills block ranges on Starknet
 // - Parses Cairo Open events and reconstructs EVM-compatible ResolvedCrossChainOrder
 // - Invokes the filler with parsed args
+// - Reports Filled events, with the filler they name, to the fill observer when one is set
 // - Persists last processed block via deployment state
+// - Can be paused, resumed and rewound to an earlier block at runtime
 
 import (
 	"bytes"
@@ -19,16 +21,24 @@ import (
 	"github.com/NethermindEth/starknet.go/rpc"
 	"github.com/NethermindEth/starknet.go/utils"
 	"github.com/ethereum/go-ethereum/common"
+	"go.opentelemetry.io/otel/attribute"
 
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/tracing"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 )
 
 // Open event topic
 var openEventSelector, _ = utils.HexToFelt("0x35D8BA7F4BF26B6E2E2060E5BD28107042BE35460FBD828C9D29A2D8AF14445")
 
+// Filled event topic
+var filledEventSelector = utils.GetSelectorFromNameFelt("Filled")
+
 // starknetListener implements listener.Listener for Starknet chains
 type starknetListener struct {
 	config             *base.ListenerConfig
@@ -38,10 +48,11 @@ type starknetListener struct {
 	stopChan           chan struct{}
 	mu                 sync.RWMutex
 	baseListener       *BaseListener
+	logger             logutil.Logger
 }
 
 // NewStarknetListener creates a new Starknet listener
-func NewStarknetListener(listenerConfig *base.ListenerConfig, rpcURL string) (base.Listener, error) {
+func NewStarknetListener(listenerConfig *base.ListenerConfig, rpcURL string, logger logutil.Logger) (base.ControllableListener, error) {
 	provider, err := rpc.NewProvider(rpcURL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to connect Starknet RPC: %w", err)
@@ -53,14 +64,14 @@ func NewStarknetListener(listenerConfig *base.ListenerConfig, rpcURL string) (ba
 	}
 
 	ctx := context.Background()
-	commonConfig, err := ResolveCommonListenerConfig(ctx, listenerConfig, provider)
+	commonConfig, err := ResolveCommonListenerConfig(ctx, listenerConfig, provider, logger)
 	if err != nil {
 		return nil, err
 	}
 
-	baseListener := NewBaseListener(*listenerConfig, provider, "Starknet")
-	baseListener.SetLastProcessedBlock(commonConfig.LastProcessedBlock)
-	
+	baseListener := NewBaseListener(*listenerConfig, provider, "Starknet", logger)
+	baseListener.resumeFrom(commonConfig)
+
 	return &starknetListener{
 		config:             listenerConfig,
 		provider:           provider,
@@ -69,6 +80,7 @@ func NewStarknetListener(listenerConfig *base.ListenerConfig, rpcURL string) (ba
 		stopChan:           make(chan struct{}),
 		mu:                 sync.RWMutex{},
 		baseListener:       baseListener,
+		logger:             baseListener.logger,
 	}, nil
 }
 
@@ -97,16 +109,15 @@ func (l *starknetListener) MarkBlockFullyProcessed(blockNumber uint64) error {
 		return fmt.Errorf("cannot mark block %d as processed, expected %d", blockNumber, l.lastProcessedBlock+1)
 	}
 	l.lastProcessedBlock = blockNumber
-	fmt.Printf("%s✅ block %d processed\n", logutil.Prefix(l.config.ChainName), blockNumber)
+	l.logger.Info("✅ block processed", logutil.Block(blockNumber))
 	return nil
 }
 
 func (l *starknetListener) startEventLoop(ctx context.Context, handler base.EventHandler) {
-	p := logutil.Prefix(l.config.ChainName)
 	if err := l.catchUpHistoricalBlocks(ctx, handler); err != nil {
-		fmt.Printf("%s❌ backfill failed: %v\n", p, err)
+		l.logger.Error("❌ backfill failed", logutil.Err(err))
 	}
-	fmt.Printf("%s🔄 backfill complete\n", p)
+	l.logger.Info("🔄 backfill complete")
 	l.startPolling(ctx, handler)
 }
 
@@ -115,18 +126,19 @@ func (l *starknetListener) catchUpHistoricalBlocks(ctx context.Context, handler
 }
 
 func (l *starknetListener) startPolling(ctx context.Context, handler base.EventHandler) {
-	fmt.Printf("%s📭 Starting event polling...\n", logutil.Prefix(l.config.ChainName))
+	l.logger.Info("📭 Starting event polling...")
 	for {
 		select {
 		case <-ctx.Done():
-			fmt.Printf("🔄 Context canceled, stopping event polling\n")
+			l.logger.Info("🔄 Context canceled, stopping event polling")
 			return
 		case <-l.stopChan:
-			fmt.Printf("🔄 Stop signal received, stopping event polling\n")
+			l.logger.Info("🔄 Stop signal received, stopping event polling")
 			return
 		default:
 			if err := l.processCurrentBlockRange(ctx, handler); err != nil {
-				fmt.Printf("%s❌ Failed to process current block range: %v\n", logutil.Prefix(l.config.ChainName), err)
+				metrics.RecordError(l.config.ChainName, protocolLabel, metrics.ErrorTypeListener, err)
+				l.logger.Error("❌ Failed to process current block range", logutil.Err(err))
 			}
 			time.Sleep(time.Duration(l.config.PollInterval) * time.Millisecond)
 		}
@@ -135,16 +147,60 @@ func (l *starknetListener) startPolling(ctx context.Context, handler base.EventH
 
 // getMapKeys returns the keys of a map as a slice
 
+// Pause stops the listener from processing new blocks until Resume is called
+func (l *starknetListener) Pause() {
+	l.baseListener.Pause()
+}
+
+// Resume continues processing from the last processed block
+func (l *starknetListener) Resume() {
+	l.baseListener.Resume()
+}
+
+// Paused reports whether the listener is paused
+func (l *starknetListener) Paused() bool {
+	return l.baseListener.Paused()
+}
+
+// WatchFills has the listener report the Filled events of its settler to observer
+func (l *starknetListener) WatchFills(observer FillObserver) {
+	l.baseListener.WatchFills(observer)
+}
+
+// LastPolled returns when the listener last finished a poll of the chain
+func (l *starknetListener) LastPolled() time.Time {
+	return l.baseListener.LastPolled()
+}
+
+// ScanOpenOrders hands observe the orders opened on the settler before the block the listener resumed from
+func (l *starknetListener) ScanOpenOrders(ctx context.Context, observe OpenOrderObserver) error {
+	return l.baseListener.scanOpenOrders(ctx, observe, l.openOrders)
+}
+
+// RescanFrom makes the listener process blocks again starting at block, once the current range is done
+func (l *starknetListener) RescanFrom(block uint64) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.baseListener.rescanFrom(block, &l.lastProcessedBlock)
+}
+
 func (l *starknetListener) processCurrentBlockRange(ctx context.Context, handler base.EventHandler) error {
+	if l.baseListener.Paused() {
+		return nil
+	}
 	l.mu.Lock()
 	defer l.mu.Unlock()
-	return ProcessCurrentBlockRangeCommon(ctx, handler, l.provider, l.config, &l.lastProcessedBlock, "Starknet", l.processBlockRange)
+	if err := ProcessCurrentBlockRangeCommon(ctx, handler, l.provider, l.config, &l.lastProcessedBlock, "Starknet", l.logger, l.processBlockRange); err != nil {
+		return err
+	}
+	l.baseListener.markPolled()
+	return nil
 }
 
 // processBlockRange processes events in [fromBlock, toBlock] and returns the highest contiguous block fully processed
 func (l *starknetListener) processBlockRange(ctx context.Context, fromBlock, toBlock uint64, handler base.EventHandler) (uint64, error) {
 	if fromBlock > toBlock {
-		fmt.Printf("⚠️  Invalid block range (%s) in processBlockRange: fromBlock (%d) > toBlock (%d), skipping\n", l.config.ChainName, fromBlock, toBlock)
+		l.logger.Warn(fmt.Sprintf("⚠️  Invalid block range in processBlockRange: fromBlock (%d) > toBlock (%d), skipping", fromBlock, toBlock))
 		return l.lastProcessedBlock, nil
 	}
 
@@ -161,7 +217,7 @@ func (l *starknetListener) processBlockRange(ctx context.Context, fromBlock, toB
 			Tag:    "",
 		},
 		Address: l.contractAddress,
-		Keys:    [][]*felt.Felt{{openEventSelector}},
+		Keys:    [][]*felt.Felt{l.eventSelectors()},
 	}
 
 	query := rpc.EventsInput{
@@ -171,12 +227,13 @@ func (l *starknetListener) processBlockRange(ctx context.Context, fromBlock, toB
 
 	logs, err := l.provider.Events(ctx, query)
 	if err != nil {
-		return l.lastProcessedBlock, fmt.Errorf("failed to filter events: %w", err)
+		return l.lastProcessedBlock, failure.Mark(fmt.Errorf("failed to filter events: %w", err), failure.ErrNetwork)
 	}
 
-	logutil.LogWithNetworkTagf(l.config.ChainName, "📩 events found: %d\n", len(logs.Events))
+	l.logger.Debug(fmt.Sprintf("📩 events found: %d", len(logs.Events)))
+	metrics.EventsSeen(l.config.ChainName, protocolLabel, len(logs.Events))
 	if len(logs.Events) > 0 {
-		fmt.Printf("📩 Found %d Open events on %s\n", len(logs.Events), l.config.ChainName)
+		l.logger.Info(fmt.Sprintf("📩 Found %d events", len(logs.Events)))
 	}
 
 	// Group logs by block
@@ -192,32 +249,37 @@ func (l *starknetListener) processBlockRange(ctx context.Context, fromBlock, toB
 
 		// Process each event in this block
 		for _, event := range events {
-			// Ensure each event is the correct type
-			isOpen := false
-			if len(event.Event.Keys) >= 1 {
-				actual := event.Event.Keys[0].Bytes()
-				expected := openEventSelector.Bytes()
-				if bytes.Equal(actual[:], expected[:]) {
-					isOpen = true
+			// Filled events only go to the fill observer
+			if hasSelector(event, filledEventSelector) {
+				orderID, fillerData, err := decodeFilledEvent(event.Event.Data)
+				if err != nil {
+					l.logger.Error("❌ Failed to parse Filled event", logutil.Block(b), logutil.Err(err))
+					continue
 				}
+				l.baseListener.observeFill(ctx, FilledEvent{OrderID: orderID, ChainName: l.config.ChainName, Block: b, FillerData: fillerData})
+				continue
 			}
-			if !isOpen {
+			// Ensure each event is the correct type
+			if !hasSelector(event, openEventSelector) {
 				continue
 			}
 
 			// Parse Open event
-			ro := decodeResolvedOrderFromFelts(event.Event.Data)
-			parsedArgs := types.ParsedArgs{
-				OrderID:       common.BytesToHash(ro.OrderID[:]).Hex(),
-				SenderAddress: ro.User,
-				Recipients:    []types.Recipient{{DestinationChainName: l.config.ChainName, RecipientAddress: "*"}},
-				ResolvedOrder: ro,
+			parsedArgs := l.openOrderArgs(event.Event.Data)
+
+			// Handle the event; the order is traced from its detection
+			fields := []any{logutil.OrderID(parsedArgs.OrderID), logutil.Block(b)}
+			attrs := []attribute.KeyValue{tracing.Chain(l.config.ChainName), tracing.Block(b)}
+			if event.TransactionHash != nil {
+				fields = append(fields, logutil.TxHash(event.TransactionHash.String()))
+				attrs = append(attrs, tracing.TxHash(event.TransactionHash.String()))
 			}
-
-			// Handle the event
-			_, herr := handler(parsedArgs, l.config.ChainName, b)
+			eventCtx, span := tracing.StartOrderSpan(ctx, tracing.SpanOrderDetected, parsedArgs.OrderID, attrs...)
+			_, herr := l.baseListener.handleIntent(eventCtx, handler, parsedArgs, b)
+			tracing.End(span, herr)
 			if herr != nil {
-				fmt.Printf("❌ Failed to handle event: %v\n", herr)
+				fields = append(fields, logutil.Err(herr))
+				l.logger.Error("❌ Failed to handle event", fields...)
 				continue
 			}
 		}
@@ -226,18 +288,107 @@ func (l *starknetListener) processBlockRange(ctx context.Context, fromBlock, toB
 		newLast = b
 		// Only log individual blocks if there are events
 		if len(events) > 0 {
-			logutil.LogWithNetworkTagf(l.config.ChainName, "   ✅ Block %d processed: %d events\n", b, len(events))
+			l.logger.Info(fmt.Sprintf("   ✅ Block %d processed: %d events", b, len(events)), logutil.Block(b))
 		}
 	}
 
 	return newLast, nil
 }
 
+// openOrderArgs decodes the order of an Open event
+func (l *starknetListener) openOrderArgs(data []*felt.Felt) types.ParsedArgs {
+	ro := decodeResolvedOrderFromFelts(data)
+	return types.ParsedArgs{
+		OrderID:       common.BytesToHash(ro.OrderID[:]).Hex(),
+		SenderAddress: ro.User,
+		Recipients:    []types.Recipient{{DestinationChainName: l.config.ChainName, RecipientAddress: "*"}},
+		ResolvedOrder: ro,
+	}
+}
+
+// openOrders reads the orders opened in [fromBlock, toBlock] without handing them to the solver
+func (l *starknetListener) openOrders(ctx context.Context, fromBlock, toBlock uint64) ([]types.ParsedArgs, error) {
+	query := rpc.EventsInput{
+		EventFilter: rpc.EventFilter{
+			FromBlock: rpc.BlockID{Number: &fromBlock, Hash: nil, Tag: ""},
+			ToBlock:   rpc.BlockID{Number: &toBlock, Hash: nil, Tag: ""},
+			Address:   l.contractAddress,
+			Keys:      [][]*felt.Felt{{openEventSelector}},
+		},
+		ResultPageRequest: rpc.ResultPageRequest{ChunkSize: 128, ContinuationToken: ""},
+	}
+
+	var orders []types.ParsedArgs
+	for {
+		page, err := l.provider.Events(ctx, query)
+		if err != nil {
+			return nil, failure.Mark(fmt.Errorf("failed to filter events: %w", err), failure.ErrNetwork)
+		}
+		for _, event := range page.Events {
+			if hasSelector(event, openEventSelector) {
+				orders = append(orders, l.openOrderArgs(event.Event.Data))
+			}
+		}
+		if page.ContinuationToken == "" {
+			return orders, nil
+		}
+		query.ContinuationToken = page.ContinuationToken
+	}
+}
+
+// eventSelectors returns the event keys to fetch: Open events, and Filled events while fills are watched
+func (l *starknetListener) eventSelectors() []*felt.Felt {
+	if l.baseListener.watchingFills() {
+		return []*felt.Felt{openEventSelector, filledEventSelector}
+	}
+	return []*felt.Felt{openEventSelector}
+}
+
+// hasSelector reports whether the event's first key is selector
+func hasSelector(event rpc.EmittedEvent, selector *felt.Felt) bool {
+	if len(event.Event.Keys) == 0 {
+		return false
+	}
+	actual := event.Event.Keys[0].Bytes()
+	expected := selector.Bytes()
+	return bytes.Equal(actual[:], expected[:])
+}
+
 // --- Decoders ---
 
+// decodeFilledEvent reads the order ID and filler data of a Filled event: order_id, origin_data and filler_data
+func decodeFilledEvent(data []*felt.Felt) (string, []byte, error) {
+	if len(data) < 2 {
+		return "", nil, fmt.Errorf("filled event has %d felts, want at least 2", len(data))
+	}
+	decoder := newFeltDecoder(data)
+	orderID := decoder.readU256()
+	if _, err := decoder.readBytes(); err != nil {
+		return "", nil, fmt.Errorf("origin data: %w", err)
+	}
+	fillerData, err := decoder.readBytes()
+	if err != nil {
+		return "", nil, fmt.Errorf("filler data: %w", err)
+	}
+	return common.BigToHash(orderID).Hex(), fillerData, nil
+}
+
+// decodeFilledOrder reads the filler data of a FilledOrder returned by filled_orders: origin_data and filler_data
+func decodeFilledOrder(data []*felt.Felt) ([]byte, error) {
+	decoder := newFeltDecoder(data)
+	if _, err := decoder.readBytes(); err != nil {
+		return nil, fmt.Errorf("origin data: %w", err)
+	}
+	fillerData, err := decoder.readBytes()
+	if err != nil {
+		return nil, fmt.Errorf("filler data: %w", err)
+	}
+	return fillerData, nil
+}
+
 func decodeResolvedOrderFromFelts(data []*felt.Felt) types.ResolvedCrossChainOrder {
 	decoder := newFeltDecoder(data)
-	
+
 	ro := types.ResolvedCrossChainOrder{
 		User:             "",
 		OriginChainID:    nil,
@@ -297,6 +448,21 @@ func (d *feltDecoder) readU256() *big.Int {
 	return new(big.Int).Add(low, new(big.Int).Lsh(high, 128))
 }
 
+// readBytes reads a Cairo Bytes: its size in bytes, its number of u128 words and the words
+func (d *feltDecoder) readBytes() ([]byte, error) {
+	if len(d.data)-d.idx < 2 {
+		return nil, fmt.Errorf("bytes header truncated at felt %d", d.idx)
+	}
+	size := d.readU64()
+	length := d.readU64()
+	if length > uint64(len(d.data)-d.idx) {
+		return nil, fmt.Errorf("bytes of %d words truncated at felt %d", length, d.idx)
+	}
+	words := d.data[d.idx : d.idx+int(length)]
+	d.idx += int(length)
+	return starknetutil.U128FeltsToBytes(words, size), nil
+}
+
 func (d *feltDecoder) readAddress() string {
 	feltBytes := d.readFelt().Bytes()
 	// Convert to slice to handle consistently
@@ -312,12 +478,12 @@ func (d *feltDecoder) readAddress() string {
 
 func (d *feltDecoder) readOutput() types.Output {
 	out := types.Output{
-		Token:    "",
-		Amount:   nil,
+		Token:     "",
+		Amount:    nil,
 		Recipient: "",
-		ChainID:  nil,
+		ChainID:   nil,
 	}
-	out.Token = d.readAddress()
+	out.Token = types.CanonicalToken(d.readAddress())
 	out.Amount = d.readU256()
 	out.Recipient = d.readAddress()
 	chainDomain := d.readU32()
@@ -325,7 +491,7 @@ func (d *feltDecoder) readOutput() types.Output {
 	if chainID, err := domainToChainID(chainDomain); err == nil {
 		out.ChainID = chainID
 	} else {
-		fmt.Printf("   ⚠️  Warning: Could not map domain %d to chain ID for output, using domain as chain ID\n", chainDomain)
+		logutil.Default().Warn(fmt.Sprintf("   ⚠️  Warning: Could not map domain %d to chain ID for output, using domain as chain ID", chainDomain))
 		out.ChainID = new(big.Int).SetUint64(uint64(chainDomain))
 	}
 	return out
@@ -342,16 +508,16 @@ func (d *feltDecoder) readOutputs() []types.Output {
 
 func (d *feltDecoder) readFillInstruction() types.FillInstruction {
 	fi := types.FillInstruction{
-		DestinationChainID:  nil,
-		DestinationSettler:  "",
-		OriginData:          nil,
+		DestinationChainID: nil,
+		DestinationSettler: "",
+		OriginData:         nil,
 	}
 	destinationDomain := d.readU32()
 	// Map destination domain to actual chain ID using config
 	if chainID, err := domainToChainID(destinationDomain); err == nil {
 		fi.DestinationChainID = chainID
 	} else {
-		fmt.Printf("   ⚠️  Warning: Could not map domain %d to chain ID, using domain as chain ID\n", destinationDomain)
+		logutil.Default().Warn(fmt.Sprintf("   ⚠️  Warning: Could not map domain %d to chain ID, using domain as chain ID", destinationDomain))
 		fi.DestinationChainID = new(big.Int).SetUint64(uint64(destinationDomain))
 	}
 	fi.DestinationSettler = d.readAddress()
@@ -408,7 +574,7 @@ func (d *feltDecoder) parseOriginData() []byte {
 	dataSize[31] = 0x00
 	evmOriginData = append(evmOriginData, dataSize...)
 	if len(evmOriginData) != evmOriginDataSize {
-		fmt.Printf("   ⚠️  origin_data unexpected length: %d\n", len(evmOriginData))
+		logutil.Default().Warn(fmt.Sprintf("   ⚠️  origin_data unexpected length: %d", len(evmOriginData)))
 	}
 	return evmOriginData
 }
@@ -5,8 +5,15 @@ import (
 	"math/big"
 	"testing"
 
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum/common"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/base"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 // TestStarknetListener tests the Starknet listener functionality
@@ -17,7 +24,7 @@ func TestStarknetListener(t *testing.T) {
 			InitialBlock:    big.NewInt(1000),
 		}
 
-		_, err := NewStarknetListener(config, "invalid-rpc-url")
+		_, err := NewStarknetListener(config, "invalid-rpc-url", logutil.Default())
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "failed to connect Starknet RPC")
 	})
@@ -28,7 +35,7 @@ func TestStarknetListener(t *testing.T) {
 			InitialBlock:    big.NewInt(1000),
 		}
 
-		_, err := NewStarknetListener(config, "http://localhost:5050")
+		_, err := NewStarknetListener(config, "http://localhost:5050", logutil.Default())
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "invalid Starknet contract address")
 	})
@@ -96,7 +103,7 @@ func TestStarknetListenerErrorHandling(t *testing.T) {
 			InitialBlock:    big.NewInt(1000),
 		}
 
-		_, err := NewStarknetListener(config, "http://nonexistent:5050")
+		_, err := NewStarknetListener(config, "http://nonexistent:5050", logutil.Default())
 		assert.Error(t, err)
 	})
 
@@ -106,7 +113,7 @@ func TestStarknetListenerErrorHandling(t *testing.T) {
 			InitialBlock:    big.NewInt(1000),
 		}
 
-		_, err := NewStarknetListener(config, "http://localhost:5050")
+		_, err := NewStarknetListener(config, "http://localhost:5050", logutil.Default())
 		assert.Error(t, err)
 		assert.Contains(t, err.Error(), "invalid Starknet contract address")
 	})
@@ -126,7 +133,7 @@ func TestStarknetListenerConcurrency(t *testing.T) {
 
 		for i := 0; i < 5; i++ {
 			go func(index int) {
-				listener, err := NewStarknetListener(config, "http://localhost:5050")
+				listener, err := NewStarknetListener(config, "http://localhost:5050", logutil.Default())
 				listeners[index] = listener
 				errors[index] = err
 			}(i)
@@ -185,3 +192,34 @@ func (m *mockStarknetListener) Stop() error {
 func (m *mockStarknetListener) GetLastProcessedBlock() uint64 {
 	return m.lastProcessedBlock
 }
+
+// cairoBytes serializes b as a Cairo Bytes: size, number of u128 words, words
+func cairoBytes(b []byte) []*felt.Felt {
+	words := starknetutil.BytesToU128Felts(b)
+	return append([]*felt.Felt{utils.Uint64ToFelt(uint64(len(b))), utils.Uint64ToFelt(uint64(len(words)))}, words...)
+}
+
+func TestDecodeFilledEvent(t *testing.T) {
+	orderID := "0x00000000000000000000000000000001000000000000000000000000000000ff"
+	low, high, err := starknetutil.ConvertSolidityOrderIDForStarknet(orderID)
+	require.NoError(t, err)
+	filler := common.LeftPadBytes(common.FromHex("0x2222"), 32)
+
+	data := []*felt.Felt{low, high}
+	data = append(data, cairoBytes(make([]byte, 40))...)
+	data = append(data, cairoBytes(filler)...)
+	gotID, gotFiller, err := decodeFilledEvent(data)
+	require.NoError(t, err)
+	assert.Equal(t, orderID, gotID)
+	assert.Equal(t, filler, gotFiller)
+
+	// filled_orders returns the same FilledOrder without the order ID
+	gotFiller, err = decodeFilledOrder(data[2:])
+	require.NoError(t, err)
+	assert.Equal(t, filler, gotFiller)
+
+	_, _, err = decodeFilledEvent(data[:len(data)-1])
+	require.Error(t, err, "truncated filler data")
+	_, err = decodeFilledOrder(nil)
+	require.Error(t, err)
+}
@@ -0,0 +1,120 @@
+package hyperlane7683
+
+// Module: Per-leg progress for multi-instruction orders
+// - Records which fill instructions (legs) of an order are filled and settled
+// - Lets a retried order skip legs that already went through
+// - An order only counts as complete once every leg is filled and settled
+
+import (
+	"sync"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// LegStatus is the progress of one fill instruction
+type LegStatus struct {
+	DestinationChainID uint64 `json:"destinationChainId"`
+	Filled             bool   `json:"filled"`
+	Settled            bool   `json:"settled"`
+}
+
+// OrderProgress is the progress of every fill instruction of an order, in instruction order
+type OrderProgress struct {
+	Legs []LegStatus
+}
+
+// Complete reports whether every leg has been filled and settled
+func (p OrderProgress) Complete() bool {
+	for _, leg := range p.Legs {
+		if !leg.Filled || !leg.Settled {
+			return false
+		}
+	}
+	return len(p.Legs) > 0
+}
+
+// anyFilled reports whether at least one leg has been filled
+func (p OrderProgress) anyFilled() bool {
+	for _, leg := range p.Legs {
+		if leg.Filled {
+			return true
+		}
+	}
+	return false
+}
+
+// legTracker holds OrderProgress per order ID; the zero value is ready to use
+type legTracker struct {
+	mu     sync.Mutex
+	orders map[string]*OrderProgress
+}
+
+// start returns the order's progress, creating it when the order is new or its legs changed
+func (t *legTracker) start(args *types.ParsedArgs) OrderProgress {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if t.orders == nil {
+		t.orders = make(map[string]*OrderProgress)
+	}
+
+	instructions := args.ResolvedOrder.FillInstructions
+	progress, ok := t.orders[args.OrderID]
+	if !ok || len(progress.Legs) != len(instructions) {
+		progress = &OrderProgress{Legs: make([]LegStatus, len(instructions))}
+		for i, instruction := range instructions {
+			if instruction.DestinationChainID != nil {
+				progress.Legs[i].DestinationChainID = instruction.DestinationChainID.Uint64()
+			}
+		}
+		t.orders[args.OrderID] = progress
+	}
+	return copyProgress(progress)
+}
+
+// markFilled records that leg has been filled
+func (t *legTracker) markFilled(orderID string, leg int) {
+	t.update(orderID, leg, func(status *LegStatus) {
+		status.Filled = true
+	})
+}
+
+// markSettled records that leg has been settled, which implies it was filled
+func (t *legTracker) markSettled(orderID string, leg int) {
+	t.update(orderID, leg, func(status *LegStatus) {
+		status.Filled = true
+		status.Settled = true
+	})
+}
+
+func (t *legTracker) update(orderID string, leg int, apply func(*LegStatus)) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if progress, ok := t.orders[orderID]; ok && leg < len(progress.Legs) {
+		apply(&progress.Legs[leg])
+	}
+}
+
+// get returns a copy of the order's progress
+func (t *legTracker) get(orderID string) (OrderProgress, bool) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	progress, ok := t.orders[orderID]
+	if !ok {
+		return OrderProgress{}, false
+	}
+	return copyProgress(progress), true
+}
+
+// forget drops the order once it no longer needs tracking
+func (t *legTracker) forget(orderID string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	delete(t.orders, orderID)
+}
+
+func copyProgress(progress *OrderProgress) OrderProgress {
+	return OrderProgress{Legs: append([]LegStatus(nil), progress.Legs...)}
+}
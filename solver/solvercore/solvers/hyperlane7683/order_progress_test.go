@@ -0,0 +1,117 @@
+package hyperlane7683
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// legHandler is a ChainHandler for one chain that records the legs it is asked to fill and settle
+type legHandler struct {
+	fills     []string
+	settles   []string
+	fillErr   error
+	settleErr error
+	statuses  map[string]string
+}
+
+func (h *legHandler) Fill(_ context.Context, args *types.ParsedArgs) (OrderAction, error) {
+	if h.fillErr != nil {
+		return OrderActionError, h.fillErr
+	}
+	h.fills = append(h.fills, args.ResolvedOrder.FillInstructions[0].DestinationSettler)
+	return OrderActionFilled, nil
+}
+
+func (h *legHandler) Settle(_ context.Context, args *types.ParsedArgs) error {
+	h.settles = append(h.settles, args.ResolvedOrder.FillInstructions[0].DestinationSettler)
+	return h.settleErr
+}
+
+func (h *legHandler) GetOrderStatus(_ context.Context, args *types.ParsedArgs) (string, error) {
+	if status, ok := h.statuses[args.ResolvedOrder.FillInstructions[0].DestinationSettler]; ok {
+		return status, nil
+	}
+	return orderStatusFilled, nil
+}
+
+func multiLegOrder() *types.ParsedArgs {
+	return &types.ParsedArgs{
+		OrderID: "0x0123456789abcdef",
+		ResolvedOrder: types.ResolvedCrossChainOrder{
+			OriginChainID: new(big.Int).SetUint64(config.Networks["Ethereum"].ChainID),
+			FillInstructions: []types.FillInstruction{
+				{DestinationChainID: new(big.Int).SetUint64(config.Networks["Base"].ChainID), DestinationSettler: "base-leg"},
+				{DestinationChainID: new(big.Int).SetUint64(config.Networks["Optimism"].ChainID), DestinationSettler: "op-leg"},
+			},
+		},
+	}
+}
+
+func newMultiLegSolver(base, optimism *legHandler) *Hyperlane7683Solver {
+	config.InitializeNetworks()
+	solver := NewHyperlane7683Solver(nil, nil, nil, nil, types.AllowBlockLists{}, nil)
+	solver.evmHandlers[config.Networks["Base"].ChainID] = base
+	solver.evmHandlers[config.Networks["Optimism"].ChainID] = optimism
+	return solver
+}
+
+func TestFillAndSettleEveryLeg(t *testing.T) {
+	base := &legHandler{}
+	optimism := &legHandler{fillErr: errors.New("insufficient allowance")}
+	solver := newMultiLegSolver(base, optimism)
+	ctx := context.Background()
+	order := multiLegOrder()
+
+	// The second leg fails; the first stays filled
+	_, err := solver.Fill(ctx, order)
+	require.Error(t, err)
+	progress, ok := solver.OrderProgress(order.OrderID)
+	require.True(t, ok)
+	assert.True(t, progress.Legs[0].Filled)
+	assert.False(t, progress.Legs[1].Filled)
+	assert.False(t, progress.Complete())
+
+	// A retry only fills the missing leg
+	optimism.fillErr = nil
+	action, err := solver.Fill(ctx, order)
+	require.NoError(t, err)
+	assert.Equal(t, OrderActionSettle, action)
+	assert.Equal(t, []string{"base-leg"}, base.fills)
+	assert.Equal(t, []string{"op-leg"}, optimism.fills)
+
+	// Each leg settles on its own chain; one settled earlier is not settled again
+	optimism.statuses = map[string]string{"op-leg": orderStatusSettled}
+	require.NoError(t, solver.SettleOrder(ctx, order))
+	assert.Equal(t, []string{"base-leg"}, base.settles)
+	assert.Empty(t, optimism.settles)
+
+	progress, _ = solver.OrderProgress(order.OrderID)
+	assert.True(t, progress.Complete())
+}
+
+func TestProcessIntentForgetsCompletedOrders(t *testing.T) {
+	solver := newMultiLegSolver(&legHandler{}, &legHandler{})
+	solver.rulesEngine = &RulesEngine{rules: []Rule{}}
+	order := multiLegOrder()
+
+	ok, err := solver.ProcessIntent(context.Background(), order)
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	_, tracked := solver.OrderProgress(order.OrderID)
+	assert.False(t, tracked)
+}
+
+func TestOrderProgressComplete(t *testing.T) {
+	assert.False(t, OrderProgress{}.Complete())
+	assert.False(t, OrderProgress{Legs: []LegStatus{{Filled: true, Settled: true}, {Filled: true}}}.Complete())
+	assert.True(t, OrderProgress{Legs: []LegStatus{{Filled: true, Settled: true}}}.Complete())
+}
@@ -0,0 +1,243 @@
+package hyperlane7683
+
+// Module: Quotes for prospective Hyperlane7683 orders
+// - Prices a route (origin/destination chain, input/output token, input amount) before any order exists
+// - Output is the input minus the solver's margin (QUOTE_MARGIN_BPS); the rules engine runs in dry-run mode on it
+// - The destination's gas cost is reported in its native token (the fee token on Starknet), and also taken out of
+//   the output when that is native; other outputs are not converted until a price source exists
+// - Refuses routes whose tokens use different decimals until a price source exists
+// - Quotes are valid for QUOTE_VALIDITY_MS
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// Defaults used when the corresponding environment variables are unset
+const (
+	// DefaultQuoteMarginBps is the solver's margin on the input amount, in basis points
+	DefaultQuoteMarginBps = 10
+	DefaultQuoteValidity  = time.Minute
+
+	basisPoints = 10_000
+	// quoteOrderID labels the synthetic order quotes are evaluated on; logs expect a hash-length ID
+	quoteOrderID = "0x0000000000000000000000000000000000000000000000000000000000000000"
+)
+
+// ErrInvalidQuoteRequest marks quote requests that cannot be priced as given
+var ErrInvalidQuoteRequest = failure.Mark(errors.New("invalid quote request"), failure.ErrValidation)
+
+// QuoteConfig controls how prospective orders are priced
+type QuoteConfig struct {
+	MarginBps uint64
+	Validity  time.Duration
+}
+
+// QuoteConfigFromEnv reads QUOTE_MARGIN_BPS and QUOTE_VALIDITY_MS
+func QuoteConfigFromEnv() QuoteConfig {
+	return QuoteConfig{
+		MarginBps: envutil.GetEnvUint64("QUOTE_MARGIN_BPS", DefaultQuoteMarginBps),
+		Validity: time.Duration(envutil.GetEnvUint64("QUOTE_VALIDITY_MS",
+			uint64(DefaultQuoteValidity.Milliseconds()))) * time.Millisecond,
+	}
+}
+
+// QuoteRequest describes an order the caller is considering opening
+type QuoteRequest struct {
+	OriginChainID      uint64   `json:"originChainId"`
+	DestinationChainID uint64   `json:"destinationChainId"`
+	InputToken         string   `json:"inputToken"`
+	OutputToken        string   `json:"outputToken"`
+	AmountIn           *big.Int `json:"amountIn"`
+}
+
+// Quote is the solver's price for a QuoteRequest
+type Quote struct {
+	Accepted bool     `json:"accepted"`
+	AmountIn *big.Int `json:"amountIn"`
+	// AmountOut is the minimum output (the order's amountOut) the solver would accept to fill for AmountIn
+	AmountOut *big.Int `json:"amountOut"`
+	// EstimatedGasCost is the cost of filling and settling on the destination, when it can be estimated, in its native
+	// token: wei on EVM chains, FRI on Starknet. It is already taken out of AmountOut only for native outputs.
+	EstimatedGasCost *big.Int `json:"estimatedGasCost,omitempty"`
+	ValidUntil       int64    `json:"validUntil"`
+	// Reasons lists why the solver would refuse the order; empty when accepted
+	Reasons []string `json:"reasons"`
+}
+
+// SetQuoteConfig sets how quotes are priced
+func (f *Hyperlane7683Solver) SetQuoteConfig(cfg QuoteConfig) {
+	f.quoteConfig = cfg
+}
+
+// Quote prices a prospective order without reserving anything.
+// A refusal is a quote with reasons; ErrInvalidQuoteRequest is returned only for requests that cannot be priced.
+func (f *Hyperlane7683Solver) Quote(ctx context.Context, req *QuoteRequest) (*Quote, error) {
+	args, err := f.quoteArgs(req)
+	if err != nil {
+		return nil, err
+	}
+	amountOut := args.ResolvedOrder.MaxSpent[0].Amount
+
+	reasons := make([]string, 0)
+	decimalsReason, err := f.quoteDecimals(ctx, args, req)
+	if err != nil {
+		return nil, err
+	}
+	if decimalsReason != "" {
+		reasons = append(reasons, decimalsReason)
+	}
+
+	// A native output pays for the destination gas, so the order's rules are evaluated on what is left of it
+	gasCost, gasReason, err := f.quoteGasCost(ctx, args, req)
+	if err != nil {
+		return nil, err
+	}
+	if gasCost != nil && gasReason == "" && types.IsNativeToken(req.OutputToken) {
+		amountOut.Sub(amountOut, gasCost)
+	}
+
+	if amountOut.Sign() <= 0 {
+		reasons = append(reasons, fmt.Sprintf("amount %s is too small to cover the solver margin and gas", req.AmountIn))
+	}
+	reasons = append(reasons, f.rulesEngine.DryRun(ctx, args)...)
+	if gasReason != "" {
+		reasons = append(reasons, gasReason)
+	}
+
+	return &Quote{
+		Accepted:         len(reasons) == 0,
+		AmountIn:         req.AmountIn,
+		AmountOut:        amountOut,
+		EstimatedGasCost: gasCost,
+		ValidUntil:       time.Now().Add(f.quoteConfig.Validity).Unix(),
+		Reasons:          reasons,
+	}, nil
+}
+
+// quoteGasCost estimates the native cost of filling and settling on the destination.
+// When the solver cannot pay for the gas, the reason the quote is refused is returned.
+func (f *Hyperlane7683Solver) quoteGasCost(ctx context.Context, args *types.ParsedArgs, req *QuoteRequest) (*big.Int, string, error) {
+	destinationChainID := new(big.Int).SetUint64(req.DestinationChainID)
+	var gasCost *big.Int
+	_, err := f.executeChainOperation(ctx, args, destinationChainID, "quote", func(ctx context.Context, handler ChainHandler) (OrderAction, error) {
+		estimator, ok := handler.(FillCostEstimator)
+		if !ok {
+			return OrderActionComplete, nil
+		}
+		var err error
+		gasCost, err = estimator.EstimateFillCost(ctx)
+		return OrderActionComplete, err
+	})
+	switch {
+	case errors.Is(err, ErrOrderRejected):
+		return gasCost, err.Error(), nil
+	case err != nil:
+		return nil, "", err
+	}
+	return gasCost, "", nil
+}
+
+// quoteDecimals returns why the quote is refused when the input and output tokens use different decimals.
+// The output is priced 1:1 against the input, which is only meaningful for tokens of the same precision.
+func (f *Hyperlane7683Solver) quoteDecimals(ctx context.Context, args *types.ParsedArgs, req *QuoteRequest) (string, error) {
+	inputDecimals, err := f.tokenDecimals(ctx, args, new(big.Int).SetUint64(req.OriginChainID), req.InputToken)
+	if err != nil {
+		return "", err
+	}
+	outputDecimals, err := f.tokenDecimals(ctx, args, new(big.Int).SetUint64(req.DestinationChainID), req.OutputToken)
+	if err != nil {
+		return "", err
+	}
+	switch {
+	case inputDecimals < 0 || outputDecimals < 0:
+		return "token decimals cannot be read on this route", nil
+	case inputDecimals != outputDecimals:
+		return fmt.Sprintf("input token has %d decimals and output token %d; quoting across decimals needs a price source",
+			inputDecimals, outputDecimals), nil
+	}
+	return "", nil
+}
+
+// tokenDecimals reads the decimals of token on chainID, or -1 when the chain's handler cannot read them
+func (f *Hyperlane7683Solver) tokenDecimals(ctx context.Context, args *types.ParsedArgs, chainID *big.Int, token string) (int, error) {
+	decimals := -1
+	_, err := f.executeChainOperation(ctx, args, chainID, "quote_decimals", func(ctx context.Context, handler ChainHandler) (OrderAction, error) {
+		reader, ok := handler.(TokenDecimalsReader)
+		if !ok {
+			return OrderActionComplete, nil
+		}
+		value, err := reader.TokenDecimals(ctx, token)
+		decimals = int(value)
+		return OrderActionComplete, err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return decimals, nil
+}
+
+// quoteArgs builds the single-instruction order a quote is evaluated on: the input minus the margin as output
+func (f *Hyperlane7683Solver) quoteArgs(req *QuoteRequest) (*types.ParsedArgs, error) {
+	if req.AmountIn == nil || req.AmountIn.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: amountIn must be positive", ErrInvalidQuoteRequest)
+	}
+	if req.InputToken == "" || req.OutputToken == "" {
+		return nil, fmt.Errorf("%w: inputToken and outputToken are required", ErrInvalidQuoteRequest)
+	}
+	originChainID := new(big.Int).SetUint64(req.OriginChainID)
+	destinationChainID := new(big.Int).SetUint64(req.DestinationChainID)
+	if _, err := f.originSettler(originChainID); err != nil {
+		return nil, fmt.Errorf("%w: unsupported origin chain %d: %w", ErrInvalidQuoteRequest, req.OriginChainID, err)
+	}
+	destinationSettler, err := f.originSettler(destinationChainID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: unsupported destination chain %d: %w", ErrInvalidQuoteRequest, req.DestinationChainID, err)
+	}
+
+	// Round the margin up so the solver always keeps something, as the profitability rule requires
+	margin := new(big.Int).Mul(req.AmountIn, new(big.Int).SetUint64(f.quoteConfig.MarginBps))
+	margin.Add(margin, big.NewInt(basisPoints-1))
+	margin.Div(margin, big.NewInt(basisPoints))
+	if margin.Sign() == 0 {
+		margin.SetInt64(1)
+	}
+	amountOut := new(big.Int).Sub(req.AmountIn, margin)
+
+	return &types.ParsedArgs{
+		OrderID:       quoteOrderID,
+		SenderAddress: "",
+		Recipients:    []types.Recipient{},
+		ResolvedOrder: types.ResolvedCrossChainOrder{
+			User:          "",
+			OriginChainID: originChainID,
+			OpenDeadline:  0,
+			FillDeadline:  0,
+			OrderID:       [32]byte{},
+			MaxSpent: []types.Output{{
+				Token:     types.CanonicalToken(req.OutputToken),
+				Amount:    amountOut,
+				Recipient: "",
+				ChainID:   destinationChainID,
+			}},
+			MinReceived: []types.Output{{
+				Token:     types.CanonicalToken(req.InputToken),
+				Amount:    new(big.Int).Set(req.AmountIn),
+				Recipient: "",
+				ChainID:   originChainID,
+			}},
+			FillInstructions: []types.FillInstruction{{
+				DestinationChainID: destinationChainID,
+				DestinationSettler: destinationSettler,
+				OriginData:         nil,
+			}},
+		},
+	}, nil
+}
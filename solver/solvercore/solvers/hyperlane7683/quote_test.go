@@ -0,0 +1,186 @@
+package hyperlane7683
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// estimatingHandler is a legHandler that also prices fills and reads token decimals, 18 unless listed in decimals
+type estimatingHandler struct {
+	legHandler
+	cost     *big.Int
+	err      error
+	decimals map[string]uint8
+}
+
+func (h *estimatingHandler) EstimateFillCost(context.Context) (*big.Int, error) {
+	return h.cost, h.err
+}
+
+func (h *estimatingHandler) TokenDecimals(_ context.Context, token string) (uint8, error) {
+	if decimals, ok := h.decimals[token]; ok {
+		return decimals, nil
+	}
+	return 18, nil
+}
+
+// failingRule always fails with reason
+type failingRule struct {
+	name   string
+	reason string
+}
+
+func (r *failingRule) Name() string { return r.name }
+
+func (r *failingRule) Evaluate(context.Context, *types.ParsedArgs) RuleResult {
+	return RuleResult{Passed: false, Reason: r.reason}
+}
+
+func newQuoteSolver(handler ChainHandler) *Hyperlane7683Solver {
+	config.InitializeNetworks()
+	solver := NewHyperlane7683Solver(nil, nil, nil, nil, types.AllowBlockLists{}, nil)
+	// No inventory is attached, so only the profitability rule applies
+	solver.rulesEngine = &RulesEngine{rules: []Rule{&ProfitabilityRule{}}}
+	solver.evmHandlers[config.Networks["Ethereum"].ChainID] = handler
+	solver.evmHandlers[config.Networks["Optimism"].ChainID] = handler
+	return solver
+}
+
+func quoteRequest(amountIn int64) *QuoteRequest {
+	return &QuoteRequest{
+		OriginChainID:      config.Networks["Ethereum"].ChainID,
+		DestinationChainID: config.Networks["Optimism"].ChainID,
+		InputToken:         "0x76878654a2D96dDdF8cF0CFe8FA608aB4CE0D499",
+		OutputToken:        "0xB844EEd1581f3fB810FFb6Dd6C5E30C049cF23F4",
+		AmountIn:           big.NewInt(amountIn),
+	}
+}
+
+func TestQuote(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("accepted with margin and gas estimate", func(t *testing.T) {
+		solver := newQuoteSolver(&estimatingHandler{cost: big.NewInt(42)})
+		solver.SetQuoteConfig(QuoteConfig{MarginBps: 10, Validity: time.Minute})
+
+		req := quoteRequest(1_000_000)
+		req.OutputToken = types.NativeToken
+		quote, err := solver.Quote(ctx, req)
+		require.NoError(t, err)
+		assert.True(t, quote.Accepted)
+		assert.Empty(t, quote.Reasons)
+		assert.Equal(t, int64(999_000-42), quote.AmountOut.Int64(), "the gas cost is paid from the native output")
+		assert.Equal(t, big.NewInt(42), quote.EstimatedGasCost)
+		assert.InDelta(t, time.Now().Add(time.Minute).Unix(), quote.ValidUntil, 2)
+	})
+
+	t.Run("ERC20 route to an EVM destination reports the gas cost beside the output", func(t *testing.T) {
+		solver := newQuoteSolver(&estimatingHandler{cost: big.NewInt(42)})
+		quote, err := solver.Quote(ctx, quoteRequest(1_000_000))
+		require.NoError(t, err)
+		assert.True(t, quote.Accepted)
+		assert.Empty(t, quote.Reasons)
+		assert.Equal(t, int64(999_000), quote.AmountOut.Int64(), "the gas cost is not converted into the ERC20 output")
+		assert.Equal(t, big.NewInt(42), quote.EstimatedGasCost)
+	})
+
+	t.Run("route to Starknet prices the gas in FRI", func(t *testing.T) {
+		t.Setenv("STARKNET_HYPERLANE_ADDRESS", "0x2369427e2142db4dfac3a61f5ea7f084e3a74f4c444b5c4e6192a12e49a349")
+		node := &starknetNode{results: map[string]any{
+			"starknet_getBlockWithTxHashes": map[string]any{
+				"status": "ACCEPTED_ON_L2", "block_hash": "0x1", "parent_hash": "0x0", "block_number": 1, "new_root": "0x0",
+				"timestamp": 1, "sequencer_address": "0x0", "starknet_version": "0.14.0", "l1_da_mode": "BLOB",
+				"l1_gas_price":      map[string]string{"price_in_fri": "0x1", "price_in_wei": "0x1"},
+				"l2_gas_price":      map[string]string{"price_in_fri": "0x2", "price_in_wei": "0x1"},
+				"l1_data_gas_price": map[string]string{"price_in_fri": "0x3", "price_in_wei": "0x1"},
+				"transactions":      []string{},
+			},
+			"balanceOf": []string{"0xde0b6b3a7640000", "0x0"},
+			"decimals":  []string{"0x12"},
+		}}
+		solver := newQuoteSolver(&estimatingHandler{})
+		solver.hyperlaneStarknet = &HyperlaneStarknet{provider: newStarknetProvider(t, node),
+			solverAddr: new(felt.Felt).SetUint64(2), logger: logutil.Default()}
+
+		req := quoteRequest(1_000_000_000)
+		req.DestinationChainID = config.Networks["Starknet"].ChainID
+		req.OutputToken = types.NativeToken
+		quote, err := solver.Quote(ctx, req)
+		require.NoError(t, err)
+		assert.True(t, quote.Accepted, quote.Reasons)
+		gasCost := int64(2*(starknetFillL2GasEstimate+starknetSettleL2GasEstimate) + 3*starknetL1DataGasEstimate)
+		assert.Equal(t, big.NewInt(gasCost), quote.EstimatedGasCost)
+		assert.Equal(t, 999_000_000-gasCost, quote.AmountOut.Int64(), "a native output pays the gas in the fee token")
+	})
+
+	t.Run("tokens with different decimals are refused", func(t *testing.T) {
+		req := quoteRequest(1_000_000)
+		solver := newQuoteSolver(&estimatingHandler{decimals: map[string]uint8{req.OutputToken: 6}})
+		quote, err := solver.Quote(ctx, req)
+		require.NoError(t, err)
+		assert.False(t, quote.Accepted)
+		require.Len(t, quote.Reasons, 1)
+		assert.Contains(t, quote.Reasons[0], "input token has 18 decimals and output token 6")
+	})
+
+	t.Run("routes without decimals are refused", func(t *testing.T) {
+		quote, err := newQuoteSolver(&legHandler{}).Quote(ctx, quoteRequest(1_000_000))
+		require.NoError(t, err)
+		assert.False(t, quote.Accepted)
+		assert.Equal(t, []string{"token decimals cannot be read on this route"}, quote.Reasons)
+	})
+
+	t.Run("margin rounds up to keep a profit", func(t *testing.T) {
+		solver := newQuoteSolver(&estimatingHandler{})
+		quote, err := solver.Quote(ctx, quoteRequest(1001))
+		require.NoError(t, err)
+		assert.True(t, quote.Accepted)
+		assert.Equal(t, int64(999), quote.AmountOut.Int64(), "10 bps of 1001 rounds up to 2")
+		assert.Nil(t, quote.EstimatedGasCost, "handlers without an estimator report no cost")
+	})
+
+	t.Run("refusal collects every reason", func(t *testing.T) {
+		handler := &estimatingHandler{
+			cost: big.NewInt(42),
+			err:  fmt.Errorf("%w: insufficient native balance for gas", ErrOrderRejected),
+		}
+		solver := newQuoteSolver(handler)
+		solver.rulesEngine.AddRule(&failingRule{name: "Paused", reason: "route paused"})
+
+		quote, err := solver.Quote(ctx, quoteRequest(1))
+		require.NoError(t, err)
+		assert.False(t, quote.Accepted)
+		assert.Zero(t, quote.AmountOut.Sign())
+		require.Len(t, quote.Reasons, 3)
+		assert.Contains(t, quote.Reasons[0], "too small to cover the solver margin")
+		assert.Equal(t, "Paused: route paused", quote.Reasons[1])
+		assert.Contains(t, quote.Reasons[2], "insufficient native balance")
+		assert.Equal(t, big.NewInt(42), quote.EstimatedGasCost)
+	})
+
+	t.Run("invalid requests", func(t *testing.T) {
+		solver := newQuoteSolver(&estimatingHandler{})
+
+		for name, mutate := range map[string]func(*QuoteRequest){
+			"zero amount":         func(r *QuoteRequest) { r.AmountIn = big.NewInt(0) },
+			"missing token":       func(r *QuoteRequest) { r.OutputToken = "" },
+			"unknown destination": func(r *QuoteRequest) { r.DestinationChainID = 1 },
+		} {
+			req := quoteRequest(1000)
+			mutate(req)
+			_, err := solver.Quote(ctx, req)
+			require.ErrorIs(t, err, ErrInvalidQuoteRequest, name)
+		}
+	})
+}
@@ -0,0 +1,359 @@
+package hyperlane7683
+
+// Module: Refund watcher for Hyperlane7683
+// - Watches orders opened by configured accounts (REFUND_WATCH_ACCOUNTS)
+// - After a restart, orders still open on the origin are found again by scanning the blocks the listeners
+//   processed before it
+// - Once an order's FillDeadline passes without a fill, calls refund on its destination chain
+// - Groups refunds per (destination chain, destination settler, origin domain) and isolates orders that break a group
+// - Tracks each refund until the origin marks the order REFUNDED, re-sending it after REFUND_RETRY_AFTER_MS
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// Defaults used when the corresponding environment variables are unset
+const (
+	DefaultRefundCheckInterval = 30 * time.Second
+	// DefaultRefundRetryAfter is how long a sent refund may take to reach the origin before it is sent again
+	DefaultRefundRetryAfter = 15 * time.Minute
+
+	// refundStatusRetention is how long refunded/filled statuses stay queryable
+	refundStatusRetention = time.Hour
+)
+
+// RefundState is the lifecycle of a watched order
+type RefundState string
+
+const (
+	RefundWatching RefundState = "WATCHING"
+	RefundSent     RefundState = "REFUND_SENT"
+	RefundRefunded RefundState = "REFUNDED"
+	// RefundFilled means the order was filled after all and needs no refund
+	RefundFilled RefundState = "FILLED"
+)
+
+// RefundStatus reports where a watched order is in the refund flow
+type RefundStatus struct {
+	State RefundState
+	// Attempts counts refund transactions sent for the order
+	Attempts  int
+	Error     string
+	UpdatedAt time.Time
+}
+
+// RefundConfig controls which orders are watched and how often they are checked
+type RefundConfig struct {
+	// Accounts whose orders are refunded once expired; empty disables the watcher
+	Accounts      []string
+	CheckInterval time.Duration
+	RetryAfter    time.Duration
+}
+
+// RefundConfigFromEnv builds a config from REFUND_WATCH_ACCOUNTS, REFUND_CHECK_INTERVAL_MS and REFUND_RETRY_AFTER_MS
+func RefundConfigFromEnv() RefundConfig {
+	var accounts []string
+	for _, account := range strings.Split(envutil.GetEnvWithDefault("REFUND_WATCH_ACCOUNTS", ""), ",") {
+		if account = strings.TrimSpace(account); account != "" {
+			accounts = append(accounts, account)
+		}
+	}
+	return RefundConfig{
+		Accounts: accounts,
+		CheckInterval: time.Duration(envutil.GetEnvUint64("REFUND_CHECK_INTERVAL_MS",
+			uint64(DefaultRefundCheckInterval.Milliseconds()))) * time.Millisecond,
+		RetryAfter: time.Duration(envutil.GetEnvUint64("REFUND_RETRY_AFTER_MS",
+			uint64(DefaultRefundRetryAfter.Milliseconds()))) * time.Millisecond,
+	}
+}
+
+// Enabled reports whether any account is watched
+func (c RefundConfig) Enabled() bool {
+	return len(c.Accounts) > 0
+}
+
+// OpenOrderObserver is told about the orders a listener finds opened on its settler
+type OpenOrderObserver func(ctx context.Context, args *types.ParsedArgs)
+
+// OpenOrderScanner is implemented by listeners that can re-read the orders opened on their settler before the
+// block they resumed from, which they do not hand to the solver again
+type OpenOrderScanner interface {
+	ScanOpenOrders(ctx context.Context, observe OpenOrderObserver) error
+}
+
+// orderStatusFunc reads an order's status from one side of the route
+type orderStatusFunc func(ctx context.Context, args *types.ParsedArgs) (string, error)
+
+type watchedOrder struct {
+	args   *types.ParsedArgs
+	status *RefundStatus
+	sentAt time.Time
+}
+
+// RefundWatcher refunds expired, unfilled orders opened by watched accounts
+type RefundWatcher struct {
+	mu       sync.Mutex
+	cfg      RefundConfig
+	accounts map[string]bool
+	orders   map[string]*watchedOrder
+	// destinationStatus reads the fill status on the destination, originStatus the order status on the origin
+	destinationStatus orderStatusFunc
+	originStatus      orderStatusFunc
+	refund            settleFunc
+	now               func() time.Time
+	logger            logutil.Logger
+}
+
+// NewRefundWatcher creates a watcher that refunds through refund and reads statuses through the given funcs
+func NewRefundWatcher(cfg RefundConfig, destinationStatus, originStatus orderStatusFunc, refund settleFunc) *RefundWatcher {
+	if cfg.CheckInterval <= 0 {
+		cfg.CheckInterval = DefaultRefundCheckInterval
+	}
+	if cfg.RetryAfter <= 0 {
+		cfg.RetryAfter = DefaultRefundRetryAfter
+	}
+	accounts := make(map[string]bool, len(cfg.Accounts))
+	for _, account := range cfg.Accounts {
+		accounts[normalizeAddress(account)] = true
+	}
+	return &RefundWatcher{
+		mu:                sync.Mutex{},
+		cfg:               cfg,
+		accounts:          accounts,
+		orders:            make(map[string]*watchedOrder),
+		destinationStatus: destinationStatus,
+		originStatus:      originStatus,
+		refund:            refund,
+		now:               time.Now,
+		logger:            logutil.Default(),
+	}
+}
+
+// Start checks watched orders every CheckInterval until ctx is cancelled
+func (w *RefundWatcher) Start(ctx context.Context) {
+	ticker := time.NewTicker(w.cfg.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			w.Check(ctx)
+		}
+	}
+}
+
+// Observe starts watching args if it was opened by a watched account
+func (w *RefundWatcher) Observe(args *types.ParsedArgs) bool {
+	if !w.accounts[normalizeAddress(args.SenderAddress)] {
+		return false
+	}
+	w.Watch(args)
+	return true
+}
+
+// Restore starts watching args, found by a scan of its origin settler after a restart, if it was opened by a
+// watched account and the origin still shows it open. An order whose origin status cannot be read is watched,
+// as Check reads both sides again before refunding it.
+func (w *RefundWatcher) Restore(ctx context.Context, args *types.ParsedArgs) bool {
+	if !w.accounts[normalizeAddress(args.SenderAddress)] {
+		return false
+	}
+	if status, err := w.originStatus(ctx, args); err == nil && status != orderStatusOpened {
+		return false
+	}
+	w.Watch(args)
+	return true
+}
+
+// Watch starts watching args regardless of its sender; orders already watched are left as they are
+func (w *RefundWatcher) Watch(args *types.ParsedArgs) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if _, ok := w.orders[args.OrderID]; ok {
+		return
+	}
+	w.orders[args.OrderID] = &watchedOrder{
+		args:   args,
+		status: &RefundStatus{State: RefundWatching, Attempts: 0, Error: "", UpdatedAt: w.now()},
+		sentAt: time.Time{},
+	}
+	w.logger.Info(fmt.Sprintf("⏳ Watching order for refund after fill deadline %d", args.ResolvedOrder.FillDeadline),
+		logutil.OrderID(args.OrderID), logutil.Stage("refund"))
+}
+
+// Status returns the refund status of a watched order
+func (w *RefundWatcher) Status(orderID string) (RefundStatus, bool) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	order, ok := w.orders[orderID]
+	if !ok {
+		return RefundStatus{}, false
+	}
+	return *order.status, true
+}
+
+// Check refunds every expired, unfilled order and follows up on refunds already sent
+func (w *RefundWatcher) Check(ctx context.Context) {
+	now := w.now()
+	var expired, sent []*types.ParsedArgs
+
+	w.mu.Lock()
+	for orderID, order := range w.orders {
+		switch order.status.State {
+		case RefundWatching:
+			if now.Unix() > int64(order.args.ResolvedOrder.FillDeadline) {
+				expired = append(expired, order.args)
+			}
+		case RefundSent:
+			sent = append(sent, order.args)
+		case RefundRefunded, RefundFilled:
+			if now.Sub(order.status.UpdatedAt) > refundStatusRetention {
+				delete(w.orders, orderID)
+			}
+		}
+	}
+	w.mu.Unlock()
+
+	groups := make(map[settlementKey][]*types.ParsedArgs)
+	for _, args := range expired {
+		status, err := w.destinationStatus(ctx, args)
+		switch {
+		case err != nil:
+			w.setStatus(args.OrderID, RefundWatching, fmt.Errorf("failed to read destination status: %w", err))
+		case status == orderStatusFilled || status == orderStatusSettled:
+			w.logger.Info("✅ Order was filled before its deadline, no refund needed", logutil.OrderID(args.OrderID), logutil.Stage("refund"))
+			w.setStatus(args.OrderID, RefundFilled, nil)
+		case status == orderStatusUnknown:
+			// Skip orders the sender already got refunded some other way
+			if origin, err := w.originStatus(ctx, args); err == nil && origin == orderStatusRefunded {
+				w.setStatus(args.OrderID, RefundRefunded, nil)
+				continue
+			}
+			key, err := settlementKeyFor(args)
+			if err != nil {
+				w.setStatus(args.OrderID, RefundWatching, err)
+				continue
+			}
+			groups[key] = append(groups[key], args)
+		default:
+			w.setStatus(args.OrderID, RefundWatching, fmt.Errorf("unexpected destination status %s", status))
+		}
+	}
+
+	for _, args := range sent {
+		status, err := w.originStatus(ctx, args)
+		if err != nil {
+			w.setStatus(args.OrderID, RefundSent, fmt.Errorf("failed to read origin status: %w", err))
+			continue
+		}
+		if status == orderStatusRefunded {
+			w.logger.Info("💸 Order refunded on the origin chain", logutil.OrderID(args.OrderID), logutil.Stage("refund"))
+			w.setStatus(args.OrderID, RefundRefunded, nil)
+			continue
+		}
+		if w.retryDue(args.OrderID, now) {
+			// The refund message never arrived; send it again once the destination still shows the order unfilled
+			w.setStatus(args.OrderID, RefundWatching, fmt.Errorf("refund not delivered after %s", w.cfg.RetryAfter))
+		}
+	}
+
+	for key, orders := range groups {
+		w.sendRefund(ctx, key, orders)
+	}
+}
+
+// sendRefund refunds orders in one call, falling back to one call per order when the group fails
+func (w *RefundWatcher) sendRefund(ctx context.Context, key settlementKey, orders []*types.ParsedArgs) {
+	w.logger.Info(fmt.Sprintf("💸 Refunding %d expired order(s) (%s)", len(orders), key), logutil.Stage("refund"))
+	err := w.refund(ctx, key, orders)
+	if err == nil || len(orders) == 1 {
+		w.finishRefund(orders, err)
+		return
+	}
+
+	// Isolate the order(s) that broke the group
+	w.logger.Warn("⚠️  Group refund failed, refunding orders individually", logutil.Stage("refund"), logutil.Err(err))
+	for _, order := range orders {
+		w.finishRefund([]*types.ParsedArgs{order}, w.refund(ctx, key, []*types.ParsedArgs{order}))
+	}
+}
+
+// finishRefund records a refund attempt; failed orders stay watched and are retried on the next check
+func (w *RefundWatcher) finishRefund(orders []*types.ParsedArgs, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	for _, args := range orders {
+		order, ok := w.orders[args.OrderID]
+		if !ok {
+			continue
+		}
+		order.status.Attempts++
+		if err != nil {
+			w.logger.Error("❌ Refund of order failed", logutil.OrderID(args.OrderID), logutil.Stage("refund"), logutil.Err(err))
+			w.setStatusLocked(order, RefundWatching, err)
+			continue
+		}
+		order.sentAt = w.now()
+		w.setStatusLocked(order, RefundSent, nil)
+	}
+}
+
+func (w *RefundWatcher) retryDue(orderID string, now time.Time) bool {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	order, ok := w.orders[orderID]
+	return ok && !order.sentAt.IsZero() && now.Sub(order.sentAt) >= w.cfg.RetryAfter
+}
+
+func (w *RefundWatcher) setStatus(orderID string, state RefundState, err error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if order, ok := w.orders[orderID]; ok {
+		w.setStatusLocked(order, state, err)
+	}
+}
+
+func (w *RefundWatcher) setStatusLocked(order *watchedOrder, state RefundState, err error) {
+	order.status.State = state
+	order.status.Error = ""
+	if err != nil {
+		order.status.Error = err.Error()
+	}
+	order.status.UpdatedAt = w.now()
+}
+
+// refundableOrder rebuilds the OnchainCrossChainOrder the destination expects in refund(), checking the
+// order has expired. The destination derives the order ID from it, so it must match what was opened.
+func refundableOrder(args *types.ParsedArgs) (contracts.OnchainCrossChainOrder, error) {
+	if len(args.ResolvedOrder.FillInstructions) != 1 {
+		return contracts.OnchainCrossChainOrder{}, fmt.Errorf("refund requires exactly one fill instruction, got %d",
+			len(args.ResolvedOrder.FillInstructions))
+	}
+	orderData := args.ResolvedOrder.FillInstructions[0].OriginData
+	if len(orderData) == 0 {
+		return contracts.OnchainCrossChainOrder{}, fmt.Errorf("order %s has no order data", args.OrderID)
+	}
+	if time.Now().Unix() <= int64(args.ResolvedOrder.FillDeadline) {
+		return contracts.OnchainCrossChainOrder{}, fmt.Errorf("order %s has not expired (fill deadline %d)",
+			args.OrderID, args.ResolvedOrder.FillDeadline)
+	}
+	return contracts.OnchainCrossChainOrder{
+		FillDeadline:  args.ResolvedOrder.FillDeadline,
+		OrderDataType: orderutil.OrderDataTypeHash(),
+		OrderData:     orderData,
+	}, nil
+}
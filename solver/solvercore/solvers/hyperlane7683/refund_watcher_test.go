@@ -0,0 +1,198 @@
+package hyperlane7683
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/orderutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+const watchedAccount = "0x000000000000000000000000aaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaaa"
+
+// fakeRefundChains serves destination/origin statuses per order and records refund calls
+type fakeRefundChains struct {
+	destination map[string]string
+	origin      map[string]string
+	failing     map[string]bool
+	// unreadable orders fail their origin status read
+	unreadable map[string]bool
+	refunds    [][]string
+}
+
+func (c *fakeRefundChains) destinationStatus(_ context.Context, args *types.ParsedArgs) (string, error) {
+	if status, ok := c.destination[args.OrderID]; ok {
+		return status, nil
+	}
+	return orderStatusUnknown, nil
+}
+
+func (c *fakeRefundChains) originStatus(_ context.Context, args *types.ParsedArgs) (string, error) {
+	if c.unreadable[args.OrderID] {
+		return orderStatusUnknown, errors.New("rpc unavailable")
+	}
+	if status, ok := c.origin[args.OrderID]; ok {
+		return status, nil
+	}
+	return orderStatusOpened, nil
+}
+
+func (c *fakeRefundChains) refund(_ context.Context, _ settlementKey, orders []*types.ParsedArgs) error {
+	ids := make([]string, len(orders))
+	for i, order := range orders {
+		ids[i] = order.OrderID
+	}
+	c.refunds = append(c.refunds, ids)
+	for _, id := range ids {
+		if c.failing[id] {
+			return errors.New("order fill not expired")
+		}
+	}
+	return nil
+}
+
+func newTestRefundWatcher(chains *fakeRefundChains, now *time.Time) *RefundWatcher {
+	w := NewRefundWatcher(RefundConfig{Accounts: []string{"0xAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAAA"}, CheckInterval: time.Second,
+		RetryAfter: 10 * time.Minute}, chains.destinationStatus, chains.originStatus, chains.refund)
+	w.now = func() time.Time { return *now }
+	return w
+}
+
+func expiringOrder(orderID string, deadline time.Time) *types.ParsedArgs {
+	order := batchOrder(orderID, "Base", "Optimism")
+	order.SenderAddress = watchedAccount
+	order.ResolvedOrder.FillDeadline = uint32(deadline.Unix())
+	return order
+}
+
+func TestRefundWatcherRefundsExpiredOrders(t *testing.T) {
+	chains := &fakeRefundChains{destination: map[string]string{"0x02": orderStatusFilled}, origin: map[string]string{}}
+	now := time.Unix(1_700_000_000, 0)
+	w := newTestRefundWatcher(chains, &now)
+	ctx := context.Background()
+
+	other := expiringOrder("0x04", now)
+	other.SenderAddress = "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	assert.False(t, w.Observe(other), "orders from other accounts are not watched")
+	for _, id := range []string{"0x01", "0x02", "0x03"} {
+		require.True(t, w.Observe(expiringOrder(id, now.Add(time.Minute))))
+	}
+
+	// Nothing happens before the deadline
+	w.Check(ctx)
+	assert.Empty(t, chains.refunds)
+
+	// Expired unfilled orders on the same route share one refund; filled ones are dropped
+	now = now.Add(2 * time.Minute)
+	w.Check(ctx)
+	require.Len(t, chains.refunds, 1)
+	assert.ElementsMatch(t, []string{"0x01", "0x03"}, chains.refunds[0])
+	filled, _ := w.Status("0x02")
+	assert.Equal(t, RefundFilled, filled.State)
+	sent, _ := w.Status("0x01")
+	assert.Equal(t, RefundSent, sent.State)
+	assert.Equal(t, 1, sent.Attempts)
+
+	// The refund is tracked until the origin releases the funds
+	chains.origin["0x01"] = orderStatusRefunded
+	w.Check(ctx)
+	refunded, _ := w.Status("0x01")
+	assert.Equal(t, RefundRefunded, refunded.State)
+	pending, _ := w.Status("0x03")
+	assert.Equal(t, RefundSent, pending.State)
+
+	// A refund that never arrives is sent again
+	now = now.Add(10 * time.Minute)
+	w.Check(ctx)
+	w.Check(ctx)
+	require.Len(t, chains.refunds, 2)
+	assert.Equal(t, []string{"0x03"}, chains.refunds[1])
+	retried, _ := w.Status("0x03")
+	assert.Equal(t, 2, retried.Attempts)
+}
+
+func TestRefundWatcherIsolatesFailingOrders(t *testing.T) {
+	chains := &fakeRefundChains{origin: map[string]string{"0x03": orderStatusRefunded}, failing: map[string]bool{"0x02": true}}
+	now := time.Unix(1_700_000_000, 0)
+	w := newTestRefundWatcher(chains, &now)
+
+	for _, id := range []string{"0x01", "0x02", "0x03"} {
+		w.Watch(expiringOrder(id, now.Add(-time.Minute)))
+	}
+	w.Check(context.Background())
+
+	// The group fails, then each order is refunded on its own
+	require.Len(t, chains.refunds, 3)
+	assert.ElementsMatch(t, []string{"0x01", "0x02"}, chains.refunds[0])
+
+	ok, _ := w.Status("0x01")
+	assert.Equal(t, RefundSent, ok.State)
+	failed, _ := w.Status("0x02")
+	assert.Equal(t, RefundWatching, failed.State)
+	assert.Contains(t, failed.Error, "order fill not expired")
+
+	// Already refunded on the origin, so no refund is sent
+	alreadyRefunded, _ := w.Status("0x03")
+	assert.Equal(t, RefundRefunded, alreadyRefunded.State)
+}
+
+func TestRefundWatcherRestoresOpenOrders(t *testing.T) {
+	chains := &fakeRefundChains{
+		origin:     map[string]string{"0x02": orderStatusRefunded, "0x03": orderStatusFilled},
+		unreadable: map[string]bool{"0x04": true},
+	}
+	now := time.Unix(1_700_000_000, 0)
+	w := newTestRefundWatcher(chains, &now)
+	ctx := context.Background()
+
+	other := expiringOrder("0x05", now)
+	other.SenderAddress = "0xbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbbb"
+	assert.False(t, w.Restore(ctx, other), "orders from other accounts are not watched")
+	assert.True(t, w.Restore(ctx, expiringOrder("0x01", now)))
+	assert.False(t, w.Restore(ctx, expiringOrder("0x02", now)), "already refunded")
+	assert.False(t, w.Restore(ctx, expiringOrder("0x03", now)), "no longer open on the origin")
+	assert.True(t, w.Restore(ctx, expiringOrder("0x04", now)), "checked again before any refund")
+
+	for id, watched := range map[string]bool{"0x01": true, "0x02": false, "0x03": false, "0x04": true, "0x05": false} {
+		status, ok := w.Status(id)
+		assert.Equal(t, watched, ok, id)
+		if ok {
+			assert.Equal(t, RefundWatching, status.State, id)
+		}
+	}
+}
+
+func TestRefundableOrder(t *testing.T) {
+	order := expiringOrder("0x01", time.Now().Add(-time.Minute))
+	order.ResolvedOrder.FillInstructions[0].OriginData = []byte{0x01, 0x02}
+
+	onchainOrder, err := refundableOrder(order)
+	require.NoError(t, err)
+	assert.Equal(t, order.ResolvedOrder.FillDeadline, onchainOrder.FillDeadline)
+	assert.Equal(t, orderutil.OrderDataTypeHash(), onchainOrder.OrderDataType)
+	assert.Equal(t, []byte{0x01, 0x02}, onchainOrder.OrderData)
+
+	order.ResolvedOrder.FillDeadline = uint32(time.Now().Add(time.Hour).Unix())
+	_, err = refundableOrder(order)
+	assert.ErrorContains(t, err, "has not expired")
+
+	order.ResolvedOrder.FillInstructions[0].OriginData = nil
+	_, err = refundableOrder(order)
+	assert.ErrorContains(t, err, "no order data")
+}
+
+func TestRefundConfigFromEnv(t *testing.T) {
+	t.Setenv("REFUND_WATCH_ACCOUNTS", " 0xabc, ,0xdef ")
+	t.Setenv("REFUND_CHECK_INTERVAL_MS", "5000")
+
+	cfg := RefundConfigFromEnv()
+	assert.True(t, cfg.Enabled())
+	assert.Equal(t, []string{"0xabc", "0xdef"}, cfg.Accounts)
+	assert.Equal(t, 5*time.Second, cfg.CheckInterval)
+	assert.Equal(t, DefaultRefundRetryAfter, cfg.RetryAfter)
+}
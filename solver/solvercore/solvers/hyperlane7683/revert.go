@@ -0,0 +1,184 @@
+package hyperlane7683
+
+// Module: Revert decoding for pre-flight simulations
+// - EVM revert data is decoded against the Hyperlane7683 custom errors, the OpenZeppelin ERC20 errors and Error(string)/Panic(uint256)
+// - Starknet execution errors are matched against the short-string errors of the Cairo settler and ERC20 tokens
+// - Decoded reverts are *RevertError values wrapping failure.ErrReverted and the failure class of the error, so retries
+//   can tell permanent failures apart
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/txmanager"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// ErrInvalidOrderStatus: the order is not in the status the call needs, e.g. it was already filled
+var ErrInvalidOrderStatus = errors.New("invalid order status")
+
+// revertClasses maps decoded error names to the sentinel they are classified under
+var revertClasses = map[string]error{
+	"InvalidOrderStatus":         ErrInvalidOrderStatus,
+	"OrderFillExpired":           failure.ErrDeadlinePassed,
+	"OrderOpenExpired":           failure.ErrDeadlinePassed,
+	"InvalidNativeAmount":        failure.ErrInsufficientFunds,
+	"ERC20InsufficientBalance":   failure.ErrInsufficientFunds,
+	"ERC20InsufficientAllowance": failure.ErrInsufficientFunds,
+	"InvalidOrderId":             failure.ErrValidation,
+	"InvalidOrderDomain":         failure.ErrValidation,
+	"InvalidOrderOrigin":         failure.ErrValidation,
+	"InvalidOrderType":           failure.ErrValidation,
+	"InvalidOriginDomain":        failure.ErrValidation,
+	"InvalidDomain":              failure.ErrValidation,
+	"InvalidSender":              failure.ErrValidation,
+	"InvalidNonce":               failure.ErrValidation,
+	"InvalidGaslessOrderOrigin":  failure.ErrValidation,
+	"InvalidGaslessOrderSettler": failure.ErrValidation,
+}
+
+// revertMessages maps revert strings, from the Cairo settler and from string reverts of ERC20 tokens,
+// to the equivalent error name; they are matched case-insensitively anywhere in the node's message
+var revertMessages = []struct{ message, name string }{
+	{"Invalid order status", "InvalidOrderStatus"},
+	{"Order fill expired", "OrderFillExpired"},
+	{"Order open expired", "OrderOpenExpired"},
+	{"Order fill not expired", "OrderFillNotExpired"},
+	{"Invalid native amount", "InvalidNativeAmount"},
+	{"Invalid order ID", "InvalidOrderId"},
+	{"Invalid order domain", "InvalidOrderDomain"},
+	{"Invalid order type", "InvalidOrderType"},
+	{"Invalid origin domain", "InvalidOriginDomain"},
+	{"Invalid sender", "InvalidSender"},
+	{"Invalid nonce", "InvalidNonce"},
+	{"Invalid gasless order settler", "InvalidGaslessOrderSettler"},
+	{"Invalid gasless order origin", "InvalidGaslessOrderOrigin"},
+	{"insufficient allowance", "ERC20InsufficientAllowance"},
+	{"exceeds allowance", "ERC20InsufficientAllowance"},
+	{"insufficient balance", "ERC20InsufficientBalance"},
+	{"exceeds balance", "ERC20InsufficientBalance"},
+}
+
+// erc20ErrorsABI holds the OpenZeppelin v5 ERC20 custom errors a fill's token transfer can revert with
+const erc20ErrorsABI = `[
+	{"type": "error", "name": "ERC20InsufficientBalance", "inputs": [
+		{"name": "sender", "type": "address"}, {"name": "balance", "type": "uint256"}, {"name": "needed", "type": "uint256"}]},
+	{"type": "error", "name": "ERC20InsufficientAllowance", "inputs": [
+		{"name": "spender", "type": "address"}, {"name": "allowance", "type": "uint256"}, {"name": "needed", "type": "uint256"}]}
+]`
+
+// revertErrors returns the custom errors EVM revert data is decoded against
+var revertErrors = sync.OnceValue(func() []abi.Error {
+	var errs []abi.Error
+	for _, raw := range []string{contracts.Hyperlane7683MetaData.ABI, erc20ErrorsABI} {
+		parsed, err := abi.JSON(strings.NewReader(raw))
+		if err != nil {
+			panic(fmt.Sprintf("invalid revert error ABI: %v", err))
+		}
+		for _, abiErr := range parsed.Errors {
+			errs = append(errs, abiErr)
+		}
+	}
+	return errs
+})
+
+// RevertError is a transaction a pre-flight simulation showed would revert
+type RevertError struct {
+	Operation string
+	ChainID   uint64
+	// Call is the reverting call, e.g. fill or approve(0x…)
+	Call string
+	// Name is the decoded error, e.g. InvalidOrderStatus; empty when the revert could not be decoded
+	Name string
+	// Reason is the decoded error with its arguments, or the node's message when it could not be decoded
+	Reason string
+	// Data is the raw EVM revert data, if any
+	Data []byte
+}
+
+func newRevertError(operation string, chainID uint64, call string, sim *txmanager.Simulation) *RevertError {
+	name, reason := decodeRevert(sim.RevertData, sim.Reason)
+	return &RevertError{Operation: operation, ChainID: chainID, Call: call, Name: name, Reason: reason, Data: sim.RevertData}
+}
+
+func (e *RevertError) Error() string {
+	return fmt.Sprintf("%s on %s would revert in %s: %s", e.Operation, logutil.NetworkNameByChainID(e.ChainID), e.Call, e.Reason)
+}
+
+// Unwrap returns failure.ErrReverted and, for known errors, their class (failure.ErrDeadlinePassed, ...).
+// A fill failing with InvalidOrderStatus was filled since the solver checked it, possibly by the solver itself;
+// lostRace reads the recorded filler to tell.
+func (e *RevertError) Unwrap() []error {
+	errs := []error{failure.ErrReverted}
+	if class, ok := revertClasses[e.Name]; ok {
+		errs = append(errs, class)
+	}
+	return errs
+}
+
+// Permanent reports whether sending the call again cannot succeed: the order's status, deadline or identity is wrong
+func (e *RevertError) Permanent() bool {
+	return errors.Is(e, ErrInvalidOrderStatus) || errors.Is(e, failure.ErrDeadlinePassed) || errors.Is(e, failure.ErrValidation)
+}
+
+// decodeRevert names a revert from its EVM data or, failing that, from the node's message
+func decodeRevert(data []byte, message string) (string, string) {
+	if len(data) >= 4 {
+		if reason, err := abi.UnpackRevert(data); err == nil {
+			return revertNameFromMessage(reason), reason
+		}
+		for _, abiErr := range revertErrors() {
+			if !bytes.Equal(data[:4], abiErr.ID[:4]) {
+				continue
+			}
+			args, err := abiErr.Unpack(data)
+			if err != nil {
+				return abiErr.Name, abiErr.Name
+			}
+			values, _ := args.([]interface{})
+			return abiErr.Name, formatRevert(abiErr.Name, values)
+		}
+	}
+
+	if name := revertNameFromMessage(message); name != "" {
+		return name, message
+	}
+	return "", message
+}
+
+// revertNameFromMessage returns the error name whose revert string appears in message, if any
+func revertNameFromMessage(message string) string {
+	lower := strings.ToLower(message)
+	for _, known := range revertMessages {
+		if strings.Contains(lower, strings.ToLower(known.message)) {
+			return known.name
+		}
+	}
+	return ""
+}
+
+// formatRevert renders a decoded custom error as Name(arg, ...)
+func formatRevert(name string, values []interface{}) string {
+	args := make([]string, len(values))
+	for i, value := range values {
+		switch v := value.(type) {
+		case [32]byte:
+			args[i] = common.Hash(v).Hex()
+		case common.Address:
+			args[i] = v.Hex()
+		case *big.Int:
+			args[i] = v.String()
+		default:
+			args[i] = fmt.Sprint(v)
+		}
+	}
+	return fmt.Sprintf("%s(%s)", name, strings.Join(args, ", "))
+}
@@ -0,0 +1,111 @@
+package hyperlane7683
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/contracts"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/txmanager"
+)
+
+// revertData encodes the custom error name of parsed with args, as a node returns it
+func revertData(t *testing.T, parsed *abi.ABI, name string, args ...interface{}) []byte {
+	t.Helper()
+	abiErr, ok := parsed.Errors[name]
+	require.True(t, ok, "unknown error %s", name)
+	packed, err := abiErr.Inputs.Pack(args...)
+	require.NoError(t, err)
+	return append(abiErr.ID.Bytes()[:4], packed...)
+}
+
+func TestDecodeRevertCustomErrors(t *testing.T) {
+	parsed, err := contracts.Hyperlane7683MetaData.GetAbi()
+	require.NoError(t, err)
+
+	name, reason := decodeRevert(revertData(t, parsed, "InvalidOrderStatus"), "execution reverted")
+	assert.Equal(t, "InvalidOrderStatus", name)
+	assert.Equal(t, "InvalidOrderStatus()", reason)
+
+	name, reason = decodeRevert(revertData(t, parsed, "InvalidOriginDomain", uint32(8453)), "execution reverted")
+	assert.Equal(t, "InvalidOriginDomain", name)
+	assert.Equal(t, "InvalidOriginDomain(8453)", reason)
+}
+
+func TestDecodeRevertERC20(t *testing.T) {
+	parsed, err := abi.JSON(strings.NewReader(erc20ErrorsABI))
+	require.NoError(t, err)
+	spender := common.HexToAddress("0x0000000000000000000000000000000000000abc")
+	data := revertData(t, &parsed, "ERC20InsufficientAllowance", spender, big.NewInt(1), big.NewInt(100))
+
+	name, reason := decodeRevert(data, "execution reverted")
+	assert.Equal(t, "ERC20InsufficientAllowance", name)
+	assert.Equal(t, fmt.Sprintf("ERC20InsufficientAllowance(%s, 1, 100)", spender.Hex()), reason)
+
+	// Older tokens revert with Error(string)
+	stringType, err := abi.NewType("string", "", nil)
+	require.NoError(t, err)
+	message, err := abi.Arguments{{Name: "", Type: stringType, Indexed: false}}.Pack("ERC20: insufficient allowance")
+	require.NoError(t, err)
+	stringRevert := append(common.FromHex("0x08c379a0"), message...)
+	name, reason = decodeRevert(stringRevert, "execution reverted")
+	assert.Equal(t, "ERC20InsufficientAllowance", name)
+	assert.Equal(t, "ERC20: insufficient allowance", reason)
+}
+
+func TestDecodeRevertStarknetMessage(t *testing.T) {
+	name, reason := decodeRevert(nil, "Transaction execution has failed: ... ('Invalid order status')")
+	assert.Equal(t, "InvalidOrderStatus", name)
+	assert.Contains(t, reason, "Invalid order status")
+
+	name, reason = decodeRevert(nil, "out of gas")
+	assert.Empty(t, name)
+	assert.Equal(t, "out of gas", reason)
+}
+
+func TestRevertErrorClassification(t *testing.T) {
+	newRevert := func(reason string) *RevertError {
+		return newRevertError("fill", 8453, "fill", &txmanager.Simulation{Reverted: true, Reason: reason, RevertData: nil,
+			Gas: 0, Fee: nil, Unit: txmanager.UnitWei})
+	}
+
+	filled := newRevert("Invalid order status")
+	assert.ErrorIs(t, filled, failure.ErrReverted)
+	assert.ErrorIs(t, filled, ErrInvalidOrderStatus)
+	assert.True(t, filled.Permanent())
+	assert.Equal(t, failure.KindRevert, failure.KindOf(fmt.Errorf("fill failed: %w", filled)), "the filler decides whether a race was lost")
+	assert.NotErrorIs(t, filled, failure.ErrCompetitorFilled)
+	assert.True(t, failure.Permanent(fmt.Errorf("fill failed: %w", filled)))
+
+	broke := newRevert("ERC20: transfer amount exceeds balance")
+	assert.Equal(t, failure.KindInsufficientFunds, failure.KindOf(broke))
+	assert.False(t, broke.Permanent())
+
+	unknown := newRevert("out of gas")
+	assert.Equal(t, failure.KindRevert, failure.KindOf(unknown))
+	assert.False(t, failure.Permanent(unknown))
+	assert.Contains(t, unknown.Error(), "fill on")
+
+	// A settle reverting on the order status is permanent too
+	settle := newRevertError("settle", 8453, "settle", &txmanager.Simulation{Reverted: true, Reason: "Invalid order status",
+		RevertData: nil, Gas: 0, Fee: nil, Unit: txmanager.UnitWei})
+	assert.Equal(t, failure.KindRevert, failure.KindOf(settle))
+	assert.True(t, failure.Permanent(settle))
+}
+
+func TestSimulationDecodesReverts(t *testing.T) {
+	sim := newSimulation("settle", 8453, txmanager.UnitWei)
+	assert.NoError(t, sim.Err())
+	sim.addTx("settle", &txmanager.Simulation{Reverted: true, Reason: "Order fill expired", RevertData: nil,
+		Gas: 0, Fee: nil, Unit: txmanager.UnitWei})
+	require.Error(t, sim.Err())
+	assert.ErrorIs(t, sim.Err(), failure.ErrDeadlinePassed)
+	assert.Equal(t, "settle: Order fill expired", sim.Reason)
+}
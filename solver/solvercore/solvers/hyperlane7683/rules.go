@@ -8,21 +8,20 @@ package hyperlane7683
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
-	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
-	"github.com/NethermindEth/oif-starknet/solver/pkg/ethutil"
-	"github.com/NethermindEth/oif-starknet/solver/pkg/starknetutil"
-	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/history"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/inventory"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/tracing"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
-	"github.com/NethermindEth/starknet.go/rpc"
-	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/holiman/uint256"
 )
 
 const (
-	starknetNetworkName = "Starknet"
 	// Profit margin calculation (100 = 100%)
 	profitMarginMultiplier = 100
 )
@@ -31,6 +30,18 @@ const (
 type RuleResult struct {
 	Passed bool
 	Reason string
+	// Err classifies a failed result for retries, metrics and alerts, e.g. wrapping failure.ErrInsufficientFunds;
+	// a failure without one is a failure.ErrValidation
+	Err error
+}
+
+// rejection is the error of a failed result, classified by its Err
+func rejection(result RuleResult) error {
+	err := fmt.Errorf("order validation failed: %s", result.Reason)
+	if result.Err != nil {
+		return failure.Mark(err, result.Err)
+	}
+	return failure.Mark(err, failure.ErrValidation)
 }
 
 // Rule defines the interface for validation rules
@@ -42,176 +53,223 @@ type Rule interface {
 
 // RulesEngine coordinates rule evaluation
 type RulesEngine struct {
-	rules []Rule
+	rules  []Rule
+	logger logutil.Logger // nil logs to logutil.Default()
+}
+
+// loggerSetter is implemented by rules that log; the engine hands them its logger
+type loggerSetter interface {
+	SetLogger(logger logutil.Logger)
 }
 
-// NewRulesEngine creates a new rules engine with default rules
-func NewRulesEngine() *RulesEngine {
+// NewRulesEngine creates a rules engine with the default rules; its balance rule checks orders against inv
+func NewRulesEngine(inv *inventory.Manager) *RulesEngine {
 	return &RulesEngine{
 		rules: []Rule{
-			&BalanceRule{},
-			&ProfitabilityRule{},
+			NewBalanceRule(inv),
+			&ProfitabilityRule{logger: nil},
 		},
+		logger: nil,
 	}
 }
 
 // AddRule adds a custom rule to the engine
 func (re *RulesEngine) AddRule(rule Rule) {
+	if setter, ok := rule.(loggerSetter); ok && re.logger != nil {
+		setter.SetLogger(re.logger)
+	}
 	re.rules = append(re.rules, rule)
 }
 
-// EvaluateAll runs all rules and returns the first failure, or success if all pass
-func (re *RulesEngine) EvaluateAll(ctx context.Context, args *types.ParsedArgs) RuleResult {
-	// Get chain IDs for cross-chain logging
-	originChainID := args.ResolvedOrder.OriginChainID.Uint64()
-	destChainID := args.ResolvedOrder.FillInstructions[0].DestinationChainID.Uint64()
-
+// SetLogger makes the engine, and every rule that logs, write to logger
+func (re *RulesEngine) SetLogger(logger logutil.Logger) {
+	re.logger = logger
 	for _, rule := range re.rules {
-		result := rule.Evaluate(ctx, args)
-		if !result.Passed {
-			logutil.CrossChainOperation(fmt.Sprintf("Rule '%s' failed: %s", rule.Name(), result.Reason), originChainID, destChainID, args.OrderID)
-			return result
+		if setter, ok := rule.(loggerSetter); ok {
+			setter.SetLogger(logger)
 		}
-		logutil.CrossChainOperation(fmt.Sprintf("Rule '%s' passed", rule.Name()), originChainID, destChainID, args.OrderID)
 	}
-	return RuleResult{Passed: true, Reason: "All rules passed"}
 }
 
-// BalanceRule validates that the solver has sufficient balance for the order
-type BalanceRule struct{}
-
-func (br *BalanceRule) Name() string {
-	return "BalanceCheck"
-}
-
-func (br *BalanceRule) Evaluate(ctx context.Context, args *types.ParsedArgs) RuleResult {
-	if len(args.ResolvedOrder.MaxSpent) == 0 {
-		return RuleResult{Passed: true, Reason: "No tokens to spend"}
+func (re *RulesEngine) log() logutil.Logger {
+	if re.logger == nil {
+		return logutil.Default()
 	}
+	return re.logger
+}
 
-	// Get destination chain ID for routing
-	destinationChainID := args.ResolvedOrder.FillInstructions[0].DestinationChainID.Uint64()
+// EvaluateAll runs all rules and returns the first failure, or success if all pass.
+// Rules see the whole order; every fill instruction must name its destination chain.
+func (re *RulesEngine) EvaluateAll(ctx context.Context, args *types.ParsedArgs) RuleResult {
+	result, _ := re.EvaluateAllWithVerdicts(ctx, args)
+	return result
+}
 
-	// Switch based on destination chain type
-	switch {
-	case isStarknetChain(destinationChainID):
-		return br.checkStarknetBalance(ctx, args)
-	default:
-		return br.checkEVMBalance(ctx, args)
-	}
+// EvaluateAllWithVerdicts is EvaluateAll that also returns the verdict of every rule evaluated, in order
+func (re *RulesEngine) EvaluateAllWithVerdicts(ctx context.Context, args *types.ParsedArgs) (RuleResult, []history.RuleVerdict) {
+	ctx, span := tracing.StartOrderSpan(ctx, tracing.SpanRules, args.OrderID)
+	result, verdicts := re.evaluateAll(ctx, args)
+	span.SetAttributes(tracing.KeyRulePassed.Bool(result.Passed), tracing.KeyReason.String(result.Reason))
+	span.End()
+	return result, verdicts
 }
 
-func (br *BalanceRule) checkStarknetBalance(_ context.Context, args *types.ParsedArgs) RuleResult {
-	// Get solver's Starknet address from environment (conditional based on IS_DEVNET)
-	solverAddrHex := envutil.GetStarknetSolverAddress()
-	if solverAddrHex == "" {
-		return RuleResult{Passed: false, Reason: "Starknet solver address not set"}
+func (re *RulesEngine) evaluateAll(ctx context.Context, args *types.ParsedArgs) (RuleResult, []history.RuleVerdict) {
+	if len(args.ResolvedOrder.FillInstructions) == 0 {
+		return RuleResult{Passed: false, Reason: "Order has no fill instructions", Err: nil}, nil
 	}
-
-	// Get Starknet RPC URL (conditional based on IS_DEVNET)
-	starknetRPC := envutil.GetStarknetRPCURL()
-	if starknetRPC == "" {
-		return RuleResult{Passed: false, Reason: "STARKNET_RPC_URL not set"}
+	for i, instruction := range args.ResolvedOrder.FillInstructions {
+		if instruction.DestinationChainID == nil {
+			return RuleResult{Passed: false, Reason: fmt.Sprintf("Fill instruction %d has no destination chain", i+1), Err: nil}, nil
+		}
 	}
-
-	provider, err := rpc.NewProvider(starknetRPC)
-	if err != nil {
-		return RuleResult{Passed: false, Reason: fmt.Sprintf("Failed to create Starknet provider: %v", err)}
+	// Filling after the deadline reverts; zero means the listener could not read it
+	if deadline := args.ResolvedOrder.FillDeadline; deadline != 0 && time.Now().Unix() > int64(deadline) {
+		return RuleResult{Passed: false, Reason: fmt.Sprintf("Fill deadline %d has passed", deadline), Err: failure.ErrDeadlinePassed}, nil
 	}
 
-	// Check balance for each token in MaxSpent (what solver needs to provide on Starknet)
-	for _, maxSpent := range args.ResolvedOrder.MaxSpent {
-		// Skip native ETH (empty string)
-		if maxSpent.Token == "" || maxSpent.Token == "0x0" {
-			continue
+	verdicts := make([]history.RuleVerdict, 0, len(re.rules))
+	for _, rule := range re.rules {
+		result := evaluateTraced(ctx, rule, args)
+		metrics.RuleEvaluated(chainLabel(args.ResolvedOrder.OriginChainID), protocolLabel, rule.Name(), result.Passed)
+		verdicts = append(verdicts, history.RuleVerdict{Rule: rule.Name(), Passed: result.Passed, Reason: result.Reason})
+		if !result.Passed {
+			re.logPerDestination(args, fmt.Sprintf("Rule '%s' failed: %s", rule.Name(), result.Reason), logutil.Stage("rules"))
+			return result, verdicts
 		}
+		re.logPerDestination(args, fmt.Sprintf("Rule '%s' passed", rule.Name()), logutil.Stage("rules"))
+	}
+	return RuleResult{Passed: true, Reason: "All rules passed", Err: nil}, verdicts
+}
+
+// evaluateTraced evaluates rule for the order inside its own span
+func evaluateTraced(ctx context.Context, rule Rule, args *types.ParsedArgs) RuleResult {
+	ctx, span := tracing.StartOrderSpan(ctx, tracing.SpanRule, args.OrderID, tracing.KeyRule.String(rule.Name()))
+	result := rule.Evaluate(ctx, args)
+	span.SetAttributes(tracing.KeyRulePassed.Bool(result.Passed), tracing.KeyReason.String(result.Reason))
+	span.End()
+	return result
+}
 
-		// Use starknetutil for balance check (assume valid input from order creation)
-		balance, err := starknetutil.ERC20Balance(provider, maxSpent.Token, solverAddrHex)
-		if err != nil {
-			return RuleResult{Passed: false, Reason: fmt.Sprintf("Failed to check balance for token %s: %v", maxSpent.Token, err)}
+// DryRun evaluates every rule without stopping at the first failure and returns the reasons of those that failed.
+// Nothing is reserved, so it can be used to price orders that do not exist yet.
+func (re *RulesEngine) DryRun(ctx context.Context, args *types.ParsedArgs) []string {
+	reasons := make([]string, 0, len(re.rules))
+	for _, rule := range re.rules {
+		if result := rule.Evaluate(ctx, args); !result.Passed {
+			reasons = append(reasons, fmt.Sprintf("%s: %s", rule.Name(), result.Reason))
 		}
+	}
+	return reasons
+}
 
-		if balance.Cmp(maxSpent.Amount) < 0 {
-			return RuleResult{Passed: false, Reason: fmt.Sprintf("Insufficient balance for token %s: have %s, need %s",
-				maxSpent.Token, balance.String(), maxSpent.Amount.String())}
+// logPerDestination logs the message once for every distinct destination chain of the order
+func (re *RulesEngine) logPerDestination(args *types.ParsedArgs, message string, fields ...any) {
+	originChainID := args.ResolvedOrder.OriginChainID.Uint64()
+	seen := make(map[uint64]bool, len(args.ResolvedOrder.FillInstructions))
+	for _, instruction := range args.ResolvedOrder.FillInstructions {
+		destChainID := instruction.DestinationChainID.Uint64()
+		if seen[destChainID] {
+			continue
 		}
+		seen[destChainID] = true
+		logutil.CrossChain(re.log(), message, originChainID, destChainID, args.OrderID, fields...)
 	}
+}
 
-	return RuleResult{Passed: true, Reason: "Starknet balance check passed"}
+// BalanceRule validates that the solver's unreserved inventory covers the order
+type BalanceRule struct {
+	inventory *inventory.Manager
 }
 
-func (br *BalanceRule) checkEVMBalance(_ context.Context, args *types.ParsedArgs) RuleResult {
-	// Get solver's EVM address from environment (conditional based on IS_DEVNET)
-	solverAddrHex := envutil.GetSolverPublicKey()
-	if solverAddrHex == "" {
-		return RuleResult{Passed: false, Reason: "Solver public key not set"}
-	}
+// NewBalanceRule creates a balance rule backed by the given inventory manager; a rule without one fails every order
+// that spends tokens
+func NewBalanceRule(inv *inventory.Manager) *BalanceRule {
+	return &BalanceRule{inventory: inv}
+}
 
-	solverAddr := common.HexToAddress(solverAddrHex)
-	destinationChainID := args.ResolvedOrder.FillInstructions[0].DestinationChainID.Uint64()
+func (br *BalanceRule) Name() string {
+	return "BalanceCheck"
+}
 
-	// Find the network config for destination chain
-	var networkConfig *config.NetworkConfig
-	for _, network := range config.Networks {
-		if network.ChainID == destinationChainID {
-			networkConfig = &network
-			break
-		}
+func (br *BalanceRule) Evaluate(ctx context.Context, args *types.ParsedArgs) RuleResult {
+	if len(args.ResolvedOrder.MaxSpent) == 0 {
+		return RuleResult{Passed: true, Reason: "No tokens to spend", Err: nil}
 	}
 
-	if networkConfig == nil {
-		return RuleResult{Passed: false, Reason: fmt.Sprintf("No network config found for chain ID %d", destinationChainID)}
+	// Without an inventory there are no balances to check against, so the order is not taken on blind
+	if br.inventory == nil {
+		return RuleResult{Passed: false, Reason: "Inventory not configured, balance cannot be checked", Err: nil}
 	}
 
-	// Connect to destination chain RPC
-	client, err := ethclient.Dial(networkConfig.RPCURL)
-	if err != nil {
-		return RuleResult{Passed: false, Reason: fmt.Sprintf("Failed to connect to EVM RPC: %v", err)}
+	// Reservations held by other in-flight orders are already subtracted from availability; the order's own
+	// reservation, taken when it was accepted as a gasless order, is not.
+	// The whole order is checked at once so legs on the same chain add up.
+	if err := br.inventory.CanCoverFor(ctx, args.OrderID, spentOutputs(args)); err != nil {
+		return RuleResult{Passed: false, Reason: fmt.Sprintf("Balance check failed: %v%s", err, br.uncoveredLegs(ctx, args)), Err: err}
 	}
-	defer client.Close()
 
-	// Check balance for each token in MaxSpent (what solver needs to provide on destination chain)
-	for _, maxSpent := range args.ResolvedOrder.MaxSpent {
-		// Skip native ETH (empty string)
-		if maxSpent.Token == "" {
-			continue
-		}
+	return RuleResult{Passed: true, Reason: "Inventory covers MaxSpent", Err: nil}
+}
 
-		// Convert token address using address_utils (assume valid input from order creation)
-		tokenAddr, err := types.ToEVMAddress(maxSpent.Token)
-		if err != nil {
-			return RuleResult{Passed: false, Reason: fmt.Sprintf("Failed to convert token address %s: %v", maxSpent.Token, err)}
+// uncoveredLegs names the fill instructions whose own outputs cannot be covered, for the failure reason
+func (br *BalanceRule) uncoveredLegs(ctx context.Context, args *types.ParsedArgs) string {
+	var legs []string
+	for i, instruction := range args.ResolvedOrder.FillInstructions {
+		leg := args.ForInstruction(i)
+		if len(leg.ResolvedOrder.MaxSpent) == 0 {
+			continue
 		}
-
-		// Use ethutil for balance check
-		balance, err := ethutil.ERC20Balance(client, tokenAddr, solverAddr)
-		if err != nil {
-			return RuleResult{Passed: false, Reason: fmt.Sprintf("Failed to check balance for token %s: %v", maxSpent.Token, err)}
+		if err := br.inventory.CanCoverFor(ctx, args.OrderID, leg.ResolvedOrder.MaxSpent); err != nil {
+			legs = append(legs, fmt.Sprintf("%d (chain %s)", i+1, instruction.DestinationChainID))
 		}
+	}
+	if len(legs) == 0 {
+		return ""
+	}
+	return fmt.Sprintf(" (fill instructions %s)", strings.Join(legs, ", "))
+}
 
-		if balance.Cmp(maxSpent.Amount) < 0 {
-			return RuleResult{Passed: false, Reason: fmt.Sprintf("Insufficient balance for token %s: have %s, need %s",
-				maxSpent.Token, balance.String(), maxSpent.Amount.String())}
+// spentOutputs returns MaxSpent across all fill instructions with a chain ID on every entry.
+// Entries without one take the chain of the instruction ParsedArgs.SpendingInstruction pairs them with.
+func spentOutputs(args *types.ParsedArgs) []types.Output {
+	outputs := make([]types.Output, 0, len(args.ResolvedOrder.MaxSpent))
+	for j, output := range args.ResolvedOrder.MaxSpent {
+		if i := args.SpendingInstruction(j); output.ChainID == nil && i < len(args.ResolvedOrder.FillInstructions) {
+			output.ChainID = args.ResolvedOrder.FillInstructions[i].DestinationChainID
 		}
+		outputs = append(outputs, output)
 	}
-
-	return RuleResult{Passed: true, Reason: "EVM balance check passed"}
+	return outputs
 }
 
 // ProfitabilityRule validates that the order is profitable for the solver
-type ProfitabilityRule struct{}
+type ProfitabilityRule struct {
+	logger logutil.Logger // nil logs to logutil.Default()
+}
 
 func (pr *ProfitabilityRule) Name() string {
 	return "ProfitabilityCheck"
 }
 
+// SetLogger makes the rule write to logger
+func (pr *ProfitabilityRule) SetLogger(logger logutil.Logger) {
+	pr.logger = logger
+}
+
+func (pr *ProfitabilityRule) log() logutil.Logger {
+	if pr.logger == nil {
+		return logutil.Default()
+	}
+	return pr.logger
+}
+
 func (pr *ProfitabilityRule) Evaluate(ctx context.Context, args *types.ParsedArgs) RuleResult {
 	// Calculate expected profit from the order
 	// This involves comparing MaxSpent vs MinReceived
 
 	if len(args.ResolvedOrder.MaxSpent) == 0 || len(args.ResolvedOrder.MinReceived) == 0 {
-		return RuleResult{Passed: false, Reason: "Missing MaxSpent or MinReceived data"}
+		return RuleResult{Passed: false, Reason: "Missing MaxSpent or MinReceived data", Err: nil}
 	}
 
 	// Simple profitability check: ensure MaxSpent > MinReceived
@@ -221,7 +279,7 @@ func (pr *ProfitabilityRule) Evaluate(ctx context.Context, args *types.ParsedArg
 	// Get chain IDs for cross-chain logging
 	originChainID := args.ResolvedOrder.OriginChainID.Uint64()
 	destChainID := args.ResolvedOrder.FillInstructions[0].DestinationChainID.Uint64()
-	logutil.CrossChainOperation("Checking order profitability", originChainID, destChainID, args.OrderID)
+	logutil.CrossChain(pr.log(), "Checking order profitability", originChainID, destChainID, args.OrderID, logutil.Stage("rules"))
 
 	// Basic profitability check: ensure MinReceived > MaxSpent + expectedFees
 	// NOTE: This is a simplified check that assumes same token types and doesn't account for:
@@ -266,6 +324,7 @@ func (pr *ProfitabilityRule) Evaluate(ctx context.Context, args *types.ParsedArg
 			Passed: false,
 			Reason: fmt.Sprintf("Order not profitable: MinReceived (%s) <= TotalCosts (%s + %s fees)",
 				totalMinReceived.Dec(), totalMaxSpent.Dec(), expectedFees.Dec()),
+			Err: nil,
 		}
 	}
 
@@ -279,6 +338,7 @@ func (pr *ProfitabilityRule) Evaluate(ctx context.Context, args *types.ParsedArg
 			Passed: false,
 			Reason: fmt.Sprintf("Order profit below threshold: NetProfit (%s) < MinThreshold (%s)",
 				netProfit.Dec(), minProfitThreshold.Dec()),
+			Err: nil,
 		}
 	}
 
@@ -286,27 +346,9 @@ func (pr *ProfitabilityRule) Evaluate(ctx context.Context, args *types.ParsedArg
 	profitMargin := new(uint256.Int).Mul(grossProfit, uint256.NewInt(profitMarginMultiplier))
 	profitMargin.Div(profitMargin, totalMaxSpent)
 
-	logutil.CrossChainOperation(fmt.Sprintf("Profitability check passed: NetProfit=%s, GrossProfit=%s (%.2f%% margin)",
-		netProfit.Dec(), grossProfit.Dec(), float64(profitMargin.Uint64())), originChainID, destChainID, args.OrderID)
+	logutil.CrossChain(pr.log(), fmt.Sprintf("Profitability check passed: NetProfit=%s, GrossProfit=%s (%.2f%% margin)",
+		netProfit.Dec(), grossProfit.Dec(), float64(profitMargin.Uint64())), originChainID, destChainID, args.OrderID, logutil.Stage("rules"))
 
 	return RuleResult{Passed: true, Reason: fmt.Sprintf("Order profitable: NetProfit=%s, GrossProfit=%s (%.2f%% margin)",
-		netProfit.Dec(), grossProfit.Dec(), float64(profitMargin.Uint64()))}
-}
-
-// Helper function to determine if a chain ID is Starknet
-func isStarknetChain(chainID uint64) bool {
-	for _, network := range config.Networks {
-		if network.ChainID == chainID && network.Name == starknetNetworkName {
-			return true
-		}
-	}
-	return false
+		netProfit.Dec(), grossProfit.Dec(), float64(profitMargin.Uint64())), Err: nil}
 }
-
-// Helper function to get chain type (EVM or Starknet)
-// func getChainType(chainID uint64) string {
-//	if isStarknetChain(chainID) {
-//		return "Starknet"
-//	}
-//	return "EVM"
-// }
@@ -5,10 +5,13 @@ import (
 	"math/big"
 	"os"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/inventory"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 )
 
@@ -27,14 +30,14 @@ func TestRulesEngine(t *testing.T) {
 	_ = config.GetDefaultNetwork()
 
 	t.Run("NewRulesEngine creation", func(t *testing.T) {
-		engine := NewRulesEngine()
+		engine := NewRulesEngine(nil)
 		assert.NotNil(t, engine)
 		assert.NotNil(t, engine.rules)
 		// Note: RulesEngine may have default rules, so we don't assert empty
 	})
 
 	t.Run("AddRule", func(t *testing.T) {
-		engine := NewRulesEngine()
+		engine := NewRulesEngine(nil)
 		initialCount := len(engine.rules)
 
 		rule := &BalanceRule{}
@@ -253,6 +256,71 @@ func TestRuleResult(t *testing.T) {
 	})
 }
 
+func TestRulesEngineClassifiesRejections(t *testing.T) {
+	args := func(deadline uint32) *types.ParsedArgs {
+		return &types.ParsedArgs{
+			OrderID: "0x01",
+			ResolvedOrder: types.ResolvedCrossChainOrder{
+				OriginChainID:    big.NewInt(1),
+				FillDeadline:     deadline,
+				FillInstructions: []types.FillInstruction{{DestinationChainID: big.NewInt(84532)}},
+			},
+		}
+	}
+
+	engine := &RulesEngine{rules: []Rule{&MockRule{name: "MockRule", shouldPass: false}}}
+
+	expired := engine.EvaluateAll(context.Background(), args(uint32(time.Now().Add(-time.Minute).Unix())))
+	assert.False(t, expired.Passed)
+	assert.Contains(t, expired.Reason, "deadline")
+	assert.Equal(t, failure.KindDeadline, failure.KindOf(rejection(expired)))
+
+	// A rule failing without a classification counts as a validation failure
+	rejected := engine.EvaluateAll(context.Background(), args(0))
+	assert.Equal(t, "Mock rule failed", rejected.Reason)
+	err := rejection(rejected)
+	assert.Equal(t, failure.KindValidation, failure.KindOf(err))
+	assert.True(t, failure.Permanent(err))
+
+	short := rejection(RuleResult{Passed: false, Reason: "insufficient balance", Err: failure.ErrInsufficientFunds})
+	assert.Equal(t, failure.KindInsufficientFunds, failure.KindOf(short))
+	assert.False(t, failure.Permanent(short), "the order can be filled once the solver is topped up")
+}
+
+// fixedBalance is an inventory.BalanceFetcher reporting the same balance for every token
+type fixedBalance int64
+
+func (b fixedBalance) Balance(context.Context, string) (*big.Int, error) {
+	return big.NewInt(int64(b)), nil
+}
+
+func TestBalanceRuleInventory(t *testing.T) {
+	args := &types.ParsedArgs{
+		OrderID: "0x01",
+		ResolvedOrder: types.ResolvedCrossChainOrder{
+			OriginChainID:    big.NewInt(1),
+			MaxSpent:         []types.Output{{Token: "0x1234567890123456789012345678901234567890", Amount: big.NewInt(1000), ChainID: big.NewInt(84532)}},
+			FillInstructions: []types.FillInstruction{{DestinationChainID: big.NewInt(84532)}},
+		},
+	}
+
+	t.Run("fails closed without an inventory", func(t *testing.T) {
+		result := NewBalanceRule(nil).Evaluate(context.Background(), args)
+		assert.False(t, result.Passed)
+		assert.Contains(t, result.Reason, "Inventory not configured")
+	})
+
+	t.Run("the default balance rule checks the engine's inventory", func(t *testing.T) {
+		inv := inventory.NewManager(0)
+		inv.RegisterChain(84532, fixedBalance(10))
+		engine := NewRulesEngine(inv)
+
+		balance := engine.rules[0].Evaluate(context.Background(), args)
+		assert.False(t, balance.Passed, "the balance rule checks the inventory it was given")
+		assert.ErrorIs(t, balance.Err, inventory.ErrInsufficientInventory)
+	})
+}
+
 // MockRule is a test rule that doesn't make network calls
 type MockRule struct {
 	name       string
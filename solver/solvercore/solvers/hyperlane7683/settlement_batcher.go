@@ -0,0 +1,506 @@
+package hyperlane7683
+
+// Module: Settlement batcher for Hyperlane7683
+// - Queues filled orders per (destination chain, destination settler, origin domain)
+// - Flushes a queue as one settle(bytes32[]) call once it reaches SETTLE_BATCH_SIZE orders
+//   or its oldest order is SETTLE_BATCH_MAX_AGE_MS old, paying a single interchain gas fee
+// - Falls back to settling orders one by one when a batch fails, so one bad order cannot block the rest
+// - Tracks each order's state and the batch it was included in
+// - Keeps every filled order that is not settled yet in SETTLE_BATCH_QUEUE_FILE, so a restart re-queues it;
+//   this includes deferred orders, which are tried again after each restart
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/tracing"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Defaults used when the corresponding environment variables are unset
+const (
+	DefaultSettleBatchSize        = 1 // settle immediately after each fill
+	DefaultSettleBatchMaxAge      = 30 * time.Second
+	DefaultSettleBatchMaxAttempts = 3
+	DefaultSettleBatchQueueFile   = "state/settlement_queue/queue.json"
+
+	// settledStatusRetention is how long settled/failed statuses stay queryable
+	settledStatusRetention = time.Hour
+	// shutdownFlushTimeout bounds the final flush of queued orders on shutdown
+	shutdownFlushTimeout = 2 * time.Minute
+
+	queueDirPerms  = 0755
+	queueFilePerms = 0644
+)
+
+// SettlementState is the lifecycle of an order inside the batcher
+type SettlementState string
+
+const (
+	SettlementQueued   SettlementState = "QUEUED"
+	SettlementSettling SettlementState = "SETTLING"
+	SettlementSettled  SettlementState = "SETTLED"
+	SettlementFailed   SettlementState = "FAILED"
+	// SettlementDeferred orders are filled but cannot be settled yet (see ErrSettlementDeferred)
+	SettlementDeferred SettlementState = "DEFERRED"
+)
+
+// SettlementStatus reports where an order is in the settlement pipeline
+type SettlementStatus struct {
+	State SettlementState
+	// BatchID identifies the settle call the order was (last) included in
+	BatchID   string
+	Attempts  int
+	Error     string
+	UpdatedAt time.Time
+}
+
+// SettlementBatchConfig controls when queued orders are flushed
+type SettlementBatchConfig struct {
+	// MaxSize is the number of orders that triggers a flush; 1 or less disables batching
+	MaxSize int
+	// MaxAge is how long the oldest order of a queue may wait before the queue is flushed
+	MaxAge time.Duration
+	// MaxAttempts bounds how often an order is retried after failed settlements
+	MaxAttempts int
+	// QueueFile keeps the orders that are filled but not settled across restarts; empty keeps them in memory only
+	QueueFile string
+}
+
+// SettlementBatchConfigFromEnv builds a config from SETTLE_BATCH_SIZE, SETTLE_BATCH_MAX_AGE_MS and SETTLE_BATCH_QUEUE_FILE
+func SettlementBatchConfigFromEnv() SettlementBatchConfig {
+	return SettlementBatchConfig{
+		MaxSize: envutil.GetEnvInt("SETTLE_BATCH_SIZE", DefaultSettleBatchSize),
+		MaxAge: time.Duration(envutil.GetEnvUint64("SETTLE_BATCH_MAX_AGE_MS",
+			uint64(DefaultSettleBatchMaxAge.Milliseconds()))) * time.Millisecond,
+		MaxAttempts: DefaultSettleBatchMaxAttempts,
+		QueueFile:   envutil.GetEnvWithDefault("SETTLE_BATCH_QUEUE_FILE", DefaultSettleBatchQueueFile),
+	}
+}
+
+// Enabled reports whether orders should be batched rather than settled right after the fill
+func (c SettlementBatchConfig) Enabled() bool {
+	return c.MaxSize > 1
+}
+
+// settlementKey groups orders that can share one settle call
+type settlementKey struct {
+	destinationChainID uint64
+	// destinationSettler is the normalised settler contract the orders were filled through
+	destinationSettler string
+	originDomain       uint32
+}
+
+func (k settlementKey) String() string {
+	return fmt.Sprintf("chain %d settler %s <- domain %d", k.destinationChainID, k.destinationSettler, k.originDomain)
+}
+
+// settleFunc settles orders sharing key in one call
+type settleFunc func(ctx context.Context, key settlementKey, orders []*types.ParsedArgs) error
+
+type queuedSettlement struct {
+	args     *types.ParsedArgs
+	queuedAt time.Time
+	// span is the order's span when it was queued; the batch that settles it links back to it
+	span trace.SpanContext
+}
+
+// SettlementBatcher accumulates filled orders and settles them in batches
+type SettlementBatcher struct {
+	mu       sync.Mutex
+	cfg      SettlementBatchConfig
+	settle   settleFunc
+	queues   map[settlementKey][]queuedSettlement
+	statuses map[string]*SettlementStatus
+	// unsettled holds every order added and not yet settled or dropped for good, as kept in cfg.QueueFile
+	unsettled map[string]*types.ParsedArgs
+	batchSeq  uint64
+	flushing  map[settlementKey]bool
+	wg        sync.WaitGroup
+	now       func() time.Time
+	logger    logutil.Logger
+}
+
+// NewSettlementBatcher creates a batcher that flushes through settle
+func NewSettlementBatcher(cfg SettlementBatchConfig, settle settleFunc) *SettlementBatcher {
+	if cfg.MaxAge <= 0 {
+		cfg.MaxAge = DefaultSettleBatchMaxAge
+	}
+	if cfg.MaxAttempts <= 0 {
+		cfg.MaxAttempts = DefaultSettleBatchMaxAttempts
+	}
+	return &SettlementBatcher{
+		mu:        sync.Mutex{},
+		cfg:       cfg,
+		settle:    settle,
+		queues:    make(map[settlementKey][]queuedSettlement),
+		statuses:  make(map[string]*SettlementStatus),
+		unsettled: make(map[string]*types.ParsedArgs),
+		batchSeq:  0,
+		flushing:  make(map[settlementKey]bool),
+		wg:        sync.WaitGroup{},
+		logger:    logutil.Default(),
+		now:       time.Now,
+	}
+}
+
+// Start flushes aged queues until ctx is cancelled, then flushes everything still queued
+func (b *SettlementBatcher) Start(ctx context.Context) {
+	interval := b.cfg.MaxAge / 4
+	if interval < 100*time.Millisecond {
+		interval = 100 * time.Millisecond
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			flushCtx, cancel := context.WithTimeout(context.Background(), shutdownFlushTimeout)
+			b.FlushAll(flushCtx)
+			cancel()
+			return
+		case <-ticker.C:
+			b.flushDue(ctx)
+		}
+	}
+}
+
+// Add queues a filled order; a full queue is flushed in the background
+func (b *SettlementBatcher) Add(ctx context.Context, args *types.ParsedArgs) error {
+	key, err := settlementKeyFor(args)
+	if err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	b.queues[key] = append(b.queues[key], queuedSettlement{args: args, queuedAt: b.now(), span: trace.SpanContextFromContext(ctx)})
+	b.setStatusLocked(args.OrderID, SettlementQueued, "", nil)
+	b.unsettled[args.OrderID] = args
+	b.saveLocked()
+	size := len(b.queues[key])
+	b.mu.Unlock()
+
+	b.logger.Info(fmt.Sprintf("📦 Order queued for batch settlement (%s, %d/%d)", key, size, b.cfg.MaxSize),
+		logutil.OrderID(args.OrderID), logutil.Stage("settle"))
+	if size >= b.cfg.MaxSize {
+		// The caller's context ends with its order's processing, not with the batch it completed
+		flushCtx := context.WithoutCancel(ctx)
+		b.wg.Add(1)
+		go func() {
+			defer b.wg.Done()
+			b.flush(flushCtx, key)
+		}()
+	}
+	return nil
+}
+
+// Restore re-queues the orders cfg.QueueFile kept from an earlier run: filled orders whose settlement was still
+// queued or in flight when the solver stopped, was deferred, or was given up on after MaxAttempts. It returns how
+// many orders were queued again.
+func (b *SettlementBatcher) Restore(ctx context.Context) (int, error) {
+	if b.cfg.QueueFile == "" {
+		return 0, nil
+	}
+	data, err := os.ReadFile(b.cfg.QueueFile)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, fmt.Errorf("failed to read settlement queue file: %w", err)
+	}
+
+	var orders []*types.ParsedArgs
+	if err := json.Unmarshal(data, &orders); err != nil {
+		return 0, fmt.Errorf("failed to parse settlement queue file %s: %w", b.cfg.QueueFile, err)
+	}
+	restored := 0
+	for _, order := range orders {
+		if err := b.Add(ctx, order); err != nil {
+			b.logger.Error("❌ Cannot re-queue order for settlement", logutil.OrderID(order.OrderID), logutil.Stage("settle"), logutil.Err(err))
+			continue
+		}
+		restored++
+	}
+	return restored, nil
+}
+
+// Status returns the settlement status of an order, if the batcher has seen it
+func (b *SettlementBatcher) Status(orderID string) (SettlementStatus, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	status, ok := b.statuses[orderID]
+	if !ok {
+		return SettlementStatus{}, false
+	}
+	return *status, true
+}
+
+// Pending returns the number of orders waiting to be settled
+func (b *SettlementBatcher) Pending() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	pending := 0
+	for _, queue := range b.queues {
+		pending += len(queue)
+	}
+	return pending
+}
+
+// FlushAll settles every queued order regardless of size or age and waits for in-flight flushes
+func (b *SettlementBatcher) FlushAll(ctx context.Context) {
+	// Failed orders are re-queued until they run out of attempts, so this terminates
+	for ctx.Err() == nil {
+		b.wg.Wait()
+		b.mu.Lock()
+		keys := make([]settlementKey, 0, len(b.queues))
+		for key := range b.queues {
+			keys = append(keys, key)
+		}
+		b.mu.Unlock()
+
+		if len(keys) == 0 {
+			return
+		}
+		for _, key := range keys {
+			b.flush(ctx, key)
+		}
+	}
+}
+
+// flushDue flushes queues whose oldest order exceeded MaxAge and prunes old statuses
+func (b *SettlementBatcher) flushDue(ctx context.Context) {
+	now := b.now()
+	var due []settlementKey
+
+	b.mu.Lock()
+	for key, queue := range b.queues {
+		if len(queue) > 0 && now.Sub(queue[0].queuedAt) >= b.cfg.MaxAge {
+			due = append(due, key)
+		}
+	}
+	for orderID, status := range b.statuses {
+		terminal := status.State == SettlementSettled || status.State == SettlementFailed || status.State == SettlementDeferred
+		if terminal && now.Sub(status.UpdatedAt) > settledStatusRetention {
+			delete(b.statuses, orderID)
+		}
+	}
+	b.mu.Unlock()
+
+	for _, key := range due {
+		b.flush(ctx, key)
+	}
+}
+
+// flush settles up to MaxSize queued orders for key; only one flush per key runs at a time
+func (b *SettlementBatcher) flush(ctx context.Context, key settlementKey) {
+	b.mu.Lock()
+	if b.flushing[key] || len(b.queues[key]) == 0 {
+		b.mu.Unlock()
+		return
+	}
+	b.flushing[key] = true
+
+	queue := b.queues[key]
+	n := len(queue)
+	if n > b.cfg.MaxSize {
+		n = b.cfg.MaxSize
+	}
+	batch := queue[:n]
+	b.queues[key] = append([]queuedSettlement(nil), queue[n:]...)
+	if len(b.queues[key]) == 0 {
+		delete(b.queues, key)
+	}
+
+	b.batchSeq++
+	batchID := fmt.Sprintf("%d-%d-%d", key.destinationChainID, key.originDomain, b.batchSeq)
+	orders := make([]*types.ParsedArgs, len(batch))
+	links := make([]trace.Link, 0, len(batch))
+	for i, queued := range batch {
+		orders[i] = queued.args
+		b.setStatusLocked(queued.args.OrderID, SettlementSettling, batchID, nil)
+		if queued.span.IsValid() {
+			links = append(links, trace.Link{SpanContext: queued.span, Attributes: []attribute.KeyValue{tracing.OrderID(queued.args.OrderID)}})
+		}
+	}
+	b.mu.Unlock()
+
+	defer func() {
+		b.mu.Lock()
+		delete(b.flushing, key)
+		b.mu.Unlock()
+	}()
+
+	// The batch runs after the orders' own traces ended, so it gets its own trace linked to each of them
+	ctx, span := tracing.Tracer().Start(ctx, tracing.SpanSettlementBatch, trace.WithLinks(links...), trace.WithAttributes(
+		tracing.KeyBatchID.String(batchID), tracing.KeyOrderCount.Int(len(orders)), tracing.OrderIDs(orders...)))
+	defer span.End()
+
+	b.logger.Info(fmt.Sprintf("📦 Settling batch %s with %d order(s) (%s)", batchID, len(orders), key), logutil.Stage("settle"))
+	err := b.settle(ctx, key, orders)
+	if err == nil {
+		b.finish(batch, batchID, nil)
+		b.logger.Info(fmt.Sprintf("✅ Settlement batch %s confirmed", batchID), logutil.Stage("settle"))
+		return
+	}
+	if errors.Is(err, ErrSettlementDeferred) {
+		// Every order in the batch shares the origin domain, so none of them can be settled yet
+		b.finish(batch, batchID, err)
+		return
+	}
+	span.RecordError(err)
+	if len(orders) == 1 {
+		b.finish(batch, batchID, err)
+		return
+	}
+
+	// Isolate the order(s) that broke the batch
+	b.logger.Warn(fmt.Sprintf("⚠️  Settlement batch %s failed, settling orders individually", batchID), logutil.Stage("settle"), logutil.Err(err))
+	for _, queued := range batch {
+		b.finish([]queuedSettlement{queued}, batchID, b.settle(ctx, key, []*types.ParsedArgs{queued.args}))
+	}
+}
+
+// finish records the outcome of settling the batch, re-queueing failures that have attempts left.
+// Failures no retry can fix (wrong order status, deadline passed, invalid order) are not retried and dropped.
+// Deferred settlements are not retried in this run but stay in the queue file for the next one.
+func (b *SettlementBatcher) finish(batch []queuedSettlement, batchID string, err error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, queued := range batch {
+		order := queued.args
+		if err == nil {
+			b.setStatusLocked(order.OrderID, SettlementSettled, batchID, nil)
+			delete(b.unsettled, order.OrderID)
+			continue
+		}
+		if errors.Is(err, ErrSettlementDeferred) {
+			b.setStatusLocked(order.OrderID, SettlementDeferred, batchID, err)
+			continue
+		}
+
+		status := b.setStatusLocked(order.OrderID, SettlementFailed, batchID, err)
+		status.Attempts++
+		if status.Attempts < b.cfg.MaxAttempts && !failure.Permanent(err) {
+			key, keyErr := settlementKeyFor(order)
+			if keyErr == nil {
+				status.State = SettlementQueued
+				b.queues[key] = append(b.queues[key], queuedSettlement{args: order, queuedAt: b.now(), span: queued.span})
+				continue
+			}
+		}
+		// An order that may still settle stays in the queue file and is tried again after a restart
+		if failure.Permanent(err) {
+			delete(b.unsettled, order.OrderID)
+		}
+		b.logger.Error(fmt.Sprintf("❌ Giving up settling order after %d attempt(s)", status.Attempts),
+			logutil.OrderID(order.OrderID), logutil.Stage("settle"), logutil.Err(err))
+	}
+	b.saveLocked()
+}
+
+// saveLocked replaces cfg.QueueFile with the unsettled orders; a failed write is logged, as the orders are still
+// settled from memory
+func (b *SettlementBatcher) saveLocked() {
+	if b.cfg.QueueFile == "" {
+		return
+	}
+	if err := b.writeQueueFileLocked(); err != nil {
+		b.logger.Warn("⚠️  Failed to save settlement queue", logutil.Stage("settle"), logutil.Err(err))
+	}
+}
+
+func (b *SettlementBatcher) writeQueueFileLocked() error {
+	orders := make([]*types.ParsedArgs, 0, len(b.unsettled))
+	for _, order := range b.unsettled {
+		orders = append(orders, order)
+	}
+	sort.Slice(orders, func(i, j int) bool { return orders[i].OrderID < orders[j].OrderID })
+	data, err := json.MarshalIndent(orders, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal settlement queue: %w", err)
+	}
+
+	dir := filepath.Dir(b.cfg.QueueFile)
+	if err := os.MkdirAll(dir, queueDirPerms); err != nil {
+		return fmt.Errorf("failed to create settlement queue directory: %w", err)
+	}
+	tmp, err := os.CreateTemp(dir, "queue-*.tmp")
+	if err != nil {
+		return fmt.Errorf("failed to create temp settlement queue file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer func() { tmp.Close(); os.Remove(tmpPath) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		return fmt.Errorf("failed to write temp settlement queue file: %w", err)
+	}
+	if err := tmp.Chmod(queueFilePerms); err != nil {
+		return fmt.Errorf("failed to set settlement queue file permissions: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		return fmt.Errorf("failed to sync temp settlement queue file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temp settlement queue file: %w", err)
+	}
+	if err := os.Rename(tmpPath, b.cfg.QueueFile); err != nil {
+		return fmt.Errorf("failed to replace settlement queue file: %w", err)
+	}
+	return nil
+}
+
+func (b *SettlementBatcher) setStatusLocked(orderID string, state SettlementState, batchID string, err error) *SettlementStatus {
+	status, ok := b.statuses[orderID]
+	if !ok {
+		status = &SettlementStatus{State: state, BatchID: "", Attempts: 0, Error: "", UpdatedAt: time.Time{}}
+		b.statuses[orderID] = status
+	}
+	status.State = state
+	if batchID != "" {
+		status.BatchID = batchID
+	}
+	status.Error = ""
+	if err != nil {
+		status.Error = err.Error()
+	}
+	status.UpdatedAt = b.now()
+	return status
+}
+
+// settlementKeyFor returns the destination chain, destination settler and origin domain an order settles under
+func settlementKeyFor(args *types.ParsedArgs) (settlementKey, error) {
+	if len(args.ResolvedOrder.FillInstructions) != 1 {
+		return settlementKey{}, fmt.Errorf("batch settlement requires exactly one fill instruction, got %d",
+			len(args.ResolvedOrder.FillInstructions))
+	}
+	if args.ResolvedOrder.OriginChainID == nil || args.ResolvedOrder.FillInstructions[0].DestinationChainID == nil {
+		return settlementKey{}, fmt.Errorf("order %s is missing chain IDs", args.OrderID)
+	}
+
+	originChainID := args.ResolvedOrder.OriginChainID.Uint64()
+	for _, network := range config.Networks {
+		if network.ChainID == originChainID {
+			return settlementKey{
+				destinationChainID: args.ResolvedOrder.FillInstructions[0].DestinationChainID.Uint64(),
+				destinationSettler: normalizeAddress(args.ResolvedOrder.FillInstructions[0].DestinationSettler),
+				originDomain:       uint32(network.HyperlaneDomain),
+			}, nil
+		}
+	}
+	return settlementKey{}, fmt.Errorf("no domain found for chain ID %d in config", originChainID)
+}
@@ -0,0 +1,305 @@
+package hyperlane7683
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/tracing"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// recordingSettler records every settle call and fails orders listed in failing
+type recordingSettler struct {
+	mu      sync.Mutex
+	calls   [][]string
+	failing map[string]bool
+}
+
+func (s *recordingSettler) settle(_ context.Context, _ settlementKey, orders []*types.ParsedArgs) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	ids := make([]string, len(orders))
+	for i, order := range orders {
+		ids[i] = order.OrderID
+	}
+	s.calls = append(s.calls, ids)
+	for _, id := range ids {
+		if s.failing[id] {
+			return errors.New("order not filled")
+		}
+	}
+	return nil
+}
+
+func batchOrder(orderID, origin, destination string) *types.ParsedArgs {
+	return &types.ParsedArgs{
+		OrderID: orderID,
+		ResolvedOrder: types.ResolvedCrossChainOrder{
+			OriginChainID: new(big.Int).SetUint64(config.Networks[origin].ChainID),
+			FillInstructions: []types.FillInstruction{{
+				DestinationChainID: new(big.Int).SetUint64(config.Networks[destination].ChainID),
+			}},
+		},
+	}
+}
+
+func newTestBatcher(settler *recordingSettler, size int) *SettlementBatcher {
+	config.InitializeNetworks()
+	return NewSettlementBatcher(SettlementBatchConfig{MaxSize: size, MaxAge: time.Minute, MaxAttempts: 2}, settler.settle)
+}
+
+func TestSettlementBatcherFlushesFullBatches(t *testing.T) {
+	settler := &recordingSettler{}
+	b := newTestBatcher(settler, 2)
+	ctx := context.Background()
+
+	require.NoError(t, b.Add(ctx, batchOrder("0x01", "Base", "Optimism")))
+	require.NoError(t, b.Add(ctx, batchOrder("0x02", "Arbitrum", "Optimism")))
+	b.wg.Wait()
+	assert.Empty(t, settler.calls, "different origin domains must not share a batch")
+
+	require.NoError(t, b.Add(ctx, batchOrder("0x03", "Base", "Optimism")))
+	b.wg.Wait()
+	require.Len(t, settler.calls, 1)
+	assert.Equal(t, []string{"0x01", "0x03"}, settler.calls[0])
+
+	first, ok := b.Status("0x01")
+	require.True(t, ok)
+	third, _ := b.Status("0x03")
+	assert.Equal(t, SettlementSettled, first.State)
+	assert.Equal(t, first.BatchID, third.BatchID)
+
+	pending, _ := b.Status("0x02")
+	assert.Equal(t, SettlementQueued, pending.State)
+	assert.Equal(t, 1, b.Pending())
+}
+
+func TestSettlementBatcherSeparatesSettlers(t *testing.T) {
+	settler := &recordingSettler{}
+	b := newTestBatcher(settler, 2)
+	ctx := context.Background()
+
+	first := batchOrder("0x01", "Base", "Optimism")
+	first.ResolvedOrder.FillInstructions[0].DestinationSettler = "0x00000000000000000000000000000000000000000000000000000000000000aa"
+	other := batchOrder("0x02", "Base", "Optimism")
+	other.ResolvedOrder.FillInstructions[0].DestinationSettler = "0xbb"
+	same := batchOrder("0x03", "Base", "Optimism")
+	same.ResolvedOrder.FillInstructions[0].DestinationSettler = "0xAA"
+
+	require.NoError(t, b.Add(ctx, first))
+	require.NoError(t, b.Add(ctx, other))
+	b.wg.Wait()
+	assert.Empty(t, settler.calls, "different destination settlers must not share a batch")
+
+	require.NoError(t, b.Add(ctx, same))
+	b.wg.Wait()
+	require.Len(t, settler.calls, 1)
+	assert.Equal(t, []string{"0x01", "0x03"}, settler.calls[0], "padded and unpadded forms of a settler share a batch")
+}
+
+func TestSettlementBatcherFlushOutlivesCallerContext(t *testing.T) {
+	config.InitializeNetworks()
+	settled := make(chan error, 1)
+	b := NewSettlementBatcher(SettlementBatchConfig{MaxSize: 2, MaxAge: time.Minute, MaxAttempts: 2},
+		func(ctx context.Context, _ settlementKey, _ []*types.ParsedArgs) error {
+			time.Sleep(10 * time.Millisecond)
+			settled <- ctx.Err()
+			return ctx.Err()
+		})
+
+	require.NoError(t, b.Add(context.Background(), batchOrder("0x01", "Base", "Optimism")))
+	ctx, cancel := context.WithCancel(context.Background())
+	require.NoError(t, b.Add(ctx, batchOrder("0x02", "Base", "Optimism")))
+	cancel()
+	b.wg.Wait()
+
+	require.NoError(t, <-settled, "the background flush must not inherit the caller's cancellation")
+	status, _ := b.Status("0x02")
+	assert.Equal(t, SettlementSettled, status.State)
+}
+
+func TestSettlementBatcherFlushesByAge(t *testing.T) {
+	settler := &recordingSettler{}
+	b := newTestBatcher(settler, 10)
+	now := time.Unix(1_700_000_000, 0)
+	b.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	require.NoError(t, b.Add(ctx, batchOrder("0x01", "Base", "Optimism")))
+	b.flushDue(ctx)
+	assert.Empty(t, settler.calls)
+
+	now = now.Add(time.Minute)
+	b.flushDue(ctx)
+	require.Len(t, settler.calls, 1)
+	assert.Zero(t, b.Pending())
+}
+
+func TestSettlementBatcherIsolatesFailures(t *testing.T) {
+	settler := &recordingSettler{failing: map[string]bool{"0x02": true}}
+	b := newTestBatcher(settler, 2)
+	ctx := context.Background()
+
+	require.NoError(t, b.Add(ctx, batchOrder("0x01", "Base", "Optimism")))
+	require.NoError(t, b.Add(ctx, batchOrder("0x02", "Base", "Optimism")))
+	b.wg.Wait()
+
+	// Batch, then each order on its own
+	require.Len(t, settler.calls, 3)
+	good, _ := b.Status("0x01")
+	assert.Equal(t, SettlementSettled, good.State)
+	bad, _ := b.Status("0x02")
+	assert.Equal(t, SettlementQueued, bad.State)
+	assert.Equal(t, 1, bad.Attempts)
+
+	// Retried once more, then abandoned after MaxAttempts
+	b.FlushAll(ctx)
+	bad, _ = b.Status("0x02")
+	assert.Equal(t, SettlementFailed, bad.State)
+	assert.Equal(t, 2, bad.Attempts)
+	assert.Zero(t, b.Pending())
+}
+
+func TestSettlementBatcherDropsPermanentReverts(t *testing.T) {
+	config.InitializeNetworks()
+	revert := &RevertError{Operation: "settle", ChainID: 10, Call: "settle", Name: "InvalidOrderStatus",
+		Reason: "InvalidOrderStatus()", Data: nil}
+	b := NewSettlementBatcher(SettlementBatchConfig{MaxSize: 1, MaxAge: time.Minute, MaxAttempts: 3},
+		func(context.Context, settlementKey, []*types.ParsedArgs) error { return revert })
+
+	require.NoError(t, b.Add(context.Background(), batchOrder("0x01", "Base", "Optimism")))
+	b.wg.Wait()
+
+	status, _ := b.Status("0x01")
+	assert.Equal(t, SettlementFailed, status.State)
+	assert.Equal(t, 1, status.Attempts)
+	assert.Zero(t, b.Pending())
+	assert.Empty(t, b.unsettled, "an order no retry can settle is not kept for the next run")
+}
+
+func TestSettlementBatcherDefersSettlements(t *testing.T) {
+	config.InitializeNetworks()
+	calls := 0
+	cfg := SettlementBatchConfig{MaxSize: 2, MaxAge: time.Minute, MaxAttempts: 3,
+		QueueFile: filepath.Join(t.TempDir(), "queue.json")}
+	b := NewSettlementBatcher(cfg, func(context.Context, settlementKey, []*types.ParsedArgs) error {
+		calls++
+		return fmt.Errorf("%w: domain not registered", ErrSettlementDeferred)
+	})
+	ctx := context.Background()
+
+	require.NoError(t, b.Add(ctx, batchOrder("0x01", "Starknet", "Base")))
+	require.NoError(t, b.Add(ctx, batchOrder("0x02", "Starknet", "Base")))
+	b.wg.Wait()
+	b.FlushAll(ctx)
+
+	assert.Equal(t, 1, calls, "deferred batches are neither split nor retried")
+	assert.Zero(t, b.Pending())
+	status, _ := b.Status("0x02")
+	assert.Equal(t, SettlementDeferred, status.State)
+
+	// The next run tries the deferred orders again
+	settler := &recordingSettler{}
+	restored, err := NewSettlementBatcher(cfg, settler.settle).Restore(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, restored, "deferred orders stay in the queue file")
+}
+
+func TestSettlementBatcherLinksOrderTraces(t *testing.T) {
+	recorder := recordSpans(t)
+	settler := &recordingSettler{}
+	b := newTestBatcher(settler, 2)
+
+	var orderSpans []string
+	for _, id := range []string{"0x01", "0x02"} {
+		ctx, span := tracing.StartOrderSpan(context.Background(), tracing.SpanProcessIntent, id)
+		require.NoError(t, b.Add(ctx, batchOrder(id, "Base", "Optimism")))
+		span.End()
+		orderSpans = append(orderSpans, span.SpanContext().SpanID().String())
+	}
+	b.wg.Wait()
+
+	var batch []string
+	for _, span := range recorder.Ended() {
+		if span.Name() != tracing.SpanSettlementBatch {
+			continue
+		}
+		assert.Equal(t, "2", spanAttribute(span, string(tracing.KeyOrderCount)))
+		assert.Equal(t, `["0x01","0x02"]`, spanAttribute(span, string(tracing.KeyOrderIDs)))
+		for _, link := range span.Links() {
+			batch = append(batch, link.SpanContext.SpanID().String())
+		}
+	}
+	assert.Equal(t, orderSpans, batch)
+}
+
+func TestSettlementBatcherRestoresUnsettledOrders(t *testing.T) {
+	config.InitializeNetworks()
+	cfg := SettlementBatchConfig{MaxSize: 3, MaxAge: time.Minute, MaxAttempts: 1,
+		QueueFile: filepath.Join(t.TempDir(), "queue.json")}
+	ctx := context.Background()
+
+	// The solver stops with one order queued and one it gave up on
+	settler := &recordingSettler{failing: map[string]bool{"0x02": true}}
+	b := NewSettlementBatcher(cfg, settler.settle)
+	require.NoError(t, b.Add(ctx, batchOrder("0x01", "Base", "Optimism")))
+	require.NoError(t, b.Add(ctx, batchOrder("0x02", "Base", "Ethereum")))
+	b.flush(ctx, mustSettlementKey(t, batchOrder("0x02", "Base", "Ethereum")))
+	status, _ := b.Status("0x02")
+	require.Equal(t, SettlementFailed, status.State)
+
+	// The next run settles both
+	settler = &recordingSettler{}
+	restarted := NewSettlementBatcher(cfg, settler.settle)
+	restored, err := restarted.Restore(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, 2, restored)
+	assert.Equal(t, 2, restarted.Pending())
+	queued := restarted.queues[mustSettlementKey(t, batchOrder("0x01", "Base", "Optimism"))]
+	require.Len(t, queued, 1)
+	assert.Equal(t, batchOrder("0x01", "Base", "Optimism"), queued[0].args, "the order is read back whole")
+
+	restarted.FlushAll(ctx)
+	assert.Len(t, settler.calls, 2)
+
+	// Nothing is left to re-queue once settled
+	restored, err = NewSettlementBatcher(cfg, settler.settle).Restore(ctx)
+	require.NoError(t, err)
+	assert.Zero(t, restored)
+}
+
+func TestSettlementBatcherWithoutQueueFile(t *testing.T) {
+	restored, err := newTestBatcher(&recordingSettler{}, 2).Restore(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, restored)
+}
+
+func mustSettlementKey(t *testing.T, order *types.ParsedArgs) settlementKey {
+	t.Helper()
+	key, err := settlementKeyFor(order)
+	require.NoError(t, err)
+	return key
+}
+
+func TestSettlementBatcherRejectsMultiInstructionOrders(t *testing.T) {
+	b := newTestBatcher(&recordingSettler{}, 2)
+	order := batchOrder("0x01", "Base", "Optimism")
+	order.ResolvedOrder.FillInstructions = append(order.ResolvedOrder.FillInstructions, order.ResolvedOrder.FillInstructions[0])
+
+	assert.Error(t, b.Add(context.Background(), order))
+}
+
+func TestSettlementBatchConfigEnabled(t *testing.T) {
+	assert.False(t, SettlementBatchConfig{MaxSize: 1, MaxAge: time.Second, MaxAttempts: 1}.Enabled())
+	assert.True(t, SettlementBatchConfig{MaxSize: 5, MaxAge: time.Second, MaxAttempts: 1}.Enabled())
+}
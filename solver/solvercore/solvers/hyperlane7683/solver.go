@@ -7,36 +7,90 @@ package hyperlane7683
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"strings"
 	"sync"
 	"time"
 
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/accounting"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/history"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/inventory"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/metrics"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/tracing"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/txmanager"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 
 	"github.com/NethermindEth/starknet.go/account"
 	"github.com/NethermindEth/starknet.go/rpc"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/ethclient"
+	"go.opentelemetry.io/otel/attribute"
 )
 
+// protocolLabel is the protocol label on this solver's metrics
+const protocolLabel = "hyperlane7683"
+
 type Hyperlane7683Solver struct {
 	// Centralized client and signer management functions from SolverManager
 	getEVMClient      func(chainID uint64) (*ethclient.Client, error)
 	getStarknetClient func() (*rpc.Provider, error)
 	getEVMSigner      func(chainID uint64) (*bind.TransactOpts, error)
 	getStarknetSigner func() (*account.Account, error)
+	// Optional shared per-chain transaction managers; handlers create their own when unset
+	getEVMTxManager      func(chainID uint64) (*txmanager.EVM, error)
+	getStarknetTxManager func() (*txmanager.Starknet, error)
 
 	// Chain handlers implementing ChainHandler interface - now per-chain
 	evmHandlers       map[uint64]ChainHandler // Map of chainID -> handler
 	evmHandlersMux    sync.RWMutex            // Protects evmHandlers map
 	hyperlaneStarknet ChainHandler
 
-	// Allow/block lists for controlling which orders to process
-	allowBlockLists types.AllowBlockLists
+	// Allow/block lists for controlling which orders to process; replaceable at runtime
+	allowBlockLists   types.AllowBlockLists
+	allowBlockListsMu sync.RWMutex
+
+	// Rules evaluated before filling, and the inventory backing the balance rule
+	rulesEngine *RulesEngine
+	inventory   *inventory.Manager
+
+	// Optional batcher; when nil orders are settled right after their fill
+	settlementBatcher *SettlementBatcher
+
+	// Optional watcher refunding expired orders opened by configured accounts
+	refundWatcher *RefundWatcher
+
+	// Fill/settle progress per fill instruction, so retries skip legs that already went through
+	legProgress legTracker
+
+	// Orders currently being processed
+	inFlight inFlightTracker
+
+	// Solver's own addresses (normalised); orders they open are rebalancing orders left for other fillers
+	ownAddresses map[string]bool
+
+	// Accounts named as filler of the solver's fills; empty addresses fall back to each handler's signer
+	fillers FillerAddresses
+
+	// Margin and validity of quotes for prospective orders
+	quoteConfig QuoteConfig
+
+	// Logger for order processing, handed to the rules and chain handlers; nil logs to logutil.Default()
+	logger logutil.Logger
+
+	// Optional record of every order processed
+	history *history.Store
+
+	// Optional ledger of the tokens and fees spent and received per order
+	ledger *accounting.Ledger
+
+	// Simulate orders instead of filling and settling them
+	dryRun bool
 
 	// Metadata for this solver
 	metadata types.Hyperlane7683Metadata
@@ -48,6 +102,7 @@ func NewHyperlane7683Solver(
 	getEVMSigner func(chainID uint64) (*bind.TransactOpts, error),
 	getStarknetSigner func() (*account.Account, error),
 	allowBlockLists types.AllowBlockLists,
+	inv *inventory.Manager,
 ) *Hyperlane7683Solver {
 	metadata := types.Hyperlane7683Metadata{
 		BaseMetadata:  types.BaseMetadata{ProtocolName: "Hyperlane7683"},
@@ -56,142 +111,574 @@ func NewHyperlane7683Solver(
 	}
 
 	return &Hyperlane7683Solver{
-		getEVMClient:      getEVMClient,
-		getStarknetClient: getStarknetClient,
-		getEVMSigner:      getEVMSigner,
-		getStarknetSigner: getStarknetSigner,
-		evmHandlers:       make(map[uint64]ChainHandler),
-		evmHandlersMux:    sync.RWMutex{},
-		hyperlaneStarknet: nil, // Will be created when needed
-		allowBlockLists:   allowBlockLists,
-		metadata:          metadata,
+		getEVMClient:         getEVMClient,
+		getStarknetClient:    getStarknetClient,
+		getEVMSigner:         getEVMSigner,
+		getStarknetSigner:    getStarknetSigner,
+		getEVMTxManager:      nil,
+		getStarknetTxManager: nil,
+		evmHandlers:          make(map[uint64]ChainHandler),
+		evmHandlersMux:       sync.RWMutex{},
+		hyperlaneStarknet:    nil, // Will be created when needed
+		allowBlockLists:      allowBlockLists,
+		allowBlockListsMu:    sync.RWMutex{},
+		rulesEngine:          NewRulesEngine(inv),
+		inventory:            inv,
+		settlementBatcher:    nil,
+		refundWatcher:        nil,
+		legProgress:          legTracker{mu: sync.Mutex{}, orders: make(map[string]*OrderProgress)},
+		inFlight:             inFlightTracker{mu: sync.Mutex{}, orders: make(map[string]InFlightOrder), cancels: make(map[string]context.CancelCauseFunc)},
+		ownAddresses:         make(map[string]bool),
+		fillers:              FillerAddresses{EVM: "", Starknet: ""},
+		quoteConfig:          QuoteConfig{MarginBps: DefaultQuoteMarginBps, Validity: DefaultQuoteValidity},
+		logger:               nil,
+		history:              nil,
+		ledger:               nil,
+		dryRun:               false,
+		metadata:             metadata,
+	}
+}
+
+// SetLogger makes the solver, its rules and the chain handlers it creates from now on write to logger
+func (f *Hyperlane7683Solver) SetLogger(logger logutil.Logger) {
+	f.logger = logger
+	f.rulesEngine.SetLogger(logger)
+}
+
+// SetHistory records every order processed from now on, with its verdicts, transactions and outcome, in store
+func (f *Hyperlane7683Solver) SetHistory(store *history.Store) {
+	f.history = store
+}
+
+// SetLedger records the tokens spent and received and the fees paid for every order in ledger
+func (f *Hyperlane7683Solver) SetLedger(ledger *accounting.Ledger) {
+	f.ledger = ledger
+}
+
+func (f *Hyperlane7683Solver) log() logutil.Logger {
+	if f.logger == nil {
+		return logutil.Default()
 	}
+	return f.logger
 }
 
+// SetEVMTxManagers makes EVM handlers send through shared per-chain transaction managers
+func (f *Hyperlane7683Solver) SetEVMTxManagers(getEVMTxManager func(chainID uint64) (*txmanager.EVM, error)) {
+	f.getEVMTxManager = getEVMTxManager
+}
+
+// SetStarknetTxManager makes the Starknet handler send through a shared transaction manager
+func (f *Hyperlane7683Solver) SetStarknetTxManager(getStarknetTxManager func() (*txmanager.Starknet, error)) {
+	f.getStarknetTxManager = getStarknetTxManager
+}
+
+// EnableSettlementBatching queues filled orders and settles them in batches until ctx is cancelled.
+// Orders an earlier run left unsettled in cfg.QueueFile are queued again first.
+func (f *Hyperlane7683Solver) EnableSettlementBatching(ctx context.Context, cfg SettlementBatchConfig) {
+	f.settlementBatcher = NewSettlementBatcher(cfg, f.settleBatch)
+	f.settlementBatcher.logger = f.log()
+	restored, err := f.settlementBatcher.Restore(ctx)
+	if err != nil {
+		f.log().Error("❌ Failed to restore the settlement queue", logutil.Stage("settle"), logutil.Err(err))
+	} else if restored > 0 {
+		f.log().Info(fmt.Sprintf("📦 Re-queued %d filled order(s) left unsettled by the last run", restored), logutil.Stage("settle"))
+	}
+	go f.settlementBatcher.Start(ctx)
+}
+
+// SettlementStatus returns where a batched order is in the settlement pipeline
+func (f *Hyperlane7683Solver) SettlementStatus(orderID string) (SettlementStatus, bool) {
+	if f.settlementBatcher == nil {
+		return SettlementStatus{}, false
+	}
+	return f.settlementBatcher.Status(orderID)
+}
+
+// EnableRefundWatching refunds expired, unfilled orders opened by cfg.Accounts until ctx is cancelled
+func (f *Hyperlane7683Solver) EnableRefundWatching(ctx context.Context, cfg RefundConfig) {
+	f.refundWatcher = NewRefundWatcher(cfg, f.destinationStatus, f.originStatus, f.refundOrders)
+	f.refundWatcher.logger = f.log()
+	go f.refundWatcher.Start(ctx)
+}
+
+// RestoreRefundWatch watches the orders of watched accounts still open on scanner's settler, which its listener
+// processed before a restart; the listener hands over the orders opened after it. It does nothing without a watcher.
+func (f *Hyperlane7683Solver) RestoreRefundWatch(ctx context.Context, chain string, scanner OpenOrderScanner) {
+	if f.refundWatcher == nil {
+		return
+	}
+	restored := 0
+	err := scanner.ScanOpenOrders(ctx, func(ctx context.Context, args *types.ParsedArgs) {
+		if f.refundWatcher.Restore(ctx, args) {
+			restored++
+		}
+	})
+	logger := f.log().With(logutil.Chain(chain), logutil.Stage("refund"))
+	if err != nil {
+		logger.Error("❌ Failed to restore watched orders", logutil.Err(err))
+	}
+	if restored > 0 {
+		logger.Info(fmt.Sprintf("⏳ Watching %d order(s) opened before the restart", restored))
+	}
+}
+
+// RefundStatus returns where a watched order is in the refund flow
+func (f *Hyperlane7683Solver) RefundStatus(orderID string) (RefundStatus, bool) {
+	if f.refundWatcher == nil {
+		return RefundStatus{}, false
+	}
+	return f.refundWatcher.Status(orderID)
+}
+
+// RefundOrder refunds one expired, unfilled order from its destination chain right away
+func (f *Hyperlane7683Solver) RefundOrder(ctx context.Context, args *types.ParsedArgs) error {
+	if f.dryRun {
+		return ErrDryRun
+	}
+	status, err := f.destinationStatus(ctx, args)
+	if err != nil {
+		return fmt.Errorf("failed to read destination status: %w", err)
+	}
+	if status != orderStatusUnknown {
+		return fmt.Errorf("order %s cannot be refunded with destination status %s", args.OrderID, status)
+	}
+	key, err := settlementKeyFor(args)
+	if err != nil {
+		return err
+	}
+	return f.refundOrders(ctx, key, []*types.ParsedArgs{args})
+}
+
+// OrderStatusOnOrigin returns the order's status on its origin chain, e.g. OPENED or REFUNDED
+func (f *Hyperlane7683Solver) OrderStatusOnOrigin(ctx context.Context, args *types.ParsedArgs) (string, error) {
+	return f.originStatus(ctx, args)
+}
+
+// OrderProgress returns the per-leg fill and settle progress of an order still being processed
+func (f *Hyperlane7683Solver) OrderProgress(orderID string) (OrderProgress, bool) {
+	return f.legProgress.get(orderID)
+}
+
+// SetAllowBlockLists replaces the allow/block lists; orders already being processed are not affected
+func (f *Hyperlane7683Solver) SetAllowBlockLists(allowBlockLists types.AllowBlockLists) {
+	f.allowBlockListsMu.Lock()
+	defer f.allowBlockListsMu.Unlock()
+	f.allowBlockLists = allowBlockLists
+}
+
+// SetOwnAddresses registers the solver's addresses so it never fills orders it opened itself
+func (f *Hyperlane7683Solver) SetOwnAddresses(addresses ...string) {
+	for _, addr := range addresses {
+		if addr != "" {
+			f.ownAddresses[normalizeAddress(addr)] = true
+		}
+	}
+}
+
+// ProcessIntent validates, fills and settles an order, tracing every stage under one span keyed by the order ID
 func (f *Hyperlane7683Solver) ProcessIntent(ctx context.Context, args *types.ParsedArgs) (bool, error) {
+	ctx, span := tracing.StartOrderSpan(ctx, tracing.SpanProcessIntent, args.OrderID, orderRoute(args)...)
+	if f.history != nil {
+		f.history.Begin(args)
+	}
+	ctx = history.WithOrders(ctx, f.history, args)
+	ctx = accounting.WithOrders(ctx, f.ledger, args)
+	settled, err := f.processIntent(ctx, args)
+	tracing.End(span, err)
+	return settled, err
+}
+
+func (f *Hyperlane7683Solver) processIntent(ctx context.Context, args *types.ParsedArgs) (bool, error) {
 	// Log the cross-chain operation
-	logutil.LogOrderProcessing(args, "Processing Order")
+	logutil.OrderProcessing(f.log(), args, "Processing Order")
+
+	// The order is aborted if another solver's fill is seen while it is being processed
+	ctx, cancel := context.WithCancelCause(ctx)
+	defer cancel(nil)
+	f.inFlight.begin(args, cancel)
+	defer f.inFlight.end(args.OrderID)
+
+	// Orders from watched accounts are refunded if nobody fills them in time
+	if f.refundWatcher != nil {
+		f.refundWatcher.Observe(args)
+	}
 
 	// Check allow/block lists first
 	if !f.isAllowedIntent(args) {
-		logutil.LogOperationComplete(args, "Order processing", false)
-		return false, fmt.Errorf("order blocked by allow/block lists")
+		err := fmt.Errorf("%w: order blocked by allow/block lists", failure.ErrValidation)
+		recordOrderError(args, metrics.ErrorTypeBlocked, err)
+		logutil.OperationComplete(f.log().With(logutil.Stage("allowlist")), args, "Order processing", false)
+		f.recordStatus(args.OrderID, history.StatusRejected, err)
+		return false, err
+	}
+
+	// Filling our own order would only move tokens back to ourselves
+	if f.ownAddresses[normalizeAddress(args.SenderAddress)] {
+		f.log().Info("⏭️  Skipping order opened by this solver", logutil.OrderID(args.OrderID))
+		f.recordStatus(args.OrderID, history.StatusSkipped, nil)
+		return false, nil
 	}
 
 	// Run validation rules before processing
-	rulesEngine := NewRulesEngine()
-	if result := rulesEngine.EvaluateAll(ctx, args); !result.Passed {
-		logutil.LogOperationComplete(args, "Order validation", false)
-		return false, fmt.Errorf("order validation failed: %s", result.Reason)
+	result, verdicts := f.rulesEngine.EvaluateAllWithVerdicts(ctx, args)
+	if f.history != nil {
+		f.history.SetRules(args.OrderID, verdicts)
+	}
+	if !result.Passed {
+		// Drops the reservation taken if the order was accepted off-chain as a gasless order
+		f.releaseInventory(args.OrderID)
+		err := rejection(result)
+		recordOrderError(args, metrics.ErrorTypeValidation, err)
+		logutil.OperationComplete(f.log().With(logutil.Stage("rules")), args, "Order validation", false)
+		f.recordStatus(args.OrderID, history.StatusRejected, err)
+		return false, err
+	}
+
+	// In dry-run mode the fill and settle are simulated; nothing is sent
+	if f.dryRun {
+		return f.simulateOrder(ctx, args)
+	}
+
+	// Earmark the tokens this order will spend so concurrent orders cannot claim them
+	if f.inventory != nil {
+		if err := f.inventory.Reserve(ctx, args.OrderID, spentOutputs(args)); err != nil {
+			recordOrderError(args, metrics.ErrorTypeInventory, err)
+			logutil.OperationComplete(f.log().With(logutil.Stage("reserve")), args, "Inventory reservation", false)
+			err = fmt.Errorf("inventory reservation failed: %w", err)
+			f.recordStatus(args.OrderID, history.StatusRejected, err)
+			return false, err
+		}
 	}
 
 	// Fill method handles its own status checks efficiently (skip if already filled)
 	action, err := f.Fill(ctx, args)
 	if err != nil {
-		logutil.LogOperationComplete(args, "Fill execution", false)
-		return false, fmt.Errorf("fill execution failed: %w", err)
+		// Legs filled before the failure have already spent their tokens
+		if progress, ok := f.legProgress.get(args.OrderID); ok && progress.anyFilled() && f.inventory != nil {
+			f.inventory.Commit(args.OrderID)
+		} else {
+			f.releaseInventory(args.OrderID)
+		}
+		// Losing a fill race is part of competing for orders, not a failure
+		if errors.Is(err, failure.ErrCompetitorFilled) {
+			f.log().Info("🏁 Another solver filled the order first, leaving it to them",
+				logutil.OrderID(args.OrderID), logutil.Stage("fill"), logutil.Err(err))
+			f.recordStatus(args.OrderID, history.StatusSkipped, err)
+			return false, nil
+		}
+		recordOrderError(args, metrics.ErrorTypeFill, err)
+		logutil.OperationComplete(f.log().With(logutil.Stage("fill"), logutil.Err(err), logutil.ErrKind(err)), args, "Fill execution", false)
+		err = fmt.Errorf("fill execution failed: %w", err)
+		f.recordStatus(args.OrderID, history.StatusFailed, err)
+		return false, err
 	}
 
 	// Check if order is already complete (filled + settled)
 	if action == OrderActionComplete {
-		fmt.Printf("✅ Order already complete (filled + settled), nothing to do\n")
+		f.releaseInventory(args.OrderID)
+		f.legProgress.forget(args.OrderID)
+		f.log().Info("✅ Order already complete (filled + settled), nothing to do", logutil.OrderID(args.OrderID))
+		f.recordStatus(args.OrderID, history.StatusSettled, nil)
+		return true, nil
+	}
+
+	// Tokens have left the wallet; keep the cached balance in line until the next refresh
+	if f.inventory != nil {
+		f.inventory.Commit(args.OrderID)
+	}
+	f.recordStatus(args.OrderID, history.StatusFilled, nil)
+	if f.ledger != nil {
+		f.ledger.RecordFill(args)
+	}
+
+	// Single-instruction orders are handed to the batcher, which settles them with others from the same route
+	if action == OrderActionSettle && f.settlementBatcher != nil && len(args.ResolvedOrder.FillInstructions) == 1 {
+		if err := f.settlementBatcher.Add(ctx, args); err != nil {
+			recordOrderError(args, metrics.ErrorTypeSettle, err)
+			logutil.OperationComplete(f.log().With(logutil.Stage("settle"), logutil.Err(err)), args, "Order settlement", false)
+			err = fmt.Errorf("failed to queue order for settlement: %w", err)
+			f.recordStatus(args.OrderID, history.StatusFailed, err)
+			return false, err
+		}
+		f.legProgress.forget(args.OrderID)
+		logutil.OperationComplete(f.log(), args, "Order processing", true)
 		return true, nil
 	}
 
 	// If fill returned OrderActionSettle, we need to settle the order
 	if action == OrderActionSettle {
-		// Add a small delay to ensure fill transaction is processed before settling
-		time.Sleep(2 * time.Second)
-
-		// Settle the order
-		if err := f.SettleOrder(ctx, args); err != nil {
-			logutil.LogOperationComplete(args, "Order settlement", false)
-			return false, fmt.Errorf("order settlement failed: %w", err)
+		// Settle the order; fills return once confirmed and settlement waits for the FILLED status itself
+		err := f.SettleOrder(ctx, args)
+		if errors.Is(err, ErrSettlementDeferred) {
+			// Nothing was released on the origin chain yet: the order stays FILLED and there is nothing to book
+			f.log().Info("⏳ Order filled, settlement deferred", logutil.OrderID(args.OrderID), logutil.Stage("settle"), logutil.Err(err))
+			f.recordStatus(args.OrderID, history.StatusFilled, err)
+			return false, nil
+		}
+		if err != nil {
+			recordOrderError(args, metrics.ErrorTypeSettle, err)
+			logutil.OperationComplete(f.log().With(logutil.Stage("settle"), logutil.Err(err), logutil.ErrKind(err)), args, "Order settlement", false)
+			err = fmt.Errorf("order settlement failed: %w", err)
+			f.recordStatus(args.OrderID, history.StatusFailed, err)
+			return false, err
 		}
 	}
 
 	// Only return true when settle completes successfully
-	logutil.LogOperationComplete(args, "Order processing", true)
+	f.recordStatus(args.OrderID, history.StatusSettled, nil)
+	if f.ledger != nil {
+		f.ledger.RecordSettlement(args)
+	}
+	f.legProgress.forget(args.OrderID)
+	logutil.OperationComplete(f.log(), args, "Order processing", true)
 	return true, nil
 }
 
+// Fill fills every fill instruction (leg) of the order on its own destination chain.
+// Legs already filled are skipped; the order is complete only once every leg is settled.
 func (f *Hyperlane7683Solver) Fill(ctx context.Context, args *types.ParsedArgs) (OrderAction, error) {
-	logutil.LogOrderProcessing(args, "Filling Order")
+	logutil.OrderProcessing(f.log().With(logutil.Stage("fill")), args, "Filling Order")
+	logger := f.log().With(logutil.OrderID(args.OrderID), logutil.Stage("fill"))
 
 	if len(args.ResolvedOrder.FillInstructions) == 0 {
 		return OrderActionError, fmt.Errorf("no fill instructions found")
 	}
 
-	// Process all fill instructions (supports both single and multiple instructions)
+	progress := f.legProgress.start(args)
 	for i, instruction := range args.ResolvedOrder.FillInstructions {
-		logutil.LogWithNetworkTagf("", "Processing fill instruction %d/%d for chain %s",
-			i+1, len(args.ResolvedOrder.FillInstructions), instruction.DestinationChainID.String())
+		if progress.Legs[i].Filled {
+			logger.Info(fmt.Sprintf("Fill instruction %d/%d already filled, skipping",
+				i+1, len(args.ResolvedOrder.FillInstructions)))
+			continue
+		}
+
+		logger.Info(fmt.Sprintf("Processing fill instruction %d/%d for chain %s",
+			i+1, len(args.ResolvedOrder.FillInstructions), instruction.DestinationChainID.String()))
 
-		action, err := f.executeChainOperation(ctx, args, instruction.DestinationChainID, "fill", func(handler ChainHandler) (OrderAction, error) {
-			return handler.Fill(ctx, args)
+		leg := args.ForInstruction(i)
+		action, err := f.executeChainOperation(ctx, leg, instruction.DestinationChainID, "fill", func(ctx context.Context, handler ChainHandler) (OrderAction, error) {
+			return handler.Fill(ctx, leg)
 		})
 		if err != nil {
+			lost, err := f.lostRace(ctx, leg, instruction.DestinationChainID, err)
+			if lost {
+				recordFillRace(leg, false)
+			}
 			return OrderActionError, fmt.Errorf("fill instruction %d failed: %w", i+1, err)
 		}
-
-		// If any instruction fails or returns an error, return immediately
-		if action == OrderActionError {
-			return OrderActionError, fmt.Errorf("fill instruction %d returned error", i+1)
-		}
-
-		// If this instruction needs settlement, return that action
-		if action == OrderActionSettle {
-			logutil.LogWithNetworkTagf("", "Fill instruction %d completed, needs settlement", i+1)
-			return OrderActionSettle, nil
+		// Only a fill this call sent wins a race; one found already done on chain was counted when it was sent
+		if action == OrderActionFilled {
+			recordFillRace(leg, true)
+			action = OrderActionSettle
 		}
 
-		// If this instruction completed successfully, continue to next
-		// (In most cases there will only be one instruction, but this supports multiple)
-		if action == OrderActionComplete {
-			logutil.LogWithNetworkTagf("", "Fill instruction %d completed successfully", i+1)
+		switch action {
+		case OrderActionSettle:
+			f.legProgress.markFilled(args.OrderID, i)
+			logger.Info(fmt.Sprintf("Fill instruction %d completed, needs settlement", i+1))
+		case OrderActionComplete:
+			f.legProgress.markSettled(args.OrderID, i)
+			logger.Info(fmt.Sprintf("Fill instruction %d completed successfully", i+1))
+		default:
+			return OrderActionError, fmt.Errorf("fill instruction %d returned error", i+1)
 		}
 	}
 
-	// All instructions processed successfully
-	return OrderActionComplete, nil
+	if progress, _ := f.legProgress.get(args.OrderID); progress.Complete() {
+		return OrderActionComplete, nil
+	}
+	return OrderActionSettle, nil
 }
 
+// SettleOrder settles every filled leg of the order that is not settled yet, each on its destination chain
 func (f *Hyperlane7683Solver) SettleOrder(ctx context.Context, args *types.ParsedArgs) error {
-	logutil.LogOrderProcessing(args, "Settling Order")
+	logutil.OrderProcessing(f.log().With(logutil.Stage("settle")), args, "Settling Order")
+	logger := f.log().With(logutil.OrderID(args.OrderID), logutil.Stage("settle"))
 
 	// Settlement happens on the destination chain - same as fill
 	if len(args.ResolvedOrder.FillInstructions) == 0 {
 		return fmt.Errorf("no fill instructions found for settlement")
 	}
 
-	// Process all settlement instructions (supports both single and multiple instructions)
+	progress := f.legProgress.start(args)
 	for i, instruction := range args.ResolvedOrder.FillInstructions {
-		logutil.LogWithNetworkTagf("", "Processing settlement instruction %d/%d for chain %s",
-			i+1, len(args.ResolvedOrder.FillInstructions), instruction.DestinationChainID.String())
+		if progress.Legs[i].Settled {
+			logger.Info(fmt.Sprintf("Settlement instruction %d/%d already settled, skipping",
+				i+1, len(args.ResolvedOrder.FillInstructions)))
+			continue
+		}
+
+		logger.Info(fmt.Sprintf("Processing settlement instruction %d/%d for chain %s",
+			i+1, len(args.ResolvedOrder.FillInstructions), instruction.DestinationChainID.String()))
 
-		_, err := f.executeChainOperation(ctx, args, instruction.DestinationChainID, "settle", func(handler ChainHandler) (OrderAction, error) {
-			err := handler.Settle(ctx, args)
+		leg := args.ForInstruction(i)
+		_, err := f.executeChainOperation(ctx, leg, instruction.DestinationChainID, "settle", func(ctx context.Context, handler ChainHandler) (OrderAction, error) {
+			// A leg settled by an earlier attempt must not be settled twice
+			if status, err := handler.GetOrderStatus(ctx, leg); err == nil && status == orderStatusSettled {
+				return OrderActionComplete, nil
+			}
+			err := handler.Settle(ctx, leg)
 			return OrderActionComplete, err // Return OrderActionComplete for successful settlement
 		})
 		if err != nil {
 			return fmt.Errorf("settlement instruction %d failed: %w", i+1, err)
 		}
 
-		logutil.LogWithNetworkTagf("", "Settlement instruction %d completed successfully", i+1)
+		f.legProgress.markSettled(args.OrderID, i)
+		logger.Info(fmt.Sprintf("Settlement instruction %d completed successfully", i+1))
 	}
 
-	logutil.LogOperationComplete(args, "Settlement", true)
+	logutil.OperationComplete(f.log().With(logutil.Stage("settle")), args, "Settlement", true)
 	return nil
 }
 
+// settleBatch settles orders sharing key through the destination chain's handler
+func (f *Hyperlane7683Solver) settleBatch(ctx context.Context, key settlementKey, orders []*types.ParsedArgs) error {
+	ctx = history.WithOrders(ctx, f.history, orders...)
+	ctx = accounting.WithOrders(ctx, f.ledger, orders...)
+	chainID := new(big.Int).SetUint64(key.destinationChainID)
+	settled := make(map[string]bool, len(orders))
+	_, err := f.executeChainOperation(ctx, orders[0], chainID, "settle", func(ctx context.Context, handler ChainHandler) (OrderAction, error) {
+		// An order settled by an earlier attempt would fail the settle call and take the rest of the batch with it
+		pending := make([]*types.ParsedArgs, 0, len(orders))
+		for _, order := range orders {
+			if status, err := handler.GetOrderStatus(ctx, order); err == nil && status == orderStatusSettled {
+				settled[order.OrderID] = true
+				continue
+			}
+			pending = append(pending, order)
+		}
+		if len(pending) == 0 {
+			return OrderActionComplete, nil
+		}
+		if batcher, ok := handler.(BatchSettler); ok {
+			return OrderActionComplete, batcher.SettleBatch(ctx, pending)
+		}
+		for _, order := range pending {
+			if err := handler.Settle(ctx, order); err != nil {
+				return OrderActionError, fmt.Errorf("order %s: %w", order.OrderID, err)
+			}
+		}
+		return OrderActionComplete, nil
+	})
+	// A failed or deferred batch stays FILLED, with the error, until it is settled;
+	// an order another solver filled is theirs to settle
+	status := history.StatusSettled
+	switch {
+	case err == nil:
+	case len(orders) == 1 && errors.Is(err, failure.ErrCompetitorFilled):
+		status = history.StatusSkipped
+	default:
+		status = history.StatusFilled
+	}
+	for _, order := range orders {
+		if settled[order.OrderID] {
+			f.recordStatus(order.OrderID, history.StatusSettled, nil)
+			continue
+		}
+		f.recordStatus(order.OrderID, status, err)
+		if err == nil && f.ledger != nil {
+			f.ledger.RecordSettlement(order)
+		}
+	}
+	return err
+}
+
+// refundOrders refunds orders sharing key through the destination chain's handler
+func (f *Hyperlane7683Solver) refundOrders(ctx context.Context, key settlementKey, orders []*types.ParsedArgs) error {
+	ctx = accounting.WithOrders(ctx, f.ledger, orders...)
+	chainID := new(big.Int).SetUint64(key.destinationChainID)
+	_, err := f.executeChainOperation(ctx, orders[0], chainID, "refund", func(ctx context.Context, handler ChainHandler) (OrderAction, error) {
+		refunder, ok := handler.(Refunder)
+		if !ok {
+			return OrderActionError, fmt.Errorf("handler does not support refunds")
+		}
+		return OrderActionComplete, refunder.Refund(ctx, orders)
+	})
+	return err
+}
+
+// destinationStatus reads the order's fill status on its (single) destination chain
+func (f *Hyperlane7683Solver) destinationStatus(ctx context.Context, args *types.ParsedArgs) (string, error) {
+	if len(args.ResolvedOrder.FillInstructions) != 1 || args.ResolvedOrder.FillInstructions[0].DestinationChainID == nil {
+		return orderStatusUnknown, fmt.Errorf("order %s needs exactly one fill instruction with a destination", args.OrderID)
+	}
+	status := orderStatusUnknown
+	_, err := f.executeChainOperation(ctx, args, args.ResolvedOrder.FillInstructions[0].DestinationChainID, "status",
+		func(ctx context.Context, handler ChainHandler) (OrderAction, error) {
+			var err error
+			status, err = handler.GetOrderStatus(ctx, args)
+			return OrderActionComplete, err
+		})
+	return status, err
+}
+
+// originStatus reads the order's status from the settler on its origin chain
+func (f *Hyperlane7683Solver) originStatus(ctx context.Context, args *types.ParsedArgs) (string, error) {
+	originChainID := args.ResolvedOrder.OriginChainID
+	if originChainID == nil {
+		return orderStatusUnknown, fmt.Errorf("order %s has no origin chain", args.OrderID)
+	}
+	settler, err := f.originSettler(originChainID)
+	if err != nil {
+		return orderStatusUnknown, err
+	}
+
+	// Handlers read the status from the first instruction's settler, so point it at the origin settler
+	view := *args
+	view.ResolvedOrder.FillInstructions = []types.FillInstruction{{
+		DestinationChainID: originChainID,
+		DestinationSettler: settler,
+		OriginData:         nil,
+	}}
+	status := orderStatusUnknown
+	_, err = f.executeChainOperation(ctx, &view, originChainID, "status", func(ctx context.Context, handler ChainHandler) (OrderAction, error) {
+		var err error
+		status, err = handler.GetOrderStatus(ctx, &view)
+		return OrderActionComplete, err
+	})
+	return status, err
+}
+
+// originSettler returns the Hyperlane7683 address on chainID
+func (f *Hyperlane7683Solver) originSettler(chainID *big.Int) (string, error) {
+	if f.isStarknetChain(chainID) {
+		// Starknet addresses do not fit the 20-byte network config field
+		addr := envutil.GetEnvWithDefault("STARKNET_HYPERLANE_ADDRESS", "")
+		if addr == "" {
+			return "", fmt.Errorf("no STARKNET_HYPERLANE_ADDRESS set in .env")
+		}
+		return addr, nil
+	}
+	network, err := f.getNetworkConfigByChainID(chainID)
+	if err != nil {
+		return "", err
+	}
+	return network.HyperlaneAddress.Hex(), nil
+}
+
+// recordStatus moves the order's history record to status, if a history is attached
+func (f *Hyperlane7683Solver) recordStatus(orderID string, status history.Status, err error) {
+	if f.history != nil {
+		f.history.SetStatus(orderID, status, err)
+	}
+}
+
+// releaseInventory frees the order's reservation, if an inventory is attached
+func (f *Hyperlane7683Solver) releaseInventory(orderID string) {
+	if f.inventory != nil {
+		f.inventory.Release(orderID)
+	}
+}
+
 // executeChainOperation is a common helper that handles chain detection, handler retrieval, and operation execution
 // This eliminates duplication between Fill, Settle, and other chain operations
 func (f *Hyperlane7683Solver) executeChainOperation(
-	_ context.Context,
-	_ *types.ParsedArgs,
+	ctx context.Context,
+	args *types.ParsedArgs,
 	chainID *big.Int,
 	operation string,
-	operationFunc func(ChainHandler) (OrderAction, error),
+	operationFunc func(context.Context, ChainHandler) (OrderAction, error),
 ) (OrderAction, error) {
 	var handler ChainHandler
 	var err error
@@ -218,7 +705,11 @@ func (f *Hyperlane7683Solver) executeChainOperation(
 	}
 
 	// Execute the operation
-	action, err := operationFunc(handler)
+	ctx, span := tracing.StartOrderSpan(ctx, tracing.OperationSpanName(operation), args.OrderID, tracing.Chain(chainLabel(chainID)))
+	started := time.Now()
+	action, err := operationFunc(ctx, handler)
+	metrics.ObserveOperation(chainLabel(chainID), protocolLabel, operation, started, err)
+	tracing.End(span, err)
 	if err != nil {
 		return OrderActionError, fmt.Errorf("%s %s failed for chain %s: %w", chainType, operation, chainID.String(), err)
 	}
@@ -226,6 +717,28 @@ func (f *Hyperlane7683Solver) executeChainOperation(
 	return action, nil
 }
 
+// chainLabel names chainID for metric labels
+func chainLabel(chainID *big.Int) string {
+	if chainID == nil {
+		return "unknown"
+	}
+	return logutil.NetworkNameByChainID(chainID.Uint64())
+}
+
+// orderRoute tags an order span with the order's origin and every destination of its fill instructions
+func orderRoute(args *types.ParsedArgs) []attribute.KeyValue {
+	destinations := make([]string, 0, len(args.ResolvedOrder.FillInstructions))
+	for _, instruction := range args.ResolvedOrder.FillInstructions {
+		destinations = append(destinations, chainLabel(instruction.DestinationChainID))
+	}
+	return tracing.Route(chainLabel(args.ResolvedOrder.OriginChainID), destinations...)
+}
+
+// recordOrderError counts a failure of the order at the given stage against its origin chain, labelled with its kind
+func recordOrderError(args *types.ParsedArgs, errType string, err error) {
+	metrics.RecordError(chainLabel(args.ResolvedOrder.OriginChainID), protocolLabel, errType, err)
+}
+
 // getEVMHandler gets or creates an EVM chain handler for the given chain ID
 func (f *Hyperlane7683Solver) getEVMHandler(chainID *big.Int) (ChainHandler, error) {
 	chainIDUint := chainID.Uint64()
@@ -257,7 +770,17 @@ func (f *Hyperlane7683Solver) getEVMHandler(chainID *big.Int) (ChainHandler, err
 		return nil, fmt.Errorf("failed to get EVM signer for chain %d: %w", chainIDUint, err)
 	}
 
-	handler := NewHyperlaneEVM(client, signer, chainIDUint)
+	var handler *HyperlaneEVM
+	if f.getEVMTxManager != nil {
+		txm, err := f.getEVMTxManager(chainIDUint)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get EVM tx manager for chain %d: %w", chainIDUint, err)
+		}
+		handler = NewHyperlaneEVMWithTxManager(client, signer, chainIDUint, txm, f.log())
+	} else {
+		handler = NewHyperlaneEVM(client, signer, chainIDUint, f.log())
+	}
+	handler.SetFillerAddresses(f.fillers)
 	f.evmHandlers[chainIDUint] = handler
 	return handler, nil
 }
@@ -275,7 +798,27 @@ func (f *Hyperlane7683Solver) getStarknetHandler(chainID *big.Int) (ChainHandler
 		return nil, fmt.Errorf("starknet network not found for chain ID %s: %w", chainID.String(), err)
 	}
 
-	f.hyperlaneStarknet = NewHyperlaneStarknet(chainConfig.RPCURL, chainConfig.ChainID)
+	if f.getStarknetTxManager == nil {
+		handler := NewHyperlaneStarknet(chainConfig.RPCURL, chainConfig.ChainID, f.log())
+		if handler == nil {
+			return nil, fmt.Errorf("failed to create Starknet handler for chain ID %s", chainID.String())
+		}
+		handler.SetFillerAddresses(f.fillers)
+		f.hyperlaneStarknet = handler
+		return f.hyperlaneStarknet, nil
+	}
+
+	provider, err := f.getStarknetClient()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Starknet client: %w", err)
+	}
+	txm, err := f.getStarknetTxManager()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get Starknet tx manager: %w", err)
+	}
+	handler := NewHyperlaneStarknetWithTxManager(provider, txm, chainConfig.ChainID, f.log())
+	handler.SetFillerAddresses(f.fillers)
+	f.hyperlaneStarknet = handler
 	return f.hyperlaneStarknet, nil
 }
 
@@ -287,17 +830,7 @@ func (f *Hyperlane7683Solver) AddDefaultRules() {
 
 // Simple chain identification helpers - works with any Starknet/EVM network names
 func (f *Hyperlane7683Solver) isStarknetChain(chainID *big.Int) bool {
-	// Ensure config is initialized to prevent segfault
-	config.InitializeNetworks()
-
-	// Find any network with "Starknet" in the name that matches this chain ID
-	for networkName, network := range config.Networks {
-		if network.ChainID == chainID.Uint64() {
-			// Check if network name contains "Starknet" (case insensitive)
-			return strings.Contains(strings.ToLower(networkName), "starknet")
-		}
-	}
-	return false
+	return isStarknetChainID(chainID)
 }
 
 func (f *Hyperlane7683Solver) isEVMChain(chainID *big.Int) bool {
@@ -316,6 +849,9 @@ func (f *Hyperlane7683Solver) isEVMChain(chainID *big.Int) bool {
 
 // isAllowedIntent checks if an intent is allowed based on allow/block lists
 func (f *Hyperlane7683Solver) isAllowedIntent(args *types.ParsedArgs) bool {
+	f.allowBlockListsMu.RLock()
+	defer f.allowBlockListsMu.RUnlock()
+
 	// Check block list first
 	for _, blockItem := range f.allowBlockLists.BlockList {
 		if f.matchesAllowBlockItem(blockItem, args) {
@@ -378,3 +914,12 @@ func (f *Hyperlane7683Solver) getNetworkConfigByChainID(chainID *big.Int) (confi
 	return config.NetworkConfig{}, fmt.Errorf("network config not found for chain ID %d", chainIDUint)
 }
 
+// normalizeAddress maps EVM addresses, bytes32-padded addresses and felts to one comparable form
+func normalizeAddress(addr string) string {
+	trimmed := strings.TrimPrefix(strings.ToLower(strings.TrimSpace(addr)), "0x")
+	value, ok := new(big.Int).SetString(trimmed, 16)
+	if !ok {
+		return strings.ToLower(addr)
+	}
+	return "0x" + value.Text(16)
+}
@@ -1,8 +1,15 @@
 package hyperlane7683
 
 import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
 	"testing"
 
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/accounting"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/history"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/tracing"
 	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
 	"github.com/NethermindEth/starknet.go/account"
 	"github.com/NethermindEth/starknet.go/rpc"
@@ -10,6 +17,9 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
 )
 
 // TestHyperlane7683Solver tests the solver creation and basic functionality
@@ -40,6 +50,7 @@ func TestHyperlane7683Solver(t *testing.T) {
 			getEVMSigner,
 			getStarknetSigner,
 			allowBlockLists,
+			nil,
 		)
 
 		require.NotNil(t, solver)
@@ -91,6 +102,7 @@ func TestSolverInitialization(t *testing.T) {
 		solver := NewHyperlane7683Solver(
 			nil, nil, nil, nil,
 			allowBlockLists,
+			nil,
 		)
 
 		assert.NotNil(t, solver)
@@ -119,6 +131,7 @@ func TestSolverInitialization(t *testing.T) {
 		solver := NewHyperlane7683Solver(
 			nil, nil, nil, nil,
 			allowBlockLists,
+			nil,
 		)
 
 		assert.NotNil(t, solver)
@@ -146,6 +159,7 @@ func TestSolverConcurrency(t *testing.T) {
 				solver := NewHyperlane7683Solver(
 					nil, nil, nil, nil,
 					allowBlockLists,
+					nil,
 				)
 				solvers[index] = solver
 				done <- true
@@ -163,3 +177,220 @@ func TestSolverConcurrency(t *testing.T) {
 		}
 	})
 }
+
+// recordSpans installs a provider that keeps ended spans in memory for the duration of the test
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+// spanAttribute returns the string value of key on span, or "" if it is not set
+func spanAttribute(span sdktrace.ReadOnlySpan, key string) string {
+	for _, kv := range span.Attributes() {
+		if string(kv.Key) == key {
+			return kv.Value.Emit()
+		}
+	}
+	return ""
+}
+
+func TestProcessIntentTracesOrder(t *testing.T) {
+	recorder := recordSpans(t)
+	solver := newMultiLegSolver(&legHandler{}, &legHandler{})
+	solver.rulesEngine = &RulesEngine{rules: []Rule{&MockRule{name: "mock", shouldPass: true}}}
+	order := multiLegOrder()
+
+	detectCtx, detected := tracing.StartOrderSpan(context.Background(), tracing.SpanOrderDetected, order.OrderID)
+	ok, err := solver.ProcessIntent(detectCtx, order)
+	detected.End()
+	require.NoError(t, err)
+	assert.True(t, ok)
+
+	spans := recorder.Ended()
+	byName := make(map[string][]sdktrace.ReadOnlySpan)
+	for _, span := range spans {
+		// Every stage belongs to the trace started when the order was detected, keyed by its ID
+		assert.Equal(t, detected.SpanContext().TraceID(), span.SpanContext().TraceID(), span.Name())
+		assert.Equal(t, order.OrderID, spanAttribute(span, string(tracing.KeyOrderID)), span.Name())
+		byName[span.Name()] = append(byName[span.Name()], span)
+	}
+
+	require.Len(t, byName[tracing.SpanProcessIntent], 1)
+	process := byName[tracing.SpanProcessIntent][0]
+	assert.Equal(t, detected.SpanContext().SpanID(), process.Parent().SpanID())
+	assert.Equal(t, "Ethereum", spanAttribute(process, string(tracing.KeyOriginChain)))
+	assert.Equal(t, "Base,Optimism", spanAttribute(process, string(tracing.KeyDestinationChain)))
+
+	require.Len(t, byName[tracing.SpanRules], 1)
+	require.Len(t, byName[tracing.SpanRule], 1)
+	assert.Equal(t, process.SpanContext().SpanID(), byName[tracing.SpanRules][0].Parent().SpanID())
+	assert.Equal(t, "mock", spanAttribute(byName[tracing.SpanRule][0], string(tracing.KeyRule)))
+	assert.Equal(t, "true", spanAttribute(byName[tracing.SpanRule][0], string(tracing.KeyRulePassed)))
+
+	// One fill and one settlement per leg, each on its destination chain
+	for _, operation := range []string{"fill", "settle"} {
+		legs := byName[tracing.OperationSpanName(operation)]
+		require.Len(t, legs, 2, operation)
+		chains := []string{spanAttribute(legs[0], string(tracing.KeyChain)), spanAttribute(legs[1], string(tracing.KeyChain))}
+		assert.ElementsMatch(t, []string{"Base", "Optimism"}, chains, operation)
+		assert.Equal(t, process.SpanContext().SpanID(), legs[0].Parent().SpanID(), operation)
+	}
+}
+
+func TestProcessIntentRecordsHistory(t *testing.T) {
+	store, err := history.Open(filepath.Join(t.TempDir(), "orders.jsonl"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+	ctx := context.Background()
+
+	t.Run("settled", func(t *testing.T) {
+		solver := newMultiLegSolver(&legHandler{}, &legHandler{})
+		solver.SetHistory(store)
+		solver.rulesEngine = &RulesEngine{rules: []Rule{&MockRule{name: "mock", shouldPass: true}}}
+		order := multiLegOrder()
+
+		ok, err := solver.ProcessIntent(ctx, order)
+		require.NoError(t, err)
+		assert.True(t, ok)
+
+		record, err := store.Get(order.OrderID)
+		require.NoError(t, err)
+		assert.Equal(t, history.StatusSettled, record.Status)
+		assert.Equal(t, "Ethereum", record.Origin)
+		assert.ElementsMatch(t, []string{"Base", "Optimism"}, record.Destinations)
+		assert.Equal(t, []history.RuleVerdict{{Rule: "mock", Passed: true, Reason: "Mock rule passed"}}, record.Rules)
+		assert.False(t, record.FilledAt.IsZero())
+		assert.False(t, record.SettledAt.IsZero())
+	})
+
+	t.Run("rejected by a rule", func(t *testing.T) {
+		solver := newMultiLegSolver(&legHandler{}, &legHandler{})
+		solver.SetHistory(store)
+		solver.rulesEngine = &RulesEngine{rules: []Rule{&MockRule{name: "mock", shouldPass: false}}}
+		order := multiLegOrder()
+		order.OrderID = "0xrejected"
+
+		_, err := solver.ProcessIntent(ctx, order)
+		require.Error(t, err)
+
+		record, err := store.Get(order.OrderID)
+		require.NoError(t, err)
+		assert.Equal(t, history.StatusRejected, record.Status)
+		assert.Contains(t, record.Error, "order validation failed")
+		require.Len(t, record.Rules, 1)
+		assert.False(t, record.Rules[0].Passed)
+	})
+
+	t.Run("failed fill", func(t *testing.T) {
+		solver := newMultiLegSolver(&legHandler{}, &legHandler{fillErr: errors.New("insufficient allowance")})
+		solver.SetHistory(store)
+		solver.rulesEngine = &RulesEngine{rules: []Rule{&MockRule{name: "mock", shouldPass: true}}}
+		order := multiLegOrder()
+		order.OrderID = "0xfailed"
+
+		_, err := solver.ProcessIntent(ctx, order)
+		require.Error(t, err)
+
+		record, err := store.Get(order.OrderID)
+		require.NoError(t, err)
+		assert.Equal(t, history.StatusFailed, record.Status)
+		assert.Contains(t, record.Error, "insufficient allowance")
+		assert.True(t, record.FilledAt.IsZero())
+	})
+}
+
+func TestProcessIntentRecordsProfitAndLoss(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	ledger, err := accounting.Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ledger.Close() })
+
+	solver := newMultiLegSolver(&legHandler{}, &legHandler{})
+	solver.SetLedger(ledger)
+	solver.rulesEngine = &RulesEngine{rules: []Rule{&MockRule{name: "mock", shouldPass: true}}}
+	order := multiLegOrder()
+
+	ok, err := solver.ProcessIntent(context.Background(), order)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	entries, err := accounting.ReadEntries(path)
+	require.NoError(t, err)
+	kinds := make(map[accounting.Kind]int)
+	for _, entry := range entries {
+		assert.Equal(t, order.OrderID, entry.OrderID)
+		assert.Equal(t, "Ethereum", entry.Origin)
+		assert.Equal(t, "Base,Optimism", entry.Destination)
+		kinds[entry.Kind]++
+	}
+	assert.Equal(t, len(order.ResolvedOrder.MaxSpent), kinds[accounting.KindSpent])
+	assert.Equal(t, len(order.ResolvedOrder.MinReceived), kinds[accounting.KindReceived])
+}
+
+func TestDeferredSettlementStaysFilled(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "ledger.jsonl")
+	ledger, err := accounting.Open(path)
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = ledger.Close() })
+
+	deferred := fmt.Errorf("%w: domain not registered", ErrSettlementDeferred)
+	solver := newMultiLegSolver(&legHandler{settleErr: deferred}, &legHandler{})
+	solver.SetLedger(ledger)
+	solver.rulesEngine = &RulesEngine{rules: []Rule{&MockRule{name: "mock", shouldPass: true}}}
+	order := multiLegOrder()
+	order.ResolvedOrder.FillInstructions = order.ResolvedOrder.FillInstructions[:1]
+
+	store, err := history.Open(filepath.Join(t.TempDir(), "orders.jsonl"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+	solver.SetHistory(store)
+
+	settled, err := solver.ProcessIntent(context.Background(), order)
+	require.NoError(t, err, "a deferred settlement is not a failure")
+	assert.False(t, settled)
+
+	record, err := store.Get(order.OrderID)
+	require.NoError(t, err)
+	assert.Equal(t, history.StatusFilled, record.Status)
+
+	entries, err := accounting.ReadEntries(path)
+	require.NoError(t, err)
+	for _, entry := range entries {
+		assert.NotEqual(t, accounting.KindReceived, entry.Kind, "nothing was released on the origin chain")
+	}
+}
+
+func TestSettleBatchSkipsSettledOrders(t *testing.T) {
+	handler := &legHandler{statuses: map[string]string{"settled": orderStatusSettled}}
+	solver := newMultiLegSolver(&legHandler{}, handler)
+	store, err := history.Open(filepath.Join(t.TempDir(), "orders.jsonl"))
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = store.Close() })
+	solver.SetHistory(store)
+
+	order := func(orderID, settler string) *types.ParsedArgs {
+		args := multiLegOrder()
+		args.OrderID = orderID
+		args.ResolvedOrder.FillInstructions = args.ResolvedOrder.FillInstructions[1:]
+		args.ResolvedOrder.FillInstructions[0].DestinationSettler = settler
+		return args
+	}
+	orders := []*types.ParsedArgs{order("0x01", "settled"), order("0x02", "filled")}
+	for _, args := range orders {
+		store.Begin(args)
+	}
+	key, err := settlementKeyFor(orders[1])
+	require.NoError(t, err)
+
+	require.NoError(t, solver.settleBatch(context.Background(), key, orders))
+	assert.Equal(t, []string{"filled"}, handler.settles, "the settled order is not settled again")
+	for _, args := range orders {
+		record, err := store.Get(args.OrderID)
+		require.NoError(t, err)
+		assert.Equal(t, history.StatusSettled, record.Status)
+	}
+}
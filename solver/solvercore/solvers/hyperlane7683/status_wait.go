@@ -0,0 +1,122 @@
+package hyperlane7683
+
+// Module: Confirmation-driven order status waits
+// - Re-reads an order's on-chain status once per new block instead of on a fixed backoff
+// - Gives up after the chain's confirmation depth in new blocks, or after a time cap on chains that mine on demand
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/config"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+)
+
+const (
+	// statusPollInterval is how often a status wait checks for a new block
+	statusPollInterval = 500 * time.Millisecond
+	// statusWaitTimeout bounds a status wait on chains that produce no new blocks, such as devnets mining on demand
+	statusWaitTimeout = 30 * time.Second
+)
+
+// statusWait waits for an order status written by a confirmed transaction to become visible.
+// Transactions are only reported once they reached the chain's confirmation depth, so the first read normally
+// matches; later reads cover RPC nodes lagging behind the one that returned the receipt.
+type statusWait struct {
+	blocks BlockNumberProvider
+	// depth is the number of new blocks re-read before giving up
+	depth   uint64
+	poll    time.Duration
+	timeout time.Duration
+	logger  logutil.Logger
+}
+
+// newStatusWait creates a status wait on chainID, reading new blocks from blocks
+func newStatusWait(blocks BlockNumberProvider, chainID uint64, logger logutil.Logger) statusWait {
+	depth, err := config.GetTxConfirmationsByChainID(chainID)
+	if err != nil || depth == 0 {
+		depth = config.DefaultTxConfirmations
+	}
+	return statusWait{
+		blocks:  blocks,
+		depth:   depth,
+		poll:    statusPollInterval,
+		timeout: statusWaitTimeout,
+		logger:  logger,
+	}
+}
+
+// until reads the status with read until it equals expected, re-reading it on every new block.
+// Without a match it returns the last status read once depth new blocks were mined or the timeout expired.
+func (w statusWait) until(ctx context.Context, expected string, read func(context.Context) (string, error)) (string, error) {
+	deadline := time.NewTimer(w.timeout)
+	defer deadline.Stop()
+	ticker := time.NewTicker(w.poll)
+	defer ticker.Stop()
+
+	maxAttempts := int(w.depth) + 1
+	// Without a baseline, any block would look new; nextBlock then takes the first block it reads as the baseline
+	lastBlock, blockErr := w.blocks.BlockNumber(ctx)
+	haveBlock := blockErr == nil
+
+	var (
+		status  string
+		err     error
+		attempt int
+	)
+	for attempt = 1; ; attempt++ {
+		status, err = read(ctx)
+		if err != nil {
+			w.logger.Warn(fmt.Sprintf("   ⚠️  Status check attempt %d failed", attempt), logutil.Err(err))
+		} else {
+			logutil.StatusCheck(w.logger, attempt, maxAttempts, status, expected)
+			if status == expected {
+				return status, nil
+			}
+		}
+		if attempt >= maxAttempts {
+			break
+		}
+
+		block, ok, waitErr := w.nextBlock(ctx, lastBlock, haveBlock, ticker.C, deadline.C)
+		if waitErr != nil {
+			return "", waitErr
+		}
+		if !ok {
+			break
+		}
+		lastBlock, haveBlock = block, true
+	}
+
+	if err != nil {
+		return orderStatusUnknown, fmt.Errorf("status check failed after %d attempts: %w", attempt, err)
+	}
+	return status, nil
+}
+
+// nextBlock waits for a block after last and returns it, or false when the wait timed out first.
+// When last is not known, the first block read successfully becomes it and only later blocks count as new.
+func (w statusWait) nextBlock(ctx context.Context, last uint64, known bool, tick, deadline <-chan time.Time) (uint64, bool, error) {
+	for {
+		select {
+		case <-ctx.Done():
+			return 0, false, ctx.Err()
+		case <-deadline:
+			w.logger.Info(fmt.Sprintf("⏳ No new block after %s, giving up on the status", w.timeout))
+			return 0, false, nil
+		case <-tick:
+		}
+		block, err := w.blocks.BlockNumber(ctx)
+		if err != nil {
+			continue
+		}
+		if !known {
+			last, known = block, true
+			continue
+		}
+		if block > last {
+			return block, true, nil
+		}
+	}
+}
@@ -0,0 +1,171 @@
+package hyperlane7683
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+)
+
+// fakeBlocks is a BlockNumberProvider whose head advances on demand; its first failures reads fail
+type fakeBlocks struct {
+	mu       sync.Mutex
+	head     uint64
+	failures int
+}
+
+func (b *fakeBlocks) BlockNumber(context.Context) (uint64, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures > 0 {
+		b.failures--
+		return 0, errors.New("rpc unavailable")
+	}
+	return b.head, nil
+}
+
+func (b *fakeBlocks) advance() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.head++
+}
+
+func testStatusWait(blocks *fakeBlocks, depth uint64) statusWait {
+	return statusWait{blocks: blocks, depth: depth, poll: time.Millisecond, timeout: time.Second, logger: logutil.Default()}
+}
+
+func TestStatusWaitMatchesWithoutWaiting(t *testing.T) {
+	blocks := &fakeBlocks{}
+	reads := 0
+	status, err := testStatusWait(blocks, 3).until(context.Background(), orderStatusFilled, func(context.Context) (string, error) {
+		reads++
+		return orderStatusFilled, nil
+	})
+	require.NoError(t, err)
+	assert.Equal(t, orderStatusFilled, status)
+	assert.Equal(t, 1, reads)
+}
+
+func TestStatusWaitRereadsOnNewBlocks(t *testing.T) {
+	blocks := &fakeBlocks{}
+	var mu sync.Mutex
+	reads := 0
+	read := func(context.Context) (string, error) {
+		mu.Lock()
+		defer mu.Unlock()
+		reads++
+		if reads == 3 {
+			return orderStatusFilled, nil
+		}
+		return orderStatusOpened, nil
+	}
+	readCount := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return reads
+	}
+
+	done := make(chan string, 1)
+	go func() {
+		status, err := testStatusWait(blocks, 3).until(context.Background(), orderStatusFilled, read)
+		assert.NoError(t, err)
+		done <- status
+	}()
+
+	require.Eventually(t, func() bool { return readCount() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 1, readCount(), "the status is not re-read without a new block")
+
+	blocks.advance()
+	require.Eventually(t, func() bool { return readCount() == 2 }, time.Second, time.Millisecond)
+	blocks.advance()
+	select {
+	case status := <-done:
+		assert.Equal(t, orderStatusFilled, status)
+	case <-time.After(time.Second):
+		t.Fatal("status wait did not return")
+	}
+}
+
+func TestStatusWaitWaitsForBlocksAfterFailedBaseline(t *testing.T) {
+	blocks := &fakeBlocks{head: 100, failures: 1}
+	var mu sync.Mutex
+	reads := 0
+	readCount := func() int {
+		mu.Lock()
+		defer mu.Unlock()
+		return reads
+	}
+
+	done := make(chan string, 1)
+	go func() {
+		status, err := testStatusWait(blocks, 2).until(context.Background(), orderStatusFilled, func(context.Context) (string, error) {
+			mu.Lock()
+			defer mu.Unlock()
+			reads++
+			return orderStatusOpened, nil
+		})
+		assert.NoError(t, err)
+		done <- status
+	}()
+
+	require.Eventually(t, func() bool { return readCount() == 1 }, time.Second, time.Millisecond)
+	time.Sleep(20 * time.Millisecond)
+	assert.Equal(t, 1, readCount(), "the current head is the baseline, not a new block")
+
+	blocks.advance()
+	require.Eventually(t, func() bool { return readCount() == 2 }, time.Second, time.Millisecond)
+	blocks.advance()
+	select {
+	case status := <-done:
+		assert.Equal(t, orderStatusOpened, status)
+		assert.Equal(t, 3, readCount())
+	case <-time.After(time.Second):
+		t.Fatal("status wait did not return")
+	}
+}
+
+func TestStatusWaitGivesUp(t *testing.T) {
+	t.Run("after the confirmation depth in new blocks", func(t *testing.T) {
+		blocks := &fakeBlocks{}
+		go func() {
+			for i := 0; i < 10; i++ {
+				time.Sleep(5 * time.Millisecond)
+				blocks.advance()
+			}
+		}()
+		reads := 0
+		status, err := testStatusWait(blocks, 2).until(context.Background(), orderStatusFilled, func(context.Context) (string, error) {
+			reads++
+			return orderStatusOpened, nil
+		})
+		require.NoError(t, err)
+		assert.Equal(t, orderStatusOpened, status)
+		assert.Equal(t, 3, reads)
+	})
+
+	t.Run("after the timeout without new blocks", func(t *testing.T) {
+		wait := testStatusWait(&fakeBlocks{}, 5)
+		wait.timeout = 20 * time.Millisecond
+		status, err := wait.until(context.Background(), orderStatusFilled, func(context.Context) (string, error) {
+			return "", errors.New("rpc unavailable")
+		})
+		require.Error(t, err)
+		assert.Equal(t, orderStatusUnknown, status)
+	})
+
+	t.Run("when the context is cancelled", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := testStatusWait(&fakeBlocks{}, 5).until(ctx, orderStatusFilled, func(context.Context) (string, error) {
+			return orderStatusOpened, nil
+		})
+		assert.ErrorIs(t, err, context.Canceled)
+	})
+}
@@ -0,0 +1,207 @@
+package tracing
+
+// Module: OpenTelemetry tracing of the order lifecycle
+// - Spans for event detection, rule evaluation, approvals, fills, status polling and settlement
+// - Every order span carries the order ID and its route, so one trace shows where an order's time went
+// - Exported over OTLP/HTTP to a collector (TRACING_OTLP_ENDPOINT); without one, spans are no-ops
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+const (
+	instrumentationName = "github.com/NethermindEth/oif-starknet/solver"
+	defaultServiceName  = "oif-solver"
+	shutdownTimeout     = 5 * time.Second
+)
+
+// Span names, one per stage of an order's life
+const (
+	SpanOrderDetected   = "order.detected"
+	SpanProcessIntent   = "order.process"
+	SpanRules           = "order.rules"
+	SpanRule            = "order.rule"
+	SpanApprove         = "order.approve"
+	SpanWaitStatus      = "order.wait_status"
+	SpanPreflight       = "order.preflight"
+	SpanSettlementBatch = "settlement.batch"
+
+	// spanOperationPrefix names the span of a chain operation (fill, settle, status, refund)
+	spanOperationPrefix = "order."
+)
+
+// Attribute keys set on order spans
+const (
+	KeyOrderID          = attribute.Key("order.id")
+	KeyChain            = attribute.Key("chain")
+	KeyOriginChain      = attribute.Key("chain.origin")
+	KeyDestinationChain = attribute.Key("chain.destination")
+	KeyBlock            = attribute.Key("block")
+	KeyTxHash           = attribute.Key("tx.hash")
+	KeyRule             = attribute.Key("rule")
+	KeyRulePassed       = attribute.Key("rule.passed")
+	KeyReason           = attribute.Key("reason")
+	KeyStatus           = attribute.Key("order.status")
+	KeyOrderIDs         = attribute.Key("order.ids")
+	KeyOrderCount       = attribute.Key("order.count")
+	KeyBatchID          = attribute.Key("batch.id")
+	KeyOperation        = attribute.Key("operation")
+)
+
+// Config configures span export
+type Config struct {
+	// Endpoint is the host:port of an OTLP/HTTP collector; empty disables export
+	Endpoint string
+	// Insecure sends spans over plain HTTP, as local collectors expect
+	Insecure bool
+	// ServiceName is reported as the service.name resource attribute
+	ServiceName string
+	// SampleRatio is the fraction of new traces kept, between 0 and 1
+	SampleRatio float64
+}
+
+// ConfigFromEnv reads TRACING_OTLP_ENDPOINT, TRACING_INSECURE, TRACING_SERVICE_NAME and TRACING_SAMPLE_RATIO
+func ConfigFromEnv() Config {
+	return Config{
+		Endpoint:    envutil.GetEnvWithDefault("TRACING_OTLP_ENDPOINT", ""),
+		Insecure:    envutil.GetEnvWithDefault("TRACING_INSECURE", "true") == "true",
+		ServiceName: envutil.GetEnvWithDefault("TRACING_SERVICE_NAME", defaultServiceName),
+		SampleRatio: envutil.GetEnvFloat64("TRACING_SAMPLE_RATIO", 1),
+	}
+}
+
+// Enabled reports whether spans should be exported
+func (c Config) Enabled() bool {
+	return c.Endpoint != ""
+}
+
+// Start installs a tracer provider exporting to cfg.Endpoint and flushes it once ctx is done
+func Start(ctx context.Context, cfg Config) error {
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(cfg.Endpoint)}
+	if cfg.Insecure {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return fmt.Errorf("failed to create OTLP exporter for %s: %w", cfg.Endpoint, err)
+	}
+
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(cfg.ServiceName)))
+	if err != nil {
+		return fmt.Errorf("failed to build tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(cfg.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+		defer cancel()
+		if err := provider.Shutdown(shutdownCtx); err != nil {
+			logutil.Default().Warn("⚠️  Failed to flush traces", logutil.Err(err))
+		}
+	}()
+
+	logutil.Default().Info(fmt.Sprintf("🔭 Exporting traces to %s", cfg.Endpoint))
+	return nil
+}
+
+// Tracer returns the solver's tracer from the global provider; it is a no-op until Start runs
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// StartSpan starts a span named name as a child of any span in ctx
+func StartSpan(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// StartOrderSpan starts a span for one stage of the order orderID
+func StartOrderSpan(ctx context.Context, name, orderID string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	return StartSpan(ctx, name, append([]attribute.KeyValue{OrderID(orderID)}, attrs...)...)
+}
+
+// OperationSpanName names the span of a chain operation such as "fill" or "settle"
+func OperationSpanName(operation string) string {
+	return spanOperationPrefix + operation
+}
+
+// Annotate adds attributes, such as the hash of a transaction just sent, to the span in ctx
+func Annotate(ctx context.Context, attrs ...attribute.KeyValue) {
+	trace.SpanFromContext(ctx).SetAttributes(attrs...)
+}
+
+// Event records a named point in time, such as one of several transactions, on the span in ctx
+func Event(ctx context.Context, name string, attrs ...attribute.KeyValue) {
+	trace.SpanFromContext(ctx).AddEvent(name, trace.WithAttributes(attrs...))
+}
+
+// End records err on span, if any, and ends it
+func End(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// OrderID tags a span with the order it belongs to
+func OrderID(orderID string) attribute.KeyValue {
+	return KeyOrderID.String(orderID)
+}
+
+// OrderIDs tags a span covering several orders with all their IDs
+func OrderIDs(orders ...*types.ParsedArgs) attribute.KeyValue {
+	ids := make([]string, len(orders))
+	for i, order := range orders {
+		ids[i] = order.OrderID
+	}
+	return KeyOrderIDs.StringSlice(ids)
+}
+
+// Chain tags a span with the network it ran against
+func Chain(name string) attribute.KeyValue {
+	return KeyChain.String(name)
+}
+
+// Route tags a span with an order's origin network and its destination networks, comma-separated
+func Route(origin string, destinations ...string) []attribute.KeyValue {
+	return []attribute.KeyValue{
+		KeyOriginChain.String(origin),
+		KeyDestinationChain.String(strings.Join(destinations, ",")),
+	}
+}
+
+// Block tags a span with a block number
+func Block(number uint64) attribute.KeyValue {
+	return KeyBlock.Int64(int64(number)) //nolint:gosec // block numbers fit in int64
+}
+
+// TxHash tags a span with a transaction hash
+func TxHash(hash string) attribute.KeyValue {
+	return KeyTxHash.String(hash)
+}
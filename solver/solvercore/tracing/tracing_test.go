@@ -0,0 +1,98 @@
+package tracing
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/types"
+)
+
+// recordSpans installs a provider that keeps ended spans in memory for the duration of the test
+func recordSpans(t *testing.T) *tracetest.SpanRecorder {
+	t.Helper()
+	recorder := tracetest.NewSpanRecorder()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	t.Cleanup(func() { otel.SetTracerProvider(previous) })
+	return recorder
+}
+
+func attributes(span sdktrace.ReadOnlySpan) map[attribute.Key]attribute.Value {
+	values := make(map[attribute.Key]attribute.Value)
+	for _, kv := range span.Attributes() {
+		values[kv.Key] = kv.Value
+	}
+	return values
+}
+
+func TestConfigFromEnv(t *testing.T) {
+	t.Run("disabled without an endpoint", func(t *testing.T) {
+		t.Setenv("TRACING_OTLP_ENDPOINT", "")
+		cfg := ConfigFromEnv()
+		assert.False(t, cfg.Enabled())
+		assert.True(t, cfg.Insecure)
+		assert.Equal(t, defaultServiceName, cfg.ServiceName)
+		assert.InDelta(t, 1.0, cfg.SampleRatio, 0)
+	})
+
+	t.Run("reads every setting", func(t *testing.T) {
+		t.Setenv("TRACING_OTLP_ENDPOINT", "localhost:4318")
+		t.Setenv("TRACING_INSECURE", "false")
+		t.Setenv("TRACING_SERVICE_NAME", "solver-eu")
+		t.Setenv("TRACING_SAMPLE_RATIO", "0.25")
+		cfg := ConfigFromEnv()
+		assert.True(t, cfg.Enabled())
+		assert.Equal(t, "localhost:4318", cfg.Endpoint)
+		assert.False(t, cfg.Insecure)
+		assert.Equal(t, "solver-eu", cfg.ServiceName)
+		assert.InDelta(t, 0.25, cfg.SampleRatio, 0)
+	})
+}
+
+func TestOrderSpans(t *testing.T) {
+	recorder := recordSpans(t)
+
+	ctx, parent := StartOrderSpan(context.Background(), SpanProcessIntent, "0xabc", Route("Ethereum", "Base", "Optimism")...)
+	fillCtx, fill := StartOrderSpan(ctx, OperationSpanName("fill"), "0xabc", Chain("Base"))
+	Annotate(fillCtx, TxHash("0xdef"))
+	Event(fillCtx, "approval confirmed", TxHash("0x123"))
+	End(fill, errors.New("reverted"))
+	End(parent, nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+
+	fillSpan, processSpan := spans[0], spans[1]
+	assert.Equal(t, "order.fill", fillSpan.Name())
+	assert.Equal(t, processSpan.SpanContext().SpanID(), fillSpan.Parent().SpanID())
+	assert.Equal(t, processSpan.SpanContext().TraceID(), fillSpan.SpanContext().TraceID())
+
+	fillAttrs := attributes(fillSpan)
+	assert.Equal(t, "0xabc", fillAttrs[KeyOrderID].AsString())
+	assert.Equal(t, "Base", fillAttrs[KeyChain].AsString())
+	assert.Equal(t, "0xdef", fillAttrs[KeyTxHash].AsString())
+	assert.Equal(t, codes.Error, fillSpan.Status().Code)
+	assert.Equal(t, "reverted", fillSpan.Status().Description)
+	require.Len(t, fillSpan.Events(), 2) // the approval and the recorded error
+	assert.Equal(t, "approval confirmed", fillSpan.Events()[0].Name)
+
+	processAttrs := attributes(processSpan)
+	assert.Equal(t, "Ethereum", processAttrs[KeyOriginChain].AsString())
+	assert.Equal(t, "Base,Optimism", processAttrs[KeyDestinationChain].AsString())
+	assert.Equal(t, codes.Unset, processSpan.Status().Code)
+}
+
+func TestOrderIDs(t *testing.T) {
+	kv := OrderIDs(&types.ParsedArgs{OrderID: "0x1"}, &types.ParsedArgs{OrderID: "0x2"})
+	assert.Equal(t, KeyOrderIDs, kv.Key)
+	assert.Equal(t, []string{"0x1", "0x2"}, kv.Value.AsStringSlice())
+}
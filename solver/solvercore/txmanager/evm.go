@@ -0,0 +1,558 @@
+package txmanager
+
+// Module: EVM transaction manager
+// - Hands out nonces from a local counter so concurrent senders on one key never collide
+// - Prices transactions with EIP-1559 fee caps (legacy gas price on chains without a base fee)
+// - Rebroadcasts stuck transactions with bumped fees under the same nonce
+// - Detects dropped and externally replaced transactions
+// - Returns the final receipt of whichever broadcast version was mined, once it is buried under the configured confirmations
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+// Defaults used when the corresponding environment variables are unset
+const (
+	DefaultResubmitInterval   = 30 * time.Second
+	DefaultReceiptTimeout     = 10 * time.Minute
+	DefaultPollInterval       = 2 * time.Second
+	DefaultFeeBumpPercent     = 15
+	DefaultMaxResubmissions   = 5
+	DefaultGasLimitMultiplier = 1.2
+	DefaultConfirmations      = 1
+
+	// minFeeBumpPercent is the replacement threshold enforced by geth-based mempools
+	minFeeBumpPercent = 10
+	baseFeeMultiplier = 2
+	percent           = 100
+)
+
+var (
+	// ErrTransactionReverted is returned together with the receipt of a mined transaction that failed
+	ErrTransactionReverted = failure.Mark(errors.New("transaction reverted"), failure.ErrReverted)
+	// ErrTransactionReplaced is returned when the nonce was consumed by a transaction this manager did not send
+	ErrTransactionReplaced = failure.Mark(errors.New("transaction nonce consumed by another transaction"), failure.ErrNetwork)
+	// ErrTransactionTimeout is returned when no broadcast version was mined within the receipt timeout
+	ErrTransactionTimeout = failure.Mark(errors.New("timed out waiting for transaction receipt"), failure.ErrNetwork)
+	// ErrFeeCapExceeded is returned when the current base fee is already above the configured fee cap
+	ErrFeeCapExceeded = failure.Mark(errors.New("network fee exceeds configured maximum"), failure.ErrInsufficientFunds)
+)
+
+// EVMBackend is the subset of ethclient.Client used by the manager
+type EVMBackend interface {
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	NonceAt(ctx context.Context, account common.Address, blockNumber *big.Int) (uint64, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*gethtypes.Header, error)
+	SuggestGasTipCap(ctx context.Context) (*big.Int, error)
+	SuggestGasPrice(ctx context.Context) (*big.Int, error)
+	EstimateGas(ctx context.Context, msg ethereum.CallMsg) (uint64, error)
+	CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error)
+	SendTransaction(ctx context.Context, tx *gethtypes.Transaction) error
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*gethtypes.Receipt, error)
+	TransactionByHash(ctx context.Context, hash common.Hash) (tx *gethtypes.Transaction, isPending bool, err error)
+}
+
+// EVMConfig controls pricing and rebroadcast behaviour
+type EVMConfig struct {
+	// ResubmitInterval is how long a broadcast may stay unmined before it is re-sent with bumped fees
+	ResubmitInterval time.Duration
+	// ReceiptTimeout bounds the total wait for a receipt
+	ReceiptTimeout time.Duration
+	PollInterval   time.Duration
+	// FeeBumpPercent is applied to both the tip and the fee cap on every resubmission (minimum 10)
+	FeeBumpPercent   uint64
+	MaxResubmissions int
+	// MaxFeePerGas caps the fee cap (or legacy gas price); nil means uncapped
+	MaxFeePerGas       *big.Int
+	GasLimitMultiplier float64
+	// Confirmations is the number of blocks, counting the one the transaction is mined in, a receipt must be buried
+	// under before Send returns it (1 = mined). It is per chain, so callers set it from the network config.
+	Confirmations uint64
+}
+
+// EVMConfigFromEnv builds a config from MAX_GAS_PRICE_WEI, GAS_LIMIT_MULTIPLIER and the EVM_TX_* variables
+func EVMConfigFromEnv() EVMConfig {
+	cfg := EVMConfig{
+		ResubmitInterval:   envDuration("EVM_TX_RESUBMIT_INTERVAL_MS", DefaultResubmitInterval),
+		ReceiptTimeout:     envDuration("EVM_TX_RECEIPT_TIMEOUT_MS", DefaultReceiptTimeout),
+		PollInterval:       DefaultPollInterval,
+		FeeBumpPercent:     envutil.GetEnvUint64("EVM_TX_FEE_BUMP_PERCENT", DefaultFeeBumpPercent),
+		MaxResubmissions:   envutil.GetEnvInt("EVM_TX_MAX_RESUBMISSIONS", DefaultMaxResubmissions),
+		MaxFeePerGas:       nil,
+		GasLimitMultiplier: envutil.GetEnvFloat64("GAS_LIMIT_MULTIPLIER", DefaultGasLimitMultiplier),
+		Confirmations:      DefaultConfirmations,
+	}
+	if maxFee := envutil.GetEnvUint64("MAX_GAS_PRICE_WEI", 0); maxFee > 0 {
+		cfg.MaxFeePerGas = new(big.Int).SetUint64(maxFee)
+	}
+	return cfg
+}
+
+// envDuration reads a millisecond duration from key
+func envDuration(key string, defaultValue time.Duration) time.Duration {
+	return time.Duration(envutil.GetEnvUint64(key, uint64(defaultValue.Milliseconds()))) * time.Millisecond
+}
+
+func (c *EVMConfig) applyDefaults() {
+	if c.ResubmitInterval <= 0 {
+		c.ResubmitInterval = DefaultResubmitInterval
+	}
+	if c.ReceiptTimeout <= 0 {
+		c.ReceiptTimeout = DefaultReceiptTimeout
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = DefaultPollInterval
+	}
+	if c.FeeBumpPercent < minFeeBumpPercent {
+		c.FeeBumpPercent = minFeeBumpPercent
+	}
+	if c.MaxResubmissions < 0 {
+		c.MaxResubmissions = 0
+	}
+	if c.GasLimitMultiplier < 1 {
+		c.GasLimitMultiplier = 1
+	}
+	if c.Confirmations == 0 {
+		c.Confirmations = DefaultConfirmations
+	}
+}
+
+// EVMTx describes a call to send; GasLimit 0 means estimate
+type EVMTx struct {
+	To       common.Address
+	Data     []byte
+	Value    *big.Int
+	GasLimit uint64
+}
+
+// fees is either a dynamic-fee pair (tip, feeCap) or a legacy gas price (tip nil)
+type fees struct {
+	tip    *big.Int
+	feeCap *big.Int
+}
+
+func (f fees) String() string {
+	if f.tip == nil {
+		return fmt.Sprintf("gasPrice=%s", f.feeCap)
+	}
+	return fmt.Sprintf("tip=%s feeCap=%s", f.tip, f.feeCap)
+}
+
+// pendingTx tracks every broadcast version of one nonce
+type pendingTx struct {
+	nonce    uint64
+	gasLimit uint64
+	call     EVMTx
+	fees     fees
+	hashes   []common.Hash
+	lastSent time.Time
+	resent   int
+}
+
+// EVM manages transactions sent from one key on one chain
+type EVM struct {
+	mu        sync.Mutex // guards nextNonce and serialises submission
+	client    EVMBackend
+	signer    *bind.TransactOpts
+	chainID   uint64
+	cfg       EVMConfig
+	nextNonce *uint64 // nil until synced from chain
+}
+
+// NewEVM creates a transaction manager for signer's key on chainID
+func NewEVM(client EVMBackend, signer *bind.TransactOpts, chainID uint64, cfg EVMConfig) *EVM {
+	cfg.applyDefaults()
+	return &EVM{
+		mu:        sync.Mutex{},
+		client:    client,
+		signer:    signer,
+		chainID:   chainID,
+		cfg:       cfg,
+		nextNonce: nil,
+	}
+}
+
+// From returns the address transactions are sent from
+func (m *EVM) From() common.Address {
+	return m.signer.From
+}
+
+// Confirmations returns the number of blocks a receipt is buried under before Send returns it
+func (m *EVM) Confirmations() uint64 {
+	return m.cfg.Confirmations
+}
+
+// ResetNonce drops the local nonce so the next submission re-reads it from chain
+func (m *EVM) ResetNonce() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextNonce = nil
+}
+
+// Send submits call and blocks until one of its broadcast versions is mined and buried under the configured confirmations.
+// A reverted transaction returns its receipt together with ErrTransactionReverted.
+func (m *EVM) Send(ctx context.Context, call EVMTx) (*gethtypes.Receipt, error) {
+	pending, err := m.submit(ctx, call)
+	if err != nil {
+		return nil, err
+	}
+	return m.wait(ctx, pending)
+}
+
+// submit assigns a nonce, prices, signs and broadcasts the first version of call
+func (m *EVM) submit(ctx context.Context, call EVMTx) (*pendingTx, error) {
+	gasLimit, err := m.gasLimit(ctx, call)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	price, err := m.currentFees(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	// One retry after a nonce resync covers transactions sent from this key by other tools
+	for attempt := 0; attempt < 2; attempt++ {
+		nonce, err := m.nonceLocked(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		pending := &pendingTx{nonce: nonce, gasLimit: gasLimit, call: call, fees: price,
+			hashes: nil, lastSent: time.Time{}, resent: 0}
+		err = m.broadcast(ctx, pending)
+		if err == nil {
+			*m.nextNonce = nonce + 1
+			return pending, nil
+		}
+		if isNonceTooLow(err) {
+			m.logger().Info(fmt.Sprintf("   🔄 Nonce %d already used, resyncing", nonce))
+			m.nextNonce = nil
+			continue
+		}
+		return nil, err
+	}
+	return nil, fmt.Errorf("failed to obtain a usable nonce on chain %d", m.chainID)
+}
+
+// wait polls for a receipt, bumping fees and rebroadcasting when the transaction is stuck or dropped,
+// then waits for the receipt's block to reach the configured confirmation depth
+func (m *EVM) wait(ctx context.Context, pending *pendingTx) (*gethtypes.Receipt, error) {
+	deadline := time.Now().Add(m.cfg.ReceiptTimeout)
+	ticker := time.NewTicker(m.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		// Receipts are re-read on every poll so a reorg that moves or drops the transaction is noticed
+		receipt := m.findReceipt(ctx, pending)
+		if receipt != nil {
+			if receipt.Status != gethtypes.ReceiptStatusSuccessful {
+				return receipt, fmt.Errorf("%w: %s", ErrTransactionReverted, receipt.TxHash.Hex())
+			}
+			if m.confirmed(ctx, receipt) {
+				return receipt, nil
+			}
+		}
+
+		if time.Now().After(deadline) {
+			if receipt != nil {
+				return receipt, fmt.Errorf("%w: %s not %d blocks deep", ErrTransactionTimeout, receipt.TxHash.Hex(), m.cfg.Confirmations)
+			}
+			// The nonce may still be pending in the mempool; let the next submission re-read it
+			m.ResetNonce()
+			return nil, fmt.Errorf("%w: nonce %d (%s)", ErrTransactionTimeout, pending.nonce, pending.latestHash().Hex())
+		}
+
+		if receipt == nil && time.Since(pending.lastSent) >= m.cfg.ResubmitInterval {
+			if err := m.resubmit(ctx, pending); err != nil {
+				return nil, err
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// confirmed reports whether the block receipt was mined in is at least Confirmations deep
+func (m *EVM) confirmed(ctx context.Context, receipt *gethtypes.Receipt) bool {
+	if m.cfg.Confirmations <= 1 {
+		return true
+	}
+	if receipt.BlockNumber == nil {
+		return false
+	}
+	head, err := m.client.HeaderByNumber(ctx, nil)
+	if err != nil || head == nil || head.Number == nil {
+		return false
+	}
+	return head.Number.Uint64()+1 >= receipt.BlockNumber.Uint64()+m.cfg.Confirmations
+}
+
+// resubmit handles a transaction that has not been mined within ResubmitInterval
+func (m *EVM) resubmit(ctx context.Context, pending *pendingTx) error {
+	confirmed, err := m.client.NonceAt(ctx, m.signer.From, nil)
+	if err == nil && confirmed > pending.nonce {
+		// The nonce is used; give receipts for our versions one more chance before giving up
+		if receipt := m.findReceipt(ctx, pending); receipt != nil {
+			return nil
+		}
+		m.ResetNonce()
+		return fmt.Errorf("%w: nonce %d", ErrTransactionReplaced, pending.nonce)
+	}
+
+	dropped := m.isDropped(ctx, pending)
+	if pending.resent >= m.cfg.MaxResubmissions && !dropped {
+		pending.lastSent = time.Now()
+		return nil
+	}
+
+	next, err := m.bumpedFees(ctx, pending.fees)
+	if err != nil {
+		return err
+	}
+	if dropped {
+		m.logger().Warn(fmt.Sprintf("   ⚠️  Transaction dropped from mempool, rebroadcasting (%s)", next), logutil.TxHash(pending.latestHash().Hex()))
+	} else {
+		m.logger().Info(fmt.Sprintf("   ⛽ Transaction stuck, bumping fees (%s)", next), logutil.TxHash(pending.latestHash().Hex()))
+	}
+
+	previous := pending.fees
+	pending.fees = next
+	if err := m.broadcast(ctx, pending); err != nil {
+		pending.fees = previous
+		if isNonceTooLow(err) || isAlreadyKnown(err) {
+			// Raced with inclusion or with our own previous broadcast; the receipt poll resolves it
+			pending.lastSent = time.Now()
+			return nil
+		}
+		if isUnderpriced(err) {
+			m.logger().Warn("   ⚠️  Replacement underpriced, retrying with a larger bump later", logutil.TxHash(pending.latestHash().Hex()))
+			pending.fees = next
+			pending.lastSent = time.Now()
+			return nil
+		}
+		return fmt.Errorf("failed to rebroadcast transaction: %w", err)
+	}
+	pending.resent++
+	return nil
+}
+
+// broadcast signs pending at its current fees and sends it
+func (m *EVM) broadcast(ctx context.Context, pending *pendingTx) error {
+	tx := m.buildTx(pending)
+	signed, err := m.signer.Signer(m.signer.From, tx)
+	if err != nil {
+		return fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	if err := m.client.SendTransaction(ctx, signed); err != nil && !isAlreadyKnown(err) {
+		return err
+	}
+
+	pending.hashes = append(pending.hashes, signed.Hash())
+	pending.lastSent = time.Now()
+	return nil
+}
+
+func (m *EVM) buildTx(pending *pendingTx) *gethtypes.Transaction {
+	value := pending.call.Value
+	if value == nil {
+		value = new(big.Int)
+	}
+	to := pending.call.To
+
+	if pending.fees.tip == nil {
+		return gethtypes.NewTx(&gethtypes.LegacyTx{
+			Nonce:    pending.nonce,
+			GasPrice: pending.fees.feeCap,
+			Gas:      pending.gasLimit,
+			To:       &to,
+			Value:    value,
+			Data:     pending.call.Data,
+			V:        nil,
+			R:        nil,
+			S:        nil,
+		})
+	}
+	return gethtypes.NewTx(&gethtypes.DynamicFeeTx{
+		ChainID:    new(big.Int).SetUint64(m.chainID),
+		Nonce:      pending.nonce,
+		GasTipCap:  pending.fees.tip,
+		GasFeeCap:  pending.fees.feeCap,
+		Gas:        pending.gasLimit,
+		To:         &to,
+		Value:      value,
+		Data:       pending.call.Data,
+		AccessList: nil,
+		V:          nil,
+		R:          nil,
+		S:          nil,
+	})
+}
+
+func (m *EVM) gasLimit(ctx context.Context, call EVMTx) (uint64, error) {
+	if call.GasLimit > 0 {
+		return call.GasLimit, nil
+	}
+
+	to := call.To
+	estimate, err := m.client.EstimateGas(ctx, ethereum.CallMsg{
+		From:              m.signer.From,
+		To:                &to,
+		Gas:               0,
+		GasPrice:          nil,
+		GasFeeCap:         nil,
+		GasTipCap:         nil,
+		Value:             call.Value,
+		Data:              call.Data,
+		AccessList:        nil,
+		BlobGasFeeCap:     nil,
+		BlobHashes:        nil,
+		AuthorizationList: nil,
+	})
+	if err != nil {
+		return 0, fmt.Errorf("gas estimation failed: %w", err)
+	}
+	return uint64(float64(estimate) * m.cfg.GasLimitMultiplier), nil
+}
+
+// currentFees prices a new transaction from the latest base fee and suggested tip
+func (m *EVM) currentFees(ctx context.Context) (fees, error) {
+	header, err := m.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return fees{}, fmt.Errorf("failed to read latest header: %w", err)
+	}
+
+	if header.BaseFee == nil {
+		gasPrice, err := m.client.SuggestGasPrice(ctx)
+		if err != nil {
+			return fees{}, fmt.Errorf("failed to get gas price: %w", err)
+		}
+		if m.cfg.MaxFeePerGas != nil && gasPrice.Cmp(m.cfg.MaxFeePerGas) > 0 {
+			gasPrice = new(big.Int).Set(m.cfg.MaxFeePerGas)
+		}
+		return fees{tip: nil, feeCap: gasPrice}, nil
+	}
+
+	if m.cfg.MaxFeePerGas != nil && header.BaseFee.Cmp(m.cfg.MaxFeePerGas) > 0 {
+		return fees{}, fmt.Errorf("%w: base fee %s > %s", ErrFeeCapExceeded, header.BaseFee, m.cfg.MaxFeePerGas)
+	}
+
+	tip, err := m.client.SuggestGasTipCap(ctx)
+	if err != nil {
+		return fees{}, fmt.Errorf("failed to get gas tip cap: %w", err)
+	}
+	feeCap := new(big.Int).Mul(header.BaseFee, big.NewInt(baseFeeMultiplier))
+	feeCap.Add(feeCap, tip)
+	return m.capFees(fees{tip: tip, feeCap: feeCap}), nil
+}
+
+// bumpedFees raises both components by FeeBumpPercent, never going below current market fees
+func (m *EVM) bumpedFees(ctx context.Context, previous fees) (fees, error) {
+	bump := func(v *big.Int) *big.Int {
+		out := new(big.Int).Mul(v, new(big.Int).SetUint64(percent+m.cfg.FeeBumpPercent))
+		return out.Div(out, big.NewInt(percent))
+	}
+
+	next := fees{tip: nil, feeCap: bump(previous.feeCap)}
+	if previous.tip != nil {
+		next.tip = bump(previous.tip)
+	}
+
+	if market, err := m.currentFees(ctx); err == nil {
+		if market.feeCap.Cmp(next.feeCap) > 0 {
+			next.feeCap = market.feeCap
+		}
+		if market.tip != nil && next.tip != nil && market.tip.Cmp(next.tip) > 0 {
+			next.tip = market.tip
+		}
+	}
+	return m.capFees(next), nil
+}
+
+func (m *EVM) capFees(f fees) fees {
+	if m.cfg.MaxFeePerGas != nil && f.feeCap.Cmp(m.cfg.MaxFeePerGas) > 0 {
+		f.feeCap = new(big.Int).Set(m.cfg.MaxFeePerGas)
+	}
+	if f.tip != nil && f.tip.Cmp(f.feeCap) > 0 {
+		f.tip = new(big.Int).Set(f.feeCap)
+	}
+	return f
+}
+
+func (m *EVM) nonceLocked(ctx context.Context) (uint64, error) {
+	if m.nextNonce == nil {
+		nonce, err := m.client.PendingNonceAt(ctx, m.signer.From)
+		if err != nil {
+			return 0, fmt.Errorf("failed to get nonce: %w", err)
+		}
+		m.nextNonce = &nonce
+	}
+	return *m.nextNonce, nil
+}
+
+func (m *EVM) findReceipt(ctx context.Context, pending *pendingTx) *gethtypes.Receipt {
+	for _, hash := range pending.hashes {
+		receipt, err := m.client.TransactionReceipt(ctx, hash)
+		if err == nil && receipt != nil {
+			return receipt
+		}
+	}
+	return nil
+}
+
+// isDropped reports whether no broadcast version is known to the node any more
+func (m *EVM) isDropped(ctx context.Context, pending *pendingTx) bool {
+	for _, hash := range pending.hashes {
+		if _, _, err := m.client.TransactionByHash(ctx, hash); !errors.Is(err, ethereum.NotFound) {
+			return false
+		}
+	}
+	return true
+}
+
+func (p *pendingTx) latestHash() common.Hash {
+	if len(p.hashes) == 0 {
+		return common.Hash{}
+	}
+	return p.hashes[len(p.hashes)-1]
+}
+
+func isNonceTooLow(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "nonce too low")
+}
+
+func isAlreadyKnown(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "already known") || strings.Contains(msg, "known transaction")
+}
+
+func isUnderpriced(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "underpriced")
+}
+
+// logger returns the default logger tagged with the manager's chain
+func (m *EVM) logger() logutil.Logger {
+	return logutil.Default().With(logutil.Chain(logutil.NetworkNameByChainID(m.chainID)))
+}
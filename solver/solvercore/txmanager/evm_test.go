@@ -0,0 +1,360 @@
+package txmanager
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi/bind"
+	"github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
+)
+
+const testChainID = 31337
+
+// fakeChain is an in-memory EVMBackend. Transactions are mined on demand via mine().
+type fakeChain struct {
+	mu           sync.Mutex
+	baseFee      *big.Int
+	confirmed    uint64 // next nonce on chain
+	pendingNonce uint64
+	mempool      map[common.Hash]*gethtypes.Transaction
+	receipts     map[common.Hash]*gethtypes.Receipt
+	sent         []*gethtypes.Transaction
+	sendErr      error
+	revert       bool
+	callErr      error
+	head         uint64 // latest block number
+}
+
+func newFakeChain() *fakeChain {
+	return &fakeChain{
+		baseFee:  big.NewInt(100),
+		mempool:  make(map[common.Hash]*gethtypes.Transaction),
+		receipts: make(map[common.Hash]*gethtypes.Receipt),
+	}
+}
+
+func (c *fakeChain) PendingNonceAt(context.Context, common.Address) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.pendingNonce, nil
+}
+
+func (c *fakeChain) NonceAt(context.Context, common.Address, *big.Int) (uint64, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.confirmed, nil
+}
+
+func (c *fakeChain) HeaderByNumber(context.Context, *big.Int) (*gethtypes.Header, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return &gethtypes.Header{BaseFee: c.baseFee, Number: new(big.Int).SetUint64(c.head)}, nil
+}
+
+func (c *fakeChain) SuggestGasTipCap(context.Context) (*big.Int, error) { return big.NewInt(10), nil }
+
+func (c *fakeChain) SuggestGasPrice(context.Context) (*big.Int, error) { return big.NewInt(200), nil }
+
+func (c *fakeChain) EstimateGas(context.Context, ethereum.CallMsg) (uint64, error) {
+	return 100000, nil
+}
+
+func (c *fakeChain) CallContract(context.Context, ethereum.CallMsg, *big.Int) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return nil, c.callErr
+}
+
+func (c *fakeChain) SendTransaction(_ context.Context, tx *gethtypes.Transaction) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.sendErr != nil {
+		err := c.sendErr
+		c.sendErr = nil
+		return err
+	}
+	if tx.Nonce() < c.confirmed {
+		return errors.New("nonce too low")
+	}
+	c.mempool[tx.Hash()] = tx
+	c.sent = append(c.sent, tx)
+	if tx.Nonce() >= c.pendingNonce {
+		c.pendingNonce = tx.Nonce() + 1
+	}
+	return nil
+}
+
+func (c *fakeChain) TransactionReceipt(_ context.Context, hash common.Hash) (*gethtypes.Receipt, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if r, ok := c.receipts[hash]; ok {
+		return r, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+func (c *fakeChain) TransactionByHash(_ context.Context, hash common.Hash) (*gethtypes.Transaction, bool, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if tx, ok := c.mempool[hash]; ok {
+		return tx, true, nil
+	}
+	return nil, false, ethereum.NotFound
+}
+
+// mine includes the most recently sent transaction
+func (c *fakeChain) mine() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	tx := c.sent[len(c.sent)-1]
+	status := gethtypes.ReceiptStatusSuccessful
+	if c.revert {
+		status = gethtypes.ReceiptStatusFailed
+	}
+	c.head++
+	c.receipts[tx.Hash()] = &gethtypes.Receipt{Status: status, TxHash: tx.Hash(), BlockNumber: new(big.Int).SetUint64(c.head)}
+	c.confirmed = tx.Nonce() + 1
+	delete(c.mempool, tx.Hash())
+}
+
+// advance mines n empty blocks
+func (c *fakeChain) advance(n uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.head += n
+}
+
+func (c *fakeChain) headBlock() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.head
+}
+
+// drop removes every transaction from the mempool
+func (c *fakeChain) drop() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.mempool = make(map[common.Hash]*gethtypes.Transaction)
+}
+
+func (c *fakeChain) sentCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.sent)
+}
+
+func newTestManager(t *testing.T, chain *fakeChain) *EVM {
+	t.Helper()
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	signer, err := bind.NewKeyedTransactorWithChainID(key, big.NewInt(testChainID))
+	require.NoError(t, err)
+
+	return NewEVM(chain, signer, testChainID, EVMConfig{
+		ResubmitInterval:   20 * time.Millisecond,
+		ReceiptTimeout:     2 * time.Second,
+		PollInterval:       5 * time.Millisecond,
+		FeeBumpPercent:     20,
+		MaxResubmissions:   3,
+		MaxFeePerGas:       nil,
+		GasLimitMultiplier: 1.5,
+		Confirmations:      1,
+	})
+}
+
+// mineWhen mines the latest transaction once cond holds
+func mineWhen(chain *fakeChain, cond func() bool) {
+	go func() {
+		for !cond() {
+			time.Sleep(time.Millisecond)
+		}
+		chain.mine()
+	}()
+}
+
+func TestSendUsesDynamicFeesAndLocalNonces(t *testing.T) {
+	chain := newFakeChain()
+	m := newTestManager(t, chain)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		n := i + 1
+		mineWhen(chain, func() bool { return chain.sentCount() >= n })
+		receipt, err := m.Send(ctx, EVMTx{To: common.HexToAddress("0x1"), Data: []byte{1}, Value: nil, GasLimit: 0})
+		require.NoError(t, err)
+		require.NotNil(t, receipt)
+	}
+
+	require.Len(t, chain.sent, 2)
+	first := chain.sent[0]
+	assert.Equal(t, uint8(gethtypes.DynamicFeeTxType), first.Type())
+	assert.Equal(t, uint64(0), first.Nonce())
+	assert.Equal(t, uint64(1), chain.sent[1].Nonce())
+	assert.Equal(t, uint64(150000), first.Gas())
+	// 2 * baseFee + tip
+	assert.Equal(t, int64(210), first.GasFeeCap().Int64())
+	assert.Equal(t, int64(10), first.GasTipCap().Int64())
+}
+
+func TestStuckTransactionIsBumped(t *testing.T) {
+	chain := newFakeChain()
+	m := newTestManager(t, chain)
+
+	mineWhen(chain, func() bool { return chain.sentCount() >= 3 })
+	receipt, err := m.Send(context.Background(), EVMTx{To: common.HexToAddress("0x1"), Data: nil, Value: nil, GasLimit: 21000})
+	require.NoError(t, err)
+	assert.Equal(t, chain.sent[2].Hash(), receipt.TxHash)
+
+	for i := 1; i < 3; i++ {
+		prev, next := chain.sent[i-1], chain.sent[i]
+		assert.Equal(t, prev.Nonce(), next.Nonce(), "replacement must reuse the nonce")
+		assert.Greater(t, next.GasTipCap().Int64(), prev.GasTipCap().Int64())
+		assert.Greater(t, next.GasFeeCap().Int64(), prev.GasFeeCap().Int64())
+	}
+}
+
+func TestSendWaitsForConfirmations(t *testing.T) {
+	chain := newFakeChain()
+	m := newTestManager(t, chain)
+	m.cfg.Confirmations = 3
+
+	mineWhen(chain, func() bool { return chain.sentCount() >= 1 })
+	done := make(chan *gethtypes.Receipt, 1)
+	go func() {
+		receipt, err := m.Send(context.Background(), EVMTx{To: common.HexToAddress("0x1"), Data: nil, Value: nil, GasLimit: 21000})
+		assert.NoError(t, err)
+		done <- receipt
+	}()
+
+	require.Eventually(t, func() bool { return chain.headBlock() == 1 }, time.Second, time.Millisecond)
+	chain.advance(1)
+	select {
+	case <-done:
+		t.Fatal("receipt returned two blocks deep")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	chain.advance(1)
+	select {
+	case receipt := <-done:
+		assert.Equal(t, uint64(1), receipt.BlockNumber.Uint64())
+	case <-time.After(time.Second):
+		t.Fatal("receipt not returned three blocks deep")
+	}
+	assert.Equal(t, 1, chain.sentCount(), "a mined transaction awaiting confirmations is not rebroadcast")
+}
+
+func TestReceiptTimeoutIsTransient(t *testing.T) {
+	chain := newFakeChain()
+	m := newTestManager(t, chain)
+	m.cfg.ReceiptTimeout = 50 * time.Millisecond
+
+	_, err := m.Send(context.Background(), EVMTx{To: common.HexToAddress("0x1"), Data: nil, Value: nil, GasLimit: 21000})
+	assert.True(t, errors.Is(err, ErrTransactionTimeout))
+	assert.Equal(t, failure.KindNetwork, failure.KindOf(err))
+	assert.True(t, failure.Transient(err), "an unmined transaction is worth retrying")
+}
+
+func TestFeeCap(t *testing.T) {
+	chain := newFakeChain()
+	m := newTestManager(t, chain)
+	m.cfg.MaxFeePerGas = big.NewInt(150)
+
+	mineWhen(chain, func() bool { return chain.sentCount() >= 2 })
+	_, err := m.Send(context.Background(), EVMTx{To: common.HexToAddress("0x1"), Data: nil, Value: nil, GasLimit: 21000})
+	require.NoError(t, err)
+	for _, tx := range chain.sent {
+		assert.LessOrEqual(t, tx.GasFeeCap().Int64(), int64(150))
+	}
+
+	chain.baseFee = big.NewInt(200)
+	_, err = m.Send(context.Background(), EVMTx{To: common.HexToAddress("0x1"), Data: nil, Value: nil, GasLimit: 21000})
+	assert.True(t, errors.Is(err, ErrFeeCapExceeded))
+	assert.Equal(t, failure.KindInsufficientFunds, failure.KindOf(err))
+}
+
+func TestDroppedTransactionIsRebroadcast(t *testing.T) {
+	chain := newFakeChain()
+	m := newTestManager(t, chain)
+	m.cfg.MaxResubmissions = 0
+
+	go func() {
+		for chain.sentCount() < 1 {
+			time.Sleep(time.Millisecond)
+		}
+		chain.drop()
+	}()
+	mineWhen(chain, func() bool { return chain.sentCount() >= 2 })
+
+	_, err := m.Send(context.Background(), EVMTx{To: common.HexToAddress("0x1"), Data: nil, Value: nil, GasLimit: 21000})
+	require.NoError(t, err)
+	assert.Equal(t, chain.sent[0].Nonce(), chain.sent[1].Nonce())
+}
+
+func TestRevertedAndReplaced(t *testing.T) {
+	t.Run("reverted receipt is returned with an error", func(t *testing.T) {
+		chain := newFakeChain()
+		chain.revert = true
+		m := newTestManager(t, chain)
+
+		mineWhen(chain, func() bool { return chain.sentCount() >= 1 })
+		receipt, err := m.Send(context.Background(), EVMTx{To: common.HexToAddress("0x1"), Data: nil, Value: nil, GasLimit: 21000})
+		require.NotNil(t, receipt)
+		assert.True(t, errors.Is(err, ErrTransactionReverted))
+		assert.Equal(t, failure.KindRevert, failure.KindOf(err))
+	})
+
+	t.Run("nonce consumed elsewhere", func(t *testing.T) {
+		chain := newFakeChain()
+		m := newTestManager(t, chain)
+
+		go func() {
+			for chain.sentCount() < 1 {
+				time.Sleep(time.Millisecond)
+			}
+			chain.mu.Lock()
+			chain.confirmed = 1
+			chain.mu.Unlock()
+		}()
+		_, err := m.Send(context.Background(), EVMTx{To: common.HexToAddress("0x1"), Data: nil, Value: nil, GasLimit: 21000})
+		assert.True(t, errors.Is(err, ErrTransactionReplaced))
+		assert.Equal(t, failure.KindNetwork, failure.KindOf(err), "a replaced nonce is retried with a fresh one")
+	})
+
+	t.Run("nonce too low resyncs", func(t *testing.T) {
+		chain := newFakeChain()
+		m := newTestManager(t, chain)
+		m.nextNonce = new(uint64) // stale local nonce 0
+		chain.confirmed, chain.pendingNonce = 5, 5
+
+		mineWhen(chain, func() bool { return chain.sentCount() >= 1 })
+		_, err := m.Send(context.Background(), EVMTx{To: common.HexToAddress("0x1"), Data: nil, Value: nil, GasLimit: 21000})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(5), chain.sent[0].Nonce())
+	})
+
+	t.Run("send errors do not consume the nonce", func(t *testing.T) {
+		chain := newFakeChain()
+		m := newTestManager(t, chain)
+		chain.sendErr = errors.New("insufficient funds")
+
+		_, err := m.Send(context.Background(), EVMTx{To: common.HexToAddress("0x1"), Data: nil, Value: nil, GasLimit: 21000})
+		require.Error(t, err)
+
+		mineWhen(chain, func() bool { return chain.sentCount() >= 1 })
+		_, err = m.Send(context.Background(), EVMTx{To: common.HexToAddress("0x1"), Data: nil, Value: nil, GasLimit: 21000})
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0), chain.sent[0].Nonce())
+	})
+}
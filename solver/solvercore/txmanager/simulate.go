@@ -0,0 +1,260 @@
+package txmanager
+
+// Module: Transaction simulation
+// - Runs EVM calls with eth_call and prices them with eth_estimateGas at the current gas price
+// - Runs EVM bundles (e.g. approve then fill) with eth_simulateV1, so later calls see the effects of earlier ones
+// - Estimates Starknet multicall invokes with starknet_estimateFee at the account's next nonce
+// - Reports execution failures as a reverted Simulation, so callers can tell them from RPC failures
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+)
+
+// Fee units of a Simulation
+const (
+	UnitWei = "WEI"
+	UnitFri = "FRI"
+)
+
+// methodNotFoundCode is the JSON-RPC code of a method the node does not implement
+const methodNotFoundCode = -32601
+
+// ErrSimulationUnsupported is returned when the node cannot run the requested simulation,
+// such as a bundle on a node without eth_simulateV1
+var ErrSimulationUnsupported = errors.New("simulation not supported by node")
+
+// Simulation is the outcome of executing a transaction against the latest state without sending it
+type Simulation struct {
+	// Reverted is set when execution failed; Reason is the node's message
+	Reverted bool
+	Reason   string
+	// RevertData is the raw revert payload returned by EVM nodes, if any
+	RevertData []byte
+	// Gas is the gas the transaction would use: EVM gas, or L1 + L1 data + L2 gas on Starknet
+	Gas uint64
+	// Fee is the network fee at current prices in Unit; nil when reverted
+	Fee  *big.Int
+	Unit string
+}
+
+// evmRawClient is implemented by ethclient.Client, exposing the JSON-RPC client for methods it has no wrapper for
+type evmRawClient interface {
+	Client() *gethrpc.Client
+}
+
+// Simulate executes call from the manager's key with eth_call and estimates its gas and fee
+func (m *EVM) Simulate(ctx context.Context, call EVMTx) (*Simulation, error) {
+	msg := m.callMsg(call)
+	if _, err := m.client.CallContract(ctx, msg, nil); err != nil {
+		if sim, ok := evmRevert(err); ok {
+			return sim, nil
+		}
+		return nil, fmt.Errorf("eth_call failed: %w", err)
+	}
+
+	gas, err := m.client.EstimateGas(ctx, msg)
+	if err != nil {
+		if sim, ok := evmRevert(err); ok {
+			return sim, nil
+		}
+		return nil, fmt.Errorf("gas estimation failed: %w", err)
+	}
+	return m.pricedSimulation(ctx, gas)
+}
+
+// SimulateBundle executes calls in order in one simulated block with eth_simulateV1, so each call sees the state
+// left by the previous ones. It returns one Simulation per call and ErrSimulationUnsupported if the node cannot run it.
+func (m *EVM) SimulateBundle(ctx context.Context, calls []EVMTx) ([]Simulation, error) {
+	raw, ok := m.client.(evmRawClient)
+	if !ok {
+		return nil, ErrSimulationUnsupported
+	}
+
+	type simulateCall struct {
+		From  common.Address `json:"from"`
+		To    common.Address `json:"to"`
+		Value *hexutil.Big   `json:"value,omitempty"`
+		Data  hexutil.Bytes  `json:"data"`
+	}
+	type simulateError struct {
+		Message string `json:"message"`
+		Data    string `json:"data"`
+	}
+	type simulateCallResult struct {
+		Status  hexutil.Uint64 `json:"status"`
+		GasUsed hexutil.Uint64 `json:"gasUsed"`
+		Error   *simulateError `json:"error"`
+	}
+	type simulateBlockResult struct {
+		Calls []simulateCallResult `json:"calls"`
+	}
+
+	requested := make([]simulateCall, len(calls))
+	for i, call := range calls {
+		requested[i] = simulateCall{From: m.signer.From, To: call.To, Value: (*hexutil.Big)(call.Value), Data: call.Data}
+	}
+	params := map[string]any{
+		"blockStateCalls": []map[string]any{{"calls": requested}},
+		"validation":      false,
+	}
+
+	var blocks []simulateBlockResult
+	if err := raw.Client().CallContext(ctx, &blocks, "eth_simulateV1", params, "latest"); err != nil {
+		var rpcErr gethrpc.Error
+		if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == methodNotFoundCode {
+			return nil, fmt.Errorf("%w: %w", ErrSimulationUnsupported, err)
+		}
+		return nil, fmt.Errorf("eth_simulateV1 failed: %w", err)
+	}
+	if len(blocks) != 1 || len(blocks[0].Calls) != len(calls) {
+		return nil, fmt.Errorf("eth_simulateV1 returned %d block(s) for %d call(s)", len(blocks), len(calls))
+	}
+
+	price, err := m.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	sims := make([]Simulation, len(calls))
+	for i, result := range blocks[0].Calls {
+		if result.Status == 0 {
+			sims[i] = Simulation{Reverted: true, Reason: "execution reverted", RevertData: nil, Gas: uint64(result.GasUsed), Fee: nil, Unit: UnitWei}
+			if result.Error != nil {
+				sims[i].Reason = result.Error.Message
+				sims[i].RevertData = common.FromHex(result.Error.Data)
+			}
+			continue
+		}
+		gas := uint64(result.GasUsed)
+		sims[i] = Simulation{Reverted: false, Reason: "", RevertData: nil, Gas: gas,
+			Fee: new(big.Int).Mul(new(big.Int).SetUint64(gas), price), Unit: UnitWei}
+	}
+	return sims, nil
+}
+
+func (m *EVM) callMsg(call EVMTx) ethereum.CallMsg {
+	to := call.To
+	return ethereum.CallMsg{
+		From:              m.signer.From,
+		To:                &to,
+		Gas:               call.GasLimit,
+		GasPrice:          nil,
+		GasFeeCap:         nil,
+		GasTipCap:         nil,
+		Value:             call.Value,
+		Data:              call.Data,
+		AccessList:        nil,
+		BlobGasFeeCap:     nil,
+		BlobHashes:        nil,
+		AuthorizationList: nil,
+	}
+}
+
+// pricedSimulation is a successful simulation using gas at the node's suggested gas price
+func (m *EVM) pricedSimulation(ctx context.Context, gas uint64) (*Simulation, error) {
+	price, err := m.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+	return &Simulation{
+		Reverted:   false,
+		Reason:     "",
+		RevertData: nil,
+		Gas:        gas,
+		Fee:        new(big.Int).Mul(new(big.Int).SetUint64(gas), price),
+		Unit:       UnitWei,
+	}, nil
+}
+
+// evmRevert turns an eth_call or eth_estimateGas error into a reverted Simulation when execution itself failed
+func evmRevert(err error) (*Simulation, bool) {
+	var dataErr gethrpc.DataError
+	hasData := errors.As(err, &dataErr)
+	if !hasData && !strings.Contains(err.Error(), "revert") {
+		return nil, false
+	}
+
+	sim := &Simulation{Reverted: true, Reason: err.Error(), RevertData: nil, Gas: 0, Fee: nil, Unit: UnitWei}
+	if hasData {
+		if data, ok := dataErr.ErrorData().(string); ok {
+			sim.RevertData = common.FromHex(data)
+		}
+	}
+	return sim, true
+}
+
+// Simulate estimates calls as one multicall invoke at the account's next nonce without sending it
+func (m *Starknet) Simulate(ctx context.Context, calls []rpc.InvokeFunctionCall) (*Simulation, error) {
+	calldata, err := m.signer.FmtCalldata(utils.InvokeFuncCallsToFunctionCalls(calls))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format calldata: %w", err)
+	}
+
+	m.mu.Lock()
+	nonce, err := m.nonceLocked(ctx)
+	m.mu.Unlock()
+	if err != nil {
+		return nil, err
+	}
+
+	tx := utils.BuildInvokeTxn(m.address, nonce, calldata, zeroResourceBounds(), &utils.TxnOptions{
+		Tip:         rpc.U64(fmt.Sprintf("0x%x", m.cfg.Tip)),
+		UseQueryBit: false,
+	})
+	if err := m.signer.SignInvokeTransaction(ctx, tx); err != nil {
+		return nil, fmt.Errorf("failed to sign invoke: %w", err)
+	}
+	estimates, err := m.backend.EstimateFee(ctx, []rpc.BroadcastTxn{tx}, []rpc.SimulationFlag{},
+		rpc.WithBlockTag(rpc.BlockTagPreConfirmed))
+	if err != nil {
+		var rpcErr *rpc.RPCError
+		if errors.As(err, &rpcErr) && rpcErr.Code == rpc.ErrTxnExec.Code {
+			return &Simulation{Reverted: true, Reason: starknetRevertReason(rpcErr), RevertData: nil, Gas: 0, Fee: nil, Unit: UnitFri}, nil
+		}
+		return nil, fmt.Errorf("fee estimation failed: %w", err)
+	}
+	if len(estimates) == 0 {
+		return nil, fmt.Errorf("fee estimation returned no result")
+	}
+
+	est := estimates[0]
+	gas := new(big.Int)
+	for _, consumed := range []*felt.Felt{est.L1GasConsumed, est.L1DataGasConsumed, est.L2GasConsumed} {
+		if consumed != nil {
+			gas.Add(gas, consumed.BigInt(new(big.Int)))
+		}
+	}
+	fee := new(big.Int)
+	if est.OverallFee != nil {
+		fee = est.OverallFee.BigInt(fee)
+	}
+	unit := string(est.Unit)
+	if unit == "" {
+		unit = UnitFri
+	}
+	return &Simulation{Reverted: false, Reason: "", RevertData: nil, Gas: gas.Uint64(), Fee: fee, Unit: unit}, nil
+}
+
+// starknetRevertReason returns the innermost execution error message of a TRANSACTION_EXECUTION_ERROR
+func starknetRevertReason(rpcErr *rpc.RPCError) string {
+	data, ok := rpcErr.Data.(*rpc.TransactionExecErrData)
+	if !ok || data == nil {
+		return rpcErr.Error()
+	}
+	execErr := &data.ExecutionError
+	for execErr.ContractExecErrInner != nil && execErr.ContractExecErrInner.Error != nil {
+		execErr = execErr.ContractExecErrInner.Error
+	}
+	return execErr.Message
+}
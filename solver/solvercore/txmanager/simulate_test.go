@@ -0,0 +1,104 @@
+package txmanager
+
+import (
+	"context"
+	"math/big"
+	"testing"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/ethereum/go-ethereum/common"
+	gethrpc "github.com/ethereum/go-ethereum/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// revertError mimics the error geth returns for a reverted eth_call
+type revertError struct {
+	data string
+}
+
+func (e revertError) Error() string          { return "execution reverted" }
+func (e revertError) ErrorData() interface{} { return e.data }
+
+var _ gethrpc.DataError = revertError{}
+
+func TestEVMSimulatePricesSuccessfulCall(t *testing.T) {
+	chain := newFakeChain()
+	m := newTestManager(t, chain)
+
+	sim, err := m.Simulate(context.Background(), EVMTx{To: common.HexToAddress("0x1"), Data: []byte{1}, Value: nil, GasLimit: 0})
+	require.NoError(t, err)
+	assert.False(t, sim.Reverted)
+	assert.Equal(t, uint64(100000), sim.Gas)
+	assert.Equal(t, big.NewInt(100000*200), sim.Fee)
+	assert.Equal(t, UnitWei, sim.Unit)
+	assert.Zero(t, chain.sentCount(), "simulation must not send anything")
+}
+
+func TestEVMSimulateReportsRevert(t *testing.T) {
+	chain := newFakeChain()
+	chain.callErr = revertError{data: "0xdeadbeef"}
+	m := newTestManager(t, chain)
+
+	sim, err := m.Simulate(context.Background(), EVMTx{To: common.HexToAddress("0x1"), Data: nil, Value: nil, GasLimit: 0})
+	require.NoError(t, err)
+	assert.True(t, sim.Reverted)
+	assert.Equal(t, []byte{0xde, 0xad, 0xbe, 0xef}, sim.RevertData)
+	assert.Nil(t, sim.Fee)
+}
+
+func TestEVMSimulateReturnsRPCFailures(t *testing.T) {
+	chain := newFakeChain()
+	chain.callErr = context.DeadlineExceeded
+	m := newTestManager(t, chain)
+
+	_, err := m.Simulate(context.Background(), EVMTx{To: common.HexToAddress("0x1"), Data: nil, Value: nil, GasLimit: 0})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
+
+func TestEVMSimulateBundleNeedsRawClient(t *testing.T) {
+	m := newTestManager(t, newFakeChain())
+
+	_, err := m.SimulateBundle(context.Background(), []EVMTx{{To: common.HexToAddress("0x1"), Data: nil, Value: nil, GasLimit: 0}})
+	assert.ErrorIs(t, err, ErrSimulationUnsupported)
+}
+
+func TestStarknetSimulateEstimatesWithoutSending(t *testing.T) {
+	chain := newFakeStarknet()
+	m := newTestStarknetManager(chain)
+
+	sim, err := m.Simulate(context.Background(), testCall)
+	require.NoError(t, err)
+	assert.False(t, sim.Reverted)
+	assert.Equal(t, uint64(1010), sim.Gas)
+	assert.Equal(t, big.NewInt(10030), sim.Fee)
+	assert.Equal(t, UnitFri, sim.Unit)
+	assert.Zero(t, chain.sentCount())
+}
+
+func TestStarknetSimulateReportsExecutionError(t *testing.T) {
+	chain := newFakeStarknet()
+	chain.estimateErr = &rpc.RPCError{
+		Code:    rpc.ErrTxnExec.Code,
+		Message: rpc.ErrTxnExec.Message,
+		Data: &rpc.TransactionExecErrData{
+			TransactionIndex: 0,
+			ExecutionError: rpc.ContractExecutionError{
+				Message: "outer",
+				ContractExecErrInner: &rpc.ContractExecutionErrorInner{
+					ContractAddress: new(felt.Felt).SetUint64(1),
+					ClassHash:       new(felt.Felt).SetUint64(2),
+					Selector:        new(felt.Felt).SetUint64(3),
+					Error:           &rpc.ContractExecutionError{Message: "Invalid order status", ContractExecErrInner: nil},
+				},
+			},
+		},
+	}
+	m := newTestStarknetManager(chain)
+
+	sim, err := m.Simulate(context.Background(), testCall)
+	require.NoError(t, err)
+	assert.True(t, sim.Reverted)
+	assert.Equal(t, "Invalid order status", sim.Reason)
+}
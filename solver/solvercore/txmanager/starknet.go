@@ -0,0 +1,373 @@
+package txmanager
+
+// Module: Starknet transaction manager
+// - Builds V3 invokes with resource bounds derived from a fee estimate
+// - Scales estimated amounts and prices by configurable multipliers and enforces a total fee cap
+// - Hands out nonces from a local counter so concurrent invokes from one account never collide
+// - Resyncs the nonce and retries once when the node rejects it
+// - Maps REVERTED/REJECTED finality to typed errors
+// - Returns receipts once the configured finality (ACCEPTED_ON_L2 or ACCEPTED_ON_L1) is reached
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/oif-starknet/solver/pkg/envutil"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/logutil"
+	"github.com/NethermindEth/starknet.go/account"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/NethermindEth/starknet.go/utils"
+)
+
+// Defaults used when the corresponding environment variables are unset
+const (
+	DefaultStarknetAmountMultiplier = 1.5
+	DefaultStarknetPriceMultiplier  = 1.5
+	DefaultStarknetReceiptTimeout   = 5 * time.Minute
+	DefaultStarknetFinality         = rpc.TxnStatusAcceptedOnL2
+)
+
+// txnStatusRejected is reported by nodes on RPC specs before v0.9 for transactions that failed validation
+const txnStatusRejected rpc.TxnStatus = "REJECTED"
+
+// ErrTransactionRejected is returned when the sequencer rejected the transaction, so its nonce was not consumed
+var ErrTransactionRejected = failure.Mark(errors.New("transaction rejected"), failure.ErrReverted)
+
+var (
+	maxU64  = new(big.Int).SetUint64(math.MaxUint64)
+	maxU128 = new(big.Int).Sub(new(big.Int).Lsh(big.NewInt(1), 128), big.NewInt(1))
+)
+
+// StarknetBackend is the subset of rpc.RPCProvider used by the manager
+type StarknetBackend interface {
+	Nonce(ctx context.Context, blockID rpc.BlockID, contractAddress *felt.Felt) (*felt.Felt, error)
+	EstimateFee(
+		ctx context.Context,
+		requests []rpc.BroadcastTxn,
+		simulationFlags []rpc.SimulationFlag,
+		blockID rpc.BlockID,
+	) ([]rpc.FeeEstimation, error)
+	AddInvokeTransaction(ctx context.Context, invokeTxn *rpc.BroadcastInvokeTxnV3) (rpc.AddInvokeTransactionResponse, error)
+	TransactionStatus(ctx context.Context, transactionHash *felt.Felt) (*rpc.TxnStatusResult, error)
+	TransactionReceipt(ctx context.Context, transactionHash *felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error)
+}
+
+// StarknetSigner is the subset of account.Account used by the manager
+type StarknetSigner interface {
+	FmtCalldata(fnCalls []rpc.FunctionCall) ([]*felt.Felt, error)
+	SignInvokeTransaction(ctx context.Context, invokeTx rpc.InvokeTxnType) error
+}
+
+// StarknetConfig controls fee bounds and confirmation waits
+type StarknetConfig struct {
+	// AmountMultiplier scales the estimated amount of every resource
+	AmountMultiplier float64
+	// PriceMultiplier scales the estimated price per unit of every resource
+	PriceMultiplier float64
+	// MaxFee caps the total fee bound (sum of max amount * max price, in FRI); nil means uncapped
+	MaxFee *big.Int
+	// Tip is paid per unit of L2 gas
+	Tip            uint64
+	ReceiptTimeout time.Duration
+	PollInterval   time.Duration
+	// Finality is the finality status a transaction must reach before Send returns its receipt:
+	// ACCEPTED_ON_L2, or ACCEPTED_ON_L1 to also wait for the block to be proven on L1
+	Finality rpc.TxnStatus
+}
+
+// StarknetConfigFromEnv builds a config from the STARKNET_TX_* variables
+func StarknetConfigFromEnv() StarknetConfig {
+	cfg := StarknetConfig{
+		AmountMultiplier: envutil.GetEnvFloat64("STARKNET_TX_AMOUNT_MULTIPLIER", DefaultStarknetAmountMultiplier),
+		PriceMultiplier:  envutil.GetEnvFloat64("STARKNET_TX_PRICE_MULTIPLIER", DefaultStarknetPriceMultiplier),
+		MaxFee:           nil,
+		Tip:              envutil.GetEnvUint64("STARKNET_TX_TIP", 0),
+		ReceiptTimeout:   envDuration("STARKNET_TX_RECEIPT_TIMEOUT_MS", DefaultStarknetReceiptTimeout),
+		PollInterval:     DefaultPollInterval,
+		Finality:         rpc.TxnStatus(strings.ToUpper(envutil.GetEnvWithDefault("STARKNET_TX_FINALITY", string(DefaultStarknetFinality)))),
+	}
+	if maxFee := envutil.GetEnvUint64("STARKNET_TX_MAX_FEE_FRI", 0); maxFee > 0 {
+		cfg.MaxFee = new(big.Int).SetUint64(maxFee)
+	}
+	return cfg
+}
+
+func (c *StarknetConfig) applyDefaults() {
+	if c.AmountMultiplier < 1 {
+		c.AmountMultiplier = 1
+	}
+	if c.PriceMultiplier < 1 {
+		c.PriceMultiplier = 1
+	}
+	if c.ReceiptTimeout <= 0 {
+		c.ReceiptTimeout = DefaultStarknetReceiptTimeout
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = DefaultPollInterval
+	}
+	if c.Finality != rpc.TxnStatusAcceptedOnL1 {
+		c.Finality = DefaultStarknetFinality
+	}
+}
+
+// final reports whether a transaction with finality status has reached the configured finality;
+// ACCEPTED_ON_L1 satisfies either setting
+func (c *StarknetConfig) final(status rpc.TxnStatus) bool {
+	return status == rpc.TxnStatusAcceptedOnL1 || (status == rpc.TxnStatusAcceptedOnL2 && c.Finality == rpc.TxnStatusAcceptedOnL2)
+}
+
+// Starknet manages invoke transactions sent from one account
+type Starknet struct {
+	mu        sync.Mutex // guards nextNonce and serialises submission
+	backend   StarknetBackend
+	signer    StarknetSigner
+	address   *felt.Felt
+	chainID   uint64
+	cfg       StarknetConfig
+	nextNonce *felt.Felt // nil until synced from chain
+}
+
+// NewStarknet creates a transaction manager for acct on chainID
+func NewStarknet(acct *account.Account, chainID uint64, cfg StarknetConfig) *Starknet {
+	return newStarknet(acct.Provider, acct, acct.Address, chainID, cfg)
+}
+
+func newStarknet(backend StarknetBackend, signer StarknetSigner, address *felt.Felt, chainID uint64,
+	cfg StarknetConfig) *Starknet {
+	cfg.applyDefaults()
+	return &Starknet{
+		mu:        sync.Mutex{},
+		backend:   backend,
+		signer:    signer,
+		address:   address,
+		chainID:   chainID,
+		cfg:       cfg,
+		nextNonce: nil,
+	}
+}
+
+// Address returns the account invokes are sent from
+func (m *Starknet) Address() *felt.Felt {
+	return m.address
+}
+
+// ResetNonce drops the local nonce so the next submission re-reads it from chain
+func (m *Starknet) ResetNonce() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.nextNonce = nil
+}
+
+// Send submits calls as one multicall invoke and blocks until it reaches the configured finality.
+// A reverted transaction returns its receipt together with ErrTransactionReverted.
+func (m *Starknet) Send(ctx context.Context, calls []rpc.InvokeFunctionCall) (*rpc.TransactionReceiptWithBlockInfo, error) {
+	hash, err := m.submit(ctx, calls)
+	if err != nil {
+		return nil, err
+	}
+	return m.wait(ctx, hash)
+}
+
+// submit assigns a nonce, estimates fees, signs and broadcasts the invoke
+func (m *Starknet) submit(ctx context.Context, calls []rpc.InvokeFunctionCall) (*felt.Felt, error) {
+	calldata, err := m.signer.FmtCalldata(utils.InvokeFuncCallsToFunctionCalls(calls))
+	if err != nil {
+		return nil, fmt.Errorf("failed to format calldata: %w", err)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	// One retry after a nonce resync covers invokes sent from this account by other tools
+	for attempt := 0; attempt < 2; attempt++ {
+		nonce, err := m.nonceLocked(ctx)
+		if err != nil {
+			return nil, err
+		}
+
+		hash, err := m.broadcast(ctx, nonce, calldata)
+		if err == nil {
+			m.nextNonce = new(felt.Felt).Add(nonce, new(felt.Felt).SetUint64(1))
+			return hash, nil
+		}
+		if isInvalidNonce(err) {
+			m.logger().Info(fmt.Sprintf("   🔄 Nonce %s rejected, resyncing", nonce.String()))
+			m.nextNonce = nil
+			continue
+		}
+		return nil, err
+	}
+	return nil, fmt.Errorf("failed to obtain a usable nonce on chain %d", m.chainID)
+}
+
+// broadcast estimates the invoke at nonce, applies resource bounds, re-signs and sends it
+func (m *Starknet) broadcast(ctx context.Context, nonce *felt.Felt, calldata []*felt.Felt) (*felt.Felt, error) {
+	tx := utils.BuildInvokeTxn(m.address, nonce, calldata, zeroResourceBounds(), &utils.TxnOptions{
+		Tip:         rpc.U64(fmt.Sprintf("0x%x", m.cfg.Tip)),
+		UseQueryBit: false,
+	})
+
+	// The estimate needs a signed transaction; the signature changes once bounds are set
+	if err := m.signer.SignInvokeTransaction(ctx, tx); err != nil {
+		return nil, fmt.Errorf("failed to sign invoke: %w", err)
+	}
+	estimates, err := m.backend.EstimateFee(ctx, []rpc.BroadcastTxn{tx}, []rpc.SimulationFlag{},
+		rpc.WithBlockTag(rpc.BlockTagPreConfirmed))
+	if err != nil {
+		return nil, fmt.Errorf("fee estimation failed: %w", err)
+	}
+	if len(estimates) == 0 {
+		return nil, fmt.Errorf("fee estimation returned no result")
+	}
+
+	bounds, err := m.resourceBounds(estimates[0])
+	if err != nil {
+		return nil, err
+	}
+	tx.ResourceBounds = bounds
+	tx.Version = rpc.TransactionV3
+	if err := m.signer.SignInvokeTransaction(ctx, tx); err != nil {
+		return nil, fmt.Errorf("failed to sign invoke: %w", err)
+	}
+
+	resp, err := m.backend.AddInvokeTransaction(ctx, tx)
+	if err != nil {
+		return nil, err
+	}
+	return resp.Hash, nil
+}
+
+// wait polls the transaction status until it reaches the configured finality, reverts or is rejected
+func (m *Starknet) wait(ctx context.Context, hash *felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error) {
+	deadline := time.Now().Add(m.cfg.ReceiptTimeout)
+	ticker := time.NewTicker(m.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		status, err := m.backend.TransactionStatus(ctx, hash)
+		if err != nil && !isHashNotFound(err) {
+			return nil, fmt.Errorf("failed to get transaction status: %w", err)
+		}
+
+		if status != nil {
+			switch {
+			case status.FinalityStatus == txnStatusRejected:
+				// Rejected invokes never reach a block, so the nonce is still free
+				m.ResetNonce()
+				return nil, fmt.Errorf("%w: %s: %s", ErrTransactionRejected, hash.String(), status.FailureReason)
+			case status.ExecutionStatus == rpc.TxnExecutionStatusREVERTED:
+				receipt, _ := m.backend.TransactionReceipt(ctx, hash)
+				return receipt, fmt.Errorf("%w: %s: %s", ErrTransactionReverted, hash.String(), status.FailureReason)
+			case m.cfg.final(status.FinalityStatus):
+				receipt, err := m.backend.TransactionReceipt(ctx, hash)
+				if err != nil {
+					return nil, fmt.Errorf("failed to get transaction receipt: %w", err)
+				}
+				return receipt, nil
+			}
+		}
+
+		if time.Now().After(deadline) {
+			// The nonce may still be pending in the mempool; let the next submission re-read it
+			m.ResetNonce()
+			return nil, fmt.Errorf("%w: %s", ErrTransactionTimeout, hash.String())
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// resourceBounds scales est by the configured multipliers, falling back to the bare estimate
+// when the scaled bounds exceed MaxFee
+func (m *Starknet) resourceBounds(est rpc.FeeEstimation) (*rpc.ResourceBoundsMapping, error) {
+	if m.cfg.MaxFee != nil && est.OverallFee != nil && est.OverallFee.BigInt(new(big.Int)).Cmp(m.cfg.MaxFee) > 0 {
+		return nil, fmt.Errorf("%w: estimated fee %s > %s", ErrFeeCapExceeded, est.OverallFee.BigInt(new(big.Int)), m.cfg.MaxFee)
+	}
+
+	bounds, total := scaleEstimate(est, m.cfg.AmountMultiplier, m.cfg.PriceMultiplier)
+	if m.cfg.MaxFee != nil && total.Cmp(m.cfg.MaxFee) > 0 {
+		m.logger().Info(fmt.Sprintf("   ⛽ Fee bound %s above cap %s, using the bare estimate", total, m.cfg.MaxFee))
+		bounds, _ = scaleEstimate(est, 1, 1)
+	}
+	return bounds, nil
+}
+
+func (m *Starknet) nonceLocked(ctx context.Context) (*felt.Felt, error) {
+	if m.nextNonce == nil {
+		nonce, err := m.backend.Nonce(ctx, rpc.WithBlockTag(rpc.BlockTagPreConfirmed), m.address)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get nonce: %w", err)
+		}
+		m.nextNonce = nonce
+	}
+	return m.nextNonce, nil
+}
+
+// scaleEstimate returns the resource bounds for est and the maximum total fee they allow
+func scaleEstimate(est rpc.FeeEstimation, amountMultiplier, priceMultiplier float64) (*rpc.ResourceBoundsMapping, *big.Int) {
+	total := new(big.Int)
+	bound := func(consumed, price *felt.Felt) rpc.ResourceBounds {
+		amount := scale(consumed, amountMultiplier, maxU64)
+		unitPrice := scale(price, priceMultiplier, maxU128)
+		total.Add(total, new(big.Int).Mul(amount, unitPrice))
+		return rpc.ResourceBounds{
+			MaxAmount:       rpc.U64(fmt.Sprintf("0x%x", amount)),
+			MaxPricePerUnit: rpc.U128(fmt.Sprintf("0x%x", unitPrice)),
+		}
+	}
+
+	bounds := &rpc.ResourceBoundsMapping{
+		L1Gas:     bound(est.L1GasConsumed, est.L1GasPrice),
+		L1DataGas: bound(est.L1DataGasConsumed, est.L1DataGasPrice),
+		L2Gas:     bound(est.L2GasConsumed, est.L2GasPrice),
+	}
+	return bounds, total
+}
+
+// scale returns ceil(value * multiplier), clamped to limit
+func scale(value *felt.Felt, multiplier float64, limit *big.Int) *big.Int {
+	if value == nil {
+		return new(big.Int)
+	}
+	scaled := new(big.Float).Mul(new(big.Float).SetInt(value.BigInt(new(big.Int))), big.NewFloat(multiplier))
+	out, accuracy := scaled.Int(nil)
+	if accuracy == big.Below {
+		out.Add(out, big.NewInt(1))
+	}
+	if out.Cmp(limit) > 0 {
+		return new(big.Int).Set(limit)
+	}
+	return out
+}
+
+func zeroResourceBounds() *rpc.ResourceBoundsMapping {
+	zero := rpc.ResourceBounds{MaxAmount: "0x0", MaxPricePerUnit: "0x0"}
+	return &rpc.ResourceBoundsMapping{L1Gas: zero, L1DataGas: zero, L2Gas: zero}
+}
+
+func isInvalidNonce(err error) bool {
+	msg := strings.ToLower(err.Error())
+	return strings.Contains(msg, "invalid transaction nonce") || strings.Contains(msg, "nonce too low") ||
+		strings.Contains(msg, "invalid nonce")
+}
+
+func isHashNotFound(err error) bool {
+	return strings.Contains(strings.ToLower(err.Error()), "transaction hash not found")
+}
+
+// logger returns the default logger tagged with the manager's chain
+func (m *Starknet) logger() logutil.Logger {
+	return logutil.Default().With(logutil.Chain(logutil.NetworkNameByChainID(m.chainID)))
+}
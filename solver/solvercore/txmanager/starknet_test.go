@@ -0,0 +1,293 @@
+package txmanager
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/NethermindEth/juno/core/felt"
+	"github.com/NethermindEth/starknet.go/rpc"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/NethermindEth/oif-starknet/solver/solvercore/failure"
+)
+
+// fakeStarknet is an in-memory StarknetBackend and StarknetSigner; invokes are finalised on demand via settle()
+type fakeStarknet struct {
+	mu          sync.Mutex
+	nonce       uint64
+	sent        []*rpc.BroadcastInvokeTxnV3
+	statuses    map[string]*rpc.TxnStatusResult
+	estimate    rpc.FeeEstimation
+	estimateErr error
+	addErr      error
+}
+
+func newFakeStarknet() *fakeStarknet {
+	return &fakeStarknet{
+		statuses: make(map[string]*rpc.TxnStatusResult),
+		estimate: rpc.FeeEstimation{
+			FeeEstimationCommon: rpc.FeeEstimationCommon{
+				L1GasConsumed:     new(felt.Felt).SetUint64(0),
+				L1GasPrice:        new(felt.Felt).SetUint64(100),
+				L2GasConsumed:     new(felt.Felt).SetUint64(1000),
+				L2GasPrice:        new(felt.Felt).SetUint64(10),
+				L1DataGasConsumed: new(felt.Felt).SetUint64(10),
+				L1DataGasPrice:    new(felt.Felt).SetUint64(3),
+				OverallFee:        new(felt.Felt).SetUint64(10030),
+			},
+			Unit: rpc.FriUnit,
+		},
+	}
+}
+
+func (c *fakeStarknet) Nonce(context.Context, rpc.BlockID, *felt.Felt) (*felt.Felt, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return new(felt.Felt).SetUint64(c.nonce), nil
+}
+
+func (c *fakeStarknet) EstimateFee(context.Context, []rpc.BroadcastTxn, []rpc.SimulationFlag, rpc.BlockID) (
+	[]rpc.FeeEstimation, error) {
+	if c.estimateErr != nil {
+		return nil, c.estimateErr
+	}
+	return []rpc.FeeEstimation{c.estimate}, nil
+}
+
+func (c *fakeStarknet) AddInvokeTransaction(_ context.Context, tx *rpc.BroadcastInvokeTxnV3) (
+	rpc.AddInvokeTransactionResponse, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if c.addErr != nil {
+		err := c.addErr
+		c.addErr = nil
+		return rpc.AddInvokeTransactionResponse{}, err
+	}
+	if tx.Nonce.Cmp(new(felt.Felt).SetUint64(c.nonce)) < 0 {
+		return rpc.AddInvokeTransactionResponse{}, errors.New("Invalid transaction nonce")
+	}
+	c.sent = append(c.sent, tx)
+	hash := new(felt.Felt).SetUint64(uint64(len(c.sent)))
+	c.statuses[hash.String()] = &rpc.TxnStatusResult{FinalityStatus: rpc.TxnStatusReceived}
+	return rpc.AddInvokeTransactionResponse{Hash: hash}, nil
+}
+
+func (c *fakeStarknet) TransactionStatus(_ context.Context, hash *felt.Felt) (*rpc.TxnStatusResult, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	status, ok := c.statuses[hash.String()]
+	if !ok {
+		return nil, errors.New("Transaction hash not found")
+	}
+	copied := *status
+	return &copied, nil
+}
+
+func (c *fakeStarknet) TransactionReceipt(_ context.Context, hash *felt.Felt) (*rpc.TransactionReceiptWithBlockInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	status := c.statuses[hash.String()]
+	receipt := &rpc.TransactionReceiptWithBlockInfo{}
+	receipt.Hash = hash
+	receipt.ExecutionStatus = status.ExecutionStatus
+	receipt.RevertReason = status.FailureReason
+	return receipt, nil
+}
+
+func (c *fakeStarknet) FmtCalldata(calls []rpc.FunctionCall) ([]*felt.Felt, error) {
+	calldata := []*felt.Felt{new(felt.Felt).SetUint64(uint64(len(calls)))}
+	for _, call := range calls {
+		calldata = append(calldata, call.ContractAddress, call.EntryPointSelector)
+		calldata = append(calldata, call.Calldata...)
+	}
+	return calldata, nil
+}
+
+func (c *fakeStarknet) SignInvokeTransaction(_ context.Context, tx rpc.InvokeTxnType) error {
+	invoke, ok := tx.(*rpc.BroadcastInvokeTxnV3)
+	if !ok {
+		return errors.New("unexpected transaction type")
+	}
+	invoke.Signature = []*felt.Felt{new(felt.Felt).SetUint64(1)}
+	return nil
+}
+
+// settle gives the latest invoke its final status and consumes its nonce unless rejected
+func (c *fakeStarknet) settle(status rpc.TxnStatusResult) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	hash := new(felt.Felt).SetUint64(uint64(len(c.sent)))
+	c.statuses[hash.String()] = &status
+	if status.FinalityStatus != txnStatusRejected {
+		c.nonce++
+	}
+}
+
+func (c *fakeStarknet) sentCount() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.sent)
+}
+
+func newTestStarknetManager(chain *fakeStarknet) *Starknet {
+	return newStarknet(chain, chain, new(felt.Felt).SetUint64(0xabc), 23448591, StarknetConfig{
+		AmountMultiplier: 1.5,
+		PriceMultiplier:  2,
+		MaxFee:           nil,
+		Tip:              0,
+		ReceiptTimeout:   2 * time.Second,
+		PollInterval:     5 * time.Millisecond,
+		Finality:         rpc.TxnStatusAcceptedOnL2,
+	})
+}
+
+// settleWhen gives the n-th invoke its final status once it has been sent
+func settleWhen(chain *fakeStarknet, n int, status rpc.TxnStatusResult) {
+	go func() {
+		for chain.sentCount() < n {
+			time.Sleep(time.Millisecond)
+		}
+		chain.settle(status)
+	}()
+}
+
+var (
+	accepted = rpc.TxnStatusResult{FinalityStatus: rpc.TxnStatusAcceptedOnL2,
+		ExecutionStatus: rpc.TxnExecutionStatusSUCCEEDED, FailureReason: ""}
+	testCall = []rpc.InvokeFunctionCall{{ContractAddress: new(felt.Felt).SetUint64(1), FunctionName: "fill", CallData: nil}}
+)
+
+func TestStarknetSendAppliesBoundsAndLocalNonces(t *testing.T) {
+	chain := newFakeStarknet()
+	m := newTestStarknetManager(chain)
+	ctx := context.Background()
+
+	for i := 1; i <= 2; i++ {
+		settleWhen(chain, i, accepted)
+		receipt, err := m.Send(ctx, testCall)
+		require.NoError(t, err)
+		require.NotNil(t, receipt)
+	}
+
+	require.Len(t, chain.sent, 2)
+	first := chain.sent[0]
+	assert.Equal(t, rpc.TransactionV3, first.Version)
+	assert.Equal(t, "0x0", first.Nonce.String())
+	assert.Equal(t, "0x1", chain.sent[1].Nonce.String())
+	assert.Equal(t, rpc.U64("0x5dc"), first.ResourceBounds.L2Gas.MaxAmount)
+	assert.Equal(t, rpc.U128("0x14"), first.ResourceBounds.L2Gas.MaxPricePerUnit)
+	assert.Equal(t, rpc.U64("0xf"), first.ResourceBounds.L1DataGas.MaxAmount)
+	assert.Equal(t, rpc.U128("0x6"), first.ResourceBounds.L1DataGas.MaxPricePerUnit)
+}
+
+func TestStarknetFeeCap(t *testing.T) {
+	t.Run("bounds above the cap fall back to the estimate", func(t *testing.T) {
+		chain := newFakeStarknet()
+		m := newTestStarknetManager(chain)
+		m.cfg.MaxFee = big.NewInt(20000)
+
+		settleWhen(chain, 1, accepted)
+		_, err := m.Send(context.Background(), testCall)
+		require.NoError(t, err)
+		assert.Equal(t, rpc.U64("0x3e8"), chain.sent[0].ResourceBounds.L2Gas.MaxAmount)
+		assert.Equal(t, rpc.U128("0xa"), chain.sent[0].ResourceBounds.L2Gas.MaxPricePerUnit)
+	})
+
+	t.Run("estimate above the cap fails", func(t *testing.T) {
+		chain := newFakeStarknet()
+		m := newTestStarknetManager(chain)
+		m.cfg.MaxFee = big.NewInt(10000)
+
+		_, err := m.Send(context.Background(), testCall)
+		assert.True(t, errors.Is(err, ErrFeeCapExceeded))
+		assert.Equal(t, failure.KindInsufficientFunds, failure.KindOf(err))
+		assert.Zero(t, chain.sentCount())
+	})
+}
+
+func TestStarknetFinality(t *testing.T) {
+	t.Run("reverted receipt is returned with an error", func(t *testing.T) {
+		chain := newFakeStarknet()
+		m := newTestStarknetManager(chain)
+
+		settleWhen(chain, 1, rpc.TxnStatusResult{FinalityStatus: rpc.TxnStatusAcceptedOnL2,
+			ExecutionStatus: rpc.TxnExecutionStatusREVERTED, FailureReason: "order already filled"})
+		receipt, err := m.Send(context.Background(), testCall)
+		require.NotNil(t, receipt)
+		assert.True(t, errors.Is(err, ErrTransactionReverted))
+		assert.Contains(t, err.Error(), "order already filled")
+	})
+
+	t.Run("L1 finality waits past ACCEPTED_ON_L2", func(t *testing.T) {
+		chain := newFakeStarknet()
+		m := newTestStarknetManager(chain)
+		m.cfg.Finality = rpc.TxnStatusAcceptedOnL1
+
+		settleWhen(chain, 1, accepted)
+		done := make(chan error, 1)
+		go func() {
+			_, err := m.Send(context.Background(), testCall)
+			done <- err
+		}()
+		select {
+		case <-done:
+			t.Fatal("receipt returned on ACCEPTED_ON_L2")
+		case <-time.After(50 * time.Millisecond):
+		}
+
+		chain.settle(rpc.TxnStatusResult{FinalityStatus: rpc.TxnStatusAcceptedOnL1,
+			ExecutionStatus: rpc.TxnExecutionStatusSUCCEEDED, FailureReason: ""})
+		select {
+		case err := <-done:
+			require.NoError(t, err)
+		case <-time.After(time.Second):
+			t.Fatal("receipt not returned on ACCEPTED_ON_L1")
+		}
+	})
+
+	t.Run("rejected invoke frees its nonce", func(t *testing.T) {
+		chain := newFakeStarknet()
+		m := newTestStarknetManager(chain)
+
+		settleWhen(chain, 1, rpc.TxnStatusResult{FinalityStatus: txnStatusRejected, ExecutionStatus: "", FailureReason: ""})
+		_, err := m.Send(context.Background(), testCall)
+		assert.True(t, errors.Is(err, ErrTransactionRejected))
+		assert.Equal(t, failure.KindRevert, failure.KindOf(err))
+
+		settleWhen(chain, 2, accepted)
+		_, err = m.Send(context.Background(), testCall)
+		require.NoError(t, err)
+		assert.Equal(t, "0x0", chain.sent[1].Nonce.String())
+	})
+
+	t.Run("invalid nonce resyncs", func(t *testing.T) {
+		chain := newFakeStarknet()
+		m := newTestStarknetManager(chain)
+		m.nextNonce = new(felt.Felt) // stale local nonce 0
+		chain.nonce = 5
+
+		settleWhen(chain, 1, accepted)
+		_, err := m.Send(context.Background(), testCall)
+		require.NoError(t, err)
+		assert.Equal(t, "0x5", chain.sent[0].Nonce.String())
+	})
+
+	t.Run("send errors do not consume the nonce", func(t *testing.T) {
+		chain := newFakeStarknet()
+		m := newTestStarknetManager(chain)
+		chain.addErr = errors.New("Account balance is smaller than the transaction's maximal fee")
+
+		_, err := m.Send(context.Background(), testCall)
+		require.Error(t, err)
+
+		settleWhen(chain, 1, accepted)
+		_, err = m.Send(context.Background(), testCall)
+		require.NoError(t, err)
+		assert.Equal(t, "0x0", chain.sent[0].Nonce.String())
+	})
+}
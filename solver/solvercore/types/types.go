@@ -2,6 +2,7 @@ package types
 
 import (
 	"math/big"
+	"strings"
 )
 
 // ParsedArgs represents the parsed arguments from an Open event
@@ -26,6 +27,30 @@ type Output struct {
 	ChainID   *big.Int `json:"chainId"`   // Destination chain ID
 }
 
+// NativeToken is the canonical Output.Token for an EVM chain's native asset (ETH).
+// Hyperlane7683 encodes native outputs as the zero address; listeners store them as NativeToken.
+// Starknet has no native asset that can be filled, so Starknet handlers reject native outputs.
+const NativeToken = "0x0"
+
+// IsNativeToken reports whether token is an explicit all-zero hex address of any width; an empty token is not native
+func IsNativeToken(token string) bool {
+	trimmed := strings.TrimPrefix(strings.ToLower(token), "0x")
+	return trimmed != "" && strings.Trim(trimmed, "0") == ""
+}
+
+// CanonicalToken returns NativeToken for native assets and token unchanged otherwise
+func CanonicalToken(token string) string {
+	if IsNativeToken(token) {
+		return NativeToken
+	}
+	return token
+}
+
+// IsNative reports whether the output is paid in the chain's native asset
+func (o Output) IsNative() bool {
+	return IsNativeToken(o.Token)
+}
+
 // FillInstruction represents instructions to parameterize each leg of the fill
 type FillInstruction struct {
 	DestinationChainID *big.Int `json:"destinationChainId"` // Chain to fill on
@@ -175,6 +200,60 @@ func (p *ParsedArgs) GetOrderIDBytes() [32]byte {
 	return result
 }
 
+// ForInstruction returns a view of the order restricted to its i-th fill instruction.
+// Every MaxSpent output belongs to at most one instruction, the one SpendingInstruction pairs it with.
+func (p *ParsedArgs) ForInstruction(i int) *ParsedArgs {
+	leg := *p
+	instruction := p.ResolvedOrder.FillInstructions[i]
+	leg.ResolvedOrder.FillInstructions = []FillInstruction{instruction}
+	leg.ResolvedOrder.MaxSpent = make([]Output, 0, len(p.ResolvedOrder.MaxSpent))
+	for j, output := range p.ResolvedOrder.MaxSpent {
+		if p.SpendingInstruction(j) != i {
+			continue
+		}
+		if output.ChainID == nil {
+			output.ChainID = instruction.DestinationChainID
+		}
+		leg.ResolvedOrder.MaxSpent = append(leg.ResolvedOrder.MaxSpent, output)
+	}
+	return &leg
+}
+
+// SpendingInstruction returns the index of the fill instruction the j-th MaxSpent output is spent on, or -1 if none
+// is on its chain. Outputs are paired with instructions in order: the k-th output on a chain goes to the k-th
+// instruction on that chain and any extra ones to the last. An output without a chain ID goes to the instruction at
+// its own index when every instruction has one output, and to the first instruction otherwise.
+func (p *ParsedArgs) SpendingInstruction(j int) int {
+	instructions := p.ResolvedOrder.FillInstructions
+	output := p.ResolvedOrder.MaxSpent[j]
+	if output.ChainID == nil {
+		if len(p.ResolvedOrder.MaxSpent) == len(instructions) {
+			return j
+		}
+		return 0
+	}
+
+	// Number of earlier outputs on the same chain, which are paired with the earlier instructions on it
+	k := 0
+	for _, earlier := range p.ResolvedOrder.MaxSpent[:j] {
+		if earlier.ChainID != nil && earlier.ChainID.Cmp(output.ChainID) == 0 {
+			k++
+		}
+	}
+	last := -1
+	for i, instruction := range instructions {
+		if instruction.DestinationChainID == nil || instruction.DestinationChainID.Cmp(output.ChainID) != 0 {
+			continue
+		}
+		if k == 0 {
+			return i
+		}
+		k--
+		last = i
+	}
+	return last
+}
+
 // hexToByte converts a hex character to byte
 func hexToByte(c byte) byte {
 	if c >= '0' && c <= '9' {
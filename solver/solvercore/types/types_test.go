@@ -184,6 +184,87 @@ func TestParsedArgs(t *testing.T) {
 			assert.Equal(t, byte(0), b)
 		}
 	})
+
+	t.Run("ForInstruction", func(t *testing.T) {
+		args := ParsedArgs{
+			OrderID: "0x01",
+			ResolvedOrder: ResolvedCrossChainOrder{
+				MaxSpent: []Output{
+					{Token: "0xaa", Amount: big.NewInt(1), ChainID: nil},
+					{Token: "0xbb", Amount: big.NewInt(2), ChainID: big.NewInt(10)},
+					{Token: "0xcc", Amount: big.NewInt(3), ChainID: big.NewInt(8453)},
+				},
+				FillInstructions: []FillInstruction{
+					{DestinationChainID: big.NewInt(10), DestinationSettler: "0x10"},
+					{DestinationChainID: big.NewInt(8453), DestinationSettler: "0x20"},
+				},
+			},
+		}
+
+		first := args.ForInstruction(0)
+		require.Len(t, first.ResolvedOrder.FillInstructions, 1)
+		assert.Equal(t, "0x10", first.ResolvedOrder.FillInstructions[0].DestinationSettler)
+		require.Len(t, first.ResolvedOrder.MaxSpent, 2)
+		assert.Equal(t, "0xaa", first.ResolvedOrder.MaxSpent[0].Token)
+		assert.Equal(t, int64(10), first.ResolvedOrder.MaxSpent[0].ChainID.Int64())
+
+		second := args.ForInstruction(1)
+		require.Len(t, second.ResolvedOrder.MaxSpent, 1)
+		assert.Equal(t, "0xcc", second.ResolvedOrder.MaxSpent[0].Token)
+		assert.Equal(t, "0x01", second.OrderID)
+
+		// The original order is left untouched
+		assert.Len(t, args.ResolvedOrder.FillInstructions, 2)
+		assert.Nil(t, args.ResolvedOrder.MaxSpent[0].ChainID)
+	})
+
+	t.Run("ForInstruction with two legs on one chain", func(t *testing.T) {
+		args := ParsedArgs{
+			OrderID: "0x01",
+			ResolvedOrder: ResolvedCrossChainOrder{
+				MaxSpent: []Output{
+					{Token: "0xaa", Amount: big.NewInt(1), ChainID: big.NewInt(10)},
+					{Token: "", Amount: big.NewInt(2), ChainID: big.NewInt(10)},
+				},
+				FillInstructions: []FillInstruction{
+					{DestinationChainID: big.NewInt(10), DestinationSettler: "0x10"},
+					{DestinationChainID: big.NewInt(10), DestinationSettler: "0x10"},
+				},
+			},
+		}
+
+		// Each leg spends its own output, so a native output is carried only by the leg that owes it
+		first := args.ForInstruction(0)
+		require.Len(t, first.ResolvedOrder.MaxSpent, 1)
+		assert.Equal(t, "0xaa", first.ResolvedOrder.MaxSpent[0].Token)
+		second := args.ForInstruction(1)
+		require.Len(t, second.ResolvedOrder.MaxSpent, 1)
+		assert.Equal(t, "", second.ResolvedOrder.MaxSpent[0].Token)
+		assert.Equal(t, int64(2), second.ResolvedOrder.MaxSpent[0].Amount.Int64())
+	})
+
+	t.Run("ForInstruction with more outputs than legs on a chain", func(t *testing.T) {
+		args := ParsedArgs{
+			OrderID: "0x01",
+			ResolvedOrder: ResolvedCrossChainOrder{
+				MaxSpent: []Output{
+					{Token: "0xaa", Amount: big.NewInt(1), ChainID: big.NewInt(10)},
+					{Token: "0xbb", Amount: big.NewInt(2), ChainID: big.NewInt(10)},
+					{Token: "0xcc", Amount: big.NewInt(3), ChainID: big.NewInt(10)},
+					{Token: "0xdd", Amount: big.NewInt(4), ChainID: big.NewInt(1)},
+				},
+				FillInstructions: []FillInstruction{
+					{DestinationChainID: big.NewInt(10), DestinationSettler: "0x10"},
+					{DestinationChainID: big.NewInt(10), DestinationSettler: "0x10"},
+				},
+			},
+		}
+
+		// Extra outputs go to the chain's last leg; outputs on no leg's chain are spent by none
+		assert.Len(t, args.ForInstruction(0).ResolvedOrder.MaxSpent, 1)
+		assert.Len(t, args.ForInstruction(1).ResolvedOrder.MaxSpent, 2)
+		assert.Equal(t, -1, args.SpendingInstruction(3))
+	})
 }
 
 // Test constants
@@ -376,3 +457,19 @@ func TestGetOrderIDBytes(t *testing.T) {
 		assert.Equal(t, expected, result)
 	})
 }
+
+func TestNativeToken(t *testing.T) {
+	assert.False(t, IsNativeToken(""), "a missing token is not the native asset")
+	assert.False(t, IsNativeToken("0x"))
+	assert.True(t, IsNativeToken(NativeToken))
+	assert.True(t, IsNativeToken("0x0000000000000000000000000000000000000000"))
+	assert.True(t, IsNativeToken("0x0000000000000000000000000000000000000000000000000000000000000000"))
+	assert.False(t, IsNativeToken("0x0000000000000000000000001234567890123456789012345678901234567890"))
+
+	assert.Equal(t, NativeToken, CanonicalToken("0x0000000000000000000000000000000000000000000000000000000000000000"))
+	assert.Equal(t, "0xabc", CanonicalToken("0xabc"))
+
+	assert.True(t, Output{Token: NativeToken, Amount: big.NewInt(1)}.IsNative())
+	assert.False(t, Output{Token: "", Amount: big.NewInt(1)}.IsNative())
+	assert.False(t, Output{Token: "0xabc", Amount: big.NewInt(1)}.IsNative())
+}